@@ -1,12 +1,15 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"bedrock-forge/internal/commands"
+	"bedrock-forge/internal/packager"
+	"bedrock-forge/internal/parser"
 	"bedrock-forge/pkg/config"
 )
 
@@ -15,20 +18,47 @@ var logger *logrus.Logger
 var rootCmd = &cobra.Command{
 	Use:   "bedrock-forge",
 	Short: "Transform YAML configurations into AWS Bedrock agent deployments",
-	Long:  `Bedrock Forge is a CLI tool that transforms YAML configurations into AWS Bedrock agent deployments using Terraform modules.`,
+	Long: `Bedrock Forge is a CLI tool that transforms YAML configurations into AWS Bedrock agent deployments using Terraform modules.
+
+Logs are written to stderr so command output on stdout stays machine-readable.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logFormat, _ := cmd.Flags().GetString("log-format")
+		logLevel, _ := cmd.Flags().GetString("log-level")
+
+		if err := config.ConfigureLogger(logger, config.LogFormat(logFormat), config.LogLevel(logLevel)); err != nil {
+			logger.WithError(err).Fatal("Invalid logging options")
+		}
+	},
 }
 
 var scanCmd = &cobra.Command{
 	Use:   "scan [path]",
 	Short: "Discover and list all resources in the current directory",
-	Long:  `Scan the current directory for YAML files and discover all Bedrock resources.`,
+	Long: `Scan the current directory for YAML files and discover all Bedrock resources.
+
+Pass - as the path to read a multi-document YAML stream from stdin instead.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var scanPath string
 		if len(args) > 0 {
 			scanPath = args[0]
 		}
 
+		format, _ := cmd.Flags().GetString("format")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
 		scanCommand := commands.NewScanCommand(logger)
+		scanCommand.SetTemplateVars(templateVars(cmd))
+		scanCommand.SetOverlayDir(overlayDir(cmd))
+		scanCommand.SetStrictFields(strictFields(cmd))
+		if err := scanCommand.SetOutputFormat(format); err != nil {
+			logger.WithError(err).Fatal("Invalid scan options")
+		}
+		if err := scanCommand.SetSelector(selector(cmd)); err != nil {
+			logger.WithError(err).Fatal("Invalid scan options")
+		}
+		if err := scanCommand.SetConcurrency(concurrency); err != nil {
+			logger.WithError(err).Fatal("Invalid scan options")
+		}
 		if err := scanCommand.Execute(scanPath); err != nil {
 			logger.WithError(err).Fatal("Failed to execute scan command")
 		}
@@ -38,31 +68,90 @@ var scanCmd = &cobra.Command{
 var validateCmd = &cobra.Command{
 	Use:   "validate [path]",
 	Short: "Validate YAML syntax and dependencies",
-	Long:  `Validate all discovered YAML files for syntax errors and dependency issues.`,
+	Long: `Validate all discovered YAML files for syntax errors and dependency issues.
+
+Pass - as the path to read a multi-document YAML stream from stdin instead; bedrock-forge.yaml
+and validation.yml are then loaded from the current directory.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var validatePath string
 		if len(args) > 0 {
 			validatePath = args[0]
 		}
 
+		profile, _ := cmd.Flags().GetString("profile")
+		configPath, _ := cmd.Flags().GetString("config")
+
 		validateCommand := commands.NewValidateCommand(logger)
+		validateCommand.SetTemplateVars(templateVars(cmd))
+		validateCommand.SetOverlayDir(overlayDir(cmd))
+		validateCommand.SetStrictFields(strictFields(cmd))
+		if profile != "" {
+			validateCommand.SetValidationProfile(profile)
+		}
+		validateCommand.SetConfigPath(configPath)
 		if err := validateCommand.Execute(validatePath); err != nil {
 			logger.WithError(err).Fatal("Failed to execute validate command")
 		}
 	},
 }
 
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Run naming, tagging and security validators",
+	Long: `Run all validators against the discovered YAML resources without generating Terraform.
+Findings are grouped by source file; the command exits non-zero when any errors are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var lintPath string
+		if len(args) > 0 {
+			lintPath = args[0]
+		}
+
+		profile, _ := cmd.Flags().GetString("profile")
+
+		lintCommand := commands.NewLintCommand(logger)
+		lintCommand.SetTemplateVars(templateVars(cmd))
+		lintCommand.SetOverlayDir(overlayDir(cmd))
+		lintCommand.SetStrictFields(strictFields(cmd))
+		if err := lintCommand.SetValidationProfile(profile); err != nil {
+			logger.WithError(err).Fatal("Invalid lint options")
+		}
+		if err := lintCommand.Execute(lintPath); err != nil {
+			logger.WithError(err).Fatal("Failed to execute lint command")
+		}
+	},
+}
+
 var generateCmd = &cobra.Command{
 	Use:   "generate [path] [output-dir]",
 	Short: "Generate Terraform configuration from YAML resources",
 	Long: `Generate Terraform configuration files from discovered YAML resources.
 
 Arguments:
-  path        Path to directory containing YAML files (default: current directory)
+  path        Path to directory containing YAML files (default: current directory),
+              or - to read a multi-document YAML stream from stdin
   output-dir  Output directory for generated Terraform files (default: outputs_tf)
 
+Resources read from stdin have no directory, so Lambdas with code.source directory and
+ActionGroups with schemaFiles are rejected, and nothing is packaged.
+
 The generated Terraform files will be placed in the outputs_tf directory by default,
-so you can immediately inspect the generated .tf files without any additional setup.`,
+so you can immediately inspect the generated .tf files without any additional setup.
+
+Packaged Lambda code and schemas are only uploaded to S3 when --upload is set,
+to the bucket set with --s3-bucket or s3Bucket in bedrock-forge.yaml.
+Use --s3-sse and --s3-kms-key-id to encrypt them; when the validation rules
+require encryption at rest (e.g. the enterprise profile), uploads without an
+SSE mode are refused.
+With --dry-run, packaging is skipped entirely and placeholder S3 keys are used;
+the output is structurally complete but not deployable as-is.
+
+With --stdout, main.tf is written to stdout and nothing is written to the output directory.
+
+With --watch, generation re-runs whenever YAML files under the input path change, until interrupted.
+
+With --incremental, a manifest of input hashes is kept in the output directory. Lambdas
+and schemas whose inputs didn't change since the last run reuse their packages, and
+the run is skipped entirely when nothing changed.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var scanPath, outputDir string
 		if len(args) > 0 {
@@ -72,28 +161,398 @@ so you can immediately inspect the generated .tf files without any additional se
 			outputDir = args[1]
 		}
 
+		upload, _ := cmd.Flags().GetBool("upload")
+		s3Region, _ := cmd.Flags().GetString("s3-region")
+		s3SSE, _ := cmd.Flags().GetString("s3-sse")
+		s3KMSKeyID, _ := cmd.Flags().GetString("s3-kms-key-id")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		watch, _ := cmd.Flags().GetBool("watch")
+		incremental, _ := cmd.Flags().GetBool("incremental")
+		packageConcurrency, _ := cmd.Flags().GetInt("package-concurrency")
+
 		generateCommand := commands.NewGenerateCommand(logger)
+		generateCommand.SetTemplateVars(templateVars(cmd))
+		generateCommand.SetOverlayDir(overlayDir(cmd))
+		generateCommand.SetStrictFields(strictFields(cmd))
+		generateCommand.SetUpload(upload, packager.AWSS3Config{
+			Region:   s3Region,
+			Profile:  awsProfile,
+			SSEMode:  s3SSE,
+			KMSKeyID: s3KMSKeyID,
+		})
+		generateCommand.SetDryRun(dryRun)
+		generateCommand.SetStdout(stdout)
+		generateCommand.SetWatch(watch)
+		generateCommand.SetIncremental(incremental)
+		generateCommand.SetPackageConcurrency(packageConcurrency)
+		if err := generateCommand.SetSelector(selector(cmd)); err != nil {
+			logger.WithError(err).Fatal("Invalid generate options")
+		}
+		generateCommand.SetProjectOverrides(projectOverrides(cmd))
 		if err := generateCommand.Execute(scanPath, outputDir); err != nil {
 			logger.WithError(err).Fatal("Failed to execute generate command")
 		}
 	},
 }
 
+var planCmd = &cobra.Command{
+	Use:   "plan [path] [output-dir]",
+	Short: "Generate Terraform configuration and run terraform plan",
+	Long: `Generate Terraform configuration files from discovered YAML resources and
+run terraform init and terraform plan against the output directory.
+
+Arguments:
+  path        Path to directory containing YAML files (default: current directory)
+  output-dir  Output directory for generated Terraform files (default: outputs_tf)
+
+A non-zero exit code from Terraform is reported as a command failure.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var scanPath, outputDir string
+		if len(args) > 0 {
+			scanPath = args[0]
+		}
+		if len(args) > 1 {
+			outputDir = args[1]
+		}
+
+		terraformBinary, _ := cmd.Flags().GetString("terraform-binary")
+
+		planCommand := commands.NewPlanCommand(logger)
+		planCommand.SetTerraformBinary(terraformBinary)
+		planCommand.SetTemplateVars(templateVars(cmd))
+		planCommand.SetOverlayDir(overlayDir(cmd))
+		planCommand.SetStrictFields(strictFields(cmd))
+		if err := planCommand.Execute(scanPath, outputDir); err != nil {
+			logger.WithError(err).Fatal("Failed to execute plan command")
+		}
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [path] [output-dir]",
+	Short: "Show differences between generated Terraform and existing output",
+	Long: `Generate Terraform configuration into a temporary directory and print a unified
+diff against the files already present in the output directory.
+
+Arguments:
+  path        Path to directory containing YAML files (default: current directory)
+  output-dir  Output directory with previously generated Terraform files (default: outputs_tf)
+
+Generation takes the same options as generate, but always runs as with --dry-run: Lambda
+code is not packaged and placeholder S3 keys are used.
+
+Use --exit-code to fail when the generated output is not up to date.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var scanPath, outputDir string
+		if len(args) > 0 {
+			scanPath = args[0]
+		}
+		if len(args) > 1 {
+			outputDir = args[1]
+		}
+
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+
+		diffCommand := commands.NewDiffCommand(logger)
+		diffCommand.SetExitCode(exitCode)
+		diffCommand.SetTemplateVars(templateVars(cmd))
+		diffCommand.SetOverlayDir(overlayDir(cmd))
+		diffCommand.SetStrictFields(strictFields(cmd))
+		if err := diffCommand.SetSelector(selector(cmd)); err != nil {
+			logger.WithError(err).Fatal("Invalid diff options")
+		}
+		diffCommand.SetProjectOverrides(projectOverrides(cmd))
+		if err := diffCommand.Execute(scanPath, outputDir); err != nil {
+			logger.WithError(err).Fatal("Failed to execute diff command")
+		}
+	},
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [path]",
+	Short: "Export the resource dependency graph as DOT or Mermaid",
+	Long: `Scan the directory for YAML resources and print the per-resource dependency graph.
+
+Nodes are labeled Kind/name and edges are labeled by the reference type.
+Use --format to choose between Graphviz DOT (default) and Mermaid output.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var scanPath string
+		if len(args) > 0 {
+			scanPath = args[0]
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+
+		graphCommand := commands.NewGraphCommand(logger)
+		graphCommand.SetTemplateVars(templateVars(cmd))
+		graphCommand.SetOverlayDir(overlayDir(cmd))
+		graphCommand.SetStrictFields(strictFields(cmd))
+		if err := graphCommand.SetOutputFormat(format); err != nil {
+			logger.WithError(err).Fatal("Invalid graph options")
+		}
+		if err := graphCommand.Execute(scanPath); err != nil {
+			logger.WithError(err).Fatal("Failed to execute graph command")
+		}
+	},
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [kind] [name] [path]",
+	Short: "Show the resolved view of a single resource",
+	Long: `Scan the directory for YAML resources and describe one of them: its effective
+definition after Defaults and overlays, what each of its references resolves to,
+everything it depends on directly or indirectly, and the resources referencing it.
+
+The kind is matched case-insensitively, e.g. "bedrock-forge describe agent customer-support".`,
+	Args:              cobra.RangeArgs(2, 3),
+	ValidArgsFunction: completeDescribeArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var scanPath string
+		if len(args) > 2 {
+			scanPath = args[2]
+		}
+
+		describeCommand := commands.NewDescribeCommand(logger)
+		describeCommand.SetTemplateVars(templateVars(cmd))
+		describeCommand.SetOverlayDir(overlayDir(cmd))
+		describeCommand.SetStrictFields(strictFields(cmd))
+		if err := describeCommand.Execute(args[0], args[1], scanPath); err != nil {
+			logger.WithError(err).Fatal("Failed to execute describe command")
+		}
+	},
+}
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate [path]",
+	Short: "Print a rough monthly cost estimate of the resources",
+	Long: `Scan the directory for YAML resources and estimate their monthly cost from their
+configuration: Lambda memory, timeout and provisioned concurrency, agent models,
+knowledge base ingestion and OpenSearch Serverless collections.
+
+The estimate is a heuristic approximation based on assumed usage and list prices,
+not a quote. Use --rates to replace any of the built-in rates and usage assumptions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var scanPath string
+		if len(args) > 0 {
+			scanPath = args[0]
+		}
+
+		ratesFile, _ := cmd.Flags().GetString("rates")
+
+		estimateCommand := commands.NewEstimateCommand(logger)
+		if err := estimateCommand.SetRatesFile(ratesFile); err != nil {
+			logger.WithError(err).Fatal("Invalid estimate options")
+		}
+		estimateCommand.SetTemplateVars(templateVars(cmd))
+		estimateCommand.SetOverlayDir(overlayDir(cmd))
+		estimateCommand.SetStrictFields(strictFields(cmd))
+		if err := estimateCommand.Execute(scanPath); err != nil {
+			logger.WithError(err).Fatal("Failed to execute estimate command")
+		}
+	},
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init [kind]",
+	Short: "Write a commented starter YAML file for a resource kind",
+	Long: `Write a starter YAML file for an agent, lambda, knowledge-base, guardrail or prompt
+into the current directory, with the required fields and sensible defaults filled in.
+
+Lambdas are written as <name>/lambda.yml with a handler in <name>/app.py, where
+packaging looks for their code. With --action-group, an agent is written together
+with such a Lambda and an action group that invokes it. Existing files are never
+overwritten.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		actionGroup, _ := cmd.Flags().GetBool("action-group")
+
+		initCommand := commands.NewInitCommand(logger)
+		if err := initCommand.SetName(name); err != nil {
+			logger.WithError(err).Fatal("Invalid init options")
+		}
+		initCommand.SetActionGroup(actionGroup)
+		if err := initCommand.Execute(args[0], ""); err != nil {
+			logger.WithError(err).Fatal("Failed to execute init command")
+		}
+	},
+}
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [path]",
+	Short: "Rewrite YAML resource files in canonical format",
+	Long: `Rewrite discovered YAML resource files with a canonical key order
+(kind, metadata, spec) and two-space indentation. Comments are preserved.
+
+The paths of reformatted files are printed. With --check, files are left
+untouched and the command fails if any of them is not formatted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var fmtPath string
+		if len(args) > 0 {
+			fmtPath = args[0]
+		}
+
+		check, _ := cmd.Flags().GetBool("check")
+
+		fmtCommand := commands.NewFmtCommand(logger)
+		fmtCommand.SetCheck(check)
+		if err := fmtCommand.Execute(fmtPath); err != nil {
+			logger.WithError(err).Fatal("Failed to execute fmt command")
+		}
+	},
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version and build info",
 	Long:  `Display the version number and build information for bedrock-forge.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		logrus.Info("bedrock-forge version 0.1.0")
+		fmt.Println("bedrock-forge version 0.1.0")
 	},
 }
 
+// completeDescribeArgs completes the kind, the names of resources of that kind in the current
+// directory, and then the path to scan
+func completeDescribeArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return commands.CompleteResourceKinds(toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return commands.CompleteResourceNames("", args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 2:
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfiles completes the --profile flag with the validation profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"default", "enterprise"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// templateVars loads the --var-file and --var values used to render YAML templates
+func templateVars(cmd *cobra.Command) map[string]interface{} {
+	varFile, _ := cmd.Flags().GetString("var-file")
+	vars, _ := cmd.Flags().GetStringArray("var")
+
+	values, err := parser.LoadTemplateVars(varFile, vars)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid template variables")
+	}
+	return values
+}
+
+// overlayDir returns the --overlay directory merged over the scanned resources
+func overlayDir(cmd *cobra.Command) string {
+	dir, _ := cmd.Flags().GetString("overlay")
+	return dir
+}
+
+// strictFields returns whether --strict-fields rejects fields the resource types don't define
+func strictFields(cmd *cobra.Command) bool {
+	strict, _ := cmd.Flags().GetBool("strict-fields")
+	return strict
+}
+
+// selector returns the --selector label selector
+func selector(cmd *cobra.Command) string {
+	selector, _ := cmd.Flags().GetString("selector")
+	return selector
+}
+
+// projectOverrides returns the generation flags that override bedrock-forge.yaml
+func projectOverrides(cmd *cobra.Command) config.ProjectConfig {
+	flags := cmd.Flags()
+	overrides := config.ProjectConfig{}
+	overrides.ProjectName, _ = flags.GetString("project-name")
+	overrides.Environment, _ = flags.GetString("environment")
+	overrides.ModuleRegistry, _ = flags.GetString("module-registry")
+	overrides.ModuleVersion, _ = flags.GetString("module-version")
+	overrides.LambdaKmsKeyArn, _ = flags.GetString("lambda-kms-key-arn")
+	overrides.GenerationMode, _ = flags.GetString("mode")
+	overrides.GlobalTags, _ = flags.GetStringToString("global-tag")
+	overrides.OutputLayout, _ = flags.GetString("output-format")
+	overrides.OutputSyntax, _ = flags.GetString("output-syntax")
+	overrides.Account, _ = flags.GetString("account")
+	overrides.Region, _ = flags.GetString("region")
+	overrides.S3Bucket, _ = flags.GetString("s3-bucket")
+	overrides.S3Prefix, _ = flags.GetString("s3-prefix")
+	return overrides
+}
+
 func init() {
 	logger = config.SetupSimpleLogger()
 
+	rootCmd.PersistentFlags().String("log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().String("log-level", "info", "Log level: debug, info, warn or error")
+
+	scanCmd.Flags().String("format", "text", "Output format: text or json")
+	scanCmd.Flags().String("selector", "", "Only include resources whose labels match, e.g. team=payments,tier!=experimental")
+	scanCmd.Flags().Int("concurrency", 0, "Number of files to parse in parallel (default: number of CPUs)")
+	validateCmd.Flags().String("profile", "", "Validation profile: default or enterprise (default: from bedrock-forge.yaml)")
+	validateCmd.Flags().String("config", "", "Path to a custom validation.yml")
+	lintCmd.Flags().String("profile", "", "Validation profile: default or enterprise (default: from bedrock-forge.yaml)")
+	generateCmd.Flags().Bool("upload", false, "Upload packaged artifacts to S3")
+	generateCmd.Flags().Bool("dry-run", false, "Skip artifact packaging and use placeholder S3 keys")
+	generateCmd.Flags().Bool("stdout", false, "Write the generated main.tf to stdout instead of the output directory")
+	generateCmd.Flags().Bool("watch", false, "Regenerate whenever YAML files under the input path change")
+	generateCmd.Flags().Bool("incremental", false, "Only repackage and regenerate what changed since the last run, tracked in a manifest in the output directory")
+	generateCmd.Flags().Int("package-concurrency", 0, "Number of Lambdas packaged at once (default: number of CPUs)")
+	generateCmd.Flags().String("s3-region", "", "AWS region of the artifact bucket (default: AWS_REGION)")
+	generateCmd.Flags().String("s3-sse", "", "Server-side encryption of uploaded artifacts: AES256 or aws:kms (implied by --s3-kms-key-id)")
+	generateCmd.Flags().String("s3-kms-key-id", "", "KMS key ARN for SSE-KMS encryption of uploaded artifacts")
+	generateCmd.Flags().String("aws-profile", "", "AWS config profile used for uploads (default: AWS_PROFILE)")
+	planCmd.Flags().String("terraform-binary", "terraform", "Path to the terraform binary")
+	diffCmd.Flags().Bool("exit-code", false, "Exit with a non-zero status when differences are found")
+	graphCmd.Flags().String("format", "dot", "Output format: dot or mermaid")
+	estimateCmd.Flags().String("rates", "", "YAML file with per-unit rates and usage assumptions replacing the built-in ones")
+	initCmd.Flags().String("name", "", "metadata.name of the resource (default: my-<kind>)")
+	initCmd.Flags().Bool("action-group", false, "For agents, also scaffold a Lambda action group in <name>-actions-lambda/")
+	fmtCmd.Flags().Bool("check", false, "List unformatted files and exit non-zero instead of rewriting them")
+
+	// diff generates like generate, so it takes the same options
+	for _, cmd := range []*cobra.Command{generateCmd, diffCmd} {
+		cmd.Flags().String("selector", "", "Only generate resources whose labels match, plus their dependencies")
+		cmd.Flags().String("project-name", "", "Project name (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("environment", "", "Environment name (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("module-registry", "", "Terraform module registry (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("module-version", "", "Terraform module version (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("lambda-kms-key-arn", "", "Default KMS key for Lambda environment variables (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("mode", "", "Generate only module calls or only native resources: module or native (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("output-format", "", "Split the configuration across files: single, per-kind or per-resource (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("output-syntax", "", "Write the configuration as hcl or as Terraform JSON (.tf.json) (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("account", "", "Deployment account ID; ARNs in other accounts are reported (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("region", "", "Deployment region; ARNs in other regions are reported (overrides bedrock-forge.yaml)")
+		cmd.Flags().StringToString("global-tag", nil, "Tag added to every resource through provider default_tags, e.g. CostCenter=1234 (repeatable)")
+		cmd.Flags().String("s3-bucket", "", "Bucket packaged artifacts are uploaded to, required with --upload (overrides bedrock-forge.yaml)")
+		cmd.Flags().String("s3-prefix", "", "Key prefix of uploaded artifacts (default: bedrock-forge, overrides bedrock-forge.yaml)")
+	}
+
+	for _, cmd := range []*cobra.Command{validateCmd, lintCmd} {
+		_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	}
+
+	for _, cmd := range []*cobra.Command{scanCmd, validateCmd, lintCmd, generateCmd, planCmd, diffCmd, graphCmd, describeCmd, estimateCmd} {
+		cmd.Flags().StringArray("var", nil, "Template variable rendered into ${{ .key }} in YAML files, e.g. environment=prod (repeatable)")
+		cmd.Flags().String("var-file", "", "YAML file with template variables; --var takes precedence")
+		cmd.Flags().String("overlay", "", "Directory of environment overlays deep-merged over resources with the same kind and name")
+		cmd.Flags().Bool("strict-fields", false, "Fail to parse resources with fields their kind doesn't define, including ones merged in from Defaults, instead of warning")
+	}
+
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(estimateCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(fmtCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+)
+
+// completionKinds are the resource kinds offered when completing a kind argument
+var completionKinds = []models.ResourceKind{
+	models.AgentKind,
+	models.LambdaKind,
+	models.LambdaLayerKind,
+	models.ActionGroupKind,
+	models.KnowledgeBaseKind,
+	models.OpenSearchServerlessKind,
+	models.GuardrailKind,
+	models.PromptKind,
+	models.IAMRoleKind,
+	models.AgentKnowledgeBaseAssociationKind,
+	models.CustomResourcesKind,
+}
+
+// CompleteResourceKinds returns the lowercase resource kinds starting with prefix
+func CompleteResourceKinds(prefix string) []string {
+	var kinds []string
+	for _, kind := range completionKinds {
+		name := strings.ToLower(string(kind))
+		if strings.HasPrefix(name, strings.ToLower(prefix)) {
+			kinds = append(kinds, name)
+		}
+	}
+	return kinds
+}
+
+// CompleteResourceNames returns the sorted names of the resources of a kind, matched
+// case-insensitively, found in YAML files under rootPath and starting with prefix. Files are only
+// decoded far enough to read kind and metadata.name, without templates, Defaults or validation, so
+// completion stays fast and works on resources that don't parse yet.
+func CompleteResourceNames(rootPath, kind, prefix string) []string {
+	if rootPath == "" {
+		rootPath = "."
+	}
+
+	// Completion output is read by the shell, so nothing may be logged
+	quiet := logrus.New()
+	quiet.SetOutput(io.Discard)
+
+	scanResult, err := parser.NewScanner(quiet).ScanDirectory(rootPath, nil, defaultExcludePatterns)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, file := range scanResult.Files {
+		for _, header := range resourceHeaders(file) {
+			if !strings.EqualFold(string(header.Kind), kind) || !strings.HasPrefix(header.Metadata.Name, prefix) {
+				continue
+			}
+			if !seen[header.Metadata.Name] {
+				seen[header.Metadata.Name] = true
+				names = append(names, header.Metadata.Name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// resourceHeader holds the fields identifying a resource
+type resourceHeader struct {
+	Kind     models.ResourceKind `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+// resourceHeaders returns the kind and name of each document in a YAML file, skipping documents
+// that can't be decoded
+func resourceHeaders(filePath string) []resourceHeader {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+
+	var headers []resourceHeader
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var header resourceHeader
+		if err := decoder.Decode(&header); err != nil {
+			// Ends at io.EOF; the decoder can't recover from syntax errors, so the rest of the file is skipped
+			break
+		}
+		if header.Kind != "" && header.Metadata.Name != "" {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
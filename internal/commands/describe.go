@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+)
+
+// DescribeCommand prints a resolved view of a single resource: its effective definition, what its
+// references resolve to, everything it depends on and the resources referencing it
+type DescribeCommand struct {
+	logger *logrus.Logger
+	scan   *ScanCommand
+}
+
+// describedReference is a reference of the described resource and the resource it resolves to
+type describedReference struct {
+	registry.ResourceReference
+	target *parser.ParsedResource
+}
+
+func NewDescribeCommand(logger *logrus.Logger) *DescribeCommand {
+	return &DescribeCommand{
+		logger: logger,
+		scan:   NewScanCommand(logger),
+	}
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *DescribeCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.scan.SetTemplateVars(vars)
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *DescribeCommand) SetOverlayDir(dir string) {
+	c.scan.SetOverlayDir(dir)
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *DescribeCommand) SetStrictFields(strict bool) {
+	c.scan.SetStrictFields(strict)
+}
+
+func (c *DescribeCommand) Execute(kind, name, rootPath string) error {
+	if rootPath == "" {
+		var err error
+		rootPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+
+	if err := c.scan.loadResources(rootPath); err != nil {
+		return fmt.Errorf("failed to scan resources: %w", err)
+	}
+
+	resourceRegistry := c.scan.GetRegistry()
+	resource, err := c.findResource(resourceRegistry, kind, name)
+	if err != nil {
+		return err
+	}
+
+	c.printResource(resourceRegistry, resource)
+	return nil
+}
+
+// findResource looks up a resource, matching the kind case-insensitively
+func (c *DescribeCommand) findResource(resourceRegistry *registry.ResourceRegistry, kind, name string) (*parser.ParsedResource, error) {
+	for resourceKind, resources := range resourceRegistry.GetAllResources() {
+		if !strings.EqualFold(string(resourceKind), kind) {
+			continue
+		}
+		if resource, exists := resources[name]; exists {
+			return resource, nil
+		}
+
+		names := resourceRegistry.ListResourceNames(resourceKind)
+		sort.Strings(names)
+		return nil, fmt.Errorf("%s %s not found, available: %s", resourceKind, name, strings.Join(names, ", "))
+	}
+
+	return nil, fmt.Errorf("no %s resources found", kind)
+}
+
+// resolveReferences pairs each reference of a resource with the resource it points to, if it exists
+func (c *DescribeCommand) resolveReferences(resourceRegistry *registry.ResourceRegistry, resource *parser.ParsedResource) []describedReference {
+	var refs []describedReference
+	for _, ref := range resourceRegistry.GetResourceReferences(resource) {
+		target, _ := resourceRegistry.GetResource(ref.Kind, ref.Name)
+		refs = append(refs, describedReference{ResourceReference: ref, target: target})
+	}
+	return refs
+}
+
+// dependencies returns every resource the given one depends on directly or through other resources,
+// keyed to the resource each indirect dependency was reached through
+func (c *DescribeCommand) dependencies(resourceRegistry *registry.ResourceRegistry, resource *parser.ParsedResource) ([]*parser.ParsedResource, map[string]string) {
+	var result []*parser.ParsedResource
+	via := make(map[string]string)
+	visited := map[string]bool{resourceID(resource): true}
+
+	queue := []*parser.ParsedResource{resource}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		currentKey := resourceID(current)
+
+		for _, ref := range c.resolveReferences(resourceRegistry, current) {
+			if ref.target == nil {
+				continue
+			}
+			key := resourceID(ref.target)
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			if current != resource {
+				via[key] = currentKey
+			}
+			result = append(result, ref.target)
+			queue = append(queue, ref.target)
+		}
+	}
+
+	return result, via
+}
+
+// referencedBy returns the references other resources make to the given one, sorted by resource
+func (c *DescribeCommand) referencedBy(resourceRegistry *registry.ResourceRegistry, resource *parser.ParsedResource) []describedReference {
+	var refs []describedReference
+	for _, kindResources := range resourceRegistry.GetAllResources() {
+		for _, other := range kindResources {
+			if other == resource {
+				continue
+			}
+			for _, ref := range resourceRegistry.GetResourceReferences(other) {
+				if ref.Kind == resource.Kind && ref.Name == resource.Metadata.Name {
+					refs = append(refs, describedReference{ResourceReference: ref, target: other})
+				}
+			}
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		left := resourceID(refs[i].target)
+		right := resourceID(refs[j].target)
+		if left != right {
+			return left < right
+		}
+		return refs[i].Field < refs[j].Field
+	})
+	return refs
+}
+
+func (c *DescribeCommand) printResource(resourceRegistry *registry.ResourceRegistry, resource *parser.ParsedResource) {
+	fmt.Printf("\n=== %s ===\n\n", resourceID(resource))
+	fmt.Printf("File: %s\n", c.scan.getRelativePath(resource.FilePath))
+	if resource.Metadata.Description != "" {
+		fmt.Printf("Description: %s\n", resource.Metadata.Description)
+	}
+
+	fmt.Printf("\n📄 Effective definition (after Defaults and overlays):\n")
+	for _, line := range strings.Split(strings.TrimRight(string(resource.RawContent), "\n"), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+
+	fmt.Printf("\n🔗 References:\n")
+	refs := c.resolveReferences(resourceRegistry, resource)
+	if len(refs) == 0 {
+		fmt.Printf("   (none)\n")
+	}
+	for _, ref := range refs {
+		fmt.Printf("   ├─ %s → %s\n", ref.Field, c.describeTarget(ref))
+	}
+
+	fmt.Printf("\n🧩 Dependencies:\n")
+	dependencies, via := c.dependencies(resourceRegistry, resource)
+	if len(dependencies) == 0 {
+		fmt.Printf("   (none)\n")
+	}
+	for _, dependency := range dependencies {
+		key := resourceID(dependency)
+		if parent, ok := via[key]; ok {
+			fmt.Printf("   ├─ %s (via %s)\n", key, parent)
+		} else {
+			fmt.Printf("   ├─ %s\n", key)
+		}
+	}
+
+	fmt.Printf("\n⬅️  Referenced by:\n")
+	referencedBy := c.referencedBy(resourceRegistry, resource)
+	if len(referencedBy) == 0 {
+		fmt.Printf("   (none)\n")
+	}
+	for _, ref := range referencedBy {
+		fmt.Printf("   ├─ %s (%s)\n", resourceID(ref.target), ref.Field)
+	}
+	fmt.Printf("\n")
+}
+
+// describeTarget describes what a reference resolves to
+func (c *DescribeCommand) describeTarget(ref describedReference) string {
+	if ref.target != nil {
+		return fmt.Sprintf("%s (%s)", resourceID(ref.target), c.scan.getRelativePath(ref.target.FilePath))
+	}
+	if strings.HasPrefix(ref.Name, "arn:") {
+		return fmt.Sprintf("%s (external)", ref.Name)
+	}
+	return fmt.Sprintf("%s (not found)", GraphNode{Kind: ref.Kind, Name: ref.Name}.ID())
+}
+
+// resourceID returns the Kind/name label of a resource
+func resourceID(resource *parser.ParsedResource) string {
+	return GraphNode{Kind: resource.Kind, Name: resource.Metadata.Name}.ID()
+}
@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/registry"
+	"bedrock-forge/pkg/config"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+type DiffCommand struct {
+	logger       *logrus.Logger
+	exitCode     bool
+	templateVars map[string]interface{}
+	overlayDir   string
+	strictFields bool
+
+	// selector and projectOverrides are passed to generate, so the diff matches its output
+	selector         string
+	projectOverrides config.ProjectConfig
+}
+
+// FileDiff describes the differences for a single generated file
+type FileDiff struct {
+	Path      string
+	Additions int
+	Removals  int
+	Unified   string
+}
+
+func NewDiffCommand(logger *logrus.Logger) *DiffCommand {
+	return &DiffCommand{
+		logger: logger,
+	}
+}
+
+// SetExitCode makes Execute return an error when differences are found
+func (c *DiffCommand) SetExitCode(exitCode bool) {
+	c.exitCode = exitCode
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *DiffCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.templateVars = vars
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *DiffCommand) SetOverlayDir(dir string) {
+	c.overlayDir = dir
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *DiffCommand) SetStrictFields(strict bool) {
+	c.strictFields = strict
+}
+
+// SetSelector limits generation to resources matching a label selector, plus their dependencies
+func (c *DiffCommand) SetSelector(selector string) error {
+	if selector != "" {
+		if _, err := registry.ParseLabelSelector(selector); err != nil {
+			return err
+		}
+	}
+	c.selector = selector
+	return nil
+}
+
+// SetProjectOverrides sets values that override the project config file, e.g. from CLI flags
+func (c *DiffCommand) SetProjectOverrides(overrides config.ProjectConfig) {
+	c.projectOverrides = overrides
+}
+
+func (c *DiffCommand) Execute(scanPath, outputDir string) error {
+	// Use './outputs_tf' as default output directory
+	if outputDir == "" {
+		outputDir = "outputs_tf"
+	}
+
+	tempDir, err := os.MkdirTemp("", "bedrock-forge-diff-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Generate into a temporary directory so the existing output is left untouched
+	generateCommand := NewGenerateCommand(c.logger)
+	generateCommand.SetTemplateVars(c.templateVars)
+	generateCommand.SetOverlayDir(c.overlayDir)
+	generateCommand.SetStrictFields(c.strictFields)
+	generateCommand.SetProjectOverrides(c.projectOverrides)
+	generateCommand.SetDryRun(true)
+	if err := generateCommand.SetSelector(c.selector); err != nil {
+		return err
+	}
+	if err := generateCommand.Execute(scanPath, tempDir); err != nil {
+		return fmt.Errorf("failed to generate Terraform configuration: %w", err)
+	}
+
+	diffs, err := c.diffDirectories(outputDir, tempDir)
+	if err != nil {
+		return fmt.Errorf("failed to compare generated output: %w", err)
+	}
+
+	c.printDiffs(diffs, outputDir)
+
+	if len(diffs) > 0 && c.exitCode {
+		return fmt.Errorf("generated output differs from %s in %d files", outputDir, len(diffs))
+	}
+
+	return nil
+}
+
+// diffDirectories compares the existing output directory with freshly generated output
+func (c *DiffCommand) diffDirectories(existingDir, generatedDir string) ([]FileDiff, error) {
+	generatedFiles, err := c.listGeneratedFiles(generatedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFiles, err := c.listGeneratedFiles(existingDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for _, path := range generatedFiles {
+		paths[path] = true
+	}
+	for _, path := range existingFiles {
+		paths[path] = true
+	}
+
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+
+	var diffs []FileDiff
+	for _, path := range sortedPaths {
+		oldContent, err := readFileIfExists(filepath.Join(existingDir, path))
+		if err != nil {
+			return nil, err
+		}
+		newContent, err := readFileIfExists(filepath.Join(generatedDir, path))
+		if err != nil {
+			return nil, err
+		}
+
+		if oldContent == newContent {
+			continue
+		}
+
+		diffs = append(diffs, unifiedDiff(path, oldContent, newContent))
+	}
+
+	return diffs, nil
+}
+
+// listGeneratedFiles returns the Terraform files in a directory, relative to it
+func (c *DiffCommand) listGeneratedFiles(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip Terraform working directories
+		if info.IsDir() {
+			if info.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".tf") && !strings.HasSuffix(path, ".tf.json") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, relPath)
+		return nil
+	})
+
+	return files, err
+}
+
+func (c *DiffCommand) printDiffs(diffs []FileDiff, outputDir string) {
+	if len(diffs) == 0 {
+		fmt.Printf("✅ Generated output is up to date with %s\n", outputDir)
+		return
+	}
+
+	for _, diff := range diffs {
+		fmt.Print(diff.Unified)
+	}
+
+	fmt.Printf("\n=== Diff Summary (%s) ===\n", outputDir)
+	for _, diff := range diffs {
+		fmt.Printf("   %s: +%d -%d\n", diff.Path, diff.Additions, diff.Removals)
+	}
+	fmt.Printf("\n%d files changed\n", len(diffs))
+}
+
+// readFileIfExists reads a file, returning empty content if it doesn't exist
+func readFileIfExists(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(content), nil
+}
+
+// diffOp is a single line-level edit operation
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	line string
+}
+
+// unifiedDiff produces a unified diff between two file contents
+func unifiedDiff(path, oldContent, newContent string) FileDiff {
+	oldLines := splitLines(oldContent)
+	newLines := splitLines(newContent)
+	ops := diffLines(oldLines, newLines)
+
+	result := FileDiff{Path: path}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- a/%s\n+++ b/%s\n", path, path)
+
+	// Group operations into hunks with surrounding context
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		hunkStart := max(start-diffContextLines, 0)
+		hunkEnd := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				hunkEnd = i
+			} else if i-hunkEnd > 2*diffContextLines {
+				break
+			}
+		}
+		hunkEnd = min(hunkEnd+diffContextLines+1, len(ops))
+
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+
+		oldCount, newCount := 0, 0
+		var hunk strings.Builder
+		for _, op := range ops[hunkStart:hunkEnd] {
+			switch op.kind {
+			case '-':
+				oldCount++
+				result.Removals++
+			case '+':
+				newCount++
+				result.Additions++
+			default:
+				oldCount++
+				newCount++
+			}
+			fmt.Fprintf(&hunk, "%c%s\n", op.kind, op.line)
+		}
+
+		fmt.Fprintf(&builder, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		builder.WriteString(hunk.String())
+
+		start = hunkEnd
+	}
+
+	result.Unified = builder.String()
+	return result
+}
+
+// splitLines splits content into lines without trailing newlines
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines computes a line-level edit script using the longest common subsequence
+func diffLines(oldLines, newLines []string) []diffOp {
+	// lcs[i][j] holds the LCS length of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{' ', oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', oldLines[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', newLines[j]})
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		ops = append(ops, diffOp{'-', oldLines[i]})
+	}
+	for ; j < len(newLines); j++ {
+		ops = append(ops, diffOp{'+', newLines[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bedrock-forge/pkg/config"
+)
+
+func TestDiffUsesGenerateOptions(t *testing.T) {
+	dir := writeResources(t, `kind: Lambda
+metadata:
+  name: order-lookup
+  labels:
+    team: orders
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+---
+kind: Lambda
+metadata:
+  name: billing-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`)
+	outputDir := filepath.Join(t.TempDir(), "out")
+	overrides := config.ProjectConfig{OutputLayout: "per-kind", S3Bucket: "team-artifacts"}
+
+	generate := NewGenerateCommand(discardLogger())
+	generate.SetDryRun(true)
+	generate.SetProjectOverrides(overrides)
+	if err := generate.SetSelector("team=orders"); err != nil {
+		t.Fatal(err)
+	}
+	if err := generate.Execute(dir, outputDir); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	diff := NewDiffCommand(discardLogger())
+	diff.SetExitCode(true)
+	diff.SetProjectOverrides(overrides)
+	if err := diff.SetSelector("team=orders"); err != nil {
+		t.Fatal(err)
+	}
+	var diffErr error
+	captureOutput(t, func() {
+		diffErr = diff.Execute(dir, outputDir)
+	})
+	if diffErr != nil {
+		t.Errorf("Execute() with the generate options = %v, want no differences", diffErr)
+	}
+
+	// Without the options the layout and the selected resources differ
+	diff = NewDiffCommand(discardLogger())
+	diff.SetExitCode(true)
+	stdout, _ := captureOutput(t, func() {
+		diffErr = diff.Execute(dir, outputDir)
+	})
+	if diffErr == nil {
+		t.Fatalf("Execute() without the generate options found no differences")
+	}
+	for _, want := range []string{"main.tf", "lambdas.tf", "billing-lookup"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("diff doesn't mention %s:\n%s", want, stdout)
+		}
+	}
+}
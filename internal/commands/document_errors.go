@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/parser"
+)
+
+// isDocumentError reports whether a parse error only concerns invalid resource documents, in which
+// case the other resources of the file were still parsed
+func isDocumentError(err error) bool {
+	var documentErr *parser.DocumentError
+	return errors.As(err, &documentErr)
+}
+
+// reportDocumentErrors returns the error of a single invalid resource document, or logs every one and
+// returns an error counting them. The command must fail rather than generate without those resources.
+func reportDocumentErrors(logger *logrus.Logger, errs []error) error {
+	var documentErrs []error
+	for _, err := range errs {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			documentErrs = append(documentErrs, joined.Unwrap()...)
+		} else {
+			documentErrs = append(documentErrs, err)
+		}
+	}
+	switch len(documentErrs) {
+	case 0:
+		return nil
+	case 1:
+		return documentErrs[0]
+	}
+
+	for _, err := range documentErrs {
+		logger.WithError(err).Error("Invalid resource document")
+	}
+	return fmt.Errorf("found %d invalid resource documents", len(documentErrs))
+}
@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/registry"
+)
+
+// EstimateCommand prints a heuristic monthly cost estimate of the scanned resources, from their
+// configuration, assumed usage and per-unit rates
+type EstimateCommand struct {
+	logger    *logrus.Logger
+	scan      *ScanCommand
+	rates     *CostRates
+	ratesFile string
+}
+
+// costLineItem is the estimated monthly cost of one resource and how it was derived
+type costLineItem struct {
+	resource string
+	monthly  float64
+	basis    string
+}
+
+func NewEstimateCommand(logger *logrus.Logger) *EstimateCommand {
+	return &EstimateCommand{
+		logger: logger,
+		scan:   NewScanCommand(logger),
+		rates:  DefaultCostRates(),
+	}
+}
+
+// SetRatesFile loads the rates file whose values replace the built-in rates
+func (c *EstimateCommand) SetRatesFile(path string) error {
+	rates, err := LoadCostRates(path)
+	if err != nil {
+		return err
+	}
+	c.rates = rates
+	c.ratesFile = path
+	return nil
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *EstimateCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.scan.SetTemplateVars(vars)
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *EstimateCommand) SetOverlayDir(dir string) {
+	c.scan.SetOverlayDir(dir)
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *EstimateCommand) SetStrictFields(strict bool) {
+	c.scan.SetStrictFields(strict)
+}
+
+func (c *EstimateCommand) Execute(rootPath string) error {
+	if rootPath == "" {
+		var err error
+		rootPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+
+	if err := c.scan.loadResources(rootPath); err != nil {
+		return fmt.Errorf("failed to scan resources: %w", err)
+	}
+
+	c.printEstimate(c.lineItems(c.scan.GetRegistry()))
+	return nil
+}
+
+// lineItems estimates every resource with a cost model, grouped by kind and sorted by name
+func (c *EstimateCommand) lineItems(resourceRegistry *registry.ResourceRegistry) []costLineItem {
+	var items []costLineItem
+
+	estimators := []struct {
+		kind     models.ResourceKind
+		estimate func(resource interface{}) (costLineItem, bool)
+	}{
+		{models.AgentKind, c.estimateAgent},
+		{models.LambdaKind, c.estimateLambda},
+		{models.KnowledgeBaseKind, c.estimateKnowledgeBase},
+		{models.OpenSearchServerlessKind, c.estimateCollection},
+	}
+
+	for _, estimator := range estimators {
+		names := resourceRegistry.ListResourceNames(estimator.kind)
+		sort.Strings(names)
+		for _, name := range names {
+			resource, _ := resourceRegistry.GetResource(estimator.kind, name)
+			if item, ok := estimator.estimate(resource.Resource); ok {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+// estimateAgent prices the assumed invocations at the on-demand token rates of the agent's model
+func (c *EstimateCommand) estimateAgent(resource interface{}) (costLineItem, bool) {
+	agent, ok := resource.(*models.Agent)
+	if !ok {
+		return costLineItem{}, false
+	}
+	item := costLineItem{resource: fmt.Sprintf("Agent/%s", agent.Metadata.Name)}
+
+	if agent.Spec.ProvisionedThroughput != "" {
+		item.basis = "provisioned throughput is billed by its commitment, not estimated"
+		return item, true
+	}
+
+	model := agent.Spec.EffectiveFoundationModel()
+	rates, known := c.rates.modelRates(model)
+	usage := c.rates.Usage
+
+	input := usage.AgentInvocationsPerMonth * usage.InputTokensPerInvocation / 1000 * rates.InputPer1K
+	output := usage.AgentInvocationsPerMonth * usage.OutputTokensPerInvocation / 1000 * rates.OutputPer1K
+	item.monthly = input + output
+	item.basis = fmt.Sprintf("%s invocations x %s input + %s output tokens of %s",
+		formatQuantity(usage.AgentInvocationsPerMonth), formatQuantity(usage.InputTokensPerInvocation), formatQuantity(usage.OutputTokensPerInvocation), model)
+	if !known {
+		item.basis += " (model not in rates, default model rates used)"
+	}
+	return item, true
+}
+
+// estimateLambda prices the assumed invocations at a share of the timeout, plus any provisioned concurrency
+func (c *EstimateCommand) estimateLambda(resource interface{}) (costLineItem, bool) {
+	lambda, ok := resource.(*models.Lambda)
+	if !ok {
+		return costLineItem{}, false
+	}
+	spec := lambda.Spec
+	usage := c.rates.Usage
+
+	// Lambda defaults
+	memoryMB := float64(spec.MemorySize)
+	if memoryMB == 0 {
+		memoryMB = 128
+	}
+	timeout := float64(spec.Timeout)
+	if timeout == 0 {
+		timeout = 3
+	}
+
+	gbSecond := c.rates.Lambda.GBSecond
+	for _, architecture := range spec.Architectures {
+		if architecture == "arm64" {
+			gbSecond = c.rates.Lambda.GBSecondArm64
+		}
+	}
+
+	memoryGB := memoryMB / 1024
+	duration := timeout * usage.LambdaDurationFraction
+	requests := usage.LambdaInvocationsPerMonth / 1000000 * c.rates.Lambda.RequestsPerMillion
+	compute := usage.LambdaInvocationsPerMonth * duration * memoryGB * gbSecond
+
+	item := costLineItem{
+		resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+		monthly:  requests + compute,
+		basis:    fmt.Sprintf("%s invocations x %s s x %s MB", formatQuantity(usage.LambdaInvocationsPerMonth), formatQuantity(duration), formatQuantity(memoryMB)),
+	}
+
+	if provisioned := spec.ProvisionedConcurrency; provisioned != nil {
+		item.monthly += float64(provisioned.Executions) * memoryGB * c.rates.HoursPerMonth * 3600 * c.rates.Lambda.ProvisionedGBSecond
+		item.basis += fmt.Sprintf(", %d provisioned executions", provisioned.Executions)
+		if provisioned.Autoscaling != nil {
+			item.basis += " before autoscaling"
+		}
+	}
+
+	return item, true
+}
+
+// estimateKnowledgeBase prices embedding the assumed ingested tokens. Vector storage is billed by the
+// store, which is only estimated for collections defined in the project.
+func (c *EstimateCommand) estimateKnowledgeBase(resource interface{}) (costLineItem, bool) {
+	kb, ok := resource.(*models.KnowledgeBase)
+	if !ok {
+		return costLineItem{}, false
+	}
+	usage := c.rates.Usage
+
+	item := costLineItem{
+		resource: fmt.Sprintf("KnowledgeBase/%s", kb.Metadata.Name),
+		monthly:  usage.IngestedTokensPerMonth / 1000 * c.rates.KnowledgeBase.EmbeddingPer1K,
+		basis:    fmt.Sprintf("%s ingested tokens embedded", formatQuantity(usage.IngestedTokensPerMonth)),
+	}
+
+	if storage := kb.Spec.StorageConfiguration; storage != nil {
+		if reference := storage.OpenSearchServerless; reference != nil && reference.CollectionName != nil && !reference.CollectionName.IsEmpty() {
+			item.basis += fmt.Sprintf("; storage in OpenSearchServerless/%s", reference.CollectionName.String())
+		} else {
+			item.basis += fmt.Sprintf("; %s storage outside the project, not estimated", storage.Type)
+		}
+	}
+
+	return item, true
+}
+
+// estimateCollection prices the minimum OCUs a collection is billed for, plus the assumed storage
+func (c *EstimateCommand) estimateCollection(resource interface{}) (costLineItem, bool) {
+	collection, ok := resource.(*models.OpenSearchServerless)
+	if !ok {
+		return costLineItem{}, false
+	}
+	rates := c.rates.OpenSearch
+	storageGB := c.rates.Usage.OpenSearchStorageGBPerMonth
+
+	return costLineItem{
+		resource: fmt.Sprintf("OpenSearchServerless/%s", collection.Metadata.Name),
+		monthly:  rates.MinimumOCUs*rates.OCUHour*c.rates.HoursPerMonth + storageGB*rates.StorageGBMonth,
+		basis:    fmt.Sprintf("%s OCUs x %s h, %s GB stored", formatQuantity(rates.MinimumOCUs), formatQuantity(c.rates.HoursPerMonth), formatQuantity(storageGB)),
+	}, true
+}
+
+func (c *EstimateCommand) printEstimate(items []costLineItem) {
+	fmt.Printf("\n=== Bedrock Forge Cost Estimate (approximation) ===\n\n")
+
+	if len(items) == 0 {
+		fmt.Printf("No resources with a cost model found.\n")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "RESOURCE\tMONTHLY (USD)\tBASIS\n")
+
+	var total float64
+	for _, item := range items {
+		total += item.monthly
+		fmt.Fprintf(writer, "%s\t$%.2f\t%s\n", item.resource, item.monthly, item.basis)
+	}
+	fmt.Fprintf(writer, "TOTAL\t$%.2f\t\n", total)
+	writer.Flush()
+
+	rates := "built-in rates"
+	if c.ratesFile != "" {
+		rates = fmt.Sprintf("rates from %s", c.ratesFile)
+	}
+	fmt.Printf("\n⚠️  Rough approximation from assumed usage and %s, not a quote. Data transfer, logs, S3, guardrails, free tiers and taxes are not included.\n\n", rates)
+}
+
+// formatQuantity formats a number without trailing zeros, with thousands separators for large values
+func formatQuantity(value float64) string {
+	if value >= 1000 && value == float64(int64(value)) {
+		digits := fmt.Sprintf("%d", int64(value))
+		var groups []string
+		for len(digits) > 3 {
+			groups = append([]string{digits[len(digits)-3:]}, groups...)
+			digits = digits[:len(digits)-3]
+		}
+		return strings.Join(append([]string{digits}, groups...), ",")
+	}
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", value), "0"), ".")
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CostRates are the per-unit prices and usage assumptions the estimate command multiplies resource
+// configuration with. Prices are in USD; the defaults are us-east-1 on-demand list prices.
+type CostRates struct {
+	HoursPerMonth float64               `yaml:"hoursPerMonth,omitempty"`
+	Usage         CostUsage             `yaml:"usage,omitempty"`
+	Lambda        LambdaRates           `yaml:"lambda,omitempty"`
+	Models        map[string]ModelRates `yaml:"models,omitempty"` // Model ID prefix -> rates, the longest matching prefix wins
+	DefaultModel  ModelRates            `yaml:"defaultModel,omitempty"`
+	KnowledgeBase KnowledgeBaseRates    `yaml:"knowledgeBase,omitempty"`
+	OpenSearch    OpenSearchRates       `yaml:"openSearch,omitempty"`
+}
+
+// CostUsage is the traffic assumed for every resource of a kind
+type CostUsage struct {
+	LambdaInvocationsPerMonth   float64 `yaml:"lambdaInvocationsPerMonth,omitempty"`
+	LambdaDurationFraction      float64 `yaml:"lambdaDurationFraction,omitempty"` // Average duration as a share of the timeout
+	AgentInvocationsPerMonth    float64 `yaml:"agentInvocationsPerMonth,omitempty"`
+	InputTokensPerInvocation    float64 `yaml:"inputTokensPerInvocation,omitempty"`
+	OutputTokensPerInvocation   float64 `yaml:"outputTokensPerInvocation,omitempty"`
+	IngestedTokensPerMonth      float64 `yaml:"ingestedTokensPerMonth,omitempty"` // Per knowledge base
+	OpenSearchStorageGBPerMonth float64 `yaml:"openSearchStorageGbPerMonth,omitempty"`
+}
+
+// LambdaRates are Lambda request and compute prices
+type LambdaRates struct {
+	RequestsPerMillion  float64 `yaml:"requestsPerMillion,omitempty"`
+	GBSecond            float64 `yaml:"gbSecond,omitempty"`
+	GBSecondArm64       float64 `yaml:"gbSecondArm64,omitempty"`
+	ProvisionedGBSecond float64 `yaml:"provisionedGbSecond,omitempty"` // Provisioned concurrency, per GB-second configured
+}
+
+// ModelRates are on-demand prices per 1,000 tokens
+type ModelRates struct {
+	InputPer1K  float64 `yaml:"inputPer1k"`
+	OutputPer1K float64 `yaml:"outputPer1k"`
+}
+
+// KnowledgeBaseRates are the prices of embedding documents during ingestion
+type KnowledgeBaseRates struct {
+	EmbeddingPer1K float64 `yaml:"embeddingPer1k,omitempty"`
+}
+
+// OpenSearchRates are OpenSearch Serverless compute and storage prices
+type OpenSearchRates struct {
+	OCUHour        float64 `yaml:"ocuHour,omitempty"`
+	MinimumOCUs    float64 `yaml:"minimumOcus,omitempty"` // Indexing and search OCUs billed for an idle collection
+	StorageGBMonth float64 `yaml:"storageGbMonth,omitempty"`
+}
+
+// DefaultCostRates returns the built-in rates
+func DefaultCostRates() *CostRates {
+	return &CostRates{
+		HoursPerMonth: 730,
+		Usage: CostUsage{
+			LambdaInvocationsPerMonth:   1000000,
+			LambdaDurationFraction:      0.1,
+			AgentInvocationsPerMonth:    100000,
+			InputTokensPerInvocation:    2000,
+			OutputTokensPerInvocation:   500,
+			IngestedTokensPerMonth:      10000000,
+			OpenSearchStorageGBPerMonth: 10,
+		},
+		Lambda: LambdaRates{
+			RequestsPerMillion:  0.20,
+			GBSecond:            0.0000166667,
+			GBSecondArm64:       0.0000133334,
+			ProvisionedGBSecond: 0.0000041667,
+		},
+		Models: map[string]ModelRates{
+			"anthropic.claude-3-5-sonnet": {InputPer1K: 0.003, OutputPer1K: 0.015},
+			"anthropic.claude-3-5-haiku":  {InputPer1K: 0.0008, OutputPer1K: 0.004},
+			"anthropic.claude-3-sonnet":   {InputPer1K: 0.003, OutputPer1K: 0.015},
+			"anthropic.claude-3-haiku":    {InputPer1K: 0.00025, OutputPer1K: 0.00125},
+			"anthropic.claude-3-opus":     {InputPer1K: 0.015, OutputPer1K: 0.075},
+			"anthropic.claude-v2":         {InputPer1K: 0.008, OutputPer1K: 0.024},
+			"anthropic.claude-instant":    {InputPer1K: 0.0008, OutputPer1K: 0.0024},
+			"amazon.nova-pro":             {InputPer1K: 0.0008, OutputPer1K: 0.0032},
+			"amazon.nova-lite":            {InputPer1K: 0.00006, OutputPer1K: 0.00024},
+			"amazon.nova-micro":           {InputPer1K: 0.000035, OutputPer1K: 0.00014},
+			"amazon.titan-text-premier":   {InputPer1K: 0.0005, OutputPer1K: 0.0015},
+			"meta.llama3-1-70b":           {InputPer1K: 0.00072, OutputPer1K: 0.00072},
+			"meta.llama3-1-8b":            {InputPer1K: 0.00022, OutputPer1K: 0.00022},
+		},
+		DefaultModel:  ModelRates{InputPer1K: 0.003, OutputPer1K: 0.015},
+		KnowledgeBase: KnowledgeBaseRates{EmbeddingPer1K: 0.0001},
+		OpenSearch: OpenSearchRates{
+			OCUHour:        0.24,
+			MinimumOCUs:    2,
+			StorageGBMonth: 0.024,
+		},
+	}
+}
+
+// LoadCostRates reads a rates file over the built-in rates, so it only needs the values it changes.
+// Models it lists are added to the built-in ones.
+func LoadCostRates(path string) (*CostRates, error) {
+	rates := DefaultCostRates()
+	if path == "" {
+		return rates, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(content, rates); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %s: %w", path, err)
+	}
+	return rates, nil
+}
+
+// modelRates returns the rates of the longest model prefix matching modelID, and whether one matched.
+// ARNs and cross-region inference profile IDs are reduced to the model ID first.
+func (r *CostRates) modelRates(modelID string) (ModelRates, bool) {
+	modelID = modelID[strings.LastIndex(modelID, "/")+1:]
+	for _, region := range []string{"us.", "eu.", "apac.", "us-gov."} {
+		modelID = strings.TrimPrefix(modelID, region)
+	}
+
+	var best string
+	for prefix := range r.Models {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return r.DefaultModel, false
+	}
+	return r.Models[best], true
+}
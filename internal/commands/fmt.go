@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/parser"
+)
+
+type FmtCommand struct {
+	logger     *logrus.Logger
+	scanner    *parser.Scanner
+	yamlParser *parser.YAMLParser
+	check      bool
+}
+
+func NewFmtCommand(logger *logrus.Logger) *FmtCommand {
+	return &FmtCommand{
+		logger:     logger,
+		scanner:    parser.NewScanner(logger),
+		yamlParser: parser.NewYAMLParser(logger),
+	}
+}
+
+// SetCheck reports unformatted files instead of rewriting them
+func (c *FmtCommand) SetCheck(check bool) {
+	c.check = check
+}
+
+func (c *FmtCommand) Execute(rootPath string) error {
+	if rootPath == "" {
+		var err error
+		rootPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+
+	scanResult, err := c.scanner.ScanDirectory(rootPath, nil, defaultExcludePatterns)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	var unformatted []string
+	for _, filePath := range scanResult.Files {
+		changed, err := c.formatFile(filePath)
+		if err != nil {
+			c.logger.WithError(err).WithField("file", filePath).Warn("Failed to format file")
+			continue
+		}
+		if changed {
+			unformatted = append(unformatted, filePath)
+			fmt.Println(filePath)
+		}
+	}
+
+	if c.check && len(unformatted) > 0 {
+		return fmt.Errorf("%d files are not formatted, run 'bedrock-forge fmt' to fix them", len(unformatted))
+	}
+
+	return nil
+}
+
+// formatFile formats a file holding Bedrock resources and reports whether it changed
+func (c *FmtCommand) formatFile(filePath string) (bool, error) {
+	// Leave YAML files that hold no resources (workflows, configs) untouched
+	resources, err := c.yamlParser.ParseFile(filePath)
+	if err != nil && !isDocumentError(err) {
+		return false, err
+	}
+	// Invalid resource documents are still resources to format
+	if len(resources) == 0 && err == nil {
+		return false, nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	formatted, err := parser.FormatYAML(content)
+	if err != nil {
+		return false, fmt.Errorf("failed to format file %s: %w", filePath, err)
+	}
+
+	if bytes.Equal(content, formatted) {
+		return false, nil
+	}
+	if c.check {
+		return true, nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat file %s: %w", filePath, err)
+	}
+	if err := os.WriteFile(filePath, formatted, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return true, nil
+}
@@ -12,10 +12,39 @@ import (
 	"bedrock-forge/internal/packager"
 	"bedrock-forge/internal/parser"
 	"bedrock-forge/internal/registry"
+	"bedrock-forge/pkg/config"
 )
 
+// placeholderS3Bucket is the artifact bucket referenced by generated Terraform when nothing is uploaded
+// and no bucket is configured
+const placeholderS3Bucket = "bedrock-artifacts"
+
 type GenerateCommand struct {
-	logger *logrus.Logger
+	logger   *logrus.Logger
+	upload   bool
+	dryRun   bool
+	stdout   bool
+	watch    bool
+	s3Config packager.AWSS3Config
+	selector *registry.LabelSelector
+
+	// incremental reuses unchanged work recorded in the manifest of the previous run
+	incremental bool
+
+	// packageConcurrency is the number of Lambdas packaged at once, 0 for the number of CPUs
+	packageConcurrency int
+
+	// templateVars are rendered into ${{ }} template actions in YAML files
+	templateVars map[string]interface{}
+
+	// overlayDir holds environment overlays merged over the scanned resources
+	overlayDir string
+
+	// strictFields rejects fields the resource types don't define
+	strictFields bool
+
+	// projectOverrides take precedence over values from bedrock-forge.yaml
+	projectOverrides config.ProjectConfig
 }
 
 func NewGenerateCommand(logger *logrus.Logger) *GenerateCommand {
@@ -24,8 +53,89 @@ func NewGenerateCommand(logger *logrus.Logger) *GenerateCommand {
 	}
 }
 
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *GenerateCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.templateVars = vars
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *GenerateCommand) SetOverlayDir(dir string) {
+	c.overlayDir = dir
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *GenerateCommand) SetStrictFields(strict bool) {
+	c.strictFields = strict
+}
+
+// SetUpload enables uploading packaged artifacts to S3 instead of a dry run
+func (c *GenerateCommand) SetUpload(upload bool, s3Config packager.AWSS3Config) {
+	c.upload = upload
+	c.s3Config = s3Config
+}
+
+// SetProjectOverrides sets values that override the project config file, e.g. from CLI flags
+func (c *GenerateCommand) SetProjectOverrides(overrides config.ProjectConfig) {
+	c.projectOverrides = overrides
+}
+
+// SetSelector limits generation to resources matching a label selector, plus their dependencies
+func (c *GenerateCommand) SetSelector(selector string) error {
+	if selector == "" {
+		c.selector = nil
+		return nil
+	}
+
+	parsed, err := registry.ParseLabelSelector(selector)
+	if err != nil {
+		return err
+	}
+	c.selector = parsed
+	return nil
+}
+
+// SetDryRun skips artifact packaging and uploads, using placeholder S3 keys instead
+func (c *GenerateCommand) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// SetStdout writes the generated main.tf to stdout instead of the output directory
+func (c *GenerateCommand) SetStdout(stdout bool) {
+	c.stdout = stdout
+}
+
+// SetWatch keeps regenerating whenever YAML files under the scan path change
+func (c *GenerateCommand) SetWatch(watch bool) {
+	c.watch = watch
+}
+
+// SetIncremental skips packaging unchanged Lambdas and schemas, and the whole run when nothing changed,
+// based on a manifest of input hashes kept in the output directory
+func (c *GenerateCommand) SetIncremental(incremental bool) {
+	c.incremental = incremental
+}
+
+// SetPackageConcurrency sets the number of Lambdas packaged at once, 0 for the number of CPUs
+func (c *GenerateCommand) SetPackageConcurrency(concurrency int) {
+	c.packageConcurrency = concurrency
+}
+
 func (c *GenerateCommand) Execute(scanPath, outputDir string) error {
-	c.logger.Info("Starting Terraform generation...")
+	if c.dryRun && c.upload {
+		return fmt.Errorf("--dry-run and --upload cannot be used together")
+	}
+	if c.stdout && c.upload {
+		return fmt.Errorf("--stdout and --upload cannot be used together")
+	}
+	if c.stdout && c.incremental {
+		return fmt.Errorf("--stdout and --incremental cannot be used together")
+	}
+	if scanPath == StdinPath && c.watch {
+		return fmt.Errorf("--watch cannot be used when reading resources from stdin")
+	}
+	if scanPath == StdinPath && c.incremental {
+		return fmt.Errorf("--incremental cannot be used when reading resources from stdin")
+	}
 
 	// Use current directory if scanPath is empty
 	if scanPath == "" {
@@ -41,15 +151,50 @@ func (c *GenerateCommand) Execute(scanPath, outputDir string) error {
 		outputDir = "outputs_tf"
 	}
 
+	if c.watch {
+		return c.watchAndGenerate(scanPath, outputDir)
+	}
+	return c.generate(scanPath, outputDir)
+}
+
+// generate runs a single scan, validation and generation pass
+func (c *GenerateCommand) generate(scanPath, outputDir string) error {
+	c.logger.Info("Starting Terraform generation...")
+
+	sourceDir, err := configDir(scanPath)
+	if err != nil {
+		return err
+	}
+	projectConfig, err := c.loadProjectConfig(sourceDir)
+	if err != nil {
+		return err
+	}
+	if c.upload && projectConfig.S3Bucket == "" {
+		return fmt.Errorf("--upload requires an artifact bucket, set --s3-bucket or s3Bucket in %s", config.ProjectConfigFileName)
+	}
+
 	// Initialize registry and parser
 	resourceRegistry := registry.NewResourceRegistry(c.logger)
 	yamlParser := parser.NewYAMLParser(c.logger)
+	yamlParser.SetTemplateVars(c.templateVars)
+	yamlParser.SetStrictFields(c.strictFields)
 
 	// Scan and parse YAML files
 	if err := c.scanAndParseFiles(scanPath, resourceRegistry, yamlParser); err != nil {
 		return fmt.Errorf("failed to scan and parse files: %w", err)
 	}
 
+	if c.overlayDir != "" {
+		if err := applyOverlays(c.logger, c.overlayDir, resourceRegistry, yamlParser); err != nil {
+			return err
+		}
+	}
+
+	if c.selector != nil {
+		kept := resourceRegistry.FilterBySelector(c.selector)
+		c.logger.WithField("resources", kept).Info("Filtered resources by selector")
+	}
+
 	// Validate dependencies
 	if errors := resourceRegistry.ValidateDependencies(); len(errors) > 0 {
 		c.logger.Error("Dependency validation failed:")
@@ -59,20 +204,86 @@ func (c *GenerateCommand) Execute(scanPath, outputDir string) error {
 		return fmt.Errorf("found %d dependency validation errors", len(errors))
 	}
 
+	if scanPath == StdinPath {
+		if err := checkStdinSources(resourceRegistry); err != nil {
+			return err
+		}
+	}
+
+	// Compare against the previous run to skip unchanged work
+	var manifest, previous *generationManifest
+	var reusedLambdas map[string]*packager.LambdaPackage
+	var reusedSchemas map[string]*packager.SchemaPackage
+	if c.incremental {
+		previous, err = loadGenerationManifest(outputDir)
+		if err != nil {
+			c.logger.WithError(err).Warn("Ignoring unusable generation manifest, generating everything")
+		}
+
+		manifest, err = c.buildGenerationManifest(outputDir, projectConfig, resourceRegistry, previous)
+		if err != nil {
+			return err
+		}
+
+		if previous != nil && previous.Settings == manifest.Settings {
+			changed := changedResources(previous, manifest, resourceRegistry)
+			switch {
+			case len(changed) > 0:
+				c.logger.WithField("changed", len(changed)).Info("Regenerating after changes since the last generation")
+				c.logger.WithField("resources", changed).Debug("Changed resources, including the ones referencing them")
+			case outputsUnchanged(previous, outputDir):
+				c.logger.WithField("output_dir", outputDir).Info("No changes since the last generation, output is up to date")
+				return nil
+			default:
+				c.logger.Info("Generated files were modified or removed since the last generation, regenerating")
+			}
+			reusedLambdas, reusedSchemas = previous.reusablePackages(changed)
+		} else {
+			c.logger.Info("No matching generation manifest, generating everything")
+		}
+	}
+
 	// Package Lambdas and extract schemas
-	lambdaPackages, schemaPackages, err := c.packageArtifacts(scanPath, resourceRegistry)
-	if err != nil {
-		return fmt.Errorf("failed to package artifacts: %w", err)
+	var lambdaPackages map[string]*packager.LambdaPackage
+	var schemaPackages map[string]*packager.SchemaPackage
+	switch {
+	case scanPath == StdinPath:
+		// Nothing read from stdin has code or schema files to package
+		c.logger.Info("Resources were read from stdin, skipping artifact packaging")
+		lambdaPackages = make(map[string]*packager.LambdaPackage)
+		schemaPackages = make(map[string]*packager.SchemaPackage)
+	case c.dryRun:
+		lambdaPackages, schemaPackages = c.placeholderArtifacts(scanPath, projectConfig, resourceRegistry)
+	default:
+		var err error
+		lambdaPackages, schemaPackages, err = c.packageArtifacts(scanPath, projectConfig, resourceRegistry, reusedLambdas, reusedSchemas)
+		if err != nil {
+			return fmt.Errorf("failed to package artifacts: %w", err)
+		}
 	}
 
 	// Generate Terraform configuration
 	generatorConfig := &generator.GeneratorConfig{
-		ModuleRegistry: "git::https://github.com/company/bedrock-terraform-modules",
-		ModuleVersion:  "v1.0.0",
-		OutputDir:      outputDir,
-		SourceDir:      scanPath,
-		ProjectName:    "bedrock-project",
-		Environment:    "dev",
+		ModuleRegistry:         projectConfig.ModuleRegistry,
+		ModuleVersion:          projectConfig.ModuleVersion,
+		OutputDir:              outputDir,
+		SourceDir:              sourceDir,
+		ProjectName:            projectConfig.ProjectName,
+		Environment:            projectConfig.Environment,
+		DefaultLambdaKmsKeyArn: projectConfig.LambdaKmsKeyArn,
+		GenerationMode:         projectConfig.GenerationMode,
+		GlobalTags:             projectConfig.GlobalTags,
+		OutputLayout:           projectConfig.OutputLayout,
+		OutputSyntax:           projectConfig.OutputSyntax,
+		Account:                projectConfig.Account,
+		Region:                 projectConfig.Region,
+		Providers:              projectConfig.Providers,
+		Backend:                projectConfig.Backend,
+		GuardrailImportAddress: projectConfig.GuardrailImportAddress,
+	}
+
+	if c.stdout {
+		generatorConfig.Output = os.Stdout
 	}
 
 	hclGenerator := generator.NewHCLGenerator(c.logger, resourceRegistry, generatorConfig)
@@ -86,6 +297,16 @@ func (c *GenerateCommand) Execute(scanPath, outputDir string) error {
 		return fmt.Errorf("failed to generate HCL: %w", err)
 	}
 
+	if manifest != nil {
+		manifest.recordPackages(lambdaPackages, schemaPackages)
+		if manifest.Outputs, err = generatedOutputHashes(outputDir); err != nil {
+			return err
+		}
+		if err := manifest.save(outputDir); err != nil {
+			return err
+		}
+	}
+
 	// Print summary
 	totalResources := resourceRegistry.GetTotalResourceCount()
 	c.logger.WithFields(logrus.Fields{
@@ -93,28 +314,60 @@ func (c *GenerateCommand) Execute(scanPath, outputDir string) error {
 		"output_dir":      outputDir,
 	}).Info("Terraform generation completed successfully")
 
-	// Print resource breakdown
-	for _, kind := range []string{"Agent", "Lambda", "ActionGroup", "KnowledgeBase", "Guardrail", "Prompt"} {
-		count := resourceRegistry.GetResourceCount(models.ResourceKind(kind))
-		if count > 0 {
-			c.logger.WithFields(logrus.Fields{
-				"kind":  kind,
-				"count": count,
-			}).Info("Generated modules")
-		}
+	return nil
+}
+
+// loadProjectConfig resolves generator settings from defaults, bedrock-forge.yaml and overrides, in that order
+func (c *GenerateCommand) loadProjectConfig(scanPath string) (*config.ProjectConfig, error) {
+	projectConfig := &config.ProjectConfig{
+		ModuleRegistry: "git::https://github.com/company/bedrock-terraform-modules",
+		ModuleVersion:  "v1.0.0",
+		ProjectName:    "bedrock-project",
+		Environment:    "dev",
+		S3Prefix:       "bedrock-forge",
 	}
 
-	return nil
+	fileConfig, path, err := config.LoadProjectConfig(scanPath)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		c.logger.WithField("config", path).Info("Using project configuration")
+	}
+
+	projectConfig.Override(*fileConfig)
+	projectConfig.Override(c.projectOverrides)
+	if err := projectConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return projectConfig, nil
 }
 
 func (c *GenerateCommand) scanAndParseFiles(scanPath string, resourceRegistry *registry.ResourceRegistry, yamlParser *parser.YAMLParser) error {
-	return filepath.Walk(scanPath, func(path string, info os.FileInfo, err error) error {
+	if scanPath == StdinPath {
+		c.logger.Info("Reading resources from stdin")
+		resources, err := parseStdin(yamlParser)
+		if err != nil && !isDocumentError(err) {
+			return err
+		}
+		c.addResources(resourceRegistry, stdinFilePath, resources)
+		if err != nil {
+			return reportDocumentErrors(c.logger, []error{err})
+		}
+		return nil
+	}
+
+	var documentErrs []error
+	err := filepath.Walk(scanPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip directories
+		// Skip directories; overlays are applied after the base resources are loaded
 		if info.IsDir() {
+			if isWithinOverlayDir(path, c.overlayDir) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -125,24 +378,35 @@ func (c *GenerateCommand) scanAndParseFiles(scanPath string, resourceRegistry *r
 
 		// Parse the file
 		resources, err := yamlParser.ParseFile(path)
-		if err != nil {
+		if err != nil && !isDocumentError(err) {
 			c.logger.WithError(err).WithField("file", path).Warn("Failed to parse YAML file")
 			return nil // Continue processing other files
 		}
-
-		// Add resources to registry
-		for _, resource := range resources {
-			if err := resourceRegistry.AddResource(resource); err != nil {
-				c.logger.WithError(err).WithFields(logrus.Fields{
-					"file": path,
-					"kind": resource.Kind,
-					"name": resource.Metadata.Name,
-				}).Warn("Failed to add resource to registry")
-			}
+		if err != nil {
+			documentErrs = append(documentErrs, err)
 		}
 
+		c.addResources(resourceRegistry, path, resources)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return reportDocumentErrors(c.logger, documentErrs)
+}
+
+// addResources adds the resources parsed from a file to the registry
+func (c *GenerateCommand) addResources(resourceRegistry *registry.ResourceRegistry, filePath string, resources []*parser.ParsedResource) {
+	for _, resource := range resources {
+		if err := resourceRegistry.AddResource(resource); err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"file": filePath,
+				"kind": resource.Kind,
+				"name": resource.Metadata.Name,
+			}).Warn("Failed to add resource to registry")
+		}
+	}
 }
 
 func isYAMLFile(path string) bool {
@@ -150,29 +414,42 @@ func isYAMLFile(path string) bool {
 	return ext == ".yml" || ext == ".yaml"
 }
 
-func (c *GenerateCommand) packageArtifacts(scanPath string, resourceRegistry *registry.ResourceRegistry) (map[string]*packager.LambdaPackage, map[string]*packager.SchemaPackage, error) {
+// packageArtifacts packages Lambdas and extracts schemas, keeping the reused packages of unchanged ones
+func (c *GenerateCommand) packageArtifacts(scanPath string, projectConfig *config.ProjectConfig, resourceRegistry *registry.ResourceRegistry, reusedLambdas map[string]*packager.LambdaPackage, reusedSchemas map[string]*packager.SchemaPackage) (map[string]*packager.LambdaPackage, map[string]*packager.SchemaPackage, error) {
 	c.logger.Info("Starting artifact packaging...")
 
-	// Create S3 client (using mock for now)
-	s3LocalDir := filepath.Join(scanPath, ".bedrock-forge", "s3-mock")
-	s3Client := packager.NewMockS3Client(c.logger, s3LocalDir)
+	// Artifacts are only uploaded when explicitly requested
+	var s3Client packager.S3Client = packager.NewDryRunS3Client(c.logger)
+	if c.upload {
+		awsS3Client, err := packager.NewAWSS3Client(c.logger, c.s3Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create S3 client: %w", err)
+		}
+		s3Client = awsS3Client
+	}
 
-	// Package configuration
-	packagerConfig := &packager.PackagerConfig{
-		S3Bucket:    "bedrock-artifacts",
-		S3KeyPrefix: "bedrock-forge",
-		TempDir:     filepath.Join(scanPath, ".bedrock-forge", "temp"),
+	packagerConfig := c.packagerConfig(scanPath, projectConfig)
+	if c.upload {
+		packagerConfig.SSEMode = c.s3Config.SSEMode
+		packagerConfig.KMSKeyID = c.s3Config.KMSKeyID
+		requireEncryption, err := c.requiresEncryptionAtRest(scanPath, projectConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		packagerConfig.RequireEncryption = requireEncryption
 	}
 
 	// Package Lambda functions
 	lambdaPackager := packager.NewLambdaPackager(c.logger, resourceRegistry, s3Client, packagerConfig)
-	lambdaPackages, err := lambdaPackager.PackageAllLambdas(scanPath)
+	lambdaPackager.ReusePackages(reusedLambdas)
+	lambdaPackages, err := lambdaPackager.PackageAllLambdas()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to package Lambdas: %w", err)
 	}
 
 	// Extract OpenAPI schemas
 	schemaExtractor := packager.NewSchemaExtractor(c.logger, resourceRegistry, s3Client, packagerConfig)
+	schemaExtractor.ReusePackages(reusedSchemas)
 	schemaPackages, err := schemaExtractor.ExtractAllSchemas(scanPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to extract schemas: %w", err)
@@ -186,3 +463,82 @@ func (c *GenerateCommand) packageArtifacts(scanPath string, resourceRegistry *re
 
 	return lambdaPackages, schemaPackages, nil
 }
+
+// packagerConfig returns the artifact packaging configuration
+func (c *GenerateCommand) packagerConfig(scanPath string, projectConfig *config.ProjectConfig) *packager.PackagerConfig {
+	s3Bucket := projectConfig.S3Bucket
+	if s3Bucket == "" {
+		s3Bucket = placeholderS3Bucket
+	}
+	return &packager.PackagerConfig{
+		S3Bucket:    s3Bucket,
+		S3KeyPrefix: projectConfig.S3Prefix,
+		TempDir:     filepath.Join(scanPath, ".bedrock-forge", "temp"),
+		Concurrency: c.packageConcurrency,
+	}
+}
+
+// requiresEncryptionAtRest reports whether the validation rules of the project require encryption at rest,
+// in which case packages are only uploaded with server-side encryption
+func (c *GenerateCommand) requiresEncryptionAtRest(scanPath string, projectConfig *config.ProjectConfig) (bool, error) {
+	validateCommand := NewValidateCommand(c.logger)
+	if projectConfig.Validation.Profile != "" {
+		validateCommand.SetValidationProfile(projectConfig.Validation.Profile)
+	}
+	validateCommand.SetConfigPath(projectConfig.Validation.ConfigPath)
+
+	validationConfig, err := validateCommand.resolveValidationConfig(scanPath)
+	if err != nil {
+		return false, err
+	}
+
+	securityPolicies := validationConfig.SecurityPolicies
+	return securityPolicies != nil && securityPolicies.EncryptionRequirements != nil &&
+		securityPolicies.EncryptionRequirements.RequireEncryptionAtRest, nil
+}
+
+// placeholderArtifacts returns packages with placeholder S3 keys for a dry run, without packaging or uploading
+func (c *GenerateCommand) placeholderArtifacts(scanPath string, projectConfig *config.ProjectConfig, resourceRegistry *registry.ResourceRegistry) (map[string]*packager.LambdaPackage, map[string]*packager.SchemaPackage) {
+	config := c.packagerConfig(scanPath, projectConfig)
+
+	lambdaPackages := make(map[string]*packager.LambdaPackage)
+	for _, lambda := range resourceRegistry.GetResourcesByType(models.LambdaKind) {
+		name := lambda.Metadata.Name
+		s3Key := fmt.Sprintf("%s/lambdas/%s/dry-run.zip", config.S3KeyPrefix, name)
+		lambdaPackages[name] = &packager.LambdaPackage{
+			Name:     name,
+			S3Bucket: config.S3Bucket,
+			S3Key:    s3Key,
+			S3URI:    fmt.Sprintf("s3://%s/%s", config.S3Bucket, s3Key),
+		}
+		c.logger.WithFields(logrus.Fields{
+			"lambda": name,
+			"uri":    lambdaPackages[name].S3URI,
+		}).Info("Dry run: would package and upload Lambda")
+	}
+
+	schemaPackages := make(map[string]*packager.SchemaPackage)
+	for _, actionGroup := range resourceRegistry.GetResourcesByType(models.ActionGroupKind) {
+		spec, ok := actionGroup.Spec.(models.ActionGroupSpec)
+		if !ok || spec.APISchema == nil || spec.APISchema.IsManaged() {
+			continue
+		}
+
+		name := actionGroup.Metadata.Name
+		s3Key := fmt.Sprintf("%s/schemas/%s/dry-run.json", config.S3KeyPrefix, name)
+		schemaPackages[name] = &packager.SchemaPackage{
+			Name:        fmt.Sprintf("%s-schema", name),
+			ActionGroup: name,
+			S3Bucket:    config.S3Bucket,
+			S3Key:       s3Key,
+			S3URI:       fmt.Sprintf("s3://%s/%s", config.S3Bucket, s3Key),
+			Source:      "dry-run",
+		}
+		c.logger.WithFields(logrus.Fields{
+			"action_group": name,
+			"uri":          schemaPackages[name].S3URI,
+		}).Info("Dry run: would extract and upload schema")
+	}
+
+	return lambdaPackages, schemaPackages
+}
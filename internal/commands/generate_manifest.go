@@ -0,0 +1,403 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/packager"
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+	"bedrock-forge/pkg/config"
+)
+
+// generationManifestFile records what the last incremental generation was built from, in the output directory
+const generationManifestFile = ".bedrock-forge-manifest.json"
+
+// generationManifestVersion changes whenever the manifest format or what it hashes changes, so older
+// manifests trigger a full generation
+const generationManifestVersion = 1
+
+// generationManifest records the inputs and outputs of an incremental generation, so the next run can
+// tell which resources changed
+type generationManifest struct {
+	Version        int                                `json:"version"`
+	Settings       string                             `json:"settings"`  // SHA256 of the options that affect every resource
+	Resources      map[string]manifestResource        `json:"resources"` // Kind/name -> hashes
+	Files          map[string]manifestFile            `json:"files,omitempty"`
+	LambdaPackages map[string]*packager.LambdaPackage `json:"lambdaPackages,omitempty"`
+	SchemaPackages map[string]*packager.SchemaPackage `json:"schemaPackages,omitempty"`
+	Outputs        map[string]string                  `json:"outputs"` // Generated file -> SHA256
+}
+
+// manifestResource holds the hashes of a resource's definition and of the files it is built from
+type manifestResource struct {
+	Definition string `json:"definition"`        // SHA256 of the parsed resource, after templates and overlays
+	Sources    string `json:"sources,omitempty"` // SHA256 of its Lambda code, ActionGroup directory or Terraform files
+}
+
+// manifestFile caches the hash of a source file, which is only read again once its size or
+// modification time changes
+type manifestFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Hash    string    `json:"hash"`
+}
+
+// loadGenerationManifest reads the manifest of the previous incremental generation, or returns nil if
+// there is none
+func loadGenerationManifest(outputDir string) (*generationManifest, error) {
+	path := filepath.Join(outputDir, generationManifestFile)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generation manifest %s: %w", path, err)
+	}
+
+	var manifest generationManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse generation manifest %s: %w", path, err)
+	}
+	if manifest.Version != generationManifestVersion {
+		return nil, fmt.Errorf("generation manifest %s has version %d, expected %d", path, manifest.Version, generationManifestVersion)
+	}
+	return &manifest, nil
+}
+
+// save writes the manifest to the output directory
+func (m *generationManifest) save(outputDir string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode generation manifest: %w", err)
+	}
+
+	path := filepath.Join(outputDir, generationManifestFile)
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write generation manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// buildGenerationManifest hashes the settings and every resource in the registry. Source files are
+// only read when their size or modification time differs from the previous manifest.
+func (c *GenerateCommand) buildGenerationManifest(outputDir string, projectConfig *config.ProjectConfig, resourceRegistry *registry.ResourceRegistry, previous *generationManifest) (*generationManifest, error) {
+	settings, err := c.generationSettingsHash(projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &generationManifest{
+		Version:   generationManifestVersion,
+		Settings:  settings,
+		Resources: make(map[string]manifestResource),
+		Files:     make(map[string]manifestFile),
+	}
+
+	hasher := &sourceHasher{files: manifest.Files}
+	if previous != nil {
+		hasher.previous = previous.Files
+	}
+	// Generated and packaged files must not count as sources when they are under a source directory
+	if hasher.excludeDir, err = filepath.Abs(outputDir); err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+
+	for kind, resources := range resourceRegistry.GetAllResources() {
+		for name, resource := range resources {
+			definition, err := yaml.Marshal(resource.Resource)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash %s %s: %w", kind, name, err)
+			}
+
+			entry := manifestResource{Definition: hashBytes([]byte(resource.FilePath + "\n" + string(definition)))}
+			if sources := resourceSourcePaths(resource); len(sources) > 0 {
+				if entry.Sources, err = hasher.hashPaths(sources); err != nil {
+					return nil, fmt.Errorf("failed to hash sources of %s %s: %w", kind, name, err)
+				}
+			}
+			manifest.Resources[resourceManifestKey(kind, name)] = entry
+		}
+	}
+
+	return manifest, nil
+}
+
+// generationSettingsHash hashes the options that affect the output of every resource, including the
+// bedrock-forge binary itself, so changing any of them regenerates everything
+func (c *GenerateCommand) generationSettingsHash(projectConfig *config.ProjectConfig) (string, error) {
+	settings := struct {
+		Project  *config.ProjectConfig
+		DryRun   bool
+		Upload   bool
+		S3Config packager.AWSS3Config
+		Binary   string
+	}{
+		Project:  projectConfig,
+		DryRun:   c.dryRun,
+		Upload:   c.upload,
+		S3Config: c.s3Config,
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		if info, err := os.Stat(executable); err == nil {
+			settings.Binary = fmt.Sprintf("%s:%d:%d", executable, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+
+	content, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash generation settings: %w", err)
+	}
+	return hashBytes(content), nil
+}
+
+// resourceSourcePaths returns the files outside its YAML definition that a resource is generated from
+func resourceSourcePaths(resource *parser.ParsedResource) []string {
+	dir := filepath.Dir(resource.FilePath)
+
+	switch r := resource.Resource.(type) {
+	case *models.Lambda:
+		if r.Spec.Code.IsSourceDirectory() {
+			return []string{dir}
+		}
+	case *models.ActionGroup:
+		if r.Spec.APISchema != nil && !r.Spec.APISchema.IsManaged() {
+			return []string{dir}
+		}
+	case *models.CustomResources:
+		var paths []string
+		for _, path := range append([]string{r.Spec.Path}, r.Spec.Files...) {
+			if path == "" {
+				continue
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			paths = append(paths, path)
+		}
+		return paths
+	}
+	return nil
+}
+
+// sourceHasher hashes source files and directories, reusing hashes of files whose size and
+// modification time haven't changed
+type sourceHasher struct {
+	previous   map[string]manifestFile
+	files      map[string]manifestFile
+	excludeDir string
+}
+
+// hashPaths returns one hash over the files under paths. A missing path is part of the hash, so
+// creating it later is a change.
+func (h *sourceHasher) hashPaths(paths []string) (string, error) {
+	digest := sha256.New()
+	for _, root := range paths {
+		fmt.Fprintf(digest, "%s\x00", root)
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					fmt.Fprintf(digest, "missing\x00")
+					return nil
+				}
+				return err
+			}
+
+			if info.IsDir() {
+				if info.Name() == ".bedrock-forge" {
+					return filepath.SkipDir
+				}
+				if absPath, err := filepath.Abs(path); err == nil && absPath == h.excludeDir {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			hash, err := h.hashFile(path, info)
+			if err != nil {
+				return err
+			}
+			relPath, _ := filepath.Rel(root, path)
+			fmt.Fprintf(digest, "%s\x00%s\x00", filepath.ToSlash(relPath), hash)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// hashFile returns the SHA256 of a file, from the previous manifest when the file looks unchanged
+func (h *sourceHasher) hashFile(path string, info os.FileInfo) (string, error) {
+	if cached, ok := h.previous[path]; ok && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		h.files[path] = cached
+		return cached.Hash, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	hash := hex.EncodeToString(digest.Sum(nil))
+	h.files[path] = manifestFile{Size: info.Size(), ModTime: info.ModTime(), Hash: hash}
+	return hash, nil
+}
+
+// changedResources returns the resources added, removed or changed since the previous manifest, plus
+// every resource that references one of them directly or transitively, sorted
+func changedResources(previous, current *generationManifest, resourceRegistry *registry.ResourceRegistry) []string {
+	changed := make(map[string]bool)
+	for key, entry := range current.Resources {
+		if previousEntry, exists := previous.Resources[key]; !exists || previousEntry != entry {
+			changed[key] = true
+		}
+	}
+	for key := range previous.Resources {
+		if _, exists := current.Resources[key]; !exists {
+			changed[key] = true
+		}
+	}
+
+	// Resources generate references to what they use, so a change propagates to its referrers
+	referrers := make(map[string][]string)
+	for kind, resources := range resourceRegistry.GetAllResources() {
+		for name, resource := range resources {
+			for _, ref := range resourceRegistry.GetResourceReferences(resource) {
+				target := resourceManifestKey(ref.Kind, ref.Name)
+				referrers[target] = append(referrers[target], resourceManifestKey(kind, name))
+			}
+		}
+	}
+
+	queue := make([]string, 0, len(changed))
+	for key := range changed {
+		queue = append(queue, key)
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, referrer := range referrers[key] {
+			if !changed[referrer] {
+				changed[referrer] = true
+				queue = append(queue, referrer)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reusablePackages returns the packages of the previous manifest whose Lambda or ActionGroup is unchanged
+func (m *generationManifest) reusablePackages(changed []string) (map[string]*packager.LambdaPackage, map[string]*packager.SchemaPackage) {
+	changedSet := make(map[string]bool, len(changed))
+	for _, key := range changed {
+		changedSet[key] = true
+	}
+
+	lambdaPackages := make(map[string]*packager.LambdaPackage)
+	for name, pkg := range m.LambdaPackages {
+		if !changedSet[resourceManifestKey(models.LambdaKind, name)] {
+			lambdaPackages[name] = pkg
+		}
+	}
+
+	schemaPackages := make(map[string]*packager.SchemaPackage)
+	for name, pkg := range m.SchemaPackages {
+		if !changedSet[resourceManifestKey(models.ActionGroupKind, name)] {
+			schemaPackages[name] = pkg
+		}
+	}
+
+	return lambdaPackages, schemaPackages
+}
+
+// recordPackages stores the packages of this run, without schema contents, which the generated
+// configuration doesn't need
+func (m *generationManifest) recordPackages(lambdaPackages map[string]*packager.LambdaPackage, schemaPackages map[string]*packager.SchemaPackage) {
+	m.LambdaPackages = lambdaPackages
+	m.SchemaPackages = make(map[string]*packager.SchemaPackage, len(schemaPackages))
+	for name, pkg := range schemaPackages {
+		recorded := *pkg
+		recorded.Content = nil
+		m.SchemaPackages[name] = &recorded
+	}
+}
+
+// generatedOutputHashes hashes the Terraform files in the output directory
+func generatedOutputHashes(outputDir string) (map[string]string, error) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output directory %s: %w", outputDir, err)
+	}
+
+	hashes := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		hashes[name] = hashBytes(content)
+	}
+	return hashes, nil
+}
+
+// outputsUnchanged reports whether the Terraform files in the output directory are still the ones the
+// previous generation wrote
+func outputsUnchanged(previous *generationManifest, outputDir string) bool {
+	current, err := generatedOutputHashes(outputDir)
+	if err != nil || len(current) != len(previous.Outputs) {
+		return false
+	}
+	for name, hash := range previous.Outputs {
+		if current[name] != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceManifestKey identifies a resource in the manifest
+func resourceManifestKey(kind models.ResourceKind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// hashBytes returns the hex encoded SHA256 of content
+func hashBytes(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// incrementalResources has a directory Lambda used by an agent, and a Lambda nothing references
+var incrementalResources = map[string]string{
+	"order-lookup/lambda.yml": `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    source: directory
+`,
+	"order-lookup/app.py": "def handler(event, context): return event\n",
+	"billing-lookup/lambda.yml": `kind: Lambda
+metadata:
+  name: billing-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    source: directory
+`,
+	"billing-lookup/app.py": "def handler(event, context): return event\n",
+	"agent.yml": `kind: Agent
+metadata:
+  name: support
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You are a helpful customer support agent.
+  actionGroups:
+    - name: orders
+      actionGroupExecutor:
+        lambda: order-lookup
+`,
+}
+
+// writeProject writes files, keyed by slash-separated paths, under dir
+func writeProject(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// generateIncrementally runs an incremental generation and returns its log entries
+func generateIncrementally(t *testing.T, dir, outputDir string) []*logrus.Entry {
+	t.Helper()
+
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	generate := NewGenerateCommand(logger)
+	generate.SetIncremental(true)
+	if err := generate.Execute(dir, outputDir); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return hook.AllEntries()
+}
+
+// findEntry returns the first log entry with the message, or nil
+func findEntry(entries []*logrus.Entry, message string) *logrus.Entry {
+	for _, entry := range entries {
+		if entry.Message == message {
+			return entry
+		}
+	}
+	return nil
+}
+
+// packagedLambdas returns the Lambdas a run packaged, rather than reusing their packages
+func packagedLambdas(entries []*logrus.Entry) map[string]bool {
+	packaged := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Message == "Successfully packaged Lambda" {
+			packaged[entry.Data["lambda"].(string)] = true
+		}
+	}
+	return packaged
+}
+
+func TestIncrementalGenerationSkipsUnchangedInput(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, incrementalResources)
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	generateIncrementally(t, dir, outputDir)
+	before, err := os.Stat(filepath.Join(outputDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := generateIncrementally(t, dir, outputDir)
+	if findEntry(entries, "No changes since the last generation, output is up to date") == nil {
+		t.Errorf("the second run with unchanged input wasn't skipped")
+	}
+	if packaged := packagedLambdas(entries); len(packaged) > 0 {
+		t.Errorf("the second run packaged %v again", packaged)
+	}
+	after, err := os.Stat(filepath.Join(outputDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("main.tf was rewritten although nothing changed")
+	}
+}
+
+func TestIncrementalGenerationRegeneratesReferrers(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, incrementalResources)
+	outputDir := filepath.Join(t.TempDir(), "out")
+	generateIncrementally(t, dir, outputDir)
+
+	// The agent references order-lookup through its action group
+	writeProject(t, dir, map[string]string{
+		"order-lookup/lambda.yml": incrementalResources["order-lookup/lambda.yml"] + "  timeout: 30\n",
+	})
+	entries := generateIncrementally(t, dir, outputDir)
+
+	entry := findEntry(entries, "Changed resources, including the ones referencing them")
+	if entry == nil {
+		t.Fatalf("the run after editing order-lookup didn't regenerate")
+	}
+	changed, _ := entry.Data["resources"].([]string)
+	want := []string{"Agent/support", "Lambda/order-lookup"}
+	if len(changed) != len(want) || changed[0] != want[0] || changed[1] != want[1] {
+		t.Errorf("changed resources = %v, want %v", changed, want)
+	}
+}
+
+func TestIncrementalGenerationRepackagesChangedLambdaSource(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, incrementalResources)
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	if packaged := packagedLambdas(generateIncrementally(t, dir, outputDir)); !packaged["order-lookup"] || !packaged["billing-lookup"] {
+		t.Fatalf("the first run packaged %v, want both Lambdas", packaged)
+	}
+
+	writeProject(t, dir, map[string]string{
+		"order-lookup/app.py": "def handler(event, context):\n    return {\"order\": event}\n",
+	})
+	entries := generateIncrementally(t, dir, outputDir)
+
+	packaged := packagedLambdas(entries)
+	if !packaged["order-lookup"] || packaged["billing-lookup"] {
+		t.Errorf("packaged %v after changing order-lookup's code, want only order-lookup", packaged)
+	}
+	reused := findEntry(entries, "Lambda is unchanged, reusing its package")
+	if reused == nil || reused.Data["lambda"] != "billing-lookup" {
+		t.Errorf("billing-lookup's package wasn't reused")
+	}
+}
+
+func TestIncrementalGenerationRestoresDeletedOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, incrementalResources)
+	outputDir := filepath.Join(t.TempDir(), "out")
+	generateIncrementally(t, dir, outputDir)
+
+	if err := os.Remove(filepath.Join(outputDir, "main.tf")); err != nil {
+		t.Fatal(err)
+	}
+	entries := generateIncrementally(t, dir, outputDir)
+
+	if findEntry(entries, "Generated files were modified or removed since the last generation, regenerating") == nil {
+		t.Errorf("deleting main.tf didn't trigger a regeneration")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "main.tf")); err != nil {
+		t.Errorf("main.tf wasn't written again: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"bedrock-forge/internal/packager"
+	"bedrock-forge/pkg/config"
+)
+
+// writeResources writes content to resources.yml in a new temporary directory
+func writeResources(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "resources.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func discardLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+const missingInstructionContent = `kind: Agent
+metadata:
+  name: support
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+`
+
+func TestGenerateFailsOnInvalidResourceDocument(t *testing.T) {
+	dir := writeResources(t, missingInstructionContent)
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	err := NewGenerateCommand(discardLogger()).Execute(dir, outputDir)
+	if err == nil || !strings.Contains(err.Error(), "spec.instruction: required field is missing") {
+		t.Errorf("Execute() = %v, want the invalid document error", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, "main.tf")); !os.IsNotExist(statErr) {
+		t.Errorf("main.tf was written for an invalid resource document")
+	}
+}
+
+func TestValidateFailsOnInvalidResourceDocument(t *testing.T) {
+	dir := writeResources(t, missingInstructionContent)
+
+	err := NewValidateCommand(discardLogger()).Execute(dir)
+	if err == nil || !strings.Contains(err.Error(), "spec.instruction: required field is missing") {
+		t.Errorf("Execute() = %v, want the invalid document error", err)
+	}
+}
+
+func TestGenerateStrictFieldsFailsOnUnknownField(t *testing.T) {
+	dir := writeResources(t, `kind: Agent
+metadata:
+  name: support
+spec:
+  foundatonModel: anthropic.claude-3-sonnet-20240229-v1:0
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You are a helpful customer support agent.
+`)
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	generate := NewGenerateCommand(discardLogger())
+	generate.SetStrictFields(true)
+	err := generate.Execute(dir, outputDir)
+
+	want := "document 0 (Agent support): Agent does not match its schema: line 5: spec.foundatonModel: unknown field"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Execute() = %v, want an error containing %q", err, want)
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, "main.tf")); !os.IsNotExist(statErr) {
+		t.Errorf("main.tf was written without the Agent")
+	}
+}
+
+func TestGenerateReportsEveryInvalidResourceDocument(t *testing.T) {
+	dir := writeResources(t, missingInstructionContent+`---
+kind: Agent
+metadata:
+  name: billing
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+`)
+
+	logger, hook := test.NewNullLogger()
+	err := NewGenerateCommand(logger).Execute(dir, filepath.Join(t.TempDir(), "out"))
+	if err == nil || !strings.Contains(err.Error(), "found 2 invalid resource documents") {
+		t.Errorf("Execute() = %v, want the invalid document count", err)
+	}
+
+	var reported []string
+	for _, entry := range hook.AllEntries() {
+		if err, ok := entry.Data[logrus.ErrorKey].(error); ok && entry.Level == logrus.ErrorLevel {
+			reported = append(reported, err.Error())
+		}
+	}
+	if len(reported) != 2 || !strings.Contains(reported[0], "document 0 (Agent support)") || !strings.Contains(reported[1], "document 1 (Agent billing)") {
+		t.Errorf("expected both documents to be reported, got %v", reported)
+	}
+}
+
+func TestGenerateFailsOnConflictingLambdaCodeSources(t *testing.T) {
+	dir := writeResources(t, `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    zipFile: dist/order-lookup.zip
+    s3Bucket: artifacts
+    s3Key: order-lookup.zip
+`)
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	err := NewGenerateCommand(discardLogger()).Execute(dir, outputDir)
+	if err == nil || !strings.Contains(err.Error(), "got: zipFile, s3Bucket") {
+		t.Errorf("Execute() = %v, want the conflicting code sources error", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, "main.tf")); !os.IsNotExist(statErr) {
+		t.Errorf("main.tf was written without the Lambda")
+	}
+}
+
+func TestGenerateUploadRequiresBucket(t *testing.T) {
+	dir := writeResources(t, `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`)
+
+	generate := NewGenerateCommand(discardLogger())
+	generate.SetUpload(true, packager.AWSS3Config{Region: "us-east-1"})
+	err := generate.Execute(dir, filepath.Join(t.TempDir(), "out"))
+	if err == nil || !strings.Contains(err.Error(), "--upload requires an artifact bucket") {
+		t.Errorf("Execute() = %v, want the missing bucket error", err)
+	}
+}
+
+func TestGenerateArtifactLocation(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		overrides config.ProjectConfig
+		want      string
+	}{
+		{
+			name: "placeholder",
+			want: "s3://bedrock-artifacts/bedrock-forge/lambdas/order-lookup/dry-run.zip",
+		},
+		{
+			name:   "project config",
+			config: "s3Bucket: company-artifacts\ns3Prefix: support\n",
+			want:   "s3://company-artifacts/support/lambdas/order-lookup/dry-run.zip",
+		},
+		{
+			name:      "flags override project config",
+			config:    "s3Bucket: company-artifacts\ns3Prefix: support\n",
+			overrides: config.ProjectConfig{S3Bucket: "team-artifacts", S3Prefix: "prod"},
+			want:      "s3://team-artifacts/prod/lambdas/order-lookup/dry-run.zip",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeResources(t, `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`)
+			if tc.config != "" {
+				if err := os.WriteFile(filepath.Join(dir, config.ProjectConfigFileName), []byte(tc.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			logger, hook := test.NewNullLogger()
+			generate := NewGenerateCommand(logger)
+			generate.SetDryRun(true)
+			generate.SetProjectOverrides(tc.overrides)
+			if err := generate.Execute(dir, filepath.Join(t.TempDir(), "out")); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			var uris []interface{}
+			for _, entry := range hook.AllEntries() {
+				if entry.Message == "Dry run: would package and upload Lambda" {
+					uris = append(uris, entry.Data["uri"])
+				}
+			}
+			if len(uris) != 1 || uris[0] != tc.want {
+				t.Errorf("packaged to %v, want %s", uris, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+)
+
+type GraphCommand struct {
+	logger       *logrus.Logger
+	format       string
+	templateVars map[string]interface{}
+	overlayDir   string
+	strictFields bool
+}
+
+// GraphNode is a single resource in the dependency graph
+type GraphNode struct {
+	Kind models.ResourceKind
+	Name string
+}
+
+// ID returns the Kind/name label of the node
+func (n GraphNode) ID() string {
+	// References to unknown resources have no kind
+	if n.Kind == "" {
+		return n.Name
+	}
+	return fmt.Sprintf("%s/%s", n.Kind, n.Name)
+}
+
+// GraphEdge is a reference from one resource to another
+type GraphEdge struct {
+	From  GraphNode
+	To    GraphNode
+	Label string
+}
+
+func NewGraphCommand(logger *logrus.Logger) *GraphCommand {
+	return &GraphCommand{
+		logger: logger,
+		format: "dot",
+	}
+}
+
+// SetOutputFormat sets the graph output format ("dot" or "mermaid")
+func (c *GraphCommand) SetOutputFormat(format string) error {
+	switch format {
+	case "", "dot":
+		c.format = "dot"
+	case "mermaid":
+		c.format = "mermaid"
+	default:
+		return fmt.Errorf("unsupported graph format '%s', must be one of: dot, mermaid", format)
+	}
+	return nil
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *GraphCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.templateVars = vars
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *GraphCommand) SetOverlayDir(dir string) {
+	c.overlayDir = dir
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *GraphCommand) SetStrictFields(strict bool) {
+	c.strictFields = strict
+}
+
+func (c *GraphCommand) Execute(scanPath string) error {
+	if scanPath == "" {
+		var err error
+		scanPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	resourceRegistry := registry.NewResourceRegistry(c.logger)
+	yamlParser := parser.NewYAMLParser(c.logger)
+	yamlParser.SetTemplateVars(c.templateVars)
+	yamlParser.SetStrictFields(c.strictFields)
+
+	generateCommand := NewGenerateCommand(c.logger)
+	generateCommand.SetOverlayDir(c.overlayDir)
+	if err := generateCommand.scanAndParseFiles(scanPath, resourceRegistry, yamlParser); err != nil {
+		return fmt.Errorf("failed to scan and parse files: %w", err)
+	}
+
+	if c.overlayDir != "" {
+		if err := applyOverlays(c.logger, c.overlayDir, resourceRegistry, yamlParser); err != nil {
+			return err
+		}
+	}
+
+	nodes, edges := c.buildResourceGraph(resourceRegistry)
+
+	if c.format == "mermaid" {
+		fmt.Print(renderMermaid(nodes, edges))
+	} else {
+		fmt.Print(renderDOT(nodes, edges))
+	}
+
+	return nil
+}
+
+// buildResourceGraph builds the per-resource dependency graph from the registry
+func (c *GraphCommand) buildResourceGraph(resourceRegistry *registry.ResourceRegistry) ([]GraphNode, []GraphEdge) {
+	var nodes []GraphNode
+	var edges []GraphEdge
+
+	for _, kindResources := range resourceRegistry.GetAllResources() {
+		for _, resource := range kindResources {
+			from := GraphNode{Kind: resource.Kind, Name: resource.Metadata.Name}
+			nodes = append(nodes, from)
+
+			for _, ref := range resourceRegistry.GetResourceReferences(resource) {
+				edges = append(edges, GraphEdge{
+					From:  from,
+					To:    GraphNode{Kind: ref.Kind, Name: ref.Name},
+					Label: referenceLabel(resource.Kind, ref.Field),
+				})
+			}
+		}
+	}
+
+	// Keep output stable across runs
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From.ID() != edges[j].From.ID() {
+			return edges[i].From.ID() < edges[j].From.ID()
+		}
+		if edges[i].To.ID() != edges[j].To.ID() {
+			return edges[i].To.ID() < edges[j].To.ID()
+		}
+		return edges[i].Label < edges[j].Label
+	})
+
+	return nodes, edges
+}
+
+// referenceLabel describes the reference type of a spec field for edge labels
+func referenceLabel(kind models.ResourceKind, field string) string {
+	switch {
+	case kind == models.AgentKnowledgeBaseAssociationKind:
+		return "kb association"
+	case strings.Contains(field, "actionGroupExecutor"):
+		return "lambda executor"
+	case strings.Contains(field, "transformationLambda"):
+		return "transformation lambda"
+	case strings.Contains(field, "guardrail"):
+		return "guardrail"
+	case strings.Contains(field, "promptOverrides"):
+		return "prompt override"
+	case field == "spec.iamRole.roleName" || field == "spec.role":
+		return "iam role"
+	case strings.Contains(field, "collaborators"):
+		return "collaborator"
+	case field == "spec.agentId":
+		return "agent"
+	case strings.Contains(field, "collectionName"):
+		return "collection"
+	case field == "spec.dependsOn" || field == "metadata.dependsOn":
+		return "depends on"
+	default:
+		return field
+	}
+}
+
+// renderDOT renders the graph in Graphviz DOT format
+func renderDOT(nodes []GraphNode, edges []GraphEdge) string {
+	var builder strings.Builder
+
+	builder.WriteString("digraph bedrock_forge {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [shape=box];\n")
+
+	for _, node := range nodes {
+		fmt.Fprintf(&builder, "  %q;\n", node.ID())
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&builder, "  %q -> %q [label=%q];\n", edge.From.ID(), edge.To.ID(), edge.Label)
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// renderMermaid renders the graph as a Mermaid flowchart
+func renderMermaid(nodes []GraphNode, edges []GraphEdge) string {
+	var builder strings.Builder
+
+	// Mermaid node IDs must be plain identifiers, so labels are attached separately
+	ids := make(map[string]string)
+	nodeID := func(node GraphNode) string {
+		if id, exists := ids[node.ID()]; exists {
+			return id
+		}
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[node.ID()] = id
+		fmt.Fprintf(&builder, "  %s[\"%s\"]\n", id, node.ID())
+		return id
+	}
+
+	builder.WriteString("flowchart LR\n")
+
+	for _, node := range nodes {
+		nodeID(node)
+	}
+	for _, edge := range edges {
+		from := nodeID(edge.From)
+		to := nodeID(edge.To)
+		fmt.Fprintf(&builder, "  %s -->|%s| %s\n", from, edge.Label, to)
+	}
+
+	return builder.String()
+}
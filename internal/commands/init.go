@@ -0,0 +1,415 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InitCommand writes commented starter YAML for a resource kind
+type InitCommand struct {
+	logger      *logrus.Logger
+	name        string
+	actionGroup bool
+}
+
+// scaffoldFile is a file written by init, relative to the target directory
+type scaffoldFile struct {
+	path     string
+	template string
+}
+
+// scaffoldData is rendered into the scaffold templates
+type scaffoldData struct {
+	Name         string
+	ActionGroup  string // Name of the paired action group, agents only
+	ActionLambda string // Name of the Lambda implementing the action group
+	Handler      string // Python function name of the action group operation
+}
+
+// scaffoldNamePattern matches names usable as resource names, Terraform labels and directory names
+var scaffoldNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+// scaffoldKinds maps the kinds accepted by init to their default resource name, which follows the
+// default naming conventions
+var scaffoldKinds = map[string]string{
+	"agent":          "my-agent",
+	"lambda":         "my-lambda",
+	"knowledge-base": "my-kb",
+	"guardrail":      "my-guardrail",
+	"prompt":         "my-prompt",
+}
+
+func NewInitCommand(logger *logrus.Logger) *InitCommand {
+	return &InitCommand{
+		logger: logger,
+	}
+}
+
+// SetName sets metadata.name of the scaffolded resource
+func (c *InitCommand) SetName(name string) error {
+	if name != "" && !scaffoldNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid name '%s', use up to 63 letters, digits, '-' and '_', starting with a letter or digit", name)
+	}
+	c.name = name
+	return nil
+}
+
+// SetActionGroup also scaffolds a Lambda action group for agents
+func (c *InitCommand) SetActionGroup(actionGroup bool) {
+	c.actionGroup = actionGroup
+}
+
+func (c *InitCommand) Execute(kind, dir string) error {
+	kind = strings.ToLower(kind)
+	if kind == "knowledgebase" {
+		kind = "knowledge-base"
+	}
+	defaultName, ok := scaffoldKinds[kind]
+	if !ok {
+		return fmt.Errorf("unsupported kind '%s', must be one of: agent, lambda, knowledge-base, guardrail, prompt", kind)
+	}
+	if c.actionGroup && kind != "agent" {
+		return fmt.Errorf("--action-group is only supported for agents")
+	}
+
+	if dir == "" {
+		var err error
+		dir, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+
+	data := scaffoldData{Name: c.name}
+	if data.Name == "" {
+		data.Name = defaultName
+	}
+	if c.actionGroup {
+		base := strings.TrimSuffix(data.Name, "-agent")
+		data.ActionGroup = base + "-actions"
+		data.ActionLambda = base + "-actions-lambda"
+		data.Handler = "get_status"
+	}
+
+	files := c.scaffoldFiles(kind, data)
+
+	// Never overwrite existing files, check them all before writing anything
+	for _, file := range files {
+		path := filepath.Join(dir, file.path)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to check %s: %w", path, err)
+		}
+	}
+
+	for _, file := range files {
+		content, err := renderScaffold(file.template, data)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", file.path, err)
+		}
+
+		path := filepath.Join(dir, file.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("Created %s\n", path)
+	}
+
+	return nil
+}
+
+// scaffoldFiles returns the files written for a kind. Lambdas get a directory of their own, which is
+// where packaging looks for their code.
+func (c *InitCommand) scaffoldFiles(kind string, data scaffoldData) []scaffoldFile {
+	switch kind {
+	case "agent":
+		files := []scaffoldFile{{path: data.Name + ".yml", template: agentScaffold}}
+		if data.ActionGroup != "" {
+			files = append(files,
+				scaffoldFile{path: filepath.Join(data.ActionLambda, "lambda.yml"), template: actionGroupLambdaScaffold},
+				scaffoldFile{path: filepath.Join(data.ActionLambda, "app.py"), template: actionGroupHandlerScaffold},
+			)
+		}
+		return files
+	case "lambda":
+		return []scaffoldFile{
+			{path: filepath.Join(data.Name, "lambda.yml"), template: lambdaScaffold},
+			{path: filepath.Join(data.Name, "app.py"), template: lambdaHandlerScaffold},
+		}
+	case "knowledge-base":
+		return []scaffoldFile{{path: data.Name + ".yml", template: knowledgeBaseScaffold}}
+	case "guardrail":
+		return []scaffoldFile{{path: data.Name + ".yml", template: guardrailScaffold}}
+	default:
+		return []scaffoldFile{{path: data.Name + ".yml", template: promptScaffold}}
+	}
+}
+
+// renderScaffold renders a scaffold template. The templates use [[ ]] delimiters so prompt
+// placeholders such as {{question}} are left alone.
+func renderScaffold(text string, data scaffoldData) ([]byte, error) {
+	tmpl, err := template.New("scaffold").Delims("[[", "]]").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+const agentScaffold = `# Bedrock agent. Run "bedrock-forge validate" to check it and "bedrock-forge generate" to
+# produce Terraform. See docs/resources/agent.md for every field.
+kind: Agent
+metadata:
+  name: "[[.Name]]"
+  description: "Describe what this agent does"
+spec:
+  # Required: the model that runs the agent and the instructions it follows
+  foundationModel: "anthropic.claude-3-5-sonnet-20241022-v2:0"
+  instruction: |
+    You are a helpful assistant. Describe the agent's role, the tasks it handles
+    and how it should respond. Bedrock requires at least 40 characters.
+
+  description: "Describe what this agent does"
+
+  # Seconds an idle conversation is kept before the session ends
+  idleSessionTtl: 600
+
+  # Uncomment to attach a guardrail, e.g. one created with "bedrock-forge init guardrail"
+  # guardrail:
+  #   name: {ref: my-guardrail}
+  #   version: "DRAFT"
+[[- if .ActionGroup]]
+
+  # Operations the agent can call, implemented by the Lambda in ./[[.ActionLambda]]/
+  actionGroups:
+    - name: "[[.ActionGroup]]"
+      description: "Operations the agent can perform"
+      actionGroupExecutor:
+        lambda: {ref: [[.ActionLambda]]}
+      functionSchema:
+        functions:
+          - name: "[[.Handler]]"
+            description: "Return the status of an item"
+            parameters:
+              item_id:
+                description: "The ID of the item to look up"
+                required: true
+                type: "string"
+[[- end]]
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+    AgentType: "assistant"
+    BusinessFunction: "describe-the-business-function"
+`
+
+const actionGroupLambdaScaffold = `# Lambda implementing the [[.ActionGroup]] action group of the [[.Name]] agent. The code in this
+# directory is packaged by "bedrock-forge generate", and the agent is granted permission to invoke it.
+kind: Lambda
+metadata:
+  name: "[[.ActionLambda]]"
+  description: "Action group Lambda for the [[.Name]] agent"
+spec:
+  runtime: "python3.11"
+  handler: "app.handler"
+  code:
+    source: "directory"  # The code is in the same directory as this file
+  timeout: 30
+  memorySize: 256
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+    Runtime: "python3.11"
+    FunctionType: "action-group"
+`
+
+const actionGroupHandlerScaffold = `import json
+
+
+def handler(event, context):
+    """Handle a function call from a Bedrock agent action group."""
+    function = event.get("function")
+    parameters = {p["name"]: p["value"] for p in event.get("parameters", [])}
+
+    if function == "[[.Handler]]":
+        body = {"item_id": parameters.get("item_id"), "status": "OK"}
+    else:
+        body = {"error": f"Unknown function {function}"}
+
+    return {
+        "messageVersion": "1.0",
+        "response": {
+            "actionGroup": event.get("actionGroup"),
+            "function": function,
+            "functionResponse": {
+                "responseBody": {"TEXT": {"body": json.dumps(body)}}
+            },
+        },
+    }
+`
+
+const lambdaScaffold = `# Lambda function. The code in this directory is packaged by "bedrock-forge generate".
+# See docs/resources/lambda.md for every field.
+kind: Lambda
+metadata:
+  name: "[[.Name]]"
+  description: "Describe what this function does"
+spec:
+  # Required: the runtime and the module.function called for each invocation
+  runtime: "python3.11"
+  handler: "app.handler"
+  code:
+    source: "directory"  # The code is in the same directory as this file
+
+  timeout: 30      # Seconds
+  memorySize: 256  # MB
+
+  # Plain values only; read secrets from SSM or Secrets Manager, e.g. "${ssm:/my-app/api-key}"
+  environment:
+    LOG_LEVEL: "INFO"
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+    Runtime: "python3.11"
+    FunctionType: "api-integration"
+`
+
+const lambdaHandlerScaffold = `import logging
+import os
+
+logger = logging.getLogger()
+logger.setLevel(os.getenv("LOG_LEVEL", "INFO"))
+
+
+def handler(event, context):
+    logger.info("Received event: %s", event)
+    return {"statusCode": 200, "body": "OK"}
+`
+
+const knowledgeBaseScaffold = `# Knowledge base that agents can query. See docs/resources/knowledge-base.md for every field.
+kind: KnowledgeBase
+metadata:
+  name: "[[.Name]]"
+  description: "Describe the documents in this knowledge base"
+spec:
+  description: "Describe the documents in this knowledge base"
+
+  # Embedding model used to index and search the documents
+  knowledgeBaseConfiguration:
+    type: "VECTOR"
+    vectorKnowledgeBaseConfiguration:
+      embeddingModelArn: "arn:aws:bedrock:us-east-1::foundation-model/amazon.titan-embed-text-v2:0"
+
+  # Vector store; replace the collection ARN, or reference an OpenSearchServerless resource instead
+  storageConfiguration:
+    type: "OPENSEARCH_SERVERLESS"
+    opensearchServerlessConfiguration:
+      collectionArn: "arn:aws:aoss:us-east-1:123456789012:collection/replace-me"
+      vectorIndexName: "bedrock-knowledge-base-index"
+      fieldMapping:
+        vectorField: "vector"
+        textField: "text"
+        metadataField: "metadata"
+
+  # Where the documents come from
+  dataSources:
+    - name: "documents"
+      type: "S3"
+      s3Configuration:
+        bucketArn: "arn:aws:s3:::replace-me"
+        inclusionPrefixes: ["docs/"]
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+    DataSource: "S3"
+    ContentType: "documentation"
+`
+
+const guardrailScaffold = `# Guardrail filtering agent input and output. Attach it to an agent with
+#   guardrail:
+#     name: {ref: [[.Name]]}
+# Topic, word and contextual grounding policies can be added alongside the filters below.
+kind: Guardrail
+metadata:
+  name: "[[.Name]]"
+  description: "Content safety for my agents"
+spec:
+  description: "Content safety for my agents"
+
+  contentPolicyConfig:
+    filtersConfig:
+      - type: "HATE"
+        inputStrength: "HIGH"
+        outputStrength: "HIGH"
+      - type: "VIOLENCE"
+        inputStrength: "HIGH"
+        outputStrength: "HIGH"
+      - type: "PROMPT_ATTACK"
+        inputStrength: "HIGH"
+        outputStrength: "NONE"  # Prompt attack filtering only applies to input
+
+  sensitiveInformationPolicyConfig:
+    piiEntitiesConfig:
+      - type: "EMAIL"
+        action: "ANONYMIZE"
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+`
+
+const promptScaffold = `# Prompt managed in Bedrock prompt management. Use it as an agent prompt override with
+#   promptOverrides:
+#     - promptType: "ORCHESTRATION"
+#       prompt: {ref: [[.Name]]}
+# See docs/resources/prompt.md for every field.
+kind: Prompt
+metadata:
+  name: "[[.Name]]"
+  description: "Describe what this prompt is for"
+spec:
+  description: "Describe what this prompt is for"
+  defaultVariant: "default"
+
+  # Required: at least one variant with a name and a model
+  variants:
+    - name: "default"
+      modelId: "anthropic.claude-3-5-sonnet-20241022-v2:0"
+      templateType: "TEXT"
+      templateConfiguration:
+        text:
+          text: |
+            Answer the question below concisely.
+
+            Question: {{question}}
+          inputVariables:
+            - name: "question"
+      inferenceConfiguration:
+        text:
+          temperature: 0.2
+          maxTokens: 1024
+
+  tags:
+    Environment: "dev"
+    Project: "my-project"
+`
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/registry"
+	"bedrock-forge/internal/validation"
+)
+
+// LintCommand runs the naming, tagging and security validators and reports findings per file.
+// Unlike generate it needs no module registry and does no packaging.
+type LintCommand struct {
+	logger   *logrus.Logger
+	validate *ValidateCommand
+}
+
+func NewLintCommand(logger *logrus.Logger) *LintCommand {
+	return &LintCommand{
+		logger:   logger,
+		validate: NewValidateCommand(logger),
+	}
+}
+
+// SetValidationProfile selects the built-in validation config ("default" or "enterprise")
+func (c *LintCommand) SetValidationProfile(profile string) error {
+	switch profile {
+	case "":
+		return nil
+	case "default", "enterprise":
+		c.validate.SetValidationProfile(profile)
+		return nil
+	default:
+		return fmt.Errorf("unsupported validation profile '%s', must be one of: default, enterprise", profile)
+	}
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *LintCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.validate.SetTemplateVars(vars)
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *LintCommand) SetOverlayDir(dir string) {
+	c.validate.SetOverlayDir(dir)
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *LintCommand) SetStrictFields(strict bool) {
+	c.validate.SetStrictFields(strict)
+}
+
+func (c *LintCommand) Execute(rootPath string) error {
+	if rootPath == "" {
+		var err error
+		rootPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+
+	v := c.validate
+	dir, err := configDir(rootPath)
+	if err != nil {
+		return err
+	}
+	if err := v.applyProjectConfig(dir); err != nil {
+		return err
+	}
+	if err := v.initializeValidator(dir); err != nil {
+		return fmt.Errorf("failed to initialize validator: %w", err)
+	}
+
+	if err := v.scanCommand.loadResources(rootPath); err != nil {
+		return fmt.Errorf("failed to scan resources: %w", err)
+	}
+
+	resourceRegistry := v.scanCommand.GetRegistry()
+	result := v.validator.ValidateRegistry(resourceRegistry, v.validationContext(dir))
+
+	c.printFindings(result, resourceRegistry)
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("lint found %d errors", len(result.Errors))
+	}
+
+	return nil
+}
+
+// printFindings prints errors and warnings grouped by the file that defines the resource
+func (c *LintCommand) printFindings(result *validation.ValidationResult, resourceRegistry *registry.ResourceRegistry) {
+	// Findings name resources as Kind/name; map them back to their source files
+	resourceFiles := make(map[string]string)
+	for kind, resources := range resourceRegistry.GetAllResources() {
+		for name, resource := range resources {
+			resourceFiles[fmt.Sprintf("%s/%s", kind, name)] = c.validate.scanCommand.getRelativePath(resource.FilePath)
+		}
+	}
+
+	findingsByFile := make(map[string][]validation.ValidationError)
+	for _, finding := range append(append([]validation.ValidationError{}, result.Errors...), result.Warnings...) {
+		file, ok := resourceFiles[finding.Resource]
+		if !ok {
+			file = "(project)"
+		}
+		findingsByFile[file] = append(findingsByFile[file], finding)
+	}
+
+	files := make([]string, 0, len(findingsByFile))
+	for file := range findingsByFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		findings := findingsByFile[file]
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].Severity != findings[j].Severity {
+				return findings[i].Severity == "error"
+			}
+			return findings[i].Resource < findings[j].Resource
+		})
+
+		fmt.Printf("%s\n", file)
+		for _, finding := range findings {
+			icon := "⚠️ "
+			if finding.Severity == "error" {
+				icon = "❌"
+			}
+			fmt.Printf("  %s %s [%s] %s", icon, finding.Resource, finding.Type, finding.Message)
+			if finding.Field != "" {
+				fmt.Printf(" (%s)", finding.Field)
+			}
+			fmt.Printf("\n")
+		}
+		fmt.Printf("\n")
+	}
+
+	fmt.Printf("%d errors, %d warnings in %d files (%d resources checked)\n",
+		len(result.Errors), len(result.Warnings), len(files), result.TotalResources)
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+)
+
+// applyOverlays parses the YAML files under overlayDir and merges each resource over the base resource
+// with the same kind and name. Every problem is logged before an error is returned.
+func applyOverlays(logger *logrus.Logger, overlayDir string, resourceRegistry *registry.ResourceRegistry, yamlParser *parser.YAMLParser) error {
+	info, err := os.Stat(overlayDir)
+	if err != nil {
+		return fmt.Errorf("failed to read overlay directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("overlay %s is not a directory", overlayDir)
+	}
+
+	var problems []error
+	err = filepath.Walk(overlayDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isYAMLFile(path) {
+			return nil
+		}
+
+		resources, err := yamlParser.ParseOverlayFile(path)
+		if err != nil {
+			problems = append(problems, err)
+			return nil
+		}
+		for _, resource := range resources {
+			if err := resourceRegistry.ApplyOverlay(resource, yamlParser); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan overlay directory: %w", err)
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			logger.WithError(problem).Error("Overlay error")
+		}
+		return fmt.Errorf("found %d overlay errors", len(problems))
+	}
+	return nil
+}
+
+// isWithinOverlayDir reports whether path is the overlay directory or inside it. Overlays under the scan
+// path are excluded from the base scan.
+func isWithinOverlayDir(path, overlayDir string) bool {
+	if overlayDir == "" {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absOverlay, err := filepath.Abs(overlayDir)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(absOverlay, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
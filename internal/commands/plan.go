@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PlanCommand struct {
+	logger          *logrus.Logger
+	terraformBinary string
+	templateVars    map[string]interface{}
+	overlayDir      string
+	strictFields    bool
+}
+
+func NewPlanCommand(logger *logrus.Logger) *PlanCommand {
+	return &PlanCommand{
+		logger:          logger,
+		terraformBinary: "terraform",
+	}
+}
+
+// SetTerraformBinary overrides the terraform binary used to run init and plan
+func (c *PlanCommand) SetTerraformBinary(binary string) {
+	if binary != "" {
+		c.terraformBinary = binary
+	}
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (c *PlanCommand) SetTemplateVars(vars map[string]interface{}) {
+	c.templateVars = vars
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (c *PlanCommand) SetOverlayDir(dir string) {
+	c.overlayDir = dir
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (c *PlanCommand) SetStrictFields(strict bool) {
+	c.strictFields = strict
+}
+
+func (c *PlanCommand) Execute(scanPath, outputDir string) error {
+	// Use './outputs_tf' as default output directory
+	if outputDir == "" {
+		outputDir = "outputs_tf"
+	}
+
+	// Generate Terraform configuration first
+	generateCommand := NewGenerateCommand(c.logger)
+	generateCommand.SetTemplateVars(c.templateVars)
+	generateCommand.SetOverlayDir(c.overlayDir)
+	generateCommand.SetStrictFields(c.strictFields)
+	if err := generateCommand.Execute(scanPath, outputDir); err != nil {
+		return fmt.Errorf("failed to generate Terraform configuration: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"binary":     c.terraformBinary,
+		"output_dir": outputDir,
+	}).Info("Running Terraform plan...")
+
+	if err := c.runTerraform(outputDir, "init", "-input=false", "-no-color"); err != nil {
+		return fmt.Errorf("terraform init failed: %w", err)
+	}
+
+	if err := c.runTerraform(outputDir, "plan", "-input=false", "-no-color"); err != nil {
+		return fmt.Errorf("terraform plan failed: %w", err)
+	}
+
+	c.logger.Info("Terraform plan completed successfully")
+	return nil
+}
+
+// runTerraform executes a terraform subcommand in the output directory, streaming its output through the logger
+func (c *PlanCommand) runTerraform(dir string, args ...string) error {
+	cmd := exec.Command(c.terraformBinary, args...)
+	cmd.Dir = dir
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to capture stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to capture stderr: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", c.terraformBinary, err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go c.streamOutput(&wg, stdout, args[0], logrus.InfoLevel)
+	go c.streamOutput(&wg, stderr, args[0], logrus.ErrorLevel)
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%s exited with code %d", c.terraformBinary, exitErr.ExitCode())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// streamOutput logs each line read from a terraform output stream
+func (c *PlanCommand) streamOutput(wg *sync.WaitGroup, reader io.Reader, subcommand string, level logrus.Level) {
+	defer wg.Done()
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		c.logger.WithField("terraform", subcommand).Log(level, scanner.Text())
+	}
+}
@@ -1,31 +1,133 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
+	"bedrock-forge/internal/generator"
 	"bedrock-forge/internal/models"
 	"bedrock-forge/internal/parser"
 	"bedrock-forge/internal/registry"
+	"bedrock-forge/internal/validation"
 )
 
+// defaultExcludePatterns are directories never searched for resource files
+var defaultExcludePatterns = []string{
+	"**/node_modules/**",
+	"**/.git/**",
+	"**/.terraform/**",
+	"**/vendor/**",
+	"**/.vscode/**",
+	"**/.idea/**",
+}
+
 type ScanCommand struct {
-	logger     *logrus.Logger
-	scanner    *parser.Scanner
-	yamlParser *parser.YAMLParser
-	registry   *registry.ResourceRegistry
+	logger      *logrus.Logger
+	scanner     *parser.Scanner
+	yamlParser  *parser.YAMLParser
+	registry    *registry.ResourceRegistry
+	format      string
+	selector    *registry.LabelSelector
+	concurrency int
+
+	templateVars map[string]interface{}
+	overlayDir   string
+	strictFields bool
+}
+
+// fileParseResult holds the outcome of parsing a single file
+type fileParseResult struct {
+	filePath  string
+	resources []*parser.ParsedResource
+	err       error
+
+	// documentErr holds the invalid resource documents of a file whose other resources were parsed
+	documentErr error
+}
+
+// ScannedResource is the machine-readable representation of a discovered resource
+type ScannedResource struct {
+	Kind         models.ResourceKind          `json:"kind"`
+	Name         string                       `json:"name"`
+	FilePath     string                       `json:"filePath"`
+	Dependencies []registry.ResourceReference `json:"dependencies"`
 }
 
 func NewScanCommand(logger *logrus.Logger) *ScanCommand {
 	return &ScanCommand{
-		logger:     logger,
-		scanner:    parser.NewScanner(logger),
-		yamlParser: parser.NewYAMLParser(logger),
-		registry:   registry.NewResourceRegistry(logger),
+		logger:      logger,
+		scanner:     parser.NewScanner(logger),
+		yamlParser:  parser.NewYAMLParser(logger),
+		registry:    registry.NewResourceRegistry(logger),
+		format:      "text",
+		concurrency: runtime.NumCPU(),
+	}
+}
+
+// SetOutputFormat sets the scan output format ("text" or "json")
+func (s *ScanCommand) SetOutputFormat(format string) error {
+	switch format {
+	case "", "text":
+		s.format = "text"
+	case "json":
+		s.format = "json"
+	default:
+		return fmt.Errorf("unsupported output format '%s', must be one of: text, json", format)
+	}
+	return nil
+}
+
+// SetSelector limits the scan to resources matching a label selector, plus their dependencies
+func (s *ScanCommand) SetSelector(selector string) error {
+	if selector == "" {
+		s.selector = nil
+		return nil
+	}
+
+	parsed, err := registry.ParseLabelSelector(selector)
+	if err != nil {
+		return err
+	}
+	s.selector = parsed
+	return nil
+}
+
+// SetConcurrency sets how many files are parsed in parallel; 0 uses the number of CPUs
+func (s *ScanCommand) SetConcurrency(concurrency int) error {
+	if concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", concurrency)
+	}
+	if concurrency == 0 {
+		concurrency = runtime.NumCPU()
 	}
+	s.concurrency = concurrency
+	return nil
+}
+
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (s *ScanCommand) SetTemplateVars(vars map[string]interface{}) {
+	s.templateVars = vars
+	s.yamlParser.SetTemplateVars(vars)
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (s *ScanCommand) SetOverlayDir(dir string) {
+	s.overlayDir = dir
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (s *ScanCommand) SetStrictFields(strict bool) {
+	s.strictFields = strict
+	s.yamlParser.SetStrictFields(strict)
 }
 
 func (s *ScanCommand) Execute(rootPath string) error {
@@ -37,42 +139,214 @@ func (s *ScanCommand) Execute(rootPath string) error {
 		}
 	}
 
-	s.logger.WithField("path", rootPath).Info("Starting resource scan")
+	if err := s.loadResources(rootPath); err != nil {
+		return err
+	}
 
-	excludePatterns := []string{
-		"**/node_modules/**",
-		"**/.git/**",
-		"**/.terraform/**",
-		"**/vendor/**",
-		"**/.vscode/**",
-		"**/.idea/**",
+	if s.format == "json" {
+		if err := s.printScanResultsJSON(); err != nil {
+			return fmt.Errorf("failed to print scan results: %w", err)
+		}
+	} else {
+		s.printScanResults()
 	}
 
-	scanResult, err := s.scanner.ScanDirectory(rootPath, nil, excludePatterns)
-	if err != nil {
-		return fmt.Errorf("failed to scan directory: %w", err)
+	if collisions := s.checkNameCollisions(); len(collisions) > 0 {
+		// Keep stdout a valid JSON document for scripts
+		if s.format == "json" {
+			s.printNameCollisions(os.Stderr, collisions)
+		} else {
+			s.printNameCollisions(os.Stdout, collisions)
+		}
+		return fmt.Errorf("found %d resource name collisions", len(collisions))
 	}
 
-	s.logger.WithField("files", len(scanResult.Files)).Info("Found YAML files")
+	return nil
+}
 
-	for _, filePath := range scanResult.Files {
-		err := s.processFile(filePath)
+// loadResources scans rootPath, or stdin for StdinPath, and adds the parsed resources to the registry
+// without printing them
+func (s *ScanCommand) loadResources(rootPath string) error {
+	if rootPath == StdinPath {
+		s.logger.Info("Reading resources from stdin")
+		resources, err := parseStdin(s.yamlParser)
+		if err != nil && !isDocumentError(err) {
+			return err
+		}
+		s.addResources(stdinFilePath, resources)
 		if err != nil {
-			s.logger.WithError(err).WithField("file", filePath).Warn("Failed to process file")
+			return reportDocumentErrors(s.logger, []error{err})
+		}
+	} else if err := s.loadFiles(rootPath); err != nil {
+		return err
+	}
+
+	if s.overlayDir != "" {
+		if err := applyOverlays(s.logger, s.overlayDir, s.registry, s.yamlParser); err != nil {
+			return err
 		}
 	}
 
-	s.printScanResults()
+	if s.selector != nil {
+		kept := s.registry.FilterBySelector(s.selector)
+		s.logger.WithField("resources", kept).Info("Filtered resources by selector")
+	}
 
 	return nil
 }
 
-func (s *ScanCommand) processFile(filePath string) error {
-	resources, err := s.yamlParser.ParseFile(filePath)
+// loadFiles adds the resources of the YAML files under rootPath to the registry
+func (s *ScanCommand) loadFiles(rootPath string) error {
+	s.logger.WithField("path", rootPath).Info("Starting resource scan")
+
+	scanResult, err := s.scanner.ScanDirectory(rootPath, nil, defaultExcludePatterns)
 	if err != nil {
-		return fmt.Errorf("failed to parse file %s: %w", filePath, err)
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	// Overlays are applied after the base resources are loaded
+	files := scanResult.Files
+	if s.overlayDir != "" {
+		files = files[:0]
+		for _, file := range scanResult.Files {
+			if !isWithinOverlayDir(file, s.overlayDir) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	s.logger.WithField("files", len(files)).Info("Found YAML files")
+
+	var documentErrs []error
+	for _, result := range s.parseFiles(files) {
+		if result.err != nil {
+			s.logger.WithError(result.err).WithField("file", result.filePath).Warn("Failed to process file")
+			continue
+		}
+		s.addResources(result.filePath, result.resources)
+		if result.documentErr != nil {
+			documentErrs = append(documentErrs, result.documentErr)
+		}
 	}
 
+	return reportDocumentErrors(s.logger, documentErrs)
+}
+
+// checkNameCollisions reports resources of different kinds whose Terraform labels would collide
+func (s *ScanCommand) checkNameCollisions() []validation.ValidationError {
+	var resources []*parser.ParsedResource
+	for _, kindResources := range s.registry.GetAllResources() {
+		for _, resource := range kindResources {
+			resources = append(resources, resource)
+		}
+	}
+
+	// Sort for deterministic reporting
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].Metadata.Name < resources[j].Metadata.Name
+	})
+
+	errors := []validation.ValidationError{}
+	seen := make(map[string]*parser.ParsedResource)
+
+	for _, resource := range resources {
+		label := generator.SanitizeResourceName(resource.Metadata.Name)
+
+		existing, exists := seen[label]
+		if !exists {
+			seen[label] = resource
+			continue
+		}
+
+		errors = append(errors, validation.ValidationError{
+			Type: "name_collision",
+			Message: fmt.Sprintf("%s/%s (%s) and %s/%s (%s) both generate the Terraform label '%s'; rename one of them, e.g. '%s-%s'",
+				existing.Kind, existing.Metadata.Name, s.getRelativePath(existing.FilePath),
+				resource.Kind, resource.Metadata.Name, s.getRelativePath(resource.FilePath),
+				label, resource.Metadata.Name, strings.ToLower(string(resource.Kind))),
+			Resource: fmt.Sprintf("%s/%s", resource.Kind, resource.Metadata.Name),
+			Field:    "metadata.name",
+			Severity: "error",
+		})
+	}
+
+	return errors
+}
+
+func (s *ScanCommand) printNameCollisions(w io.Writer, collisions []validation.ValidationError) {
+	fmt.Fprintf(w, "❌ Found %d resource name collisions:\n\n", len(collisions))
+
+	for i, collision := range collisions {
+		fmt.Fprintf(w, "   %d. [%s] %s\n", i+1, collision.Type, collision.Message)
+		fmt.Fprintf(w, "      Resource: %s\n", collision.Resource)
+		fmt.Fprintf(w, "      Field: %s\n\n", collision.Field)
+	}
+}
+
+// parseFiles parses files with a bounded worker pool and returns the results sorted by file path
+func (s *ScanCommand) parseFiles(files []string) []fileParseResult {
+	results := make([]fileParseResult, len(files))
+
+	workers := s.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	// Single-worker scans reuse the command's parser
+	if workers <= 1 {
+		for i, filePath := range files {
+			results[i] = s.parseFile(s.yamlParser, filePath)
+		}
+		return sortParseResults(results)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// YAMLParser keeps per-file state, so each worker needs its own
+			yamlParser := parser.NewYAMLParser(s.logger)
+			yamlParser.SetTemplateVars(s.templateVars)
+			yamlParser.SetStrictFields(s.strictFields)
+			for i := range indexes {
+				results[i] = s.parseFile(yamlParser, files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return sortParseResults(results)
+}
+
+func (s *ScanCommand) parseFile(yamlParser *parser.YAMLParser, filePath string) fileParseResult {
+	resources, err := yamlParser.ParseFile(filePath)
+	if err != nil && !isDocumentError(err) {
+		return fileParseResult{filePath: filePath, err: fmt.Errorf("failed to parse file %s: %w", filePath, err)}
+	}
+	return fileParseResult{filePath: filePath, resources: resources, documentErr: err}
+}
+
+func sortParseResults(results []fileParseResult) []fileParseResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].filePath < results[j].filePath
+	})
+	return results
+}
+
+func (s *ScanCommand) addResources(filePath string, resources []*parser.ParsedResource) {
 	for _, resource := range resources {
 		err := s.registry.AddResource(resource)
 		if err != nil {
@@ -83,8 +357,6 @@ func (s *ScanCommand) processFile(filePath string) error {
 			}).Warn("Failed to add resource to registry")
 		}
 	}
-
-	return nil
 }
 
 func (s *ScanCommand) printScanResults() {
@@ -103,6 +375,7 @@ func (s *ScanCommand) printScanResults() {
 	resourceKinds := []models.ResourceKind{
 		models.AgentKind,
 		models.LambdaKind,
+		models.LambdaLayerKind,
 		models.ActionGroupKind,
 		models.KnowledgeBaseKind,
 		models.OpenSearchServerlessKind,
@@ -133,6 +406,42 @@ func (s *ScanCommand) printScanResults() {
 	}
 }
 
+// printScanResultsJSON prints the discovered resources as a JSON array sorted by kind and name
+func (s *ScanCommand) printScanResultsJSON() error {
+	results := []ScannedResource{}
+
+	for _, resources := range s.registry.GetAllResources() {
+		for _, resource := range resources {
+			dependencies := s.registry.GetResourceReferences(resource)
+			if dependencies == nil {
+				dependencies = []registry.ResourceReference{}
+			}
+
+			results = append(results, ScannedResource{
+				Kind:         resource.Kind,
+				Name:         resource.Metadata.Name,
+				FilePath:     s.getRelativePath(resource.FilePath),
+				Dependencies: dependencies,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	output, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
 func (s *ScanCommand) getRelativePath(filePath string) string {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeSyntheticTree writes a tree of dirs directories holding filesPerDir resource files each
+func writeSyntheticTree(b *testing.B, dirs, filesPerDir int) []string {
+	b.Helper()
+
+	root := b.TempDir()
+	var files []string
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("team-%03d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for f := 0; f < filesPerDir; f++ {
+			name := fmt.Sprintf("agent-%03d-%03d", d, f)
+			content := fmt.Sprintf(`kind: Agent
+metadata:
+  name: %s
+  description: Synthetic agent for scan benchmarks
+  labels:
+    team: team-%03d
+spec:
+  foundationModel: "anthropic.claude-3-sonnet-20240229-v1:0"
+  instruction: |
+    You are a helpful assistant for team %03d. Answer questions about orders,
+    products and policies, and explain what you searched for when you can't help.
+  idleSessionTtl: 1800
+  tags:
+    Team: team-%03d
+    Environment: dev
+---
+kind: Lambda
+metadata:
+  name: %s-handler
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    source: directory
+    path: ./src
+  timeout: 30
+  memorySize: 256
+  environment:
+    AGENT: %s
+`, name, d, d, d, name, name)
+			path := filepath.Join(dir, name+".yml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				b.Fatal(err)
+			}
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+func BenchmarkParseFiles(b *testing.B) {
+	files := writeSyntheticTree(b, 20, 25)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	levels := []int{1, 4}
+	if runtime.NumCPU() > 4 {
+		levels = append(levels, runtime.NumCPU())
+	}
+
+	for _, concurrency := range levels {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			scan := NewScanCommand(logger)
+			if err := scan.SetConcurrency(concurrency); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, result := range scan.parseFiles(files) {
+					if result.err != nil {
+						b.Fatal(result.err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// captureOutput runs fn with stdout and stderr redirected and returns what it wrote to each
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	capture := func(target **os.File) (func() string, error) {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		original := *target
+		*target = writer
+
+		output := make(chan string)
+		go func() {
+			content, _ := io.ReadAll(reader)
+			output <- string(content)
+		}()
+		return func() string {
+			writer.Close()
+			*target = original
+			return <-output
+		}, nil
+	}
+
+	stdout, err := capture(&os.Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := capture(&os.Stderr)
+	if err != nil {
+		stdout()
+		t.Fatal(err)
+	}
+
+	fn()
+	return stdout(), stderr()
+}
+
+func TestScanJSONOutputWithNameCollisions(t *testing.T) {
+	dir := t.TempDir()
+	content := `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+---
+kind: Guardrail
+metadata:
+  name: order_lookup
+spec:
+  blockedInputMessaging: Blocked
+  blockedOutputsMessaging: Blocked
+`
+	if err := os.WriteFile(filepath.Join(dir, "resources.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	scan := NewScanCommand(logger)
+	if err := scan.SetOutputFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var scanErr error
+	stdout, stderr := captureOutput(t, func() {
+		scanErr = scan.Execute(dir)
+	})
+
+	if scanErr == nil || !strings.Contains(scanErr.Error(), "1 resource name collisions") {
+		t.Errorf("Execute() = %v, want the collision error", scanErr)
+	}
+
+	var resources []ScannedResource
+	if err := json.Unmarshal([]byte(stdout), &resources); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(resources) != 2 {
+		t.Errorf("expected 2 resources, got %d", len(resources))
+	}
+	if !strings.Contains(stderr, "Found 1 resource name collisions") {
+		t.Errorf("collisions not reported on stderr:\n%s", stderr)
+	}
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+)
+
+// StdinPath is the scan path that reads resources from a multi-document YAML stream on stdin
+const StdinPath = "-"
+
+// stdinFilePath is the file path reported for resources read from stdin
+const stdinFilePath = "<stdin>"
+
+// parseStdin parses the resources in the YAML stream on stdin
+func parseStdin(yamlParser *parser.YAMLParser) ([]*parser.ParsedResource, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return yamlParser.ParseContent(content, stdinFilePath)
+}
+
+// configDir returns the directory bedrock-forge.yaml and validation.yml are loaded from: the scan
+// path, or the working directory when resources are read from stdin
+func configDir(scanPath string) (string, error) {
+	if scanPath != StdinPath {
+		return scanPath, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return dir, nil
+}
+
+// checkStdinSources returns an error naming the resources whose files are found relative to their own
+// directory, which resources read from stdin don't have
+func checkStdinSources(resourceRegistry *registry.ResourceRegistry) error {
+	var problems []string
+
+	for _, lambda := range resourceRegistry.GetResourcesByType(models.LambdaKind) {
+		spec, ok := lambda.Spec.(models.LambdaSpec)
+		if ok && spec.Code.IsSourceDirectory() {
+			problems = append(problems, fmt.Sprintf("Lambda %s uses code.source: %s", lambda.Metadata.Name, spec.Code.Source))
+		}
+	}
+
+	for _, actionGroup := range resourceRegistry.GetResourcesByType(models.ActionGroupKind) {
+		spec, ok := actionGroup.Spec.(models.ActionGroupSpec)
+		if ok && spec.APISchema != nil && len(spec.APISchema.SchemaFiles) > 0 {
+			problems = append(problems, fmt.Sprintf("ActionGroup %s uses apiSchema.schemaFiles", actionGroup.Metadata.Name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("resources read from stdin have no directory to load files from, use inline, zipFile or s3Bucket Lambda code and inline or S3 schemas instead: %s", strings.Join(problems, "; "))
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"bedrock-forge/internal/validation"
+	"bedrock-forge/pkg/config"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
@@ -16,7 +17,10 @@ type ValidateCommand struct {
 	scanCommand       *ScanCommand
 	validator         *validation.Validator
 	configPath        string
-	validationProfile string // "default", "enterprise", "custom"
+	lambdaKmsKeyArn   string          // Project-wide default from bedrock-forge.yaml
+	providers         map[string]bool // Named providers declared in bedrock-forge.yaml
+	validationProfile string          // "default", "enterprise", "custom"
+	profileSet        bool            // Explicitly set profiles take precedence over bedrock-forge.yaml
 }
 
 func NewValidateCommand(logger *logrus.Logger) *ValidateCommand {
@@ -30,6 +34,7 @@ func NewValidateCommand(logger *logrus.Logger) *ValidateCommand {
 // SetValidationProfile sets the validation profile to use
 func (v *ValidateCommand) SetValidationProfile(profile string) {
 	v.validationProfile = profile
+	v.profileSet = true
 }
 
 // SetConfigPath sets the path to a custom validation configuration file
@@ -37,6 +42,21 @@ func (v *ValidateCommand) SetConfigPath(configPath string) {
 	v.configPath = configPath
 }
 
+// SetTemplateVars sets the values rendered into ${{ }} template actions in YAML files
+func (v *ValidateCommand) SetTemplateVars(vars map[string]interface{}) {
+	v.scanCommand.SetTemplateVars(vars)
+}
+
+// SetOverlayDir merges the resources in YAML files under dir over the base resources with the same kind and name
+func (v *ValidateCommand) SetOverlayDir(dir string) {
+	v.scanCommand.SetOverlayDir(dir)
+}
+
+// SetStrictFields rejects fields the resource types don't define instead of ignoring them
+func (v *ValidateCommand) SetStrictFields(strict bool) {
+	v.scanCommand.SetStrictFields(strict)
+}
+
 func (v *ValidateCommand) Execute(rootPath string) error {
 	if rootPath == "" {
 		var err error
@@ -48,8 +68,16 @@ func (v *ValidateCommand) Execute(rootPath string) error {
 
 	v.logger.WithField("path", rootPath).Info("Starting comprehensive resource validation")
 
+	dir, err := configDir(rootPath)
+	if err != nil {
+		return err
+	}
+	if err := v.applyProjectConfig(dir); err != nil {
+		return err
+	}
+
 	// Initialize validator with appropriate configuration
-	err := v.initializeValidator(rootPath)
+	err = v.initializeValidator(dir)
 	if err != nil {
 		return fmt.Errorf("failed to initialize validator: %w", err)
 	}
@@ -77,15 +105,8 @@ func (v *ValidateCommand) Execute(rootPath string) error {
 
 	fmt.Printf("Validating %d resources...\n\n", totalResources)
 
-	// Create validation context
-	context := &validation.ValidationContext{
-		Team:        v.extractTeamFromPath(rootPath),
-		Environment: v.extractEnvironmentFromPath(rootPath),
-		Project:     v.extractProjectFromPath(rootPath),
-	}
-
 	// Run comprehensive validation
-	result := v.validator.ValidateRegistry(registry, context)
+	result := v.validator.ValidateRegistry(registry, v.validationContext(dir))
 
 	// Print results
 	result.PrintSummary()
@@ -97,8 +118,62 @@ func (v *ValidateCommand) Execute(rootPath string) error {
 	return nil
 }
 
+// validationContext derives the team, environment and project of rootPath for the validators
+func (v *ValidateCommand) validationContext(rootPath string) *validation.ValidationContext {
+	return &validation.ValidationContext{
+		Team:                   v.extractTeamFromPath(rootPath),
+		Environment:            v.extractEnvironmentFromPath(rootPath),
+		Project:                v.extractProjectFromPath(rootPath),
+		DefaultLambdaKmsKeyArn: v.lambdaKmsKeyArn,
+		Providers:              v.providers,
+	}
+}
+
+// applyProjectConfig fills in the profile and config path from bedrock-forge.yaml unless set explicitly
+func (v *ValidateCommand) applyProjectConfig(rootPath string) error {
+	projectConfig, path, err := config.LoadProjectConfig(rootPath)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return nil
+	}
+
+	v.logger.WithField("config", path).Info("Using project configuration")
+	if !v.profileSet && projectConfig.Validation.Profile != "" {
+		v.validationProfile = projectConfig.Validation.Profile
+	}
+	if v.configPath == "" {
+		v.configPath = projectConfig.Validation.ConfigPath
+	}
+	v.lambdaKmsKeyArn = projectConfig.LambdaKmsKeyArn
+	v.providers = make(map[string]bool, len(projectConfig.Providers))
+	for name := range projectConfig.Providers {
+		v.providers[name] = true
+	}
+
+	return nil
+}
+
 // initializeValidator creates a validator with the appropriate configuration
 func (v *ValidateCommand) initializeValidator(rootPath string) error {
+	config, err := v.resolveValidationConfig(rootPath)
+	if err != nil {
+		return err
+	}
+
+	// Create validator
+	v.validator, err = validation.NewValidator(v.logger, config)
+	if err != nil {
+		return fmt.Errorf("failed to create validator: %w", err)
+	}
+
+	return nil
+}
+
+// resolveValidationConfig returns the custom configuration, a local validation.yml or the built-in
+// configuration of the profile, in that order
+func (v *ValidateCommand) resolveValidationConfig(rootPath string) (*validation.ValidationConfig, error) {
 	var config *validation.ValidationConfig
 	var err error
 
@@ -106,7 +181,7 @@ func (v *ValidateCommand) initializeValidator(rootPath string) error {
 		// Load custom configuration
 		config, err = v.loadCustomConfig(v.configPath)
 		if err != nil {
-			return fmt.Errorf("failed to load custom validation config: %w", err)
+			return nil, fmt.Errorf("failed to load custom validation config: %w", err)
 		}
 		v.logger.WithField("config", v.configPath).Info("Using custom validation configuration")
 	} else {
@@ -127,13 +202,7 @@ func (v *ValidateCommand) initializeValidator(rootPath string) error {
 		}
 	}
 
-	// Create validator
-	v.validator, err = validation.NewValidator(v.logger, config)
-	if err != nil {
-		return fmt.Errorf("failed to create validator: %w", err)
-	}
-
-	return nil
+	return config, nil
 }
 
 // loadCustomConfig loads a custom validation configuration from file
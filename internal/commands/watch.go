@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// watchDebounce is how long changes must settle before regenerating, so saving several files or an
+// editor writing a file in steps triggers a single run
+const watchDebounce = 300 * time.Millisecond
+
+// watchAndGenerate generates once and then again after every change to YAML files under the scan
+// path, until interrupted. Failed runs are reported and watching continues.
+func (c *GenerateCommand) watchAndGenerate(scanPath, outputDir string) error {
+	// Generated files must not trigger regeneration when the output directory is inside the scan path
+	excludeDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	roots := []string{scanPath}
+	if c.overlayDir != "" {
+		roots = append(roots, c.overlayDir)
+	}
+
+	// Watch before the first run so changes made while it runs aren't missed
+	watcher, err := newYAMLWatcher(c.logger, excludeDir, roots...)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	c.regenerate(scanPath, outputDir, "initial run")
+	fmt.Fprintf(os.Stderr, "👀 Watching %s for YAML changes (Ctrl+C to stop)\n", scanPath)
+
+	err = watcher.run(ctx, watchDebounce, func(changed []string) {
+		c.logger.WithField("files", changed).Debug("Detected YAML changes")
+		c.regenerate(scanPath, outputDir, "YAML files changed")
+	})
+	fmt.Fprintf(os.Stderr, "Stopped watching %s\n", scanPath)
+	return err
+}
+
+// regenerate runs one generation pass and prints a banner with its outcome
+func (c *GenerateCommand) regenerate(scanPath, outputDir, reason string) {
+	fmt.Fprintf(os.Stderr, "\n=== Regenerating at %s (%s) ===\n", time.Now().Format("15:04:05"), reason)
+
+	if err := c.generate(scanPath, outputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Generation failed: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "✅ Generated Terraform in %s\n", outputDir)
+}
+
+// yamlWatcher reports changes to YAML files in directory trees, including directories created while
+// watching, except under excludeDir
+type yamlWatcher struct {
+	logger     *logrus.Logger
+	watcher    *fsnotify.Watcher
+	excludeDir string
+}
+
+// newYAMLWatcher starts watching every directory under the roots, skipping excludeDir
+func newYAMLWatcher(logger *logrus.Logger, excludeDir string, roots ...string) (*yamlWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start file watcher: %w", err)
+	}
+
+	w := &yamlWatcher{logger: logger, watcher: watcher, excludeDir: excludeDir}
+	for _, root := range roots {
+		if _, err := w.addRecursive(root); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Close stops watching
+func (w *yamlWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// addRecursive watches root and every directory below it, returning the YAML files already in them
+func (w *yamlWatcher) addRecursive(root string) ([]string, error) {
+	var yamlFiles []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Directories may disappear between listing and watching while files are being saved
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !entry.IsDir() {
+			if isYAMLFile(path) {
+				yamlFiles = append(yamlFiles, path)
+			}
+			return nil
+		}
+		if w.excluded(path) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s for changes: %w", root, err)
+	}
+	return yamlFiles, nil
+}
+
+// excluded reports whether path is the excluded directory or inside it
+func (w *yamlWatcher) excluded(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return absPath == w.excludeDir || strings.HasPrefix(absPath, w.excludeDir+string(filepath.Separator))
+}
+
+// run calls onChange with the changed YAML files, sorted, once no further change has arrived for
+// debounce. It returns when ctx is done.
+func (w *yamlWatcher) run(ctx context.Context, debounce time.Duration, onChange func(changed []string)) error {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	changed := make(map[string]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if w.excluded(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Files may have been written to the new directory before it was watched
+					yamlFiles, err := w.addRecursive(event.Name)
+					if err != nil {
+						w.logger.WithError(err).Warn("Failed to watch new directory")
+					}
+					for _, path := range yamlFiles {
+						changed[path] = true
+					}
+					if len(yamlFiles) > 0 {
+						timer.Reset(debounce)
+					}
+					continue
+				}
+			}
+
+			if isYAMLFile(event.Name) {
+				changed[event.Name] = true
+				timer.Reset(debounce)
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.WithError(err).Warn("File watcher error")
+
+		case <-timer.C:
+			files := make([]string, 0, len(changed))
+			for path := range changed {
+				files = append(files, path)
+			}
+			sort.Strings(files)
+			changed = make(map[string]bool)
+
+			onChange(files)
+		}
+	}
+}
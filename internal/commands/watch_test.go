@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testWatchDebounce = 200 * time.Millisecond
+
+// startWatcher runs a YAML watcher over root until the test ends and returns the batches of changes
+// it reports
+func startWatcher(t *testing.T, root, excludeDir string) <-chan []string {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	excludeDir, err := filepath.Abs(excludeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := newYAMLWatcher(logger, excludeDir, root)
+	if err != nil {
+		t.Fatalf("newYAMLWatcher: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []string, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := watcher.run(ctx, testWatchDebounce, func(changed []string) { batches <- changed }); err != nil {
+			t.Errorf("run: %v", err)
+		}
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		watcher.Close()
+	})
+	return batches
+}
+
+func writeWatchedFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// nextBatch waits for the next batch of changes, failing the test if none arrives
+func nextBatch(t *testing.T, batches <-chan []string) []string {
+	t.Helper()
+
+	select {
+	case batch := <-batches:
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+		return nil
+	}
+}
+
+// expectNoBatch fails the test if a batch of changes arrives within a few debounce periods
+func expectNoBatch(t *testing.T, batches <-chan []string) {
+	t.Helper()
+
+	select {
+	case batch := <-batches:
+		t.Errorf("unexpected changes %v", batch)
+	case <-time.After(3 * testWatchDebounce):
+	}
+}
+
+func TestYAMLWatcherDebouncesBursts(t *testing.T) {
+	root := t.TempDir()
+	batches := startWatcher(t, root, filepath.Join(root, "terraform"))
+
+	// A burst of saves, each well within the debounce period of the previous one
+	agent := filepath.Join(root, "agent.yml")
+	lambda := filepath.Join(root, "lambda.yaml")
+	for i := 0; i < 3; i++ {
+		writeWatchedFile(t, agent, "kind: Agent\n")
+		writeWatchedFile(t, lambda, "kind: Lambda\n")
+		writeWatchedFile(t, filepath.Join(root, "notes.txt"), "not YAML")
+		time.Sleep(testWatchDebounce / 4)
+	}
+
+	if batch, want := nextBatch(t, batches), []string{agent, lambda}; !reflect.DeepEqual(batch, want) {
+		t.Errorf("changes = %v, want %v", batch, want)
+	}
+	expectNoBatch(t, batches)
+
+	// Removing a file is a change too
+	if err := os.Remove(agent); err != nil {
+		t.Fatal(err)
+	}
+	if batch, want := nextBatch(t, batches), []string{agent}; !reflect.DeepEqual(batch, want) {
+		t.Errorf("changes = %v, want %v", batch, want)
+	}
+}
+
+func TestYAMLWatcherWatchesNewDirectories(t *testing.T) {
+	root := t.TempDir()
+	batches := startWatcher(t, root, filepath.Join(root, "terraform"))
+
+	lambda := filepath.Join(root, "lambdas", "order-lookup", "lambda.yml")
+	writeWatchedFile(t, lambda, "kind: Lambda\n")
+
+	if batch := nextBatch(t, batches); !reflect.DeepEqual(batch, []string{lambda}) {
+		t.Errorf("changes = %v, want %v", batch, []string{lambda})
+	}
+}
+
+func TestYAMLWatcherExcludesOutputDirectory(t *testing.T) {
+	root := t.TempDir()
+	outputDir := filepath.Join(root, "terraform")
+	writeWatchedFile(t, filepath.Join(outputDir, "existing.yml"), "kind: Agent\n")
+	batches := startWatcher(t, root, outputDir)
+
+	// Files in the output directory, existing or created while watching, and in new directories below it
+	writeWatchedFile(t, filepath.Join(outputDir, "existing.yml"), "kind: Lambda\n")
+	writeWatchedFile(t, filepath.Join(outputDir, "generated.yaml"), "kind: Lambda\n")
+	writeWatchedFile(t, filepath.Join(outputDir, "modules", "agent.yml"), "kind: Agent\n")
+	expectNoBatch(t, batches)
+
+	agent := filepath.Join(root, "agent.yml")
+	writeWatchedFile(t, agent, "kind: Agent\n")
+	if batch := nextBatch(t, batches); !reflect.DeepEqual(batch, []string{agent}) {
+		t.Errorf("changes = %v, want %v", batch, []string{agent})
+	}
+}
+
+func TestYAMLWatcherExcludesCreatedOutputDirectory(t *testing.T) {
+	root := t.TempDir()
+	outputDir := filepath.Join(root, "terraform")
+	batches := startWatcher(t, root, outputDir)
+
+	// The first generation creates the output directory
+	writeWatchedFile(t, filepath.Join(outputDir, "generated.yml"), "kind: Agent\n")
+	expectNoBatch(t, batches)
+}
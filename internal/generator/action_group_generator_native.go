@@ -0,0 +1,233 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// generateActionGroupNative creates a native aws_bedrockagent_agent_action_group for a standalone ActionGroup resource
+func (g *HCLGenerator) generateActionGroupNative(body *hclwrite.Body, resource models.BaseResource) error {
+	actionGroup, ok := resource.Spec.(models.ActionGroupSpec)
+	if !ok {
+		// Try to parse as map and convert to ActionGroupSpec
+		specMap, mapOk := resource.Spec.(map[string]interface{})
+		if !mapOk {
+			return fmt.Errorf("invalid action group spec format")
+		}
+
+		specJSON, err := json.Marshal(specMap)
+		if err != nil {
+			return fmt.Errorf("failed to marshal action group spec: %w", err)
+		}
+
+		if err := json.Unmarshal(specJSON, &actionGroup); err != nil {
+			return fmt.Errorf("failed to unmarshal action group spec: %w", err)
+		}
+	}
+
+	if actionGroup.AgentId.IsEmpty() {
+		return fmt.Errorf("action group %s must specify agentId", resource.Metadata.Name)
+	}
+	if actionGroup.ActionGroupExecutor == nil {
+		return fmt.Errorf("action group %s must specify actionGroupExecutor", resource.Metadata.Name)
+	}
+
+	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
+
+	agBlock := body.AppendNewBlock("resource", []string{"aws_bedrockagent_agent_action_group", resourceName})
+	agBody := agBlock.Body()
+
+	// Agents defined in this project are referenced natively, anything else is an existing agent ID
+	agentName := actionGroup.AgentId.String()
+	if g.registry.HasResource(models.AgentKind, agentName) {
+		agBody.SetAttributeRaw("agent_id", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_agent.%s.agent_id", g.sanitizeResourceName(agentName)))},
+		})
+	} else {
+		agBody.SetAttributeValue("agent_id", cty.StringVal(agentName))
+		g.logger.WithField("agent", agentName).Debug("Agent not found in registry, using agentId as an existing agent ID")
+	}
+
+	agentVersion := actionGroup.AgentVersion
+	if agentVersion == "" {
+		agentVersion = "DRAFT"
+	}
+	agBody.SetAttributeValue("agent_version", cty.StringVal(agentVersion))
+	agBody.SetAttributeValue("action_group_name", cty.StringVal(resource.Metadata.Name))
+
+	if actionGroup.SkipResourceInUseCheck {
+		agBody.SetAttributeValue("skip_resource_in_use_check", cty.BoolVal(true))
+	}
+
+	if actionGroup.Description != "" {
+		agBody.SetAttributeValue("description", cty.StringVal(actionGroup.Description))
+	}
+
+	if actionGroup.ActionGroupState != "" {
+		agBody.SetAttributeValue("action_group_state", cty.StringVal(actionGroup.ActionGroupState))
+	} else {
+		agBody.SetAttributeValue("action_group_state", cty.StringVal("ENABLED"))
+	}
+
+	if actionGroup.ParentActionGroupSignature != "" {
+		agBody.SetAttributeValue("parent_action_group_signature", cty.StringVal(actionGroup.ParentActionGroupSignature))
+	}
+
+	if prepareAgent := g.actionGroupPrepareAgent(actionGroup); prepareAgent != nil {
+		agBody.SetAttributeValue("prepare_agent", cty.BoolVal(*prepareAgent))
+	}
+
+	if err := g.setActionGroupExecutorNative(agBody, resourceKey(resource.Kind, resource.Metadata.Name), actionGroup.ActionGroupExecutor); err != nil {
+		return err
+	}
+
+	if actionGroup.APISchema != nil {
+		apiSchema := *actionGroup.APISchema
+
+		if apiSchema.IsManaged() {
+			if apiSchema.S3.S3BucketName == "" || apiSchema.S3.S3ObjectKey == "" {
+				return fmt.Errorf("action group %s: managed apiSchema.s3 requires s3BucketName and s3ObjectKey", resource.Metadata.Name)
+			}
+		} else if bucket, key := g.context.GetSchemaS3Location(resource.Metadata.Name); bucket != "" && key != "" {
+			// Prefer the S3 location of the packaged schema when one was extracted
+			apiSchema.S3 = &models.S3APISchema{S3BucketName: bucket, S3ObjectKey: key}
+			g.logger.WithFields(logrus.Fields{
+				"action_group": resource.Metadata.Name,
+				"bucket":       bucket,
+				"key":          key,
+			}).Debug("Using packaged schema S3 location")
+		}
+
+		g.setAPISchemaNative(agBody, &apiSchema)
+	}
+
+	if actionGroup.FunctionSchema != nil {
+		g.setFunctionSchemaNative(agBody, actionGroup.FunctionSchema)
+	}
+
+	if actionGroup.Timeouts != nil {
+		timeoutsBlock := agBody.AppendNewBlock("timeouts", nil)
+		timeoutsBody := timeoutsBlock.Body()
+		if actionGroup.Timeouts.Create != "" {
+			timeoutsBody.SetAttributeValue("create", cty.StringVal(actionGroup.Timeouts.Create))
+		}
+		if actionGroup.Timeouts.Update != "" {
+			timeoutsBody.SetAttributeValue("update", cty.StringVal(actionGroup.Timeouts.Update))
+		}
+		if actionGroup.Timeouts.Delete != "" {
+			timeoutsBody.SetAttributeValue("delete", cty.StringVal(actionGroup.Timeouts.Delete))
+		}
+	}
+
+	// aws_bedrockagent_agent_action_group has no tags argument
+	if len(actionGroup.Tags) > 0 {
+		g.logger.WithField("action_group", resource.Metadata.Name).Warn("Action groups do not support tags, ignoring spec.tags")
+	}
+
+	body.AppendNewline()
+
+	g.logger.WithField("action_group", resource.Metadata.Name).Info("Generated native action group")
+	return nil
+}
+
+// actionGroupPrepareAgent returns the prepare_agent value of an action group: its own setting, or false
+// when the versioning of its agent doesn't prepare on action group changes
+func (g *HCLGenerator) actionGroupPrepareAgent(actionGroup models.ActionGroupSpec) *bool {
+	if actionGroup.PrepareAgent != nil {
+		return actionGroup.PrepareAgent
+	}
+
+	resource, exists := g.registry.GetResource(models.AgentKind, actionGroup.AgentId.String())
+	if !exists {
+		return nil
+	}
+	if agent, ok := resource.Resource.(*models.Agent); ok && !agent.Spec.Versioning.PreparesOnActionGroupChange() {
+		prepareAgent := false
+		return &prepareAgent
+	}
+	return nil
+}
+
+// setActionGroupExecutorNative adds an action_group_executor block
+func (g *HCLGenerator) setActionGroupExecutorNative(body *hclwrite.Body, owner string, executor *models.ActionGroupExecutor) error {
+	if executor == nil {
+		return nil
+	}
+
+	target, ok, err := g.resolveLambdaTarget(owner, executor)
+	if err != nil {
+		return err
+	}
+
+	executorBlock := body.AppendNewBlock("action_group_executor", nil)
+	executorBody := executorBlock.Body()
+
+	if ok {
+		executorBody.SetAttributeRaw("lambda", target.tokens())
+	} else if executor.CustomControl != "" {
+		executorBody.SetAttributeValue("custom_control", cty.StringVal(executor.CustomControl))
+	}
+	return nil
+}
+
+// setAPISchemaNative adds an api_schema block
+func (g *HCLGenerator) setAPISchemaNative(body *hclwrite.Body, apiSchema *models.APISchema) {
+	apiSchemaBlock := body.AppendNewBlock("api_schema", nil)
+	apiSchemaBody := apiSchemaBlock.Body()
+
+	if apiSchema.S3 != nil {
+		s3Block := apiSchemaBody.AppendNewBlock("s3", nil)
+		s3Body := s3Block.Body()
+		s3Body.SetAttributeValue("s3_bucket_name", cty.StringVal(apiSchema.S3.S3BucketName))
+		s3Body.SetAttributeValue("s3_object_key", cty.StringVal(apiSchema.S3.S3ObjectKey))
+	} else if apiSchema.Payload != "" {
+		apiSchemaBody.SetAttributeValue("payload", cty.StringVal(apiSchema.Payload))
+	}
+}
+
+// setFunctionSchemaNative adds a function_schema block with its member functions
+func (g *HCLGenerator) setFunctionSchemaNative(body *hclwrite.Body, functionSchema *models.FunctionSchema) {
+	functionSchemaBlock := body.AppendNewBlock("function_schema", nil)
+	functionSchemaBody := functionSchemaBlock.Body()
+
+	memberFunctionsBlock := functionSchemaBody.AppendNewBlock("member_functions", nil)
+	memberFunctionsBody := memberFunctionsBlock.Body()
+
+	for _, fn := range functionSchema.Functions {
+		functionBlock := memberFunctionsBody.AppendNewBlock("functions", nil)
+		functionBody := functionBlock.Body()
+
+		functionBody.SetAttributeValue("name", cty.StringVal(fn.Name))
+		if fn.Description != "" {
+			functionBody.SetAttributeValue("description", cty.StringVal(fn.Description))
+		}
+
+		// Sort parameters for stable output
+		paramNames := make([]string, 0, len(fn.Parameters))
+		for paramName := range fn.Parameters {
+			paramNames = append(paramNames, paramName)
+		}
+		sort.Strings(paramNames)
+
+		for _, paramName := range paramNames {
+			param := fn.Parameters[paramName]
+			paramBlock := functionBody.AppendNewBlock("parameters", nil)
+			paramBody := paramBlock.Body()
+
+			paramBody.SetAttributeValue("map_block_key", cty.StringVal(paramName))
+			paramBody.SetAttributeValue("type", cty.StringVal(param.Type))
+			paramBody.SetAttributeValue("required", cty.BoolVal(param.Required))
+			if param.Description != "" {
+				paramBody.SetAttributeValue("description", cty.StringVal(param.Description))
+			}
+		}
+	}
+}
@@ -3,14 +3,18 @@ package generator
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 
 	"bedrock-forge/internal/models"
 )
 
-// generateAgentAliases creates agent alias resources for an agent
-func (g *HCLGenerator) generateAgentAliases(body *hclwrite.Body, agentName string, aliases []models.AgentAlias) error {
+// generateAgentAliases creates native aws_bedrockagent_agent_alias resources for an agent
+func (g *HCLGenerator) generateAgentAliases(body *hclwrite.Body, agentName string, agent models.AgentSpec, dependsOn []string) error {
+	aliases := agent.Aliases
 	if len(aliases) == 0 {
 		return nil
 	}
@@ -18,28 +22,50 @@ func (g *HCLGenerator) generateAgentAliases(body *hclwrite.Body, agentName strin
 	agentResourceName := g.sanitizeResourceName(agentName)
 
 	for _, alias := range aliases {
-		aliasResourceName := fmt.Sprintf("%s_%s_alias", agentResourceName, g.sanitizeResourceName(alias.Name))
+		if alias.Name == "" {
+			return fmt.Errorf("alias name is required for agent %s", agentName)
+		}
+
+		aliasResourceName := g.agentAliasResourceName(agentName, alias.Name)
 
 		g.logger.WithField("agent", agentName).WithField("alias", alias.Name).Debug("Generating agent alias")
 
-		// Create module block for agent alias
-		moduleBlock := body.AppendNewBlock("module", []string{aliasResourceName})
-		moduleBody := moduleBlock.Body()
-
-		// Set module source
-		moduleSource := fmt.Sprintf("%s//modules/bedrock-agent-alias", g.config.ModuleRegistry)
-		if g.config.ModuleVersion != "" {
-			moduleSource += fmt.Sprintf("?ref=%s", g.config.ModuleVersion)
-		}
-		moduleBody.SetAttributeValue("source", cty.StringVal(moduleSource))
+		// Create native AWS resource block
+		aliasBlock := body.AppendNewBlock("resource", []string{"aws_bedrockagent_agent_alias", aliasResourceName})
+		aliasBody := aliasBlock.Body()
 
 		// Set required attributes
-		moduleBody.SetAttributeValue("agent_alias_name", cty.StringVal(alias.Name))
-		moduleBody.SetAttributeValue("agent_id", cty.StringVal(fmt.Sprintf("${module.%s.agent_id}", agentResourceName)))
+		aliasBody.SetAttributeValue("agent_alias_name", cty.StringVal(alias.Name))
+		aliasBody.SetAttributeRaw("agent_id", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_agent.%s.agent_id", agentResourceName))},
+		})
 
 		// Optional description
 		if alias.Description != "" {
-			moduleBody.SetAttributeValue("description", cty.StringVal(alias.Description))
+			aliasBody.SetAttributeValue("description", cty.StringVal(alias.Description))
+		}
+
+		// Routing configuration pins the alias to specific agent versions
+		for _, routing := range agent.AliasRouting(alias) {
+			if routing.AgentVersion == "" {
+				return fmt.Errorf("routing configuration for alias %s of agent %s requires agentVersion", alias.Name, agentName)
+			}
+
+			routingBlock := aliasBody.AppendNewBlock("routing_configuration", nil)
+			routingBody := routingBlock.Body()
+
+			routingBody.SetAttributeValue("agent_version", cty.StringVal(routing.AgentVersion))
+			if routing.ProvisionedThroughput != "" {
+				routingBody.SetAttributeValue("provisioned_throughput", cty.StringVal(routing.ProvisionedThroughput))
+			}
+		}
+
+		if alias.Guardrail != nil && !alias.Guardrail.Name.IsEmpty() {
+			g.logger.WithFields(logrus.Fields{
+				"agent":     agentName,
+				"alias":     alias.Name,
+				"guardrail": alias.Guardrail.Name.String(),
+			}).Info("Agent aliases can't carry a guardrail, exposing the alias guardrail as outputs for callers to apply")
 		}
 
 		// Tags
@@ -48,13 +74,48 @@ func (g *HCLGenerator) generateAgentAliases(body *hclwrite.Body, agentName strin
 			for key, value := range alias.Tags {
 				tagValues[key] = cty.StringVal(value)
 			}
-			moduleBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
+			aliasBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
 		}
 
+		// Aliases snapshot the agent, so create them after its action groups and collaborators
+		appendDependsOn(aliasBody, dependsOn)
+
 		body.AppendNewline()
 
-		g.logger.WithField("agent", agentName).WithField("alias", alias.Name).Info("Generated agent alias module")
+		g.logger.WithField("agent", agentName).WithField("alias", alias.Name).Info("Generated native agent alias resource")
 	}
 
 	return nil
 }
+
+// agentAliasResourceName returns the Terraform resource name for an agent alias
+func (g *HCLGenerator) agentAliasResourceName(agentName, aliasName string) string {
+	return fmt.Sprintf("%s_%s_alias", g.sanitizeResourceName(agentName), g.sanitizeResourceName(aliasName))
+}
+
+// addAliasGuardrailOutputs adds outputs with the guardrail ID and version callers of an alias apply.
+// Bedrock agent aliases have no guardrail of their own, every alias uses the agent's.
+func (g *HCLGenerator) addAliasGuardrailOutputs(body *hclwrite.Body, agentName string, alias models.AgentAlias) {
+	aliasResourceName := g.agentAliasResourceName(agentName, alias.Name)
+	guardrailName := g.sanitizeResourceName(alias.Guardrail.Name.String())
+
+	idBody := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_guardrail_id", aliasResourceName)}).Body()
+	idBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ID of the guardrail for the %s alias of the %s agent", alias.Name, agentName)))
+	idBody.SetAttributeTraversal("value", hcl.Traversal{
+		hcl.TraverseRoot{Name: "module"},
+		hcl.TraverseAttr{Name: guardrailName},
+		hcl.TraverseAttr{Name: "guardrail_id"},
+	})
+
+	versionBody := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_guardrail_version", aliasResourceName)}).Body()
+	versionBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Version of the guardrail for the %s alias of the %s agent", alias.Name, agentName)))
+	if alias.Guardrail.Version != "" {
+		versionBody.SetAttributeValue("value", cty.StringVal(alias.Guardrail.Version))
+	} else {
+		versionBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: guardrailName},
+			hcl.TraverseAttr{Name: "version"},
+		})
+	}
+}
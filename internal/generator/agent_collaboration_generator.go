@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// isAgentSupervisor reports whether an agent orchestrates collaborator agents
+func isAgentSupervisor(agent models.AgentSpec) bool {
+	return agent.AgentCollaboration != nil &&
+		agent.AgentCollaboration.CollaborationType != "" &&
+		agent.AgentCollaboration.CollaborationType != "DISABLED"
+}
+
+// generateAgentCollaborators creates aws_bedrockagent_agent_collaborator resources for a supervisor agent
+// and returns their Terraform addresses
+func (g *HCLGenerator) generateAgentCollaborators(body *hclwrite.Body, agentName string, collaboration *models.AgentCollaboration) ([]string, error) {
+	agentResourceName := g.sanitizeResourceName(agentName)
+	var addresses []string
+
+	for _, collaborator := range collaboration.Collaborators {
+		aliasArn, err := g.resolveCollaboratorAliasArn(agentName, collaborator)
+		if err != nil {
+			return nil, err
+		}
+
+		collaboratorName := collaborator.Name
+		if collaboratorName == "" {
+			collaboratorName = collaborator.Agent.String()
+		}
+		if collaboratorName == "" {
+			return nil, fmt.Errorf("collaborator of agent %s with aliasArn %s requires a name", agentName, collaborator.AliasArn)
+		}
+		if collaborator.CollaborationInstruction == "" {
+			return nil, fmt.Errorf("collaborator %s of agent %s requires collaborationInstruction", collaboratorName, agentName)
+		}
+
+		collaboratorResourceName := fmt.Sprintf("%s_%s_collaborator", agentResourceName, g.sanitizeResourceName(collaboratorName))
+
+		collaboratorBlock := body.AppendNewBlock("resource", []string{"aws_bedrockagent_agent_collaborator", collaboratorResourceName})
+		collaboratorBody := collaboratorBlock.Body()
+
+		collaboratorBody.SetAttributeRaw("agent_id", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_agent.%s.agent_id", agentResourceName))},
+		})
+		collaboratorBody.SetAttributeValue("agent_version", cty.StringVal("DRAFT"))
+		collaboratorBody.SetAttributeValue("collaborator_name", cty.StringVal(collaboratorName))
+		collaboratorBody.SetAttributeValue("collaboration_instruction", cty.StringVal(collaborator.CollaborationInstruction))
+
+		relayConversationHistory := "DISABLED"
+		if collaborator.RelayConversationHistory {
+			relayConversationHistory = "TO_COLLABORATOR"
+		}
+		collaboratorBody.SetAttributeValue("relay_conversation_history", cty.StringVal(relayConversationHistory))
+
+		descriptorBlock := collaboratorBody.AppendNewBlock("agent_descriptor", nil)
+		descriptorBody := descriptorBlock.Body()
+		if collaborator.AliasArn != "" {
+			descriptorBody.SetAttributeValue("alias_arn", cty.StringVal(aliasArn))
+		} else {
+			descriptorBody.SetAttributeRaw("alias_arn", hclwrite.Tokens{
+				{Type: hclsyntax.TokenIdent, Bytes: []byte(aliasArn)},
+			})
+		}
+
+		body.AppendNewline()
+
+		addresses = append(addresses, fmt.Sprintf("aws_bedrockagent_agent_collaborator.%s", collaboratorResourceName))
+		g.logger.WithField("agent", agentName).WithField("collaborator", collaboratorName).Debug("Generated agent collaborator")
+	}
+
+	return addresses, nil
+}
+
+// resolveCollaboratorAliasArn returns an external alias ARN, or the reference to the alias of a collaborator agent in the project
+func (g *HCLGenerator) resolveCollaboratorAliasArn(agentName string, collaborator models.AgentCollaborator) (string, error) {
+	if collaborator.AliasArn != "" {
+		return collaborator.AliasArn, nil
+	}
+
+	collaboratorAgent := collaborator.Agent.String()
+	if collaboratorAgent == "" {
+		return "", fmt.Errorf("collaborator of agent %s must specify agent or aliasArn", agentName)
+	}
+
+	var collaboratorSpec *models.AgentSpec
+	for _, resource := range g.registry.GetResourcesByType(models.AgentKind) {
+		if resource.Metadata.Name == collaboratorAgent {
+			if spec, ok := resource.Spec.(models.AgentSpec); ok {
+				collaboratorSpec = &spec
+			}
+			break
+		}
+	}
+	if collaboratorSpec == nil {
+		return "", fmt.Errorf("agent %s references non-existent collaborator agent %s", agentName, collaboratorAgent)
+	}
+
+	// Collaborators are invoked through an alias, so the referenced agent must define one
+	if len(collaboratorSpec.Aliases) == 0 {
+		return "", fmt.Errorf("collaborator agent %s of agent %s must define an alias", collaboratorAgent, agentName)
+	}
+
+	aliasName := collaborator.Alias
+	if aliasName == "" {
+		aliasName = collaboratorSpec.Aliases[0].Name
+	}
+
+	for _, alias := range collaboratorSpec.Aliases {
+		if alias.Name == aliasName {
+			return fmt.Sprintf("aws_bedrockagent_agent_alias.%s.agent_alias_arn", g.agentAliasResourceName(collaboratorAgent, aliasName)), nil
+		}
+	}
+
+	return "", fmt.Errorf("collaborator agent %s of agent %s has no alias named %s", collaboratorAgent, agentName, aliasName)
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -33,6 +34,10 @@ func (g *HCLGenerator) generateAgentNative(body *hclwrite.Body, resource models.
 		}
 	}
 
+	if err := agent.ValidateFoundationModel(); err != nil {
+		return fmt.Errorf("agent %s: %w", resource.Metadata.Name, err)
+	}
+
 	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
 
 	// Generate IAM role for the agent if not provided by user
@@ -46,7 +51,9 @@ func (g *HCLGenerator) generateAgentNative(body *hclwrite.Body, resource models.
 
 	// Set basic attributes according to AWS provider schema
 	resourceBody.SetAttributeValue("agent_name", cty.StringVal(resource.Metadata.Name))
-	resourceBody.SetAttributeValue("foundation_model", cty.StringVal(agent.FoundationModel))
+	if err := g.setStringOrCustomOutput(resourceBody, "foundation_model", agent.EffectiveFoundationModel()); err != nil {
+		return fmt.Errorf("agent %s provisionedThroughput: %w", resource.Metadata.Name, err)
+	}
 	resourceBody.SetAttributeValue("instruction", cty.StringVal(agent.Instruction))
 
 	// IAM role reference - handle both auto-generated and user-provided roles
@@ -76,9 +83,36 @@ func (g *HCLGenerator) generateAgentNative(body *hclwrite.Body, resource models.
 		resourceBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
 	}
 
+	// Multi-agent collaboration
+	if agent.AgentCollaboration != nil && agent.AgentCollaboration.CollaborationType != "" {
+		resourceBody.SetAttributeValue("agent_collaboration", cty.StringVal(agent.AgentCollaboration.CollaborationType))
+	}
+
+	// Memory configuration
+	if agent.MemoryConfiguration != nil {
+		if err := agent.MemoryConfiguration.Validate(); err != nil {
+			return fmt.Errorf("invalid memory configuration for agent %s: %w", resource.Metadata.Name, err)
+		}
+		setAgentMemoryConfiguration(resourceBody, *agent.MemoryConfiguration)
+	}
+
+	// Prompt overrides use the templates of the prompt versions they pin
+	if err := g.setAgentPromptOverrides(resourceBody, agent.PromptOverrides); err != nil {
+		return fmt.Errorf("agent %s: %w", resource.Metadata.Name, err)
+	}
+
 	// Terraform-specific attributes
+	if err := agent.Versioning.Validate(); err != nil {
+		return fmt.Errorf("invalid versioning for agent %s: %w", resource.Metadata.Name, err)
+	}
+	if err := agent.ValidatePreparation(); err != nil {
+		return fmt.Errorf("agent %s: %w", resource.Metadata.Name, err)
+	}
 	if agent.PrepareAgent != nil {
 		resourceBody.SetAttributeValue("prepare_agent", cty.BoolVal(*agent.PrepareAgent))
+	} else if isAgentSupervisor(agent) {
+		// A supervisor can only be prepared once its collaborators are associated
+		resourceBody.SetAttributeValue("prepare_agent", cty.BoolVal(false))
 	}
 
 	if agent.SkipResourceInUseCheck != nil {
@@ -104,16 +138,38 @@ func (g *HCLGenerator) generateAgentNative(body *hclwrite.Body, resource models.
 
 	body.AppendNewline()
 
+	// Generate CloudWatch logging resources if enabled
+	if g.isAgentLoggingEnabled(agent) {
+		if err := g.generateAgentLogging(body, resource.Metadata.Name, agent); err != nil {
+			return fmt.Errorf("failed to generate agent logging: %w", err)
+		}
+	}
+
 	// Generate separate action group resources if specified
 	if len(agent.ActionGroups) > 0 {
-		if err := g.generateAgentActionGroups(body, resource.Metadata.Name, agent.ActionGroups); err != nil {
+		if err := g.generateAgentActionGroups(body, resource.Metadata.Name, agent.ActionGroups, agent.Versioning); err != nil {
 			return fmt.Errorf("failed to generate agent action groups: %w", err)
 		}
 	}
 
+	// Aliases snapshot the agent, so they depend on its action groups and collaborators
+	var aliasDependencies []string
+	for _, ag := range agent.ActionGroups {
+		aliasDependencies = append(aliasDependencies, fmt.Sprintf("aws_bedrockagent_agent_action_group.%s_%s", resourceName, g.sanitizeResourceName(ag.Name)))
+	}
+
+	// Generate collaborator associations for supervisor agents
+	if isAgentSupervisor(agent) {
+		collaboratorAddresses, err := g.generateAgentCollaborators(body, resource.Metadata.Name, agent.AgentCollaboration)
+		if err != nil {
+			return fmt.Errorf("failed to generate agent collaborators: %w", err)
+		}
+		aliasDependencies = append(aliasDependencies, collaboratorAddresses...)
+	}
+
 	// Generate agent aliases if specified
 	if len(agent.Aliases) > 0 {
-		if err := g.generateAgentAliases(body, resource.Metadata.Name, agent.Aliases); err != nil {
+		if err := g.generateAgentAliases(body, resource.Metadata.Name, agent, aliasDependencies); err != nil {
 			return fmt.Errorf("failed to generate agent aliases: %w", err)
 		}
 	}
@@ -122,8 +178,63 @@ func (g *HCLGenerator) generateAgentNative(body *hclwrite.Body, resource models.
 	return nil
 }
 
+// setAgentMemoryConfiguration adds the memory_configuration block of an agent
+func setAgentMemoryConfiguration(resourceBody *hclwrite.Body, memory models.MemoryConfiguration) {
+	memoryBody := resourceBody.AppendNewBlock("memory_configuration", nil).Body()
+	memoryBody.SetAttributeValue("enabled_memory_types", stringListValue(memory.EnabledMemoryTypes))
+	if memory.StorageDays > 0 {
+		memoryBody.SetAttributeValue("storage_days", cty.NumberIntVal(int64(memory.StorageDays)))
+	}
+	if memory.SessionSummaryConfiguration != nil {
+		summaryBody := memoryBody.AppendNewBlock("session_summary_configuration", nil).Body()
+		summaryBody.SetAttributeValue("max_recent_sessions", cty.NumberIntVal(int64(memory.SessionSummaryConfiguration.MaxRecentSessions)))
+	}
+}
+
+// setAgentPromptOverrides adds the prompt_override_configuration block for the overrides that reference
+// a Prompt resource
+func (g *HCLGenerator) setAgentPromptOverrides(resourceBody *hclwrite.Body, promptOverrides []models.PromptOverride) error {
+	var configurations []hclwrite.Tokens
+	for _, promptOverride := range promptOverrides {
+		if promptOverride.Prompt.IsEmpty() {
+			continue
+		}
+
+		promptResource, exists := g.registry.GetResource(models.PromptKind, promptOverride.Prompt.String())
+		if !exists {
+			return fmt.Errorf("prompt override %s references non-existent prompt %s", promptOverride.PromptType, promptOverride.Prompt)
+		}
+		prompt := promptResource.Resource.(*models.Prompt)
+
+		// Without a pinned variant the override uses the default variant, or the only one
+		variant := promptOverride.VariantName()
+		if variant == "" {
+			variant = prompt.Spec.DefaultVariant
+		}
+		if variant == "" && len(prompt.Spec.Variants) > 0 {
+			variant = prompt.Spec.Variants[0].Name
+		}
+
+		attributes := []hclwrite.ObjectAttrTokens{
+			{Name: hclwrite.TokensForIdentifier("base_prompt_template"), Value: g.promptOverrideTemplateTokens(promptOverride, variant)},
+			{Name: hclwrite.TokensForIdentifier("parser_mode"), Value: hclwrite.TokensForValue(cty.StringVal("DEFAULT"))},
+			{Name: hclwrite.TokensForIdentifier("prompt_creation_mode"), Value: hclwrite.TokensForValue(cty.StringVal("OVERRIDDEN"))},
+			{Name: hclwrite.TokensForIdentifier("prompt_state"), Value: hclwrite.TokensForValue(cty.StringVal("ENABLED"))},
+			{Name: hclwrite.TokensForIdentifier("prompt_type"), Value: hclwrite.TokensForValue(cty.StringVal(promptOverride.PromptType))},
+		}
+		configurations = append(configurations, hclwrite.TokensForObject(attributes))
+	}
+	if len(configurations) == 0 {
+		return nil
+	}
+
+	overrideBody := resourceBody.AppendNewBlock("prompt_override_configuration", nil).Body()
+	overrideBody.SetAttributeRaw("prompt_configurations", hclwrite.TokensForTuple(configurations))
+	return nil
+}
+
 // generateAgentActionGroups creates separate aws_bedrockagent_agent_action_group resources
-func (g *HCLGenerator) generateAgentActionGroups(body *hclwrite.Body, agentName string, actionGroups []models.InlineActionGroup) error {
+func (g *HCLGenerator) generateAgentActionGroups(body *hclwrite.Body, agentName string, actionGroups []models.InlineActionGroup, versioning *models.AgentVersioning) error {
 	agentResourceName := g.sanitizeResourceName(agentName)
 
 	for _, ag := range actionGroups {
@@ -155,72 +266,21 @@ func (g *HCLGenerator) generateAgentActionGroups(body *hclwrite.Body, agentName
 			agBody.SetAttributeValue("parent_action_group_signature", cty.StringVal(ag.ParentActionGroupSignature))
 		}
 
-		// Action group executor configuration (using block syntax)
-		if ag.ActionGroupExecutor != nil {
-			executorBlock := agBody.AppendNewBlock("action_group_executor", nil)
-			executorBody := executorBlock.Body()
+		if !versioning.PreparesOnActionGroupChange() {
+			agBody.SetAttributeValue("prepare_agent", cty.BoolVal(false))
+		}
 
-			if !ag.ActionGroupExecutor.Lambda.IsEmpty() {
-				// Reference to a Lambda resource
-				lambdaResourceName := g.sanitizeResourceName(ag.ActionGroupExecutor.Lambda.String())
-				executorBody.SetAttributeRaw("lambda", hclwrite.Tokens{
-					{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.arn", lambdaResourceName))},
-				})
-			} else if ag.ActionGroupExecutor.LambdaArn != "" {
-				// Direct Lambda ARN
-				executorBody.SetAttributeValue("lambda", cty.StringVal(ag.ActionGroupExecutor.LambdaArn))
-			} else if ag.ActionGroupExecutor.CustomControl != "" {
-				executorBody.SetAttributeValue("custom_control", cty.StringVal(ag.ActionGroupExecutor.CustomControl))
-			}
+		// Action group executor, API schema and function schema blocks
+		if err := g.setActionGroupExecutorNative(agBody, inlineActionGroupOwner(agentName, ag.Name), ag.ActionGroupExecutor); err != nil {
+			return err
 		}
 
-		// API Schema configuration (using block syntax)
 		if ag.APISchema != nil {
-			apiSchemaBlock := agBody.AppendNewBlock("api_schema", nil)
-			apiSchemaBody := apiSchemaBlock.Body()
-
-			if ag.APISchema.S3 != nil {
-				s3Block := apiSchemaBody.AppendNewBlock("s3", nil)
-				s3Body := s3Block.Body()
-				s3Body.SetAttributeValue("s3_bucket_name", cty.StringVal(ag.APISchema.S3.S3BucketName))
-				s3Body.SetAttributeValue("s3_object_key", cty.StringVal(ag.APISchema.S3.S3ObjectKey))
-			} else if ag.APISchema.Payload != "" {
-				apiSchemaBody.SetAttributeValue("payload", cty.StringVal(ag.APISchema.Payload))
-			}
+			g.setAPISchemaNative(agBody, ag.APISchema)
 		}
 
-		// Function Schema configuration (using proper block syntax)
 		if ag.FunctionSchema != nil {
-			functionSchemaBlock := agBody.AppendNewBlock("function_schema", nil)
-			functionSchemaBody := functionSchemaBlock.Body()
-
-			// Create member_functions block
-			memberFunctionsBlock := functionSchemaBody.AppendNewBlock("member_functions", nil)
-			memberFunctionsBody := memberFunctionsBlock.Body()
-
-			// Add functions
-			for _, fn := range ag.FunctionSchema.Functions {
-				functionBlock := memberFunctionsBody.AppendNewBlock("functions", nil)
-				functionBody := functionBlock.Body()
-
-				functionBody.SetAttributeValue("name", cty.StringVal(fn.Name))
-				if fn.Description != "" {
-					functionBody.SetAttributeValue("description", cty.StringVal(fn.Description))
-				}
-
-				// Add parameters
-				for paramName, param := range fn.Parameters {
-					paramBlock := functionBody.AppendNewBlock("parameters", nil)
-					paramBody := paramBlock.Body()
-
-					paramBody.SetAttributeValue("map_block_key", cty.StringVal(paramName))
-					paramBody.SetAttributeValue("type", cty.StringVal(param.Type))
-					paramBody.SetAttributeValue("required", cty.BoolVal(param.Required))
-					if param.Description != "" {
-						paramBody.SetAttributeValue("description", cty.StringVal(param.Description))
-					}
-				}
-			}
+			g.setFunctionSchemaNative(agBody, ag.FunctionSchema)
 		}
 
 		body.AppendNewline()
@@ -237,19 +297,18 @@ func (g *HCLGenerator) generateAgentExecutionRoleNative(body *hclwrite.Body, age
 	roleBlock := body.AppendNewBlock("resource", []string{"aws_iam_role", roleResourceName})
 	roleBody := roleBlock.Body()
 
+	conditions, err := g.agentAssumeRoleConditions(agent)
+	if err != nil {
+		return fmt.Errorf("invalid IAM role for agent %s: %w", agentName, err)
+	}
+	assumeRolePolicy, err := assumeRolePolicyDocument("bedrock.amazonaws.com", conditions)
+	if err != nil {
+		return err
+	}
+
 	roleBody.SetAttributeValue("name", cty.StringVal(fmt.Sprintf("%s-execution-role", agentName)))
-	roleBody.SetAttributeValue("assume_role_policy", cty.StringVal(`{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Action": "sts:AssumeRole",
-      "Effect": "Allow",
-      "Principal": {
-        "Service": "bedrock.amazonaws.com"
-      }
-    }
-  ]
-}`))
+	roleBody.SetAttributeValue("assume_role_policy", cty.StringVal(assumeRolePolicy))
+	setGeneratedRoleTags(roleBody, agent.Tags, models.RoleTypeAgentExecution)
 
 	// Create IAM role policy attachment for Bedrock service
 	bedrockPolicyAttachmentBlock := body.AppendNewBlock("resource", []string{"aws_iam_role_policy_attachment", fmt.Sprintf("%s_bedrock_policy", roleResourceName)})
@@ -261,7 +320,10 @@ func (g *HCLGenerator) generateAgentExecutionRoleNative(body *hclwrite.Body, age
 	bedrockPolicyAttachmentBody.SetAttributeValue("policy_arn", cty.StringVal("arn:aws:iam::aws:policy/AmazonBedrockFullAccess"))
 
 	// Build specific Lambda ARNs from action groups
-	lambdaArns := g.buildLambdaArnsFromActionGroups(agent.ActionGroups)
+	lambdaArns, err := g.buildLambdaArnsFromActionGroups(agentName, agent.ActionGroups)
+	if err != nil {
+		return err
+	}
 
 	// Create inline policy for specific Bedrock agent permissions
 	inlinePolicyBlock := body.AppendNewBlock("resource", []string{"aws_iam_role_policy", fmt.Sprintf("%s_inline_policy", roleResourceName)})
@@ -272,9 +334,20 @@ func (g *HCLGenerator) generateAgentExecutionRoleNative(body *hclwrite.Body, age
 		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_iam_role.%s.id", roleResourceName))},
 	})
 
-	// Generate policy with specific Lambda ARNs
-	policyJson := g.buildAgentExecutionPolicy(lambdaArns)
-	inlinePolicyBody.SetAttributeValue("policy", cty.StringVal(policyJson))
+	// Allow writing to the agent log group when logging is enabled
+	logGroupArn := ""
+	if g.isAgentLoggingEnabled(agent) {
+		logGroupArn = fmt.Sprintf("aws_cloudwatch_log_group.%s.arn", g.agentLogGroupResourceName(agentName))
+	}
+
+	modelArns, knowledgeBaseArns, err := g.agentPolicyResources(agentName, agent)
+	if err != nil {
+		return err
+	}
+
+	// Generate policy with specific Lambda ARNs; the policy interpolates references, so it isn't escaped
+	policyJson := g.buildAgentExecutionPolicy(lambdaArns, logGroupArn, modelArns, knowledgeBaseArns)
+	inlinePolicyBody.SetAttributeRaw("policy", templateStringTokens(policyJson))
 
 	body.AppendNewline()
 
@@ -283,39 +356,118 @@ func (g *HCLGenerator) generateAgentExecutionRoleNative(body *hclwrite.Body, age
 }
 
 // buildLambdaArnsFromActionGroups extracts Lambda function references from action groups
-func (g *HCLGenerator) buildLambdaArnsFromActionGroups(actionGroups []models.InlineActionGroup) []string {
+func (g *HCLGenerator) buildLambdaArnsFromActionGroups(agentName string, actionGroups []models.InlineActionGroup) ([]string, error) {
 	var lambdaArns []string
 
 	for _, ag := range actionGroups {
-		if ag.ActionGroupExecutor != nil {
-			if !ag.ActionGroupExecutor.Lambda.IsEmpty() {
-				// Reference to a Lambda resource
-				lambdaResourceName := g.sanitizeResourceName(ag.ActionGroupExecutor.Lambda.String())
-				lambdaArn := fmt.Sprintf("aws_lambda_function.%s.arn", lambdaResourceName)
-				lambdaArns = append(lambdaArns, lambdaArn)
-			} else if ag.ActionGroupExecutor.LambdaArn != "" {
-				// Direct Lambda ARN
-				lambdaArns = append(lambdaArns, ag.ActionGroupExecutor.LambdaArn)
-			}
+		target, ok, err := g.resolveLambdaTarget(inlineActionGroupOwner(agentName, ag.Name), ag.ActionGroupExecutor)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			lambdaArns = append(lambdaArns, target.template())
+		}
+	}
+
+	return lambdaArns, nil
+}
+
+// inlineActionGroupOwner identifies an inline action group in logs and errors
+func inlineActionGroupOwner(agentName, actionGroupName string) string {
+	return fmt.Sprintf("%s action group %s", resourceKey(models.AgentKind, agentName), actionGroupName)
+}
+
+// agentPolicyResources returns the models and knowledge bases the agent execution role may use. All
+// models and knowledge bases are allowed unless the agent's iamRole scopes them; a nil knowledgeBaseArns
+// means all knowledge bases.
+func (g *HCLGenerator) agentPolicyResources(agentName string, agent models.AgentSpec) ([]string, []string, error) {
+	modelArns := []string{"arn:aws:bedrock:*::foundation-model/*"}
+	// An agent on provisioned throughput invokes only that provisioned model
+	if agent.ProvisionedThroughput != "" {
+		provisionedArn, err := g.provisionedThroughputArn(agent.ProvisionedThroughput)
+		if err != nil {
+			return nil, nil, fmt.Errorf("agent %s provisionedThroughput: %w", agentName, err)
+		}
+		modelArns = []string{provisionedArn}
+	}
+	// Invoking through an inference profile requires access to the profile as well as its models
+	inferenceProfileArn := agent.InferenceProfileArn()
+	if inferenceProfileArn != "" {
+		modelArns = append(modelArns, inferenceProfileArn)
+	}
+
+	if agent.IAMRole == nil {
+		return modelArns, nil, nil
+	}
+
+	if agent.IAMRole.ScopeModelAccess && agent.ProvisionedThroughput == "" {
+		if inferenceProfileArn != "" {
+			// The models behind a profile aren't known here
+			g.logger.WithField("agent", agentName).Warn("Model access can't be scoped for agents using an inference profile, allowing all foundation models")
+		} else if strings.HasPrefix(agent.FoundationModel, "arn:") {
+			modelArns = []string{agent.FoundationModel}
+		} else {
+			modelArns = []string{fmt.Sprintf("arn:aws:bedrock:*::foundation-model/%s", agent.FoundationModel)}
 		}
 	}
 
-	return lambdaArns
+	if !agent.IAMRole.ScopeKnowledgeBaseAccess {
+		return modelArns, nil, nil
+	}
+
+	knowledgeBaseArns := []string{}
+	for _, resource := range g.registry.GetResourcesByType(models.AgentKnowledgeBaseAssociationKind) {
+		association, ok := resource.Spec.(models.AgentKnowledgeBaseAssociationSpec)
+		if !ok {
+			continue
+		}
+
+		agentRef, knowledgeBaseRef := association.AgentName, association.KnowledgeBaseName
+		if agentRef.IsEmpty() {
+			agentRef = association.AgentId
+		}
+		if knowledgeBaseRef.IsEmpty() {
+			knowledgeBaseRef = association.KnowledgeBaseId
+		}
+		if agentRef.String() != agentName {
+			continue
+		}
+
+		knowledgeBaseArn, err := g.resolveReferenceToOutput(knowledgeBaseRef, models.KnowledgeBaseKind, "knowledge_base_arn")
+		if err != nil {
+			g.logger.WithError(err).WithField("association", resource.Metadata.Name).Warn("Skipping knowledge base in agent role policy")
+			continue
+		}
+		knowledgeBaseArns = append(knowledgeBaseArns, knowledgeBaseArn)
+	}
+	sort.Strings(knowledgeBaseArns)
+
+	return modelArns, knowledgeBaseArns, nil
+}
+
+// provisionedThroughputArn returns the provisioned model ARN for the execution policy, interpolating
+// ${ref:custom.<outputName>} references
+func (g *HCLGenerator) provisionedThroughputArn(value string) (string, error) {
+	tokens, isRef, err := g.resolveCustomOutputReference(value)
+	if err != nil {
+		return "", err
+	}
+	if isRef {
+		return fmt.Sprintf("${%s}", strings.TrimSpace(string(tokens.Bytes()))), nil
+	}
+	return value, nil
 }
 
-// buildAgentExecutionPolicy creates the IAM policy JSON with specific Lambda ARNs
-func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string) string {
+// buildAgentExecutionPolicy creates the IAM policy JSON with specific Lambda ARNs, write access
+// to the agent log group when logGroupArn is set, and model and knowledge base access limited to
+// the given ARNs. With nil knowledgeBaseArns all knowledge bases are allowed, with an empty list none.
+func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string, logGroupArn string, modelArns []string, knowledgeBaseArns []string) string {
 	// Build Lambda resource array
 	lambdaResourcesJson := ""
 	if len(lambdaArns) > 0 {
 		resources := make([]string, len(lambdaArns))
 		for i, arn := range lambdaArns {
-			// Check if it's a Terraform reference or direct ARN
-			if strings.HasPrefix(arn, "aws_lambda_function.") {
-				resources[i] = fmt.Sprintf("        \"${%s}\"", arn)
-			} else {
-				resources[i] = fmt.Sprintf("        \"%s\"", arn)
-			}
+			resources[i] = fmt.Sprintf("        \"%s\"", arn)
 		}
 		lambdaResourcesJson = strings.Join(resources, ",\n")
 	} else {
@@ -323,6 +475,44 @@ func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string) string {
 		lambdaResourcesJson = "        \"arn:aws:lambda:*:*:function:*\""
 	}
 
+	modelResourcesJson := policyResourcesJson(modelArns)
+
+	// Build knowledge base statement, left out when the agent has no knowledge bases to scope to
+	knowledgeBaseStatementJson := ""
+	if knowledgeBaseArns == nil || len(knowledgeBaseArns) > 0 {
+		knowledgeBaseResourcesJson := `"arn:aws:bedrock:*:*:knowledge-base/*"`
+		if knowledgeBaseArns != nil {
+			knowledgeBaseResourcesJson = policyResourcesJson(knowledgeBaseArns)
+		}
+		knowledgeBaseStatementJson = fmt.Sprintf(`
+    {
+      "Effect": "Allow",
+      "Action": [
+        "bedrock:Retrieve",
+        "bedrock:RetrieveAndGenerate"
+      ],
+      "Resource": %s
+    },`, knowledgeBaseResourcesJson)
+	}
+
+	// Build optional log group statement
+	logGroupStatementJson := ""
+	if logGroupArn != "" {
+		logGroupStatementJson = fmt.Sprintf(`,
+    {
+      "Effect": "Allow",
+      "Action": [
+        "logs:CreateLogStream",
+        "logs:PutLogEvents",
+        "logs:DescribeLogStreams"
+      ],
+      "Resource": [
+        "${%s}",
+        "${%s}:*"
+      ]
+    }`, logGroupArn, logGroupArn)
+	}
+
 	return fmt.Sprintf(`{
   "Version": "2012-10-17",
   "Statement": [
@@ -332,7 +522,7 @@ func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string) string {
         "bedrock:InvokeModel",
         "bedrock:InvokeModelWithResponseStream"
       ],
-      "Resource": "arn:aws:bedrock:*::foundation-model/*"
+      "Resource": %s
     },
     {
       "Effect": "Allow",
@@ -351,15 +541,7 @@ func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string) string {
       "Resource": [
 %s
       ]
-    },
-    {
-      "Effect": "Allow",
-      "Action": [
-        "bedrock:Retrieve",
-        "bedrock:RetrieveAndGenerate"
-      ],
-      "Resource": "arn:aws:bedrock:*:*:knowledge-base/*"
-    },
+    },%s
     {
       "Effect": "Allow",
       "Action": [
@@ -368,9 +550,59 @@ func (g *HCLGenerator) buildAgentExecutionPolicy(lambdaArns []string) string {
         "logs:PutLogEvents"
       ],
       "Resource": "arn:aws:logs:*:*:*"
-    }
+    }%s
   ]
-}`, lambdaResourcesJson)
+}`, modelResourcesJson, lambdaResourcesJson, knowledgeBaseStatementJson, logGroupStatementJson)
+}
+
+// policyResourcesJson renders a policy Resource value, a string for a single ARN and a list otherwise
+func policyResourcesJson(arns []string) string {
+	if len(arns) == 1 {
+		return fmt.Sprintf("%q", arns[0])
+	}
+
+	quoted := make([]string, len(arns))
+	for i, arn := range arns {
+		quoted[i] = fmt.Sprintf("        %q", arn)
+	}
+	return fmt.Sprintf("[\n%s\n      ]", strings.Join(quoted, ",\n"))
+}
+
+// templateStringTokens renders s as a quoted HCL string that keeps ${} interpolations, unlike
+// cty.StringVal which escapes them
+func templateStringTokens(s string) hclwrite.Tokens {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "%{", "%%{").Replace(s)
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(escaped)},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+	}
+}
+
+// agentAssumeRoleConditions returns the trust policy conditions of an agent's generated role. Without
+// explicit conditions the role is scoped to the configured deployment account, if there is one.
+func (g *HCLGenerator) agentAssumeRoleConditions(agent models.AgentSpec) (map[string]map[string]string, error) {
+	var configured models.AssumeRoleConditions
+	if agent.IAMRole != nil && agent.IAMRole.AssumeRoleConditions != nil {
+		if err := agent.IAMRole.AssumeRoleConditions.Validate(); err != nil {
+			return nil, err
+		}
+		configured = *agent.IAMRole.AssumeRoleConditions
+	}
+
+	sourceAccount := configured.SourceAccount
+	if sourceAccount == "" {
+		sourceAccount = g.config.Account
+	}
+
+	conditions := make(map[string]map[string]string)
+	if sourceAccount != "" {
+		conditions["StringEquals"] = map[string]string{"aws:SourceAccount": sourceAccount}
+	}
+	if configured.SourceArn != "" {
+		conditions["ArnLike"] = map[string]string{"aws:SourceArn": configured.SourceArn}
+	}
+	return conditions, nil
 }
 
 // handleAgentExecutionRole determines whether to generate an IAM role or use an existing one
@@ -404,18 +636,22 @@ func (g *HCLGenerator) handleAgentExecutionRole(body *hclwrite.Body, agentName s
 
 // setAgentRoleReference sets the appropriate IAM role reference based on configuration
 func (g *HCLGenerator) setAgentRoleReference(resourceBody *hclwrite.Body, agentName string, agent models.AgentSpec) error {
+	return g.setAgentRoleAttribute(resourceBody, "agent_resource_role_arn", agentName, agent)
+}
+
+// setAgentRoleAttribute sets the named attribute to the ARN of the agent's execution role
+func (g *HCLGenerator) setAgentRoleAttribute(resourceBody *hclwrite.Body, attributeName string, agentName string, agent models.AgentSpec) error {
 	if agent.IAMRole != nil {
 		// User has provided IAM role configuration
 		if agent.IAMRole.RoleArn != "" {
-			// Direct ARN
-			resourceBody.SetAttributeValue("agent_resource_role_arn", cty.StringVal(agent.IAMRole.RoleArn))
-			return nil
+			// Direct ARN or reference to a custom resource output
+			return g.setStringOrCustomOutput(resourceBody, attributeName, agent.IAMRole.RoleArn)
 		}
 
 		if !agent.IAMRole.RoleName.IsEmpty() {
 			// Reference to IAMRole resource
 			roleResourceName := g.sanitizeResourceName(agent.IAMRole.RoleName.String())
-			resourceBody.SetAttributeRaw("agent_resource_role_arn", hclwrite.Tokens{
+			resourceBody.SetAttributeRaw(attributeName, hclwrite.Tokens{
 				{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_iam_role.%s.arn", roleResourceName))},
 			})
 			return nil
@@ -424,7 +660,7 @@ func (g *HCLGenerator) setAgentRoleReference(resourceBody *hclwrite.Body, agentN
 
 	// Default: reference auto-generated role
 	agentRoleName := fmt.Sprintf("%s_execution_role", g.sanitizeResourceName(agentName))
-	resourceBody.SetAttributeRaw("agent_resource_role_arn", hclwrite.Tokens{
+	resourceBody.SetAttributeRaw(attributeName, hclwrite.Tokens{
 		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_iam_role.%s.arn", agentRoleName))},
 	})
 	return nil
@@ -0,0 +1,425 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/internal/models"
+)
+
+const scopedPolicyResources = `
+kind: KnowledgeBase
+metadata:
+  name: product-docs
+spec:
+  description: Product documentation
+---
+kind: KnowledgeBase
+metadata:
+  name: faq
+spec:
+  description: Frequently asked questions
+---
+kind: KnowledgeBase
+metadata:
+  name: billing-records
+spec:
+  description: Billing records, only for the billing agent
+---
+kind: AgentKnowledgeBaseAssociation
+metadata:
+  name: support-docs
+spec:
+  agentName: support-agent
+  knowledgeBaseName: product-docs
+---
+kind: AgentKnowledgeBaseAssociation
+metadata:
+  name: support-faq
+spec:
+  agentName: {ref: support-agent}
+  knowledgeBaseName: {ref: faq}
+---
+kind: AgentKnowledgeBaseAssociation
+metadata:
+  name: billing-records
+spec:
+  agentName: billing-agent
+  knowledgeBaseName: billing-records
+`
+
+// agentPolicyStatement is a statement of the generated agent execution policy, with Resource always a list
+type agentPolicyStatement struct {
+	Action   []string
+	Resource []string
+}
+
+// agentExecutionPolicy generates the execution role of an agent and returns its inline policy statements
+func agentExecutionPolicy(t *testing.T, g *HCLGenerator, agentName string, agent models.AgentSpec) []agentPolicyStatement {
+	t.Helper()
+
+	body := hclwrite.NewEmptyFile().Body()
+	if err := g.generateAgentExecutionRoleNative(body, agentName, agent); err != nil {
+		t.Fatalf("generateAgentExecutionRoleNative: %v", err)
+	}
+
+	policies := findBlocks(parseBody(t, body), "resource", "aws_iam_role_policy")
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 inline policy, got %d", len(policies))
+	}
+
+	var document struct {
+		Statement []struct {
+			Action   []string
+			Resource json.RawMessage
+		}
+	}
+	policy := templateAttribute(t, policies[0], "policy")
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		t.Fatalf("policy is not valid JSON: %v\n%s", err, policy)
+	}
+
+	statements := make([]agentPolicyStatement, len(document.Statement))
+	for i, statement := range document.Statement {
+		statements[i].Action = statement.Action
+		var single string
+		if err := json.Unmarshal(statement.Resource, &single); err == nil {
+			statements[i].Resource = []string{single}
+		} else if err := json.Unmarshal(statement.Resource, &statements[i].Resource); err != nil {
+			t.Fatalf("statement %d has an invalid Resource: %s", i, statement.Resource)
+		}
+	}
+	return statements
+}
+
+// statementResources returns the resources of the statement granting action, and whether there is one
+func statementResources(statements []agentPolicyStatement, action string) ([]string, bool) {
+	for _, statement := range statements {
+		for _, statementAction := range statement.Action {
+			if statementAction == action {
+				return statement.Resource, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func TestAgentExecutionPolicyScoping(t *testing.T) {
+	const claude = "anthropic.claude-3-sonnet-20240229-v1:0"
+	const claudeArn = "arn:aws:bedrock:us-east-1::foundation-model/" + claude
+
+	tests := []struct {
+		name            string
+		agentName       string
+		foundationModel string
+		iamRole         *models.IAMRoleConfig
+		models          []string
+		knowledgeBases  []string // nil when the policy has no knowledge base statement
+	}{
+		{
+			name:            "broad by default",
+			agentName:       "support-agent",
+			foundationModel: claude,
+			models:          []string{"arn:aws:bedrock:*::foundation-model/*"},
+			knowledgeBases:  []string{"arn:aws:bedrock:*:*:knowledge-base/*"},
+		},
+		{
+			name:            "broad with an iamRole that doesn't scope",
+			agentName:       "support-agent",
+			foundationModel: claude,
+			iamRole:         &models.IAMRoleConfig{},
+			models:          []string{"arn:aws:bedrock:*::foundation-model/*"},
+			knowledgeBases:  []string{"arn:aws:bedrock:*:*:knowledge-base/*"},
+		},
+		{
+			name:            "scoped to the model ID",
+			agentName:       "support-agent",
+			foundationModel: claude,
+			iamRole:         &models.IAMRoleConfig{ScopeModelAccess: true},
+			models:          []string{"arn:aws:bedrock:*::foundation-model/" + claude},
+			knowledgeBases:  []string{"arn:aws:bedrock:*:*:knowledge-base/*"},
+		},
+		{
+			name:            "scoped to the model ARN",
+			agentName:       "support-agent",
+			foundationModel: claudeArn,
+			iamRole:         &models.IAMRoleConfig{ScopeModelAccess: true},
+			models:          []string{claudeArn},
+			knowledgeBases:  []string{"arn:aws:bedrock:*:*:knowledge-base/*"},
+		},
+		{
+			name:            "scoped to the associated knowledge bases",
+			agentName:       "support-agent",
+			foundationModel: claude,
+			iamRole:         &models.IAMRoleConfig{ScopeModelAccess: true, ScopeKnowledgeBaseAccess: true},
+			models:          []string{"arn:aws:bedrock:*::foundation-model/" + claude},
+			knowledgeBases: []string{
+				"${module.faq.knowledge_base_arn}",
+				"${module.product_docs.knowledge_base_arn}",
+			},
+		},
+		{
+			name:            "scoped without associated knowledge bases",
+			agentName:       "triage-agent",
+			foundationModel: claude,
+			iamRole:         &models.IAMRoleConfig{ScopeKnowledgeBaseAccess: true},
+			models:          []string{"arn:aws:bedrock:*::foundation-model/*"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, scopedPolicyResources, nil)
+			agent := models.AgentSpec{FoundationModel: test.foundationModel, IAMRole: test.iamRole}
+			statements := agentExecutionPolicy(t, g, test.agentName, agent)
+
+			modelResources, _ := statementResources(statements, "bedrock:InvokeModel")
+			if !reflect.DeepEqual(modelResources, test.models) {
+				t.Errorf("model resources = %v, want %v", modelResources, test.models)
+			}
+
+			knowledgeBaseResources, ok := statementResources(statements, "bedrock:Retrieve")
+			switch {
+			case test.knowledgeBases == nil && ok:
+				t.Errorf("unexpected knowledge base statement with resources %v", knowledgeBaseResources)
+			case !reflect.DeepEqual(knowledgeBaseResources, test.knowledgeBases):
+				t.Errorf("knowledge base resources = %v, want %v", knowledgeBaseResources, test.knowledgeBases)
+			}
+
+			for _, statement := range statements {
+				for _, resource := range statement.Resource {
+					if strings.Contains(resource, "billing_records") {
+						t.Errorf("policy grants access to another agent's knowledge base: %v", statement)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAgentPromptOverridesPinVersions(t *testing.T) {
+	const promptResources = `
+kind: Prompt
+metadata:
+  name: support-prompt
+spec:
+  defaultVariant: production
+  variants:
+    - name: production
+      modelId: anthropic.claude-3-sonnet-20240229-v1:0
+      templateType: TEXT
+      templateConfiguration:
+        text:
+          text: "You are a support agent. {{question}}"
+    - name: experimental
+      modelId: anthropic.claude-3-sonnet-20240229-v1:0
+      templateType: TEXT
+      templateConfiguration:
+        text:
+          text: "You are a concise support agent. {{question}}"
+  versions:
+    - description: Initial release
+    - description: Tightened escalation rules
+`
+
+	tests := []struct {
+		name     string
+		override models.PromptOverride
+		want     string
+	}{
+		{
+			name:     "draft by default",
+			override: models.PromptOverride{PromptType: "ORCHESTRATION"},
+			want:     `module.support_prompt.version_templates["DRAFT"]["production"]`,
+		},
+		{
+			name:     "pinned version and variant",
+			override: models.PromptOverride{PromptType: "ORCHESTRATION", Version: "2", Variant: "experimental"},
+			want:     `module.support_prompt.version_templates["2"]["experimental"]`,
+		},
+		{
+			name:     "latest version",
+			override: models.PromptOverride{PromptType: "ORCHESTRATION", Version: "latest"},
+			want:     `module.support_prompt.version_templates[module.support_prompt.version]["production"]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, promptResources, nil)
+			test.override.Prompt = models.Reference{Name: "support-prompt"}
+			agent := models.AgentSpec{
+				FoundationModel: "anthropic.claude-3-sonnet-20240229-v1:0",
+				Instruction:     "You are a helpful customer support agent.",
+				PromptOverrides: []models.PromptOverride{
+					test.override,
+					{PromptType: "PRE_PROCESSING", PromptArn: "arn:aws:bedrock:us-east-1:123456789012:prompt/external"},
+				},
+			}
+
+			file := hclwrite.NewEmptyFile()
+			if err := g.generateAgentNative(file.Body(), models.BaseResource{Kind: models.AgentKind, Metadata: models.Metadata{Name: "support-agent"}, Spec: agent}); err != nil {
+				t.Fatalf("generateAgentNative: %v", err)
+			}
+			content := file.Bytes()
+			parsed, diags := hclsyntax.ParseConfig(content, "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("generated configuration doesn't parse: %s", diags.Error())
+			}
+
+			agentBlocks := findBlocks(parsed.Body.(*hclsyntax.Body), "resource", "aws_bedrockagent_agent")
+			if len(agentBlocks) != 1 {
+				t.Fatalf("expected 1 agent, got %d", len(agentBlocks))
+			}
+			var configurations []*hclsyntax.Block
+			for _, block := range agentBlocks[0].Body.Blocks {
+				if block.Type == "prompt_override_configuration" {
+					configurations = append(configurations, block)
+				}
+			}
+			if len(configurations) != 1 {
+				t.Fatalf("expected 1 prompt_override_configuration, got %d", len(configurations))
+			}
+
+			// Only the override referencing a Prompt resource has a template to pin
+			tuple, ok := configurations[0].Body.Attributes["prompt_configurations"].Expr.(*hclsyntax.TupleConsExpr)
+			if !ok || len(tuple.Exprs) != 1 {
+				t.Fatalf("expected 1 prompt configuration, got %#v", configurations[0].Body.Attributes["prompt_configurations"].Expr)
+			}
+			for _, item := range tuple.Exprs[0].(*hclsyntax.ObjectConsExpr).Items {
+				key, _ := item.KeyExpr.Value(nil)
+				if key.AsString() != "base_prompt_template" {
+					continue
+				}
+				if got := string(item.ValueExpr.Range().SliceBytes(content)); got != test.want {
+					t.Errorf("base_prompt_template = %s, want %s", got, test.want)
+				}
+				return
+			}
+			t.Error("prompt configuration has no base_prompt_template")
+		})
+	}
+}
+
+const versioningResources = `
+kind: Guardrail
+metadata:
+  name: content-safety
+spec:
+  blockedInputMessaging: Blocked
+  blockedOutputsMessaging: Blocked
+  contentPolicyConfig:
+    filtersConfig:
+      - type: HATE
+        inputStrength: HIGH
+        outputStrength: HIGH
+`
+
+func TestAgentVersioningPreparation(t *testing.T) {
+	disabled := false
+	enabled := true
+
+	tests := []struct {
+		name                   string
+		versioning             models.AgentVersioning
+		prepareAgent           *bool
+		wantErr                string
+		wantAgentPrepare       *bool
+		wantActionGroupPrepare *bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:                   "action group changes don't prepare",
+			versioning:             models.AgentVersioning{PrepareOnActionGroupChange: &disabled},
+			wantActionGroupPrepare: &disabled,
+		},
+		{
+			name:       "guardrail changes don't prepare a prepared agent",
+			versioning: models.AgentVersioning{PrepareOnGuardrailChange: &disabled},
+			wantErr:    "prepareOnGuardrailChange: false requires prepareAgent: false",
+		},
+		{
+			name:         "guardrail changes don't prepare an explicitly prepared agent",
+			versioning:   models.AgentVersioning{PrepareOnGuardrailChange: &disabled},
+			prepareAgent: &enabled,
+			wantErr:      "prepareOnGuardrailChange: false requires prepareAgent: false",
+		},
+		{
+			name:             "guardrail changes don't prepare an unprepared agent",
+			versioning:       models.AgentVersioning{PrepareOnGuardrailChange: &disabled},
+			prepareAgent:     &disabled,
+			wantAgentPrepare: &disabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, versioningResources, nil)
+			agent := models.AgentSpec{
+				FoundationModel: "anthropic.claude-3-sonnet-20240229-v1:0",
+				Instruction:     "You are a helpful customer support agent.",
+				Guardrail:       &models.GuardrailConfig{Name: models.Reference{Name: "content-safety"}, Version: "1"},
+				ActionGroups: []models.InlineActionGroup{{
+					Name:                "orders",
+					ActionGroupExecutor: &models.ActionGroupExecutor{CustomControl: "RETURN_CONTROL"},
+				}},
+				Versioning:   &test.versioning,
+				PrepareAgent: test.prepareAgent,
+			}
+
+			body := hclwrite.NewEmptyFile().Body()
+			err := g.generateAgentNative(body, models.BaseResource{Kind: models.AgentKind, Metadata: models.Metadata{Name: "support-agent"}, Spec: agent})
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("generateAgentNative() = %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateAgentNative: %v", err)
+			}
+
+			parsed := parseBody(t, body)
+			for _, check := range []struct {
+				resourceType string
+				want         *bool
+			}{
+				{"aws_bedrockagent_agent", test.wantAgentPrepare},
+				{"aws_bedrockagent_agent_action_group", test.wantActionGroupPrepare},
+			} {
+				var blocks []*hclsyntax.Block
+				for _, block := range parsed.Blocks {
+					if block.Type == "resource" && block.Labels[0] == check.resourceType {
+						blocks = append(blocks, block)
+					}
+				}
+				if len(blocks) != 1 {
+					t.Fatalf("expected 1 %s, got %d", check.resourceType, len(blocks))
+				}
+				attribute, ok := blocks[0].Body.Attributes["prepare_agent"]
+				if check.want == nil {
+					if ok {
+						t.Errorf("%s sets prepare_agent, want it unset", check.resourceType)
+					}
+					continue
+				}
+				if !ok {
+					t.Errorf("%s doesn't set prepare_agent, want %v", check.resourceType, *check.want)
+					continue
+				}
+				value, _ := attribute.Expr.Value(nil)
+				if value.True() != *check.want {
+					t.Errorf("%s prepare_agent = %v, want %v", check.resourceType, value.True(), *check.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// generateAgentKnowledgeBaseAssociationNative creates a native aws_bedrockagent_agent_knowledge_base_association
+// resource for an AgentKnowledgeBaseAssociation
+func (g *HCLGenerator) generateAgentKnowledgeBaseAssociationNative(body *hclwrite.Body, resource models.BaseResource) error {
+	association, ok := resource.Spec.(models.AgentKnowledgeBaseAssociationSpec)
+	if !ok {
+		// Try to parse as map and convert to AgentKnowledgeBaseAssociationSpec
+		specMap, mapOk := resource.Spec.(map[string]interface{})
+		if !mapOk {
+			return fmt.Errorf("invalid agent knowledge base association spec format")
+		}
+
+		specJSON, err := json.Marshal(specMap)
+		if err != nil {
+			return fmt.Errorf("failed to marshal association spec: %w", err)
+		}
+
+		if err := json.Unmarshal(specJSON, &association); err != nil {
+			return fmt.Errorf("failed to unmarshal association spec: %w", err)
+		}
+	}
+
+	// The association requires a state, and Bedrock enables knowledge bases unless told otherwise
+	state := association.State
+	switch state {
+	case "":
+		state = "ENABLED"
+	case "ENABLED", "DISABLED":
+	default:
+		return fmt.Errorf("invalid knowledge base state '%s' for association %s, must be one of: ENABLED, DISABLED", state, resource.Metadata.Name)
+	}
+
+	agentId, err := g.resolveReferenceToOutput(association.AgentName, models.AgentKind, "agent_id")
+	if err != nil {
+		return fmt.Errorf("failed to resolve agent reference: %w", err)
+	}
+	knowledgeBaseId, err := g.resolveReferenceToOutput(association.KnowledgeBaseName, models.KnowledgeBaseKind, "knowledge_base_id")
+	if err != nil {
+		return fmt.Errorf("failed to resolve knowledge base reference: %w", err)
+	}
+
+	// The description is required and tells the agent when to query the knowledge base
+	description := association.Description
+	if description == "" {
+		description = fmt.Sprintf("Knowledge base %s for agent %s", association.KnowledgeBaseName, association.AgentName)
+	}
+
+	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
+
+	associationBody := body.AppendNewBlock("resource", []string{"aws_bedrockagent_agent_knowledge_base_association", resourceName}).Body()
+	associationBody.SetAttributeRaw("agent_id", referenceTokens(agentId))
+	associationBody.SetAttributeRaw("knowledge_base_id", referenceTokens(knowledgeBaseId))
+	associationBody.SetAttributeValue("description", cty.StringVal(description))
+	associationBody.SetAttributeValue("knowledge_base_state", cty.StringVal(state))
+
+	body.AppendNewline()
+
+	g.logger.WithField("association", resource.Metadata.Name).Info("Generated native agent knowledge base association resource")
+	return nil
+}
+
+// referenceTokens turns a "${...}" reference from resolveReferenceToOutput into a bare expression
+func referenceTokens(reference string) hclwrite.Tokens {
+	expression := strings.TrimSuffix(strings.TrimPrefix(reference, "${"), "}")
+	return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(expression)}}
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// isAgentLoggingEnabled checks if CloudWatch logging is enabled for an agent
+func (g *HCLGenerator) isAgentLoggingEnabled(agent models.AgentSpec) bool {
+	return agent.Logging != nil && agent.Logging.Enabled
+}
+
+// agentLogGroupResourceName returns the Terraform resource name of an agent's log group
+func (g *HCLGenerator) agentLogGroupResourceName(agentName string) string {
+	return fmt.Sprintf("%s_logs", g.sanitizeResourceName(agentName))
+}
+
+// generateAgentLogging creates the CloudWatch log group and model invocation logging configuration for an agent
+func (g *HCLGenerator) generateAgentLogging(body *hclwrite.Body, agentName string, agent models.AgentSpec) error {
+	logGroupResourceName := g.agentLogGroupResourceName(agentName)
+
+	logGroupName := agent.Logging.LogGroupName
+	if logGroupName == "" {
+		logGroupName = fmt.Sprintf("/aws/bedrock/agents/%s", agentName)
+	}
+
+	// Create CloudWatch log group
+	logGroupBlock := body.AppendNewBlock("resource", []string{"aws_cloudwatch_log_group", logGroupResourceName})
+	logGroupBody := logGroupBlock.Body()
+
+	logGroupBody.SetAttributeValue("name", cty.StringVal(logGroupName))
+
+	if agent.Logging.RetentionDays > 0 {
+		logGroupBody.SetAttributeValue("retention_in_days", cty.NumberIntVal(int64(agent.Logging.RetentionDays)))
+	}
+
+	if len(agent.Tags) > 0 {
+		tagValues := make(map[string]cty.Value)
+		for key, value := range agent.Tags {
+			tagValues[key] = cty.StringVal(value)
+		}
+		logGroupBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
+	}
+
+	body.AppendNewline()
+
+	// User-provided roles are not modified, so they must already allow writing to the log group
+	if agent.IAMRole != nil && (agent.IAMRole.RoleArn != "" || !agent.IAMRole.RoleName.IsEmpty()) {
+		g.logger.WithField("agent", agentName).WithField("logGroup", logGroupName).Warn("Agent uses an existing IAM role; ensure it allows logs:CreateLogStream and logs:PutLogEvents on the log group")
+	}
+
+	// Model invocation logging is configured once per account and region
+	if g.invocationLoggingAgent != "" {
+		g.logger.WithFields(logrus.Fields{
+			"agent":      agentName,
+			"configured": g.invocationLoggingAgent,
+		}).Warn("Model invocation logging already configured by another agent, skipping")
+		return nil
+	}
+	g.invocationLoggingAgent = agentName
+
+	loggingConfigBlock := body.AppendNewBlock("resource", []string{"aws_bedrock_model_invocation_logging_configuration", fmt.Sprintf("%s_invocation_logging", g.sanitizeResourceName(agentName))})
+	loggingConfigBody := loggingConfigBlock.Body()
+
+	loggingBlock := loggingConfigBody.AppendNewBlock("logging_config", nil)
+	loggingBody := loggingBlock.Body()
+
+	loggingBody.SetAttributeValue("text_data_delivery_enabled", cty.BoolVal(true))
+	loggingBody.SetAttributeValue("image_data_delivery_enabled", cty.BoolVal(false))
+	loggingBody.SetAttributeValue("embedding_data_delivery_enabled", cty.BoolVal(false))
+
+	cloudwatchBlock := loggingBody.AppendNewBlock("cloudwatch_config", nil)
+	cloudwatchBody := cloudwatchBlock.Body()
+
+	cloudwatchBody.SetAttributeRaw("log_group_name", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_cloudwatch_log_group.%s.name", logGroupResourceName))},
+	})
+	if err := g.setAgentRoleAttribute(cloudwatchBody, "role_arn", agentName, agent); err != nil {
+		return fmt.Errorf("failed to set logging role: %w", err)
+	}
+
+	body.AppendNewline()
+
+	g.logger.WithField("agent", agentName).WithField("logGroup", logGroupName).Info("Generated agent logging configuration")
+	return nil
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"bedrock-forge/internal/models"
+)
+
+// accountIDPattern matches AWS account IDs; managed policy ARNs use "aws" instead
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// arnReference is an ARN found in a resource spec
+type arnReference struct {
+	field string
+	arn   string
+}
+
+// checkArnTargets warns about ARNs whose account or region differ from the deployment target,
+// which otherwise only fail at apply time. It does nothing unless an account or region is configured.
+func (g *HCLGenerator) checkArnTargets(dependencyOrder []models.ResourceKind) {
+	if g.config.Account == "" && g.config.Region == "" && len(g.config.Providers) == 0 {
+		return
+	}
+
+	for _, kind := range dependencyOrder {
+		resources := g.registry.GetResourcesByType(kind)
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].Metadata.Name < resources[j].Metadata.Name
+		})
+
+		for _, resource := range resources {
+			// Resources pinned to a region or a named provider are deployed there rather than to the default target
+			expectedAccount, expectedRegion := g.config.Account, g.config.Region
+			if resource.Metadata.Region != "" {
+				expectedRegion = resource.Metadata.Region
+			}
+			if provider, exists := g.config.Providers[resource.Metadata.Provider]; exists {
+				if account := provider.Account(); account != "" {
+					expectedAccount = account
+				}
+				if provider.Region != "" {
+					expectedRegion = provider.Region
+				}
+			}
+
+			references, err := specArnReferences(resource.Spec)
+			if err != nil {
+				g.logger.WithError(err).WithField("resource", resourceKey(resource.Kind, resource.Metadata.Name)).Debug("Skipping ARN account check")
+				continue
+			}
+
+			for _, reference := range references {
+				// arn:partition:service:region:account:resource
+				parts := strings.SplitN(reference.arn, ":", 6)
+				if len(parts) < 6 {
+					continue
+				}
+				region, account := parts[3], parts[4]
+
+				logger := g.logger.WithFields(logrus.Fields{
+					"resource": resourceKey(resource.Kind, resource.Metadata.Name),
+					"field":    reference.field,
+					"arn":      reference.arn,
+				})
+				if expectedAccount != "" && accountIDPattern.MatchString(account) && account != expectedAccount {
+					logger.WithField("expected_account", expectedAccount).Warn("ARN belongs to a different account than the deployment target")
+				}
+				if expectedRegion != "" && region != "" && region != "*" && region != expectedRegion {
+					logger.WithField("expected_region", expectedRegion).Warn("ARN is in a different region than the deployment target")
+				}
+			}
+		}
+	}
+}
+
+// specArnReferences returns the literal ARNs in a resource spec with the YAML path of their field
+func specArnReferences(spec interface{}) ([]arnReference, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %w", err)
+	}
+
+	var references []arnReference
+	collectArnReferences("spec", value, &references)
+	return references, nil
+}
+
+func collectArnReferences(field string, value interface{}, references *[]arnReference) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectArnReferences(field+"."+key, v[key], references)
+		}
+	case []interface{}:
+		for i, item := range v {
+			collectArnReferences(fmt.Sprintf("%s[%d]", field, i), item, references)
+		}
+	case string:
+		// Terraform expressions are resolved at apply time and can't be checked here
+		if strings.HasPrefix(v, "arn:") && !strings.Contains(v, "${") {
+			*references = append(*references, arnReference{field: field, arn: v})
+		}
+	}
+}
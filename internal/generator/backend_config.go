@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// addBackendBlock adds the backend block to the terraform block
+func (g *HCLGenerator) addBackendBlock(terraformBody *hclwrite.Body) {
+	backend := g.config.Backend
+	if backend == nil {
+		return
+	}
+
+	backendBlock := terraformBody.AppendNewBlock("backend", []string{backend.Type})
+	backendBody := backendBlock.Body()
+
+	switch backend.Type {
+	case "s3":
+		backendBody.SetAttributeValue("bucket", cty.StringVal(backend.Bucket))
+		backendBody.SetAttributeValue("key", cty.StringVal(backend.Key))
+		backendBody.SetAttributeValue("region", cty.StringVal(backend.Region))
+		if backend.DynamoDBTable != "" {
+			backendBody.SetAttributeValue("dynamodb_table", cty.StringVal(backend.DynamoDBTable))
+		}
+		if backend.Encrypt != nil {
+			backendBody.SetAttributeValue("encrypt", cty.BoolVal(*backend.Encrypt))
+		}
+		if backend.KmsKeyId != "" {
+			backendBody.SetAttributeValue("kms_key_id", cty.StringVal(backend.KmsKeyId))
+		}
+	case "remote":
+		if backend.Hostname != "" {
+			backendBody.SetAttributeValue("hostname", cty.StringVal(backend.Hostname))
+		}
+		backendBody.SetAttributeValue("organization", cty.StringVal(backend.Organization))
+
+		workspacesBody := backendBody.AppendNewBlock("workspaces", nil).Body()
+		if backend.WorkspaceName != "" {
+			workspacesBody.SetAttributeValue("name", cty.StringVal(backend.WorkspaceName))
+		} else {
+			workspacesBody.SetAttributeValue("prefix", cty.StringVal(backend.WorkspacePrefix))
+		}
+	case "local":
+		if backend.Path != "" {
+			backendBody.SetAttributeValue("path", cty.StringVal(backend.Path))
+		}
+	}
+}
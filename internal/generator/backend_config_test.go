@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/pkg/config"
+)
+
+func TestTerraformBlockBackend(t *testing.T) {
+	encrypt := true
+	tests := []struct {
+		name       string
+		backend    *config.BackendConfig
+		attributes map[string]string
+		workspaces map[string]string
+	}{
+		{
+			name: "s3",
+			backend: &config.BackendConfig{
+				Type: "s3", Bucket: "tf-state", Key: "bedrock/terraform.tfstate", Region: "us-east-1",
+				DynamoDBTable: "tf-locks", Encrypt: &encrypt,
+			},
+			attributes: map[string]string{
+				"bucket":         "tf-state",
+				"key":            "bedrock/terraform.tfstate",
+				"region":         "us-east-1",
+				"dynamodb_table": "tf-locks",
+			},
+		},
+		{
+			name:       "remote",
+			backend:    &config.BackendConfig{Type: "remote", Organization: "company", WorkspacePrefix: "bedrock-"},
+			attributes: map[string]string{"organization": "company"},
+			workspaces: map[string]string{"prefix": "bedrock-"},
+		},
+		{
+			name:       "local",
+			backend:    &config.BackendConfig{Type: "local", Path: "state/terraform.tfstate"},
+			attributes: map[string]string{"path": "state/terraform.tfstate"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, "", &GeneratorConfig{Backend: test.backend})
+			body := hclwrite.NewEmptyFile().Body()
+			g.addTerraformBlock(body)
+
+			terraform := parseBody(t, body).Blocks[0]
+			var backends []*hclsyntax.Block
+			for _, block := range terraform.Body.Blocks {
+				if block.Type == "backend" {
+					backends = append(backends, block)
+				}
+			}
+			if len(backends) != 1 || backends[0].Labels[0] != test.backend.Type {
+				t.Fatalf("expected one %s backend, got %v", test.backend.Type, backends)
+			}
+
+			for name, want := range test.attributes {
+				if got := stringAttribute(t, backends[0], name); got != want {
+					t.Errorf("%s = %s, want %s", name, got, want)
+				}
+			}
+			for name, want := range test.workspaces {
+				if got := stringAttribute(t, backends[0].Body.Blocks[0], name); got != want {
+					t.Errorf("workspaces %s = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+
+	g, _ := newTestGenerator(t, "", nil)
+	body := hclwrite.NewEmptyFile().Body()
+	g.addTerraformBlock(body)
+	for _, block := range parseBody(t, body).Blocks[0].Body.Blocks {
+		if block.Type == "backend" {
+			t.Errorf("unexpected backend %v without a backend configured", block.Labels)
+		}
+	}
+}
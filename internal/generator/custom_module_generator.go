@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -39,13 +40,19 @@ func (g *HCLGenerator) generateCustomResourcesModule(body *hclwrite.Body, resour
 	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
 	g.logger.WithField("custom_resources", resource.Metadata.Name).Debug("Processing custom resources")
 
+	// Streamed output only carries main.tf, so the user's files can't be included
+	if g.config.Output != nil {
+		g.logger.WithField("custom_resources", resource.Metadata.Name).Warn("Custom resources terraform files are not included in streamed output")
+		return nil
+	}
+
 	// Copy user's .tf files to output directory
 	if err := g.copyUserTerraformFiles(customResources, resource.SourceFilePath); err != nil {
 		return fmt.Errorf("failed to copy user terraform files: %w", err)
 	}
 
 	// Generate variables.tf file for user's custom resources if variables are provided
-	if len(customResources.Variables) > 0 {
+	if len(customResources.Variables) > 0 || len(customResources.VariableDefinitions) > 0 {
 		if err := g.generateCustomResourcesVariables(customResources, resourceName); err != nil {
 			return fmt.Errorf("failed to generate variables for custom resources: %w", err)
 		}
@@ -77,7 +84,8 @@ func convertToCtyValue(value interface{}) (cty.Value, error) {
 			}
 			values = append(values, ctyItem)
 		}
-		return cty.ListVal(values), nil
+		// Tuples allow empty and mixed lists, the variable type converts them
+		return cty.TupleVal(values), nil
 	case map[string]interface{}:
 		values := make(map[string]cty.Value)
 		for key, val := range v {
@@ -226,42 +234,22 @@ func (g *HCLGenerator) generateCustomResourcesVariables(spec models.CustomResour
 		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
 	})
 
+	variables, err := customVariables(spec)
+	if err != nil {
+		return err
+	}
+
 	// Generate variable blocks
-	for varName, varValue := range spec.Variables {
-		varBlock := body.AppendNewBlock("variable", []string{varName})
-		varBody := varBlock.Body()
-
-		// Determine variable type and set default
-		switch v := varValue.(type) {
-		case string:
-			varBody.SetAttributeValue("type", cty.StringVal("string"))
-			varBody.SetAttributeValue("default", cty.StringVal(v))
-		case bool:
-			varBody.SetAttributeValue("type", cty.StringVal("bool"))
-			varBody.SetAttributeValue("default", cty.BoolVal(v))
-		case int, int64, float64:
-			varBody.SetAttributeValue("type", cty.StringVal("number"))
-			if num, ok := v.(int); ok {
-				varBody.SetAttributeValue("default", cty.NumberIntVal(int64(num)))
-			} else if num, ok := v.(int64); ok {
-				varBody.SetAttributeValue("default", cty.NumberIntVal(num))
-			} else if num, ok := v.(float64); ok {
-				varBody.SetAttributeValue("default", cty.NumberFloatVal(num))
-			}
-		case []interface{}:
-			varBody.SetAttributeValue("type", cty.StringVal("list"))
-			ctyValue, _ := convertToCtyValue(v)
-			varBody.SetAttributeValue("default", ctyValue)
-		case map[string]interface{}:
-			varBody.SetAttributeValue("type", cty.StringVal("map"))
-			ctyValue, _ := convertToCtyValue(v)
-			varBody.SetAttributeValue("default", ctyValue)
-		default:
-			// Fallback to string
-			varBody.SetAttributeValue("type", cty.StringVal("string"))
-			varBody.SetAttributeValue("default", cty.StringVal(fmt.Sprintf("%v", v)))
-		}
+	varNames := make([]string, 0, len(variables))
+	for varName := range variables {
+		varNames = append(varNames, varName)
+	}
+	sort.Strings(varNames)
 
+	for _, varName := range varNames {
+		if err := appendCustomVariable(body, varName, variables[varName]); err != nil {
+			return err
+		}
 		body.AppendNewline()
 	}
 
@@ -272,7 +260,8 @@ func (g *HCLGenerator) generateCustomResourcesVariables(spec models.CustomResour
 	}
 	defer file.Close()
 
-	_, err = file.Write(hclFile.Bytes())
+	// Formatting also spaces out the type expressions, which are written as single tokens
+	_, err = file.Write(hclwrite.Format(hclFile.Bytes()))
 	if err != nil {
 		return fmt.Errorf("failed to write variables file %s: %w", variablesPath, err)
 	}
@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// customOutputRefPattern matches ${ref:custom.<outputName>} references to outputs declared in CustomResources
+var customOutputRefPattern = regexp.MustCompile(`^\$\{ref:custom\.([A-Za-z_][A-Za-z0-9_-]*)\}$`)
+
+// IsCustomOutputReference reports whether a value uses the ${ref:custom.<outputName>} syntax
+func IsCustomOutputReference(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "${ref:custom.")
+}
+
+// customTerraformFile is what a custom terraform file declares and references
+type customTerraformFile struct {
+	addresses  []string // Resources and modules
+	references []customTerraformReference
+}
+
+// discoverCustomOutputs parses the Terraform files of all CustomResources and records their declared
+// outputs, resources, data sources and modules, and the addresses they reference
+func (g *HCLGenerator) discoverCustomOutputs() error {
+	g.customOutputs = make(map[string]hclwrite.Tokens)
+	g.customResourceAddresses = make(map[string][]string)
+	g.customReferences = make(map[string][]customTerraformReference)
+	g.customDeclarations = make(map[string]bool)
+	parsedFiles := make(map[string]*customTerraformFile)
+
+	for _, resource := range g.registry.GetResourcesByType(models.CustomResourcesKind) {
+		spec, ok := resource.Spec.(models.CustomResourcesSpec)
+		if !ok {
+			continue
+		}
+
+		files, err := g.customTerraformFiles(spec, resource.SourceFilePath)
+		if err != nil {
+			return fmt.Errorf("failed to list terraform files for custom resources %s: %w", resource.Metadata.Name, err)
+		}
+
+		for _, file := range files {
+			// The same file may be shared by several CustomResources but is only parsed once
+			file = filepath.Clean(file)
+			parsedFile, parsed := parsedFiles[file]
+			if !parsed {
+				parsedFile, err = g.parseCustomTerraformFile(file)
+				if err != nil {
+					return err
+				}
+				parsedFiles[file] = parsedFile
+			}
+			g.customResourceAddresses[resource.Metadata.Name] = append(g.customResourceAddresses[resource.Metadata.Name], parsedFile.addresses...)
+			g.customReferences[resource.Metadata.Name] = append(g.customReferences[resource.Metadata.Name], parsedFile.references...)
+		}
+	}
+
+	g.logger.WithField("outputs", len(g.customOutputs)).Debug("Discovered custom resource outputs")
+	return nil
+}
+
+// customTerraformFiles returns the .tf files copied for a CustomResources spec
+func (g *HCLGenerator) customTerraformFiles(spec models.CustomResourcesSpec, sourceFilePath string) ([]string, error) {
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(filepath.Dir(sourceFilePath), path)
+	}
+
+	var files []string
+	if spec.Path != "" {
+		srcPath := resolve(spec.Path)
+		info, err := os.Stat(srcPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat path %s: %w", srcPath, err)
+		}
+
+		if !info.IsDir() {
+			return []string{srcPath}, nil
+		}
+
+		err = filepath.Walk(srcPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(path, ".tf") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		return files, err
+	}
+
+	for _, file := range spec.Files {
+		files = append(files, resolve(file))
+	}
+	return files, nil
+}
+
+// parseCustomTerraformFile records the value expression of every output block in a Terraform file
+// and the addresses it declares, and returns the resources and modules it declares and what it references
+func (g *HCLGenerator) parseCustomTerraformFile(path string) (*customTerraformFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform file %s: %w", path, err)
+	}
+
+	syntaxFile, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse custom terraform:\n%w", diagnosticsError(diags))
+	}
+
+	// Output values are copied into the generated configuration as tokens
+	file, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse custom terraform:\n%w", diagnosticsError(diags))
+	}
+
+	parsed := &customTerraformFile{
+		references: customTerraformReferences(syntaxFile.Body.(*hclsyntax.Body)),
+	}
+	for _, block := range file.Body().Blocks() {
+		if block.Type() == "resource" || block.Type() == "module" || block.Type() == "data" {
+			address := blockAddress(block)
+			if address == "" {
+				continue
+			}
+			g.customDeclarations[address] = true
+			if block.Type() != "data" {
+				parsed.addresses = append(parsed.addresses, address)
+			}
+			continue
+		}
+
+		if block.Type() != "output" || len(block.Labels()) != 1 {
+			continue
+		}
+
+		valueAttr := block.Body().GetAttribute("value")
+		if valueAttr == nil {
+			continue
+		}
+
+		outputName := block.Labels()[0]
+		if _, exists := g.customOutputs[outputName]; exists {
+			return nil, fmt.Errorf("output '%s' is declared more than once in custom terraform files", outputName)
+		}
+		g.customOutputs[outputName] = valueAttr.Expr().BuildTokens(nil)
+	}
+
+	return parsed, nil
+}
+
+// resolveCustomOutputReference resolves a ${ref:custom.<outputName>} value to the expression of the declared output.
+// It returns false if the value is not a custom output reference.
+func (g *HCLGenerator) resolveCustomOutputReference(value string) (hclwrite.Tokens, bool, error) {
+	if !IsCustomOutputReference(value) {
+		return nil, false, nil
+	}
+
+	matches := customOutputRefPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return nil, true, fmt.Errorf("invalid custom output reference '%s', expected ${ref:custom.<outputName>}", value)
+	}
+
+	outputName := matches[1]
+	tokens, exists := g.customOutputs[outputName]
+	if !exists {
+		return nil, true, fmt.Errorf("output '%s' is not declared in any CustomResources terraform file (available: %s)",
+			outputName, strings.Join(g.customOutputNames(), ", "))
+	}
+
+	return tokens, true, nil
+}
+
+// setStringOrCustomOutput sets an attribute to a literal string or to a referenced custom output expression
+func (g *HCLGenerator) setStringOrCustomOutput(body *hclwrite.Body, attributeName string, value string) error {
+	tokens, isRef, err := g.resolveCustomOutputReference(value)
+	if err != nil {
+		return err
+	}
+
+	if isRef {
+		body.SetAttributeRaw(attributeName, tokens)
+		return nil
+	}
+
+	body.SetAttributeValue(attributeName, cty.StringVal(value))
+	return nil
+}
+
+// customOutputNames returns the sorted names of all discovered custom outputs
+func (g *HCLGenerator) customOutputNames() []string {
+	names := make([]string, 0, len(g.customOutputs))
+	for name := range g.customOutputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
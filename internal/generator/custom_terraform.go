@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/internal/models"
+)
+
+// customTerraformReference is a reference from a custom terraform file to a resource, data source or module
+type customTerraformReference struct {
+	Address string
+	Range   hcl.Range
+}
+
+// customTerraformRoots are traversal roots that never name a resource
+var customTerraformRoots = map[string]bool{
+	"var": true, "local": true, "count": true, "each": true, "path": true, "self": true, "terraform": true,
+}
+
+// diagnosticsError reports every error diagnostic with its file and line
+func diagnosticsError(diags hcl.Diagnostics) error {
+	var messages []string
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		message := diag.Summary
+		if diag.Detail != "" {
+			message = fmt.Sprintf("%s; %s", diag.Summary, diag.Detail)
+		}
+		if diag.Subject != nil {
+			message = fmt.Sprintf("%s:%d: %s", diag.Subject.Filename, diag.Subject.Start.Line, message)
+		}
+		messages = append(messages, message)
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "\n"))
+}
+
+// customTerraformReferences returns the resources, data sources and modules referenced in a parsed file
+func customTerraformReferences(body *hclsyntax.Body) []customTerraformReference {
+	var references []customTerraformReference
+	collectCustomTerraformReferences(body, nil, &references)
+
+	// Attributes are visited in map order
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].Range.Start.Byte < references[j].Range.Start.Byte
+	})
+	return references
+}
+
+// collectCustomTerraformReferences walks a body, skipping the iterators of enclosing dynamic blocks
+func collectCustomTerraformReferences(body *hclsyntax.Body, iterators map[string]bool, references *[]customTerraformReference) {
+	for name, attribute := range body.Attributes {
+		// ignore_changes lists attribute names rather than references
+		if name == "ignore_changes" {
+			continue
+		}
+		for _, traversal := range attribute.Expr.Variables() {
+			if address := referencedAddress(traversal, iterators); address != "" {
+				*references = append(*references, customTerraformReference{Address: address, Range: traversal.SourceRange()})
+			}
+		}
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "moved", "import", "removed":
+			// Their addresses are state addresses, which may no longer be declared
+			continue
+		case "dynamic":
+			blockIterators := make(map[string]bool, len(iterators)+1)
+			for iterator := range iterators {
+				blockIterators[iterator] = true
+			}
+			if len(block.Labels) == 1 {
+				blockIterators[block.Labels[0]] = true
+			}
+			if attribute, ok := block.Body.Attributes["iterator"]; ok {
+				if traversal, diags := hcl.AbsTraversalForExpr(attribute.Expr); !diags.HasErrors() {
+					blockIterators[traversal.RootName()] = true
+				}
+			}
+			collectCustomTerraformReferences(block.Body, blockIterators, references)
+		default:
+			collectCustomTerraformReferences(block.Body, iterators, references)
+		}
+	}
+}
+
+// referencedAddress returns the address a traversal refers to, or "" when it doesn't refer to a
+// resource, data source or module
+func referencedAddress(traversal hcl.Traversal, iterators map[string]bool) string {
+	root := traversal.RootName()
+	if customTerraformRoots[root] || iterators[root] {
+		return ""
+	}
+
+	var labels []string
+	for _, step := range traversal[1:] {
+		attribute, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		labels = append(labels, attribute.Name)
+	}
+
+	switch {
+	case root == "module" && len(labels) >= 1:
+		return fmt.Sprintf("module.%s", labels[0])
+	case root == "data" && len(labels) >= 2:
+		return fmt.Sprintf("data.%s.%s", labels[0], labels[1])
+	case strings.Contains(root, "_") && len(labels) >= 1:
+		// Resource types are prefixed with their provider
+		return fmt.Sprintf("%s.%s", root, labels[0])
+	}
+	return ""
+}
+
+// customTerraformDependencies returns the kinds of the project resources whose generated blocks the
+// terraform files of a CustomResources reference. Generated blocks are labelled with the sanitized
+// resource name, or prefixed with it, so the longest matching name wins.
+func (g *HCLGenerator) customTerraformDependencies(customResourcesName string) []models.ResourceKind {
+	var kinds []models.ResourceKind
+	for _, reference := range g.customReferences[customResourcesName] {
+		if g.customDeclarations[reference.Address] {
+			continue
+		}
+
+		label := reference.Address[strings.LastIndex(reference.Address, ".")+1:]
+		var match string
+		var matchKinds []models.ResourceKind
+		for kind, resources := range g.registry.GetAllResources() {
+			if kind == models.CustomResourcesKind {
+				continue
+			}
+			for name := range resources {
+				name = g.sanitizeResourceName(name)
+				if label != name && !strings.HasPrefix(label, name+"_") {
+					continue
+				}
+				switch {
+				case len(name) > len(match):
+					match = name
+					matchKinds = []models.ResourceKind{kind}
+				case len(name) == len(match) && !g.containsKind(matchKinds, kind):
+					matchKinds = append(matchKinds, kind)
+				}
+			}
+		}
+
+		for _, kind := range matchKinds {
+			if !g.containsKind(kinds, kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+// checkCustomReferences returns an error for every reference in custom terraform files to an address
+// that is neither generated nor declared in a custom terraform file, which terraform would reject at plan time
+func (g *HCLGenerator) checkCustomReferences(body *hclwrite.Body) error {
+	declared := make(map[string]bool, len(g.customDeclarations))
+	for address := range g.customDeclarations {
+		declared[address] = true
+	}
+	for _, block := range body.Blocks() {
+		if address := blockAddress(block); address != "" {
+			declared[address] = true
+		}
+	}
+
+	names := make([]string, 0, len(g.customReferences))
+	for name := range g.customReferences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// A file shared by several CustomResources is reported once
+	reported := make(map[hcl.Range]bool)
+	var messages []string
+	for _, name := range names {
+		for _, reference := range g.customReferences[name] {
+			if declared[reference.Address] || reported[reference.Range] {
+				continue
+			}
+			reported[reference.Range] = true
+			messages = append(messages, fmt.Sprintf("%s:%d: reference to undeclared %s (CustomResources %s)",
+				reference.Range.Filename, reference.Range.Start.Line, reference.Address, name))
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("custom terraform files reference resources that are neither generated nor declared:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
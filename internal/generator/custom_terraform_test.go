@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parseCustomTerraform parses the content of a custom terraform file
+func parseCustomTerraform(t *testing.T, content string) *hclsyntax.Body {
+	t.Helper()
+
+	file, diags := hclsyntax.ParseConfig([]byte(content), "notifications.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("ParseConfig: %s", diags.Error())
+	}
+	return file.Body.(*hclsyntax.Body)
+}
+
+func TestCustomTerraformReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "resources, data sources and modules",
+			content: `
+resource "aws_sns_topic_subscription" "alerts" {
+  topic_arn = aws_sns_topic.alerts.arn
+  endpoint  = "${module.alerting.queue_arn}/${data.aws_caller_identity.current.account_id}"
+  protocol  = var.protocol
+}
+`,
+			want: []string{"aws_sns_topic.alerts", "module.alerting", "data.aws_caller_identity.current"},
+		},
+		{
+			name: "dynamic block iterators",
+			content: `
+resource "aws_security_group" "agents" {
+  dynamic "ingress" {
+    for_each = aws_vpc_endpoint.bedrock.cidr_blocks
+    content {
+      cidr_blocks = [ingress.value]
+    }
+  }
+  dynamic "egress" {
+    for_each = local.egress_rules
+    iterator = rule
+    content {
+      cidr_blocks = [rule.value]
+      dynamic "tag" {
+        for_each = rule.value.tags
+        content {
+          key = tag.key
+        }
+      }
+    }
+  }
+}
+`,
+			want: []string{"aws_vpc_endpoint.bedrock"},
+		},
+		{
+			name: "ignore_changes",
+			content: `
+resource "aws_lambda_alias" "live" {
+  function_version = aws_lambda_function.handler.version
+  lifecycle {
+    ignore_changes = [function_version, routing_config]
+  }
+}
+`,
+			want: []string{"aws_lambda_function.handler"},
+		},
+		{
+			name: "moved, import and removed blocks",
+			content: `
+moved {
+  from = aws_sns_topic.old_alerts
+  to   = aws_sns_topic.alerts
+}
+
+import {
+  to = aws_sns_topic.imported
+  id = "arn:aws:sns:us-east-1:123456789012:imported"
+}
+
+removed {
+  from = aws_sns_topic.retired
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, reference := range customTerraformReferences(parseCustomTerraform(t, test.content)) {
+				got = append(got, reference.Address)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("references = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReferencedAddress(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"aws_sns_topic.alerts.arn", "aws_sns_topic.alerts"},
+		{"aws_sns_topic.alerts[0].arn", "aws_sns_topic.alerts"},
+		{"data.aws_iam_policy_document.assume.json", "data.aws_iam_policy_document.assume"},
+		{"module.alerting.queue_arn", "module.alerting"},
+		{"module.alerting", "module.alerting"},
+		{"var.environment", ""},
+		{"local.tags", ""},
+		{"each.value", ""},
+		{"count.index", ""},
+		{"path.module", ""},
+		{"self.arn", ""},
+		{"terraform.workspace", ""},
+		{"rule.value", ""}, // Iterator of an enclosing dynamic block
+		{"data.aws_region", ""},
+		{"aws_sns_topic", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			traversal, diags := hclsyntax.ParseTraversalAbs([]byte(test.expression), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("ParseTraversalAbs: %s", diags.Error())
+			}
+			if got := referencedAddress(traversal, map[string]bool{"rule": true}); got != test.want {
+				t.Errorf("referencedAddress(%s) = %q, want %q", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckCustomReferences(t *testing.T) {
+	g, _ := newTestGenerator(t, "", nil)
+	references := customTerraformReferences(parseCustomTerraform(t, `resource "aws_sns_topic_subscription" "alerts" {
+  topic_arn = aws_sns_topic.alerts.arn
+  endpoint  = aws_sqs_queue.alerts.arn
+  protocol  = aws_lambda_function.notifier.arn
+}
+`))
+	// The same file included by two CustomResources is reported once
+	g.customReferences = map[string][]customTerraformReference{
+		"notifications": references,
+		"monitoring":    references,
+	}
+	g.customDeclarations = map[string]bool{"aws_sns_topic.alerts": true}
+
+	body := hclwrite.NewEmptyFile().Body()
+	body.AppendNewBlock("resource", []string{"aws_lambda_function", "notifier"}).Body().SetAttributeValue("function_name", cty.StringVal("notifier"))
+
+	err := g.checkCustomReferences(body)
+	want := "custom terraform files reference resources that are neither generated nor declared:\n" +
+		"notifications.tf:3: reference to undeclared aws_sqs_queue.alerts (CustomResources monitoring)"
+	if err == nil || err.Error() != want {
+		t.Errorf("checkCustomReferences() = %v, want %q", err, want)
+	}
+
+	g.customDeclarations["aws_sqs_queue.alerts"] = true
+	if err := g.checkCustomReferences(body); err != nil {
+		t.Errorf("checkCustomReferences() with every address declared = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"bedrock-forge/internal/models"
+)
+
+// customVariables merges the plain variables, which only carry a default, with the variable definitions
+func customVariables(spec models.CustomResourcesSpec) (map[string]models.CustomVariable, error) {
+	variables := make(map[string]models.CustomVariable, len(spec.Variables)+len(spec.VariableDefinitions))
+	for name, value := range spec.Variables {
+		variables[name] = models.CustomVariable{Default: value}
+	}
+	for name, variable := range spec.VariableDefinitions {
+		if _, exists := variables[name]; exists {
+			return nil, fmt.Errorf("variable %s is set in both variables and variableDefinitions", name)
+		}
+		variables[name] = variable
+	}
+	return variables, nil
+}
+
+// appendCustomVariable writes a variable block. The type is the declared one or inferred from the
+// default, and the default must conform to it.
+func appendCustomVariable(body *hclwrite.Body, name string, variable models.CustomVariable) error {
+	if !hclsyntax.ValidIdentifier(name) {
+		return fmt.Errorf("variable name %s is not a valid Terraform identifier", name)
+	}
+
+	var defaultValue cty.Value
+	hasDefault := variable.Default != nil
+	if hasDefault {
+		var err error
+		defaultValue, err = convertToCtyValue(variable.Default)
+		if err != nil {
+			return fmt.Errorf("variable %s: invalid default: %w", name, err)
+		}
+	}
+
+	typeExpression := variable.Type
+	if typeExpression == "" {
+		variableType := cty.DynamicPseudoType
+		if hasDefault {
+			variableType = inferVariableType(defaultValue.Type())
+		}
+		typeExpression = typeexpr.TypeString(variableType)
+	}
+
+	variableType, err := parseTypeConstraint(typeExpression)
+	if err != nil {
+		return fmt.Errorf("variable %s: %w", name, err)
+	}
+	if hasDefault {
+		if _, err := convert.Convert(defaultValue, variableType); err != nil {
+			return fmt.Errorf("variable %s: default does not match type %s: %w", name, typeExpression, err)
+		}
+	}
+
+	variableBody := body.AppendNewBlock("variable", []string{name}).Body()
+	variableBody.SetAttributeRaw("type", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(typeExpression)}})
+	if variable.Description != "" {
+		variableBody.SetAttributeValue("description", cty.StringVal(variable.Description))
+	}
+	if hasDefault {
+		variableBody.SetAttributeValue("default", defaultValue)
+	}
+	if variable.Sensitive {
+		variableBody.SetAttributeValue("sensitive", cty.True)
+	}
+
+	for i, validation := range variable.Validations {
+		if err := checkValidationCondition(name, validation.Condition); err != nil {
+			return fmt.Errorf("variable %s: validation %d: %w", name, i+1, err)
+		}
+		if validation.ErrorMessage == "" {
+			return fmt.Errorf("variable %s: validation %d: errorMessage is required", name, i+1)
+		}
+
+		validationBody := variableBody.AppendNewBlock("validation", nil).Body()
+		validationBody.SetAttributeRaw("condition", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(validation.Condition)}})
+		validationBody.SetAttributeValue("error_message", cty.StringVal(validation.ErrorMessage))
+	}
+
+	return nil
+}
+
+// inferVariableType returns the type constraint of a default value: lists and maps when all elements
+// share a type, tuples and objects otherwise
+func inferVariableType(valueType cty.Type) cty.Type {
+	switch {
+	case valueType.IsTupleType():
+		elementTypes := valueType.TupleElementTypes()
+		if len(elementTypes) == 0 {
+			return cty.List(cty.DynamicPseudoType)
+		}
+		inferred := make([]cty.Type, len(elementTypes))
+		for i, elementType := range elementTypes {
+			inferred[i] = inferVariableType(elementType)
+		}
+		if sameTypes(inferred) {
+			return cty.List(inferred[0])
+		}
+		return cty.Tuple(inferred)
+
+	case valueType.IsObjectType():
+		attributeTypes := valueType.AttributeTypes()
+		if len(attributeTypes) == 0 {
+			return cty.Map(cty.DynamicPseudoType)
+		}
+		names := make([]string, 0, len(attributeTypes))
+		for name := range attributeTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		inferred := make([]cty.Type, len(names))
+		attributes := make(map[string]cty.Type, len(names))
+		validNames := true
+		for i, name := range names {
+			inferred[i] = inferVariableType(attributeTypes[name])
+			attributes[name] = inferred[i]
+			validNames = validNames && hclsyntax.ValidIdentifier(name)
+		}
+		if sameTypes(inferred) {
+			return cty.Map(inferred[0])
+		}
+		// Object type attributes must be identifiers
+		if !validNames {
+			return cty.DynamicPseudoType
+		}
+		return cty.Object(attributes)
+	}
+
+	return valueType
+}
+
+// sameTypes reports whether all types are equal
+func sameTypes(types []cty.Type) bool {
+	for _, t := range types[1:] {
+		if !t.Equals(types[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTypeConstraint parses a Terraform type constraint such as map(list(string))
+func parseTypeConstraint(typeExpression string) (cty.Type, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(typeExpression), "type", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, fmt.Errorf("invalid type %s: %s", typeExpression, diags.Error())
+	}
+	variableType, diags := typeexpr.TypeConstraint(expr)
+	if diags.HasErrors() {
+		return cty.NilType, fmt.Errorf("invalid type %s: %s", typeExpression, diags.Error())
+	}
+	return variableType, nil
+}
+
+// checkValidationCondition checks that a validation condition is an expression referring to the variable,
+// as Terraform requires
+func checkValidationCondition(name, condition string) error {
+	if condition == "" {
+		return fmt.Errorf("condition is required")
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(condition), "condition", hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("invalid condition %s: %s", condition, diags.Error())
+	}
+
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != "var" || len(traversal) < 2 {
+			continue
+		}
+		if attribute, ok := traversal[1].(hcl.TraverseAttr); ok && attribute.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("condition %s must refer to var.%s", condition, name)
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"bedrock-forge/internal/models"
+)
+
+func TestCustomResourcesVariablesRoundTrip(t *testing.T) {
+	spec := models.CustomResourcesSpec{
+		Variables: map[string]interface{}{
+			"bucket_name":  "agent-artifacts",
+			"retention":    30,
+			"enabled":      true,
+			"subnet_ids":   []interface{}{"subnet-a", "subnet-b"},
+			"tags":         map[string]interface{}{"team": "platform", "env": "dev"},
+			"settings":     map[string]interface{}{"name": "docs", "replicas": 2},
+			"alarm_levels": []interface{}{},
+		},
+		VariableDefinitions: map[string]models.CustomVariable{
+			"environment": {
+				Type:        "string",
+				Description: "Deployment environment",
+				Default:     "dev",
+				Validations: []models.CustomVariableValidation{{
+					Condition:    `contains(["dev", "prod"], var.environment)`,
+					ErrorMessage: "environment must be dev or prod.",
+				}},
+			},
+			"api_key": {
+				Type:      "string",
+				Sensitive: true,
+			},
+			"ports": {
+				Type:    "set(number)",
+				Default: []interface{}{80, 443},
+			},
+		},
+	}
+
+	// Types as Terraform reads them back, inferred from the default where not declared
+	want := map[string]cty.Type{
+		"bucket_name":  cty.String,
+		"retention":    cty.Number,
+		"enabled":      cty.Bool,
+		"subnet_ids":   cty.List(cty.String),
+		"tags":         cty.Map(cty.String),
+		"settings":     cty.Object(map[string]cty.Type{"name": cty.String, "replicas": cty.Number}),
+		"alarm_levels": cty.List(cty.DynamicPseudoType),
+		"environment":  cty.String,
+		"api_key":      cty.String,
+		"ports":        cty.Set(cty.Number),
+	}
+
+	outputDir := t.TempDir()
+	g, _ := newTestGenerator(t, "", &GeneratorConfig{OutputDir: outputDir})
+	if err := g.generateCustomResourcesVariables(spec, "shared"); err != nil {
+		t.Fatalf("generateCustomResourcesVariables: %v", err)
+	}
+
+	variablesPath := filepath.Join(outputDir, "variables_shared.tf")
+	file, diags := hclparse.NewParser().ParseHCLFile(variablesPath)
+	if diags.HasErrors() {
+		t.Fatalf("generated variables don't parse: %s", diags.Error())
+	}
+
+	blocks := file.Body.(*hclsyntax.Body).Blocks
+	if len(blocks) != len(want) {
+		t.Fatalf("expected %d variable blocks, got %d", len(want), len(blocks))
+	}
+
+	for _, block := range blocks {
+		if block.Type != "variable" || len(block.Labels) != 1 {
+			t.Fatalf("unexpected %s block %v", block.Type, block.Labels)
+		}
+		name := block.Labels[0]
+		wantType, ok := want[name]
+		if !ok {
+			t.Errorf("unexpected variable %s", name)
+			continue
+		}
+
+		typeAttribute, ok := block.Body.Attributes["type"]
+		if !ok {
+			t.Errorf("variable %s has no type", name)
+			continue
+		}
+		variableType, diags := typeexpr.TypeConstraint(typeAttribute.Expr)
+		if diags.HasErrors() {
+			t.Errorf("variable %s has an invalid type: %s", name, diags.Error())
+			continue
+		}
+		if !variableType.Equals(wantType) {
+			t.Errorf("variable %s has type %s, want %s", name, typeexpr.TypeString(variableType), typeexpr.TypeString(wantType))
+		}
+
+		if defaultAttribute, ok := block.Body.Attributes["default"]; ok {
+			defaultValue, diags := defaultAttribute.Expr.Value(nil)
+			if diags.HasErrors() {
+				t.Errorf("variable %s has an invalid default: %s", name, diags.Error())
+			} else if _, err := convert.Convert(defaultValue, variableType); err != nil {
+				t.Errorf("default of variable %s doesn't conform to its type: %v", name, err)
+			}
+		}
+	}
+
+	environment := findBlocks(file.Body.(*hclsyntax.Body), "variable", "environment")[0]
+	if description := stringAttribute(t, environment, "description"); description != "Deployment environment" {
+		t.Errorf("environment has description %q", description)
+	}
+	validations := environment.Body.Blocks
+	if len(validations) != 1 || validations[0].Type != "validation" {
+		t.Fatalf("expected a validation block on environment, got %v", validations)
+	}
+	condition := validations[0].Body.Attributes["condition"].Expr
+	if references := condition.Variables(); len(references) != 1 || references[0].RootName() != "var" {
+		t.Errorf("validation condition refers to %v, want var.environment", references)
+	}
+
+	apiKey := findBlocks(file.Body.(*hclsyntax.Body), "variable", "api_key")[0]
+	if sensitive, _ := apiKey.Body.Attributes["sensitive"].Expr.Value(nil); !sensitive.True() {
+		t.Errorf("api_key is not sensitive")
+	}
+
+	terraformValidate(t, outputDir)
+}
+
+// terraformValidate runs terraform validate on a directory when a terraform binary is available
+func terraformValidate(t *testing.T, dir string) {
+	t.Helper()
+
+	terraform, err := exec.LookPath("terraform")
+	if err != nil {
+		t.Log("terraform not found, skipping terraform validate")
+		return
+	}
+
+	// Keep terraform state and plugin caches out of the working directory
+	env := append(os.Environ(), "TF_DATA_DIR="+filepath.Join(t.TempDir(), ".terraform"), "TF_IN_AUTOMATION=1")
+	for _, args := range [][]string{{"init", "-backend=false", "-input=false"}, {"validate", "-no-color"}} {
+		command := exec.Command(terraform, args...)
+		command.Dir = dir
+		command.Env = env
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("terraform %s failed: %v\n%s", args[0], err, output)
+		}
+	}
+}
+
+func TestCustomResourcesVariableErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable models.CustomVariable
+		err      string
+	}{
+		{
+			name:     "unknown type",
+			variable: models.CustomVariable{Type: "list(strings)"},
+			err:      "invalid type",
+		},
+		{
+			name:     "quoted type",
+			variable: models.CustomVariable{Type: `"list"`},
+			err:      "invalid type",
+		},
+		{
+			name:     "default of another type",
+			variable: models.CustomVariable{Type: "number", Default: "ten"},
+			err:      "default does not match type number",
+		},
+		{
+			name: "condition on another variable",
+			variable: models.CustomVariable{
+				Type:        "string",
+				Validations: []models.CustomVariableValidation{{Condition: `var.other != ""`, ErrorMessage: "Required."}},
+			},
+			err: "must refer to var.value",
+		},
+		{
+			name: "missing error message",
+			variable: models.CustomVariable{
+				Type:        "string",
+				Validations: []models.CustomVariableValidation{{Condition: `var.value != ""`}},
+			},
+			err: "errorMessage is required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := appendCustomVariable(hclwrite.NewEmptyFile().Body(), "value", test.variable)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("appendCustomVariable() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+
+	spec := models.CustomResourcesSpec{
+		Variables:           map[string]interface{}{"name": "a"},
+		VariableDefinitions: map[string]models.CustomVariable{"name": {Type: "string"}},
+	}
+	if _, err := customVariables(spec); err == nil {
+		t.Errorf("expected an error for a variable set in both variables and variableDefinitions")
+	}
+}
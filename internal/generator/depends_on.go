@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/internal/models"
+)
+
+// resourceKey identifies a generated resource by kind and name
+func resourceKey(kind models.ResourceKind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// blockAddress returns the Terraform address of a resource, data or module block
+func blockAddress(block *hclwrite.Block) string {
+	labels := block.Labels()
+	switch {
+	case block.Type() == "resource" && len(labels) == 2:
+		return fmt.Sprintf("%s.%s", labels[0], labels[1])
+	case block.Type() == "data" && len(labels) == 2:
+		return fmt.Sprintf("data.%s.%s", labels[0], labels[1])
+	case block.Type() == "module" && len(labels) == 1:
+		return fmt.Sprintf("module.%s", labels[0])
+	}
+	return ""
+}
+
+// recordResourceBlocks remembers the blocks generated for a resource so explicit dependencies can target them
+func (g *HCLGenerator) recordResourceBlocks(resource models.BaseResource, blocks []*hclwrite.Block) {
+	if g.resourceBlocks == nil {
+		g.resourceBlocks = make(map[string][]*hclwrite.Block)
+	}
+	key := resourceKey(resource.Kind, resource.Metadata.Name)
+	g.resourceBlocks[key] = append(g.resourceBlocks[key], blocks...)
+}
+
+// resourceAddresses returns the Terraform addresses created for a resource
+func (g *HCLGenerator) resourceAddresses(kind models.ResourceKind, name string) []string {
+	// Custom resources live in the copied user files rather than in main.tf
+	if kind == models.CustomResourcesKind {
+		return g.customResourceAddresses[name]
+	}
+
+	var addresses []string
+	for _, block := range g.resourceBlocks[resourceKey(kind, name)] {
+		if block.Type() == "data" {
+			continue
+		}
+		if address := blockAddress(block); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// applyMetadataDependsOn adds depends_on for metadata.dependsOn to the blocks of every resource
+func (g *HCLGenerator) applyMetadataDependsOn(dependencyOrder []models.ResourceKind) error {
+	for _, kind := range dependencyOrder {
+		for _, resource := range g.registry.GetResourcesByType(kind) {
+			if len(resource.Metadata.DependsOn) == 0 {
+				continue
+			}
+
+			var addresses []string
+			for _, depRef := range resource.Metadata.DependsOn {
+				depKind := g.getResourceKindByName(depRef.String())
+				if depKind == "" {
+					return fmt.Errorf("%s %s depends on non-existent resource %s", resource.Kind, resource.Metadata.Name, depRef.String())
+				}
+
+				depAddresses := g.resourceAddresses(depKind, depRef.String())
+				if len(depAddresses) == 0 {
+					g.logger.WithField("dependency", depRef.String()).Warn("Dependency has no Terraform resources to depend on")
+				}
+				addresses = append(addresses, depAddresses...)
+			}
+
+			// Copied custom terraform files are not rewritten, so only their ordering is affected
+			if resource.Kind == models.CustomResourcesKind {
+				g.logger.WithField("custom_resources", resource.Metadata.Name).Warn("metadata.dependsOn on CustomResources only affects generation order, add depends_on to the terraform files directly")
+				continue
+			}
+
+			for _, block := range g.resourceBlocks[resourceKey(resource.Kind, resource.Metadata.Name)] {
+				if block.Type() != "resource" && block.Type() != "module" {
+					continue
+				}
+				appendDependsOn(block.Body(), addresses)
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendDependsOn adds addresses to a block's depends_on list, creating it if needed
+func appendDependsOn(body *hclwrite.Body, addresses []string) {
+	var existing []string
+	if attr := body.GetAttribute("depends_on"); attr != nil {
+		list := strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+		list = strings.TrimSuffix(strings.TrimPrefix(list, "["), "]")
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				existing = append(existing, item)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var merged []string
+	for _, address := range append(existing, addresses...) {
+		if !seen[address] {
+			seen[address] = true
+			merged = append(merged, address)
+		}
+	}
+	if len(merged) == 0 {
+		return
+	}
+
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	for i, address := range merged {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(address)})
+	}
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+
+	body.SetAttributeRaw("depends_on", tokens)
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/internal/models"
+)
+
+// Generation modes for resource kinds that can be generated either natively or as module calls
+const (
+	GenerationModeModule = "module"
+	GenerationModeNative = "native"
+)
+
+// resourceGenerator emits the Terraform blocks for a single resource
+type resourceGenerator func(body *hclwrite.Body, resource models.BaseResource) error
+
+// kindGenerators returns the generators available for each kind, keyed by generation mode
+func (g *HCLGenerator) kindGenerators() map[models.ResourceKind]map[string]resourceGenerator {
+	return map[models.ResourceKind]map[string]resourceGenerator{
+		models.AgentKind:                {GenerationModeNative: g.generateAgentNative},
+		models.LambdaKind:               {GenerationModeNative: g.generateLambdaNative},
+		models.LambdaLayerKind:          {GenerationModeNative: g.generateLambdaLayerNative},
+		models.ActionGroupKind:          {GenerationModeNative: g.generateActionGroupNative},
+		models.OpenSearchServerlessKind: {GenerationModeNative: g.generateOpenSearchServerlessModule},
+		models.KnowledgeBaseKind: {
+			GenerationModeModule: g.generateKnowledgeBaseModule,
+			GenerationModeNative: g.generateKnowledgeBaseNative,
+		},
+		models.GuardrailKind: {GenerationModeModule: g.generateGuardrailModule},
+		models.PromptKind:    {GenerationModeModule: g.generatePromptModule},
+		models.IAMRoleKind:   {GenerationModeModule: g.generateIAMRoleModule},
+		models.AgentKnowledgeBaseAssociationKind: {
+			GenerationModeModule: g.generateAgentKnowledgeBaseAssociationModule,
+			GenerationModeNative: g.generateAgentKnowledgeBaseAssociationNative,
+		},
+		// Custom Terraform files are copied as-is, whatever the mode
+		models.CustomResourcesKind: {
+			GenerationModeModule: g.generateCustomResourcesModule,
+			GenerationModeNative: g.generateCustomResourcesModule,
+		},
+	}
+}
+
+// generationModeFor returns the mode a kind is generated in. Without a configured mode each kind
+// uses its module generator when it has one.
+func (g *HCLGenerator) generationModeFor(kind models.ResourceKind) string {
+	if g.config.GenerationMode != "" {
+		return g.config.GenerationMode
+	}
+	if _, exists := g.kindGenerators()[kind][GenerationModeModule]; exists {
+		return GenerationModeModule
+	}
+	return GenerationModeNative
+}
+
+// resolveGenerator returns the generator for a kind in its generation mode
+func (g *HCLGenerator) resolveGenerator(kind models.ResourceKind) (resourceGenerator, error) {
+	generators, exists := g.kindGenerators()[kind]
+	if !exists {
+		return nil, fmt.Errorf("unsupported resource kind: %s", kind)
+	}
+
+	mode := g.generationModeFor(kind)
+	generator, exists := generators[mode]
+	if !exists {
+		return nil, fmt.Errorf("%s resources cannot be generated in %s mode, supported modes: %s", kind, mode, strings.Join(supportedModes(generators), ", "))
+	}
+	return generator, nil
+}
+
+// validateGenerationMode checks that every kind in the registry can be generated in the configured mode
+func (g *HCLGenerator) validateGenerationMode() error {
+	switch g.config.GenerationMode {
+	case "":
+		return nil
+	case GenerationModeModule, GenerationModeNative:
+	default:
+		return fmt.Errorf("unsupported generation mode '%s', must be one of: %s, %s", g.config.GenerationMode, GenerationModeModule, GenerationModeNative)
+	}
+
+	var problems []string
+	for kind := range g.registry.GetAllResources() {
+		if g.registry.GetResourceCount(kind) == 0 {
+			continue
+		}
+		if _, err := g.resolveGenerator(kind); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("resources cannot be generated in %s mode: %s", g.config.GenerationMode, strings.Join(problems, "; "))
+}
+
+// supportedModes lists the modes a kind can be generated in
+func supportedModes(generators map[string]resourceGenerator) []string {
+	modes := make([]string, 0, len(generators))
+	for mode := range generators {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
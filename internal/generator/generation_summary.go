@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+)
+
+// logGenerationSummary logs what was generated per kind, the IAM roles and Lambda permissions created
+// on behalf of other resources, and the order in which kinds were generated. That order has kinds
+// before the kinds they depend on, which Terraform doesn't mind, so it isn't a dependency order.
+func (g *HCLGenerator) logGenerationSummary(body *hclwrite.Body, dependencyOrder []models.ResourceKind) {
+	var generatedKinds []string
+	for _, kind := range dependencyOrder {
+		count := g.registry.GetResourceCount(kind)
+		if count == 0 {
+			continue
+		}
+		generatedKinds = append(generatedKinds, string(kind))
+		g.logger.WithFields(logrus.Fields{
+			"kind":  kind,
+			"count": count,
+		}).Info("Generated resources")
+	}
+
+	// Roles owned by IAMRole resources are counted above; the rest were created automatically
+	var autoRoles []string
+	for key, blocks := range g.resourceBlocks {
+		if strings.HasPrefix(key, string(models.IAMRoleKind)+"/") {
+			continue
+		}
+		for _, block := range blocks {
+			if labels := block.Labels(); block.Type() == "resource" && len(labels) == 2 && labels[0] == "aws_iam_role" {
+				autoRoles = append(autoRoles, key+" -> "+blockAddress(block))
+			}
+		}
+	}
+	sort.Strings(autoRoles)
+	for _, role := range autoRoles {
+		g.logger.WithField("role", role).Debug("Auto-generated IAM role")
+	}
+
+	lambdaPermissions := 0
+	for _, block := range body.Blocks() {
+		if labels := block.Labels(); block.Type() == "resource" && len(labels) == 2 && labels[0] == "aws_lambda_permission" {
+			lambdaPermissions++
+		}
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"resources":          g.registry.GetTotalResourceCount(),
+		"auto_iam_roles":     len(autoRoles),
+		"lambda_permissions": lambdaPermissions,
+		"generation_order":   strings.Join(generatedKinds, " -> "),
+	}).Info("Generation summary")
+}
@@ -1,8 +1,13 @@
 package generator
 
 import (
+	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
@@ -31,6 +36,10 @@ func (g *HCLGenerator) generateGuardrailModule(body *hclwrite.Body, resource mod
 		}
 	}
 
+	if err := guardrail.Validate(); err != nil {
+		return fmt.Errorf("invalid guardrail policy: %w", err)
+	}
+
 	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
 
 	// Create module block
@@ -38,11 +47,7 @@ func (g *HCLGenerator) generateGuardrailModule(body *hclwrite.Body, resource mod
 	moduleBody := moduleBlock.Body()
 
 	// Set module source
-	moduleSource := fmt.Sprintf("%s//modules/bedrock-guardrail", g.config.ModuleRegistry)
-	if g.config.ModuleVersion != "" {
-		moduleSource += fmt.Sprintf("?ref=%s", g.config.ModuleVersion)
-	}
-	moduleBody.SetAttributeValue("source", cty.StringVal(moduleSource))
+	g.setModuleSource(moduleBody, "bedrock-guardrail")
 
 	// Set basic attributes
 	moduleBody.SetAttributeValue("guardrail_name", cty.StringVal(resource.Metadata.Name))
@@ -150,12 +155,17 @@ func (g *HCLGenerator) generateGuardrailModule(body *hclwrite.Body, resource mod
 	if guardrail.WordPolicyConfig != nil {
 		wordPolicyValues := make(map[string]cty.Value)
 
-		if len(guardrail.WordPolicyConfig.WordsConfig) > 0 {
-			wordsList := make([]cty.Value, 0, len(guardrail.WordPolicyConfig.WordsConfig))
+		words, err := g.guardrailWords(guardrail.WordPolicyConfig, resource.SourceFilePath)
+		if err != nil {
+			return fmt.Errorf("guardrail %s: %w", resource.Metadata.Name, err)
+		}
+
+		if len(words) > 0 {
+			wordsList := make([]cty.Value, 0, len(words))
 
-			for _, word := range guardrail.WordPolicyConfig.WordsConfig {
+			for _, word := range words {
 				wordValues := make(map[string]cty.Value)
-				wordValues["text"] = cty.StringVal(word.Text)
+				wordValues["text"] = cty.StringVal(word)
 
 				wordsList = append(wordsList, cty.ObjectVal(wordValues))
 			}
@@ -195,3 +205,96 @@ func (g *HCLGenerator) generateGuardrailModule(body *hclwrite.Body, resource mod
 	g.logger.WithField("guardrail", resource.Metadata.Name).Info("Generated guardrail module")
 	return nil
 }
+
+// Bedrock limits on custom word filters
+const (
+	maxGuardrailWords      = 10000
+	maxGuardrailWordLength = 100
+)
+
+// guardrailWords merges inline words with entries from wordsFile, dropping duplicates
+func (g *HCLGenerator) guardrailWords(config *models.WordPolicyConfig, sourceFilePath string) ([]string, error) {
+	words := make([]string, 0, len(config.WordsConfig))
+	seen := make(map[string]bool)
+
+	add := func(word string) {
+		if word == "" || seen[word] {
+			return
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+
+	for _, word := range config.WordsConfig {
+		add(word.Text)
+	}
+
+	if config.WordsFile != "" {
+		fileWords, err := readWordsFile(config.WordsFile, sourceFilePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, word := range fileWords {
+			add(word)
+		}
+	}
+
+	if len(words) > maxGuardrailWords {
+		return nil, fmt.Errorf("word policy has %d words, Bedrock allows at most %d", len(words), maxGuardrailWords)
+	}
+	for _, word := range words {
+		if len(word) > maxGuardrailWordLength {
+			return nil, fmt.Errorf("word '%s' exceeds Bedrock's limit of %d characters", word, maxGuardrailWordLength)
+		}
+	}
+
+	return words, nil
+}
+
+// readWordsFile reads a word list, one word per line or comma-separated for .csv files
+func readWordsFile(path string, sourceFilePath string) ([]string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(sourceFilePath), path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open words file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var words []string
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read words file %s: %w", path, err)
+		}
+		for _, record := range records {
+			for _, field := range record {
+				if word := strings.TrimSpace(field); word != "" {
+					words = append(words, word)
+				}
+			}
+		}
+		return words, nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read words file %s: %w", path, err)
+	}
+
+	return words, nil
+}
@@ -1,10 +1,13 @@
 package generator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -15,6 +18,7 @@ import (
 
 	"bedrock-forge/internal/models"
 	"bedrock-forge/internal/registry"
+	"bedrock-forge/pkg/config"
 )
 
 // HCLGenerator handles the transformation of YAML resources to HCL Terraform modules
@@ -23,6 +27,34 @@ type HCLGenerator struct {
 	registry *registry.ResourceRegistry
 	config   *GeneratorConfig
 	context  *GenerationContext
+
+	// invocationLoggingAgent is the agent that owns the account-level model invocation logging configuration
+	invocationLoggingAgent string
+
+	// customOutputs maps outputs declared in CustomResources terraform files to their value expressions
+	customOutputs map[string]hclwrite.Tokens
+
+	// customResourceAddresses maps CustomResources names to the Terraform addresses declared in their files
+	customResourceAddresses map[string][]string
+
+	// customReferences maps CustomResources names to the addresses their terraform files reference
+	customReferences map[string][]customTerraformReference
+
+	// customDeclarations holds the resource, data and module addresses declared in custom terraform files
+	customDeclarations map[string]bool
+
+	// resourceBlocks maps Kind/name to the blocks generated for that resource
+	resourceBlocks map[string][]*hclwrite.Block
+
+	// environmentDataSources maps the data sources read by ${ssm:...} and ${secret:...} environment values
+	// to the Lambda variable they were generated for
+	environmentDataSources map[string]string
+
+	// externalLambdaWarnings records the owners already warned about a Lambda name used as an ARN
+	externalLambdaWarnings map[string]bool
+
+	// staleFiles are generated files from an earlier run that this run hasn't written yet
+	staleFiles map[string]bool
 }
 
 // GeneratorConfig holds configuration for HCL generation
@@ -33,6 +65,37 @@ type GeneratorConfig struct {
 	SourceDir      string
 	ProjectName    string
 	Environment    string
+	Backend        *config.BackendConfig
+
+	// DefaultLambdaKmsKeyArn encrypts environment variables of Lambdas that don't set their own key
+	DefaultLambdaKmsKeyArn string
+
+	// GenerationMode restricts generation to module calls or native resources. When empty, each kind
+	// uses its default generator.
+	GenerationMode string
+
+	// GlobalTags are added to the provider default_tags of every generated resource
+	GlobalTags map[string]string
+
+	// OutputLayout splits the configuration across files: single (default), per-kind or per-resource
+	OutputLayout string
+
+	// OutputSyntax writes the configuration as HCL (default) or as Terraform JSON (.tf.json)
+	OutputSyntax string
+
+	// Account and Region are the deployment target; ARNs pointing elsewhere are reported as warnings
+	Account string
+	Region  string
+
+	// Providers are the named AWS providers resources select with metadata.provider
+	Providers map[string]config.ProviderConfig
+
+	// GuardrailImportAddress is the guardrail resource inside the guardrail module that metadata.import
+	// adopts into, aws_bedrock_guardrail.this when empty
+	GuardrailImportAddress string
+
+	// Output receives main.tf instead of OutputDir when set; nothing is written to disk
+	Output io.Writer
 }
 
 // NewHCLGenerator creates a new HCL generator instance
@@ -54,9 +117,51 @@ func (g *HCLGenerator) SetGenerationContext(context *GenerationContext) {
 func (g *HCLGenerator) Generate() error {
 	g.logger.Info("Starting HCL generation...")
 
-	// Ensure output directory exists
-	if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %s: %w", g.config.OutputDir, err)
+	// Validate backend configuration before writing anything
+	if g.config.Backend != nil {
+		if err := g.config.Backend.Validate(); err != nil {
+			return fmt.Errorf("invalid backend configuration: %w", err)
+		}
+	}
+
+	// Fail before writing anything if a kind can't be generated in the configured mode
+	if err := g.validateGenerationMode(); err != nil {
+		return err
+	}
+
+	if err := g.validateModuleSource(); err != nil {
+		return err
+	}
+
+	if err := g.validateOutputLayout(); err != nil {
+		return err
+	}
+
+	if err := g.validateOutputSyntax(); err != nil {
+		return err
+	}
+
+	if err := g.validateResourceProviders(); err != nil {
+		return err
+	}
+
+	if g.config.Output == nil {
+		// Ensure output directory exists
+		if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory %s: %w", g.config.OutputDir, err)
+		}
+
+		// Files split off main.tf by an earlier run are removed once this run's files are written
+		staleFiles, err := g.findGeneratedFiles()
+		if err != nil {
+			return err
+		}
+		g.staleFiles = staleFiles
+	}
+
+	// Discover what custom terraform declares and references, so it can be referenced and ordered
+	if err := g.discoverCustomOutputs(); err != nil {
+		return fmt.Errorf("failed to read custom terraform files: %w", err)
 	}
 
 	// Build dependency graph
@@ -65,6 +170,8 @@ func (g *HCLGenerator) Generate() error {
 		return fmt.Errorf("failed to build dependency order: %w", err)
 	}
 
+	g.checkArnTargets(dependencyOrder)
+
 	// Generate main.tf file
 	mainFile := hclwrite.NewEmptyFile()
 	body := mainFile.Body()
@@ -91,16 +198,39 @@ func (g *HCLGenerator) Generate() error {
 		}
 	}
 
+	// Apply explicit metadata.dependsOn now that every resource block exists
+	if err := g.applyMetadataDependsOn(dependencyOrder); err != nil {
+		return fmt.Errorf("failed to apply resource dependencies: %w", err)
+	}
+
+	if err := g.checkCustomReferences(body); err != nil {
+		return err
+	}
+
+	// Migrate state of renamed resources
+	if err := g.addMovedBlocks(body, dependencyOrder); err != nil {
+		return fmt.Errorf("failed to add moved blocks: %w", err)
+	}
+
+	// Adopt existing AWS resources into state
+	if err := g.addImportBlocks(body, dependencyOrder); err != nil {
+		return fmt.Errorf("failed to add import blocks: %w", err)
+	}
+
 	// Add outputs block
-	g.addOutputsBlock(body)
+	if err := g.addOutputsBlock(body); err != nil {
+		return fmt.Errorf("failed to add outputs: %w", err)
+	}
 
-	// Write the file
-	outputPath := filepath.Join(g.config.OutputDir, "main.tf")
-	if err := g.writeHCLFile(outputPath, mainFile); err != nil {
-		return fmt.Errorf("failed to write main.tf: %w", err)
+	// Write the files
+	if err := g.writeConfiguration(mainFile); err != nil {
+		return err
+	}
+	if err := g.removeStaleGeneratedFiles(); err != nil {
+		return err
 	}
 
-	g.logger.WithField("output", outputPath).Info("Generated main.tf successfully")
+	g.logGenerationSummary(body, dependencyOrder)
 	return nil
 }
 
@@ -128,6 +258,7 @@ func (g *HCLGenerator) buildDependencyGraph() map[models.ResourceKind][]models.R
 		models.CustomResourcesKind,
 		models.GuardrailKind,
 		models.PromptKind,
+		models.LambdaLayerKind,
 		models.LambdaKind,
 		models.OpenSearchServerlessKind,
 		models.KnowledgeBaseKind,
@@ -167,6 +298,11 @@ func (g *HCLGenerator) extractResourceDependencies(resource models.BaseResource)
 			if agent.Guardrail != nil && !agent.Guardrail.Name.IsEmpty() {
 				dependencies = append(dependencies, models.GuardrailKind)
 			}
+			for _, alias := range agent.Aliases {
+				if alias.Guardrail != nil && !alias.Guardrail.Name.IsEmpty() {
+					dependencies = append(dependencies, models.GuardrailKind)
+				}
+			}
 
 			for _, promptOverride := range agent.PromptOverrides {
 				if !promptOverride.Prompt.IsEmpty() {
@@ -179,6 +315,19 @@ func (g *HCLGenerator) extractResourceDependencies(resource models.BaseResource)
 					dependencies = append(dependencies, models.LambdaKind)
 				}
 			}
+
+			// Supervisors also depend on their collaborator agents. Both are Agents, so there is no
+			// kind-level edge; Terraform orders them through the collaborator alias references.
+		}
+
+	case models.LambdaKind:
+		// Lambda depends on the layers defined as resources
+		if lambda, ok := resource.Spec.(models.LambdaSpec); ok {
+			for _, layer := range lambda.Layers {
+				if !layer.IsEmpty() && !models.IsExternalLayer(layer) {
+					dependencies = append(dependencies, models.LambdaLayerKind)
+				}
+			}
 		}
 
 	case models.ActionGroupKind:
@@ -235,9 +384,18 @@ func (g *HCLGenerator) extractResourceDependencies(resource models.BaseResource)
 				}
 			}
 		}
+		// And on the generated resources their terraform files reference
+		dependencies = append(dependencies, g.customTerraformDependencies(resource.Metadata.Name)...)
 
 	}
 
+	// Explicit metadata.dependsOn applies to every kind; dependencies within the same kind need no ordering
+	for _, depRef := range resource.Metadata.DependsOn {
+		if depKind := g.getResourceKindByName(depRef.String()); depKind != "" && depKind != resource.Kind {
+			dependencies = append(dependencies, depKind)
+		}
+	}
+
 	return dependencies
 }
 
@@ -248,6 +406,7 @@ func (g *HCLGenerator) getResourceKindByName(resourceName string) models.Resourc
 		models.CustomResourcesKind,
 		models.GuardrailKind,
 		models.PromptKind,
+		models.LambdaLayerKind,
 		models.LambdaKind,
 		models.OpenSearchServerlessKind,
 		models.KnowledgeBaseKind,
@@ -301,6 +460,11 @@ func (g *HCLGenerator) topologicalSort(graph map[models.ResourceKind][]models.Re
 		}
 	}
 
+	// Sort the initial queue so the generated order is stable across runs
+	sort.Slice(queue, func(i, j int) bool {
+		return queue[i] < queue[j]
+	})
+
 	// Process nodes
 	for len(queue) > 0 {
 		current := queue[0]
@@ -318,42 +482,186 @@ func (g *HCLGenerator) topologicalSort(graph map[models.ResourceKind][]models.Re
 
 	// Check for cycles
 	if len(result) != len(graph) {
+		if cycle := g.findResourceCycle(); len(cycle) > 0 {
+			return nil, fmt.Errorf("circular dependency detected: %s", strings.Join(cycle, " -> "))
+		}
+		if cycle := g.findKindCycle(graph); len(cycle) > 0 {
+			return nil, fmt.Errorf("circular dependency detected between resource kinds: %s", strings.Join(cycle, " -> "))
+		}
 		return nil, fmt.Errorf("circular dependency detected")
 	}
 
 	return result, nil
 }
 
+// findResourceCycle returns the chain of Kind/name pairs forming a dependency cycle between resources, if any
+func (g *HCLGenerator) findResourceCycle() []string {
+	var nodes []string
+	edges := make(map[string][]string)
+
+	for kind, resources := range g.registry.GetAllResources() {
+		for name, resource := range resources {
+			node := fmt.Sprintf("%s/%s", kind, name)
+			nodes = append(nodes, node)
+
+			for _, ref := range g.registry.GetResourceReferences(resource) {
+				if ref.Kind != "" && g.registry.HasResource(ref.Kind, ref.Name) {
+					edges[node] = append(edges[node], fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+				}
+			}
+		}
+	}
+
+	return findCycle(nodes, edges)
+}
+
+// findKindCycle returns the chain of resource kinds forming a cycle in the kind dependency graph, if any
+func (g *HCLGenerator) findKindCycle(graph map[models.ResourceKind][]models.ResourceKind) []string {
+	var nodes []string
+	edges := make(map[string][]string)
+
+	for kind, dependencies := range graph {
+		nodes = append(nodes, string(kind))
+		for _, dep := range dependencies {
+			edges[string(kind)] = append(edges[string(kind)], string(dep))
+		}
+	}
+
+	return findCycle(nodes, edges)
+}
+
+// findCycle performs a depth-first search with a recursion stack and returns the first cycle found,
+// starting and ending with the same node
+func findCycle(nodes []string, edges map[string][]string) []string {
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+
+	// Sort for deterministic diagnostics
+	sort.Strings(nodes)
+	for _, targets := range edges {
+		sort.Strings(targets)
+	}
+
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(node string) bool
+	visit = func(node string) bool {
+		state[node] = inStack
+		stack = append(stack, node)
+
+		for _, next := range edges[node] {
+			switch state[next] {
+			case inStack:
+				for i, stacked := range stack {
+					if stacked == next {
+						cycle = append(append([]string{}, stack[i:]...), next)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(next) {
+					return true
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[node] = done
+		return false
+	}
+
+	for _, node := range nodes {
+		if state[node] == unvisited && visit(node) {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
 // generateModuleCall creates a module call for a specific resource
 func (g *HCLGenerator) generateModuleCall(body *hclwrite.Body, resource models.BaseResource) error {
 	g.logger.WithFields(logrus.Fields{
 		"kind": resource.Kind,
 		"name": resource.Metadata.Name,
 	}).Debug("Generating module call")
-	switch resource.Kind {
-	case models.AgentKind:
-		return g.generateAgentNative(body, resource)
-	case models.LambdaKind:
-		return g.generateLambdaNative(body, resource)
-	case models.ActionGroupKind:
-		return g.generateActionGroupModule(body, resource)
-	case models.KnowledgeBaseKind:
-		return g.generateKnowledgeBaseModule(body, resource)
-	case models.GuardrailKind:
-		return g.generateGuardrailModule(body, resource)
-	case models.PromptKind:
-		return g.generatePromptModule(body, resource)
-	case models.IAMRoleKind:
-		return g.generateIAMRoleModule(body, resource)
-	case models.CustomResourcesKind:
-		return g.generateCustomResourcesModule(body, resource)
-	case models.OpenSearchServerlessKind:
-		return g.generateOpenSearchServerlessModule(body, resource)
-	case models.AgentKnowledgeBaseAssociationKind:
-		return g.generateAgentKnowledgeBaseAssociationModule(body, resource)
-	default:
-		return fmt.Errorf("unsupported resource kind: %s", resource.Kind)
+
+	// Track the blocks emitted for this resource so region and named providers can be applied to them
+	blocksBefore := len(body.Blocks())
+
+	generate, err := g.resolveGenerator(resource.Kind)
+	if err != nil {
+		return err
 	}
+	if err := generate(body, resource); err != nil {
+		return err
+	}
+
+	g.recordResourceBlocks(resource, body.Blocks()[blocksBefore:])
+
+	switch {
+	case resource.Metadata.Provider != "":
+		g.applyProvider(body.Blocks()[blocksBefore:], resource.Metadata.Provider)
+	case resource.Metadata.Region != "":
+		g.applyProvider(body.Blocks()[blocksBefore:], regionProviderAlias(resource.Metadata.Region))
+	}
+
+	return nil
+}
+
+// applyProvider pins the given blocks to an aliased AWS provider
+func (g *HCLGenerator) applyProvider(blocks []*hclwrite.Block, alias string) {
+	providerRef := fmt.Sprintf("aws.%s", alias)
+
+	for _, block := range blocks {
+		labels := block.Labels()
+		switch block.Type() {
+		case "resource", "data":
+			// Only AWS resources are routed through the aliased provider
+			if len(labels) > 0 && strings.HasPrefix(labels[0], "aws_") {
+				block.Body().SetAttributeRaw("provider", hclwrite.Tokens{
+					{Type: hclsyntax.TokenIdent, Bytes: []byte(providerRef)},
+				})
+			}
+		case "module":
+			block.Body().SetAttributeRaw("providers", hclwrite.Tokens{
+				{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+				{Type: hclsyntax.TokenIdent, Bytes: []byte("aws")},
+				{Type: hclsyntax.TokenEqual, Bytes: []byte("=")},
+				{Type: hclsyntax.TokenIdent, Bytes: []byte(providerRef)},
+				{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")},
+			})
+		}
+	}
+}
+
+// regionProviderAlias converts a region name into a valid provider alias
+func regionProviderAlias(region string) string {
+	return strings.ReplaceAll(region, "-", "_")
+}
+
+// getProviderRegions returns the regions referenced by resource metadata
+func (g *HCLGenerator) getProviderRegions() []string {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, resources := range g.registry.GetAllResources() {
+		for _, resource := range resources {
+			region := resource.Metadata.Region
+			if region != "" && !seen[region] {
+				seen[region] = true
+				regions = append(regions, region)
+			}
+		}
+	}
+
+	// Keep provider output stable across runs
+	sort.Strings(regions)
+	return regions
 }
 
 // addTerraformBlock adds the terraform configuration block
@@ -370,9 +678,20 @@ func (g *HCLGenerator) addTerraformBlock(body *hclwrite.Body) {
 		"version": cty.StringVal("~> 5.0"),
 	}))
 
+	// Vector indexes are created with the opensearch provider
+	if g.usesDeclarativeVectorIndex() {
+		reqProvidersBody.SetAttributeValue("opensearch", cty.ObjectVal(map[string]cty.Value{
+			"source":  cty.StringVal("opensearch-project/opensearch"),
+			"version": cty.StringVal("~> 2.3"),
+		}))
+	}
+
 	// Add required version
 	terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 1.0"))
 
+	// Add state backend
+	g.addBackendBlock(terraformBody)
+
 	body.AppendNewline()
 }
 
@@ -385,13 +704,109 @@ func (g *HCLGenerator) addProviderBlock(body *hclwrite.Body) {
 	defaultTagsBlock := providerBody.AppendNewBlock("default_tags", nil)
 	defaultTagsBody := defaultTagsBlock.Body()
 
-	defaultTagsBody.SetAttributeValue("tags", cty.ObjectVal(map[string]cty.Value{
+	defaultTagsBody.SetAttributeValue("tags", g.providerDefaultTags())
+
+	body.AppendNewline()
+
+	// Add an aliased provider per region
+	for _, region := range g.getProviderRegions() {
+		regionBlock := body.AppendNewBlock("provider", []string{"aws"})
+		regionBody := regionBlock.Body()
+
+		regionBody.SetAttributeValue("alias", cty.StringVal(regionProviderAlias(region)))
+		regionBody.SetAttributeValue("region", cty.StringVal(region))
+
+		regionTagsBlock := regionBody.AppendNewBlock("default_tags", nil)
+		regionTagsBlock.Body().SetAttributeValue("tags", g.providerDefaultTags())
+
+		body.AppendNewline()
+	}
+
+	// Add the named providers, e.g. for resources deployed to other accounts
+	names := make([]string, 0, len(g.config.Providers))
+	for name := range g.config.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		provider := g.config.Providers[name]
+		namedBody := body.AppendNewBlock("provider", []string{"aws"}).Body()
+
+		namedBody.SetAttributeValue("alias", cty.StringVal(name))
+		if provider.Region != "" {
+			namedBody.SetAttributeValue("region", cty.StringVal(provider.Region))
+		}
+
+		if assumeRole := provider.AssumeRole; assumeRole != nil {
+			assumeRoleBody := namedBody.AppendNewBlock("assume_role", nil).Body()
+			assumeRoleBody.SetAttributeValue("role_arn", cty.StringVal(assumeRole.RoleArn))
+			if assumeRole.SessionName != "" {
+				assumeRoleBody.SetAttributeValue("session_name", cty.StringVal(assumeRole.SessionName))
+			}
+			if assumeRole.ExternalID != "" {
+				assumeRoleBody.SetAttributeValue("external_id", cty.StringVal(assumeRole.ExternalID))
+			}
+		}
+
+		namedTagsBlock := namedBody.AppendNewBlock("default_tags", nil)
+		namedTagsBlock.Body().SetAttributeValue("tags", g.providerDefaultTags())
+
+		body.AppendNewline()
+	}
+}
+
+// validateResourceProviders checks that every metadata.provider names a declared provider, and that
+// named providers don't take the alias of a region provider
+func (g *HCLGenerator) validateResourceProviders() error {
+	var messages []string
+
+	regionAliases := make(map[string]string)
+	for _, region := range g.getProviderRegions() {
+		regionAliases[regionProviderAlias(region)] = region
+	}
+	for name := range g.config.Providers {
+		if region, exists := regionAliases[name]; exists {
+			messages = append(messages, fmt.Sprintf("provider '%s' has the same alias as the provider for region %s", name, region))
+		}
+	}
+
+	for kind, resources := range g.registry.GetAllResources() {
+		for name, resource := range resources {
+			provider := resource.Metadata.Provider
+			if provider == "" {
+				continue
+			}
+			if _, declared := g.config.Providers[provider]; !declared {
+				messages = append(messages, fmt.Sprintf("%s uses provider '%s', which is not declared in the providers of bedrock-forge.yaml", resourceKey(kind, name), provider))
+			}
+			if resource.Metadata.Region != "" {
+				messages = append(messages, fmt.Sprintf("%s sets both metadata.provider and metadata.region, set the region on the provider instead", resourceKey(kind, name)))
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return fmt.Errorf("invalid resource providers:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// providerDefaultTags returns the default tags applied by every AWS provider.
+// Global tags may replace Project and Environment, but ManagedBy is always bedrock-forge.
+func (g *HCLGenerator) providerDefaultTags() cty.Value {
+	tags := map[string]cty.Value{
 		"Project":     cty.StringVal(g.config.ProjectName),
 		"Environment": cty.StringVal(g.config.Environment),
-		"ManagedBy":   cty.StringVal("bedrock-forge"),
-	}))
+	}
 
-	body.AppendNewline()
+	for key, value := range g.config.GlobalTags {
+		tags[key] = cty.StringVal(value)
+	}
+
+	tags["ManagedBy"] = cty.StringVal("bedrock-forge")
+
+	return cty.ObjectVal(tags)
 }
 
 // addVariablesBlock adds common variables
@@ -418,7 +833,7 @@ func (g *HCLGenerator) addVariablesBlock(body *hclwrite.Body) {
 }
 
 // addOutputsBlock adds outputs for created resources
-func (g *HCLGenerator) addOutputsBlock(body *hclwrite.Body) {
+func (g *HCLGenerator) addOutputsBlock(body *hclwrite.Body) error {
 	// Add outputs for each resource type
 	agents := g.registry.GetResourcesByType(models.AgentKind)
 	for _, agent := range agents {
@@ -453,6 +868,48 @@ func (g *HCLGenerator) addOutputsBlock(body *hclwrite.Body) {
 			hcl.TraverseAttr{Name: agentName},
 			hcl.TraverseAttr{Name: "agent_version"},
 		})
+
+		// Agent alias outputs
+		if agentSpec, ok := agent.Spec.(models.AgentSpec); ok {
+			for _, alias := range agentSpec.Aliases {
+				aliasResourceName := g.agentAliasResourceName(agent.Metadata.Name, alias.Name)
+
+				aliasIdBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_id", aliasResourceName)})
+				aliasIdBody := aliasIdBlock.Body()
+				aliasIdBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ID of the %s alias of the %s agent", alias.Name, agent.Metadata.Name)))
+				aliasIdBody.SetAttributeTraversal("value", hcl.Traversal{
+					hcl.TraverseRoot{Name: "aws_bedrockagent_agent_alias"},
+					hcl.TraverseAttr{Name: aliasResourceName},
+					hcl.TraverseAttr{Name: "agent_alias_id"},
+				})
+
+				aliasArnBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_arn", aliasResourceName)})
+				aliasArnBody := aliasArnBlock.Body()
+				aliasArnBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ARN of the %s alias of the %s agent", alias.Name, agent.Metadata.Name)))
+				aliasArnBody.SetAttributeTraversal("value", hcl.Traversal{
+					hcl.TraverseRoot{Name: "aws_bedrockagent_agent_alias"},
+					hcl.TraverseAttr{Name: aliasResourceName},
+					hcl.TraverseAttr{Name: "agent_alias_arn"},
+				})
+
+				// Aliases can't carry a guardrail, so callers apply the alias guardrail from these outputs
+				if alias.Guardrail != nil && !alias.Guardrail.Name.IsEmpty() {
+					g.addAliasGuardrailOutputs(body, agent.Metadata.Name, alias)
+				}
+			}
+
+			// Prompt versions used by the agent's prompt overrides
+			for _, promptOverride := range agentSpec.PromptOverrides {
+				if promptOverride.Prompt.IsEmpty() {
+					continue
+				}
+
+				overrideVersionBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_%s_prompt_version", agentName, strings.ToLower(promptOverride.PromptType))})
+				overrideVersionBody := overrideVersionBlock.Body()
+				overrideVersionBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Version of the %s prompt override of the %s agent", promptOverride.PromptType, agent.Metadata.Name)))
+				overrideVersionBody.SetAttributeRaw("value", g.promptOverrideVersionTokens(promptOverride))
+			}
+		}
 	}
 
 	// Action Group outputs
@@ -465,7 +922,7 @@ func (g *HCLGenerator) addOutputsBlock(body *hclwrite.Body) {
 		agIdBody := agIdBlock.Body()
 		agIdBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ID of the %s action group", actionGroup.Metadata.Name)))
 		agIdBody.SetAttributeTraversal("value", hcl.Traversal{
-			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseRoot{Name: "aws_bedrockagent_agent_action_group"},
 			hcl.TraverseAttr{Name: agName},
 			hcl.TraverseAttr{Name: "action_group_id"},
 		})
@@ -561,13 +1018,120 @@ func (g *HCLGenerator) addOutputsBlock(body *hclwrite.Body) {
 			hcl.TraverseAttr{Name: lambdaName},
 			hcl.TraverseAttr{Name: "role"},
 		})
+
+		// Lambda Function URL output
+		if lambdaSpec, ok := lambda.Spec.(models.LambdaSpec); ok && lambdaSpec.FunctionURL != nil {
+			lambdaURLBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_lambda_function_url", lambdaName)})
+			lambdaURLBody := lambdaURLBlock.Body()
+			lambdaURLBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Function URL of the %s lambda function", lambda.Metadata.Name)))
+			lambdaURLBody.SetAttributeTraversal("value", hcl.Traversal{
+				hcl.TraverseRoot{Name: "aws_lambda_function_url"},
+				hcl.TraverseAttr{Name: lambdaName},
+				hcl.TraverseAttr{Name: "function_url"},
+			})
+		}
+	}
+
+	// Lambda Layer outputs
+	lambdaLayers := g.registry.GetResourcesByType(models.LambdaLayerKind)
+	for _, lambdaLayer := range lambdaLayers {
+		layerName := g.sanitizeResourceName(lambdaLayer.Metadata.Name)
+
+		// Lambda Layer Version ARN output
+		layerArnBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_lambda_layer_arn", layerName)})
+		layerArnBody := layerArnBlock.Body()
+		layerArnBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ARN of the current version of the %s lambda layer", lambdaLayer.Metadata.Name)))
+		layerArnBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "aws_lambda_layer_version"},
+			hcl.TraverseAttr{Name: layerName},
+			hcl.TraverseAttr{Name: "arn"},
+		})
+
+		// Lambda Layer Version output
+		layerVersionBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_lambda_layer_version", layerName)})
+		layerVersionBody := layerVersionBlock.Body()
+		layerVersionBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Version of the %s lambda layer", lambdaLayer.Metadata.Name)))
+		layerVersionBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "aws_lambda_layer_version"},
+			hcl.TraverseAttr{Name: layerName},
+			hcl.TraverseAttr{Name: "version"},
+		})
+	}
+
+	// Knowledge Base outputs
+	knowledgeBases := g.registry.GetResourcesByType(models.KnowledgeBaseKind)
+	for _, knowledgeBase := range knowledgeBases {
+		kbName := g.sanitizeResourceName(knowledgeBase.Metadata.Name)
+
+		// Native knowledge bases expose id/arn, the module exposes knowledge_base_id/knowledge_base_arn
+		kbRoot, kbIdAttr, kbArnAttr := "module", "knowledge_base_id", "knowledge_base_arn"
+		if g.generationModeFor(models.KnowledgeBaseKind) == GenerationModeNative {
+			kbRoot, kbIdAttr, kbArnAttr = "aws_bedrockagent_knowledge_base", "id", "arn"
+		}
+
+		// Knowledge Base ID output
+		kbIdBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_knowledge_base_id", kbName)})
+		kbIdBody := kbIdBlock.Body()
+		kbIdBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ID of the %s knowledge base", knowledgeBase.Metadata.Name)))
+		kbIdBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: kbRoot},
+			hcl.TraverseAttr{Name: kbName},
+			hcl.TraverseAttr{Name: kbIdAttr},
+		})
+
+		// Knowledge Base ARN output
+		kbArnBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_knowledge_base_arn", kbName)})
+		kbArnBody := kbArnBlock.Body()
+		kbArnBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ARN of the %s knowledge base", knowledgeBase.Metadata.Name)))
+		kbArnBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: kbRoot},
+			hcl.TraverseAttr{Name: kbName},
+			hcl.TraverseAttr{Name: kbArnAttr},
+		})
+	}
+
+	// Guardrail outputs
+	guardrails := g.registry.GetResourcesByType(models.GuardrailKind)
+	for _, guardrail := range guardrails {
+		guardrailName := g.sanitizeResourceName(guardrail.Metadata.Name)
+
+		// Guardrail ID output
+		guardrailIdBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_guardrail_id", guardrailName)})
+		guardrailIdBody := guardrailIdBlock.Body()
+		guardrailIdBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("ID of the %s guardrail", guardrail.Metadata.Name)))
+		guardrailIdBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: guardrailName},
+			hcl.TraverseAttr{Name: "guardrail_id"},
+		})
+
+		// Guardrail Version output
+		guardrailVersionBlock := body.AppendNewBlock("output", []string{fmt.Sprintf("%s_guardrail_version", guardrailName)})
+		guardrailVersionBody := guardrailVersionBlock.Body()
+		guardrailVersionBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Version of the %s guardrail", guardrail.Metadata.Name)))
+		guardrailVersionBody.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: guardrailName},
+			hcl.TraverseAttr{Name: "version"},
+		})
+	}
+
+	// Outputs requested through metadata.outputs
+	if err := g.addRequestedOutputs(body); err != nil {
+		return err
 	}
 
 	body.AppendNewline()
+	return nil
 }
 
 // sanitizeResourceName converts resource names to valid Terraform identifiers
 func (g *HCLGenerator) sanitizeResourceName(name string) string {
+	return SanitizeResourceName(name)
+}
+
+// SanitizeResourceName converts a resource name into the label used for Terraform blocks
+func SanitizeResourceName(name string) string {
 	// Replace hyphens and spaces with underscores
 	sanitized := strings.ReplaceAll(name, "-", "_")
 	sanitized = strings.ReplaceAll(sanitized, " ", "_")
@@ -580,7 +1144,10 @@ func (g *HCLGenerator) sanitizeResourceName(name string) string {
 
 // writeHCLFile writes the HCL file to disk
 func (g *HCLGenerator) writeHCLFile(path string, file *hclwrite.File) error {
-	content := file.Bytes()
+	content, err := g.encodeConfiguration(file.Bytes())
+	if err != nil {
+		return err
+	}
 
 	// Create directory if it doesn't exist
 	if err := g.ensureDir(filepath.Dir(path)); err != nil {
@@ -595,8 +1162,13 @@ func (g *HCLGenerator) ensureDir(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
-// writeFile writes content to a file
+// writeFile writes content to a file. A file that already has this content is left untouched, so
+// unchanged output keeps its modification time.
 func (g *HCLGenerator) writeFile(path string, content []byte) error {
+	delete(g.staleFiles, path)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
 	return os.WriteFile(path, content, 0644)
 }
 
@@ -632,8 +1204,22 @@ func (g *HCLGenerator) resolveReferenceToOutput(ref models.Reference, expectedKi
 		default:
 			return fmt.Sprintf("${aws_lambda_function.%s.%s}", sanitizedName, outputName), nil
 		}
+	case models.LambdaLayerKind:
+		return fmt.Sprintf("${aws_lambda_layer_version.%s.%s}", sanitizedName, outputName), nil
 	case models.IAMRoleKind:
 		return fmt.Sprintf("${aws_iam_role.%s.%s}", sanitizedName, outputName), nil
+	case models.KnowledgeBaseKind:
+		if g.generationModeFor(models.KnowledgeBaseKind) != GenerationModeNative {
+			return fmt.Sprintf("${module.%s.%s}", sanitizedName, outputName), nil
+		}
+		switch outputName {
+		case "knowledge_base_id":
+			return fmt.Sprintf("${aws_bedrockagent_knowledge_base.%s.id}", sanitizedName), nil
+		case "knowledge_base_arn":
+			return fmt.Sprintf("${aws_bedrockagent_knowledge_base.%s.arn}", sanitizedName), nil
+		default:
+			return fmt.Sprintf("${aws_bedrockagent_knowledge_base.%s.%s}", sanitizedName, outputName), nil
+		}
 	default:
 		// For other resource types, use the generic pattern
 		return fmt.Sprintf("${module.%s.%s}", sanitizedName, outputName), nil
@@ -675,11 +1261,7 @@ func (g *HCLGenerator) generateAgentKnowledgeBaseAssociationModule(body *hclwrit
 	moduleBody := moduleBlock.Body()
 
 	// Set module source
-	moduleSource := fmt.Sprintf("%s//modules/bedrock-agent-knowledge-base-association", g.config.ModuleRegistry)
-	if g.config.ModuleVersion != "" {
-		moduleSource += fmt.Sprintf("?ref=%s", g.config.ModuleVersion)
-	}
-	moduleBody.SetAttributeValue("source", cty.StringVal(moduleSource))
+	g.setModuleSource(moduleBody, "bedrock-agent-knowledge-base-association")
 
 	// Set basic attributes
 	moduleBody.SetAttributeValue("association_name", cty.StringVal(resource.Metadata.Name))
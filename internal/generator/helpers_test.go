@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+)
+
+// newTestGenerator creates a generator over the resources parsed from content, with its log entries
+// recorded by the returned hook
+func newTestGenerator(t *testing.T, content string, config *GeneratorConfig) (*HCLGenerator, *test.Hook) {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	resourceRegistry := registry.NewResourceRegistry(logger)
+	if content != "" {
+		resources, err := parser.NewYAMLParser(logger).ParseContent([]byte(content), "resources.yml")
+		if err != nil {
+			t.Fatalf("ParseContent: %v", err)
+		}
+		for _, resource := range resources {
+			if err := resourceRegistry.AddResource(resource); err != nil {
+				t.Fatalf("AddResource: %v", err)
+			}
+		}
+	}
+
+	if config == nil {
+		config = &GeneratorConfig{}
+	}
+	return NewHCLGenerator(logger, resourceRegistry, config), hook
+}
+
+// parseBody parses generated configuration back, failing the test if it isn't valid HCL
+func parseBody(t *testing.T, body *hclwrite.Body) *hclsyntax.Body {
+	t.Helper()
+
+	file := hclwrite.NewEmptyFile()
+	for _, block := range body.Blocks() {
+		file.Body().AppendBlock(block)
+	}
+
+	parsed, diags := hclsyntax.ParseConfig(file.Bytes(), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("generated configuration doesn't parse: %s\n%s", diags.Error(), file.Bytes())
+	}
+	return parsed.Body.(*hclsyntax.Body)
+}
+
+// findBlocks returns the blocks of a type whose first label is firstLabel
+func findBlocks(body *hclsyntax.Body, blockType, firstLabel string) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, block := range body.Blocks {
+		if block.Type == blockType && len(block.Labels) > 0 && block.Labels[0] == firstLabel {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// stringAttribute evaluates a literal string attribute of a block
+func stringAttribute(t *testing.T, block *hclsyntax.Block, name string) string {
+	t.Helper()
+
+	attribute, ok := block.Body.Attributes[name]
+	if !ok {
+		t.Fatalf("%s %v has no %s", block.Type, block.Labels, name)
+	}
+	value, diags := attribute.Expr.Value(nil)
+	if diags.HasErrors() {
+		t.Fatalf("%s of %s %v is not a literal: %s", name, block.Type, block.Labels, diags.Error())
+	}
+	return value.AsString()
+}
+
+// referenceAttribute returns the address an attribute refers to, e.g. "aws_iam_role.agent.arn"
+func referenceAttribute(t *testing.T, block *hclsyntax.Block, name string) string {
+	t.Helper()
+
+	attribute, ok := block.Body.Attributes[name]
+	if !ok {
+		t.Fatalf("%s %v has no %s", block.Type, block.Labels, name)
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(attribute.Expr)
+	if diags.HasErrors() {
+		t.Fatalf("%s of %s %v is not a reference: %s", name, block.Type, block.Labels, diags.Error())
+	}
+
+	address := traversal.RootName()
+	for _, step := range traversal[1:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			address += "." + attr.Name
+		}
+	}
+	return address
+}
+
+// templateAttribute renders a template string attribute of a block with its interpolated references
+// kept as ${address}, e.g. a generated policy document
+func templateAttribute(t *testing.T, block *hclsyntax.Block, name string) string {
+	t.Helper()
+
+	attribute, ok := block.Body.Attributes[name]
+	if !ok {
+		t.Fatalf("%s %v has no %s", block.Type, block.Labels, name)
+	}
+	template, ok := attribute.Expr.(*hclsyntax.TemplateExpr)
+	if !ok {
+		return stringAttribute(t, block, name)
+	}
+
+	var rendered strings.Builder
+	for _, part := range template.Parts {
+		switch part := part.(type) {
+		case *hclsyntax.LiteralValueExpr:
+			rendered.WriteString(part.Val.AsString())
+		case *hclsyntax.ScopeTraversalExpr:
+			address := part.Traversal.RootName()
+			for _, step := range part.Traversal[1:] {
+				if attr, ok := step.(hcl.TraverseAttr); ok {
+					address += "." + attr.Name
+				}
+			}
+			rendered.WriteString("${" + address + "}")
+		default:
+			t.Fatalf("%s of %s %v interpolates an unsupported %T", name, block.Type, block.Labels, part)
+		}
+	}
+	return rendered.String()
+}
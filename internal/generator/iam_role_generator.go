@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
@@ -25,8 +26,7 @@ func (g *HCLGenerator) generateIAMRoleModule(body *hclwrite.Body, resource model
 	moduleBody := moduleBlock.Body()
 
 	// Set module source
-	moduleBody.SetAttributeValue("source", cty.StringVal(fmt.Sprintf("%s//modules/iam-role?ref=%s",
-		g.config.ModuleRegistry, g.config.ModuleVersion)))
+	g.setModuleSource(moduleBody, "iam-role")
 
 	// Set basic attributes
 	moduleBody.SetAttributeValue("role_name", cty.StringVal(resource.Metadata.Name))
@@ -216,3 +216,42 @@ func (g *HCLGenerator) buildPolicyDocument(policy *models.IAMPolicyDocument) cty
 		"statement": cty.ListVal(statements),
 	})
 }
+
+// setGeneratedRoleTags tags an auto-generated IAM role with the tags of the resource it belongs to,
+// so it is covered by the same cost allocation and tagging policies
+func setGeneratedRoleTags(roleBody *hclwrite.Body, parentTags map[string]string, roleType string) {
+	tagValues := make(map[string]cty.Value)
+	for key, value := range models.GeneratedRoleTags(parentTags, roleType) {
+		tagValues[key] = cty.StringVal(value)
+	}
+	roleBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
+}
+
+// assumeRolePolicyDocument returns the trust policy letting an AWS service assume a generated role,
+// with optional conditions keyed by operator
+func assumeRolePolicyDocument(service string, conditions map[string]map[string]string) (string, error) {
+	type statement struct {
+		Action    string                       `json:"Action"`
+		Effect    string                       `json:"Effect"`
+		Principal map[string]string            `json:"Principal"`
+		Condition map[string]map[string]string `json:"Condition,omitempty"`
+	}
+	document := struct {
+		Version   string      `json:"Version"`
+		Statement []statement `json:"Statement"`
+	}{
+		Version: "2012-10-17",
+		Statement: []statement{{
+			Action:    "sts:AssumeRole",
+			Effect:    "Allow",
+			Principal: map[string]string{"Service": service},
+			Condition: conditions,
+		}},
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode assume role policy: %w", err)
+	}
+	return string(content), nil
+}
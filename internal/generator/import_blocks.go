@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// importTargets are the blocks that metadata.import adopts into state, per supported kind
+var importTargets = map[models.ResourceKind]struct{ blockType, resourceType string }{
+	models.AgentKind:  {"resource", "aws_bedrockagent_agent"},
+	models.LambdaKind: {"resource", "aws_lambda_function"},
+	// Guardrails are module calls, imported into the guardrail resource inside the module
+	models.GuardrailKind: {"module", ""},
+}
+
+// defaultGuardrailImportAddress is the guardrail resource inside the bedrock-guardrail module
+const defaultGuardrailImportAddress = "aws_bedrock_guardrail.this"
+
+// importBlocksMinimumVersion is the first Terraform version supporting import blocks
+var importBlocksMinimumVersion = []int{1, 5}
+
+// minimumVersionPattern matches a required_version that only sets a minimum, e.g. ">= 1.0"
+var minimumVersionPattern = regexp.MustCompile(`^\s*>=\s*(\d+(?:\.\d+)*)\s*$`)
+
+// addImportBlocks emits import blocks for resources with metadata.import, so existing AWS resources are
+// adopted into state instead of being created again. IDs are checked by the registry beforehand.
+func (g *HCLGenerator) addImportBlocks(body *hclwrite.Body, dependencyOrder []models.ResourceKind) error {
+	imported := 0
+	for _, kind := range dependencyOrder {
+		for _, resource := range g.registry.GetResourcesByType(kind) {
+			id := resource.Metadata.Import
+			if id == "" {
+				continue
+			}
+
+			target, supported := importTargets[kind]
+			if !supported {
+				return fmt.Errorf("metadata.import is not supported for %s %s", kind, resource.Metadata.Name)
+			}
+
+			var to string
+			for _, block := range g.resourceBlocks[resourceKey(kind, resource.Metadata.Name)] {
+				labels := block.Labels()
+				if block.Type() != target.blockType || (target.resourceType != "" && labels[0] != target.resourceType) {
+					continue
+				}
+				to = blockAddress(block)
+				break
+			}
+			if to == "" {
+				return fmt.Errorf("no %s block was generated for %s %s to import into", target.blockType, kind, resource.Metadata.Name)
+			}
+
+			if kind == models.GuardrailKind {
+				address := g.config.GuardrailImportAddress
+				if address == "" {
+					address = defaultGuardrailImportAddress
+				}
+				to = fmt.Sprintf("%s.%s", to, address)
+				// The provider imports a guardrail by ID and version; the generated guardrail is the working draft
+				if !strings.Contains(id, ",") {
+					id += ",DRAFT"
+				}
+			}
+
+			importBody := body.AppendNewBlock("import", nil).Body()
+			importBody.SetAttributeRaw("to", hclwrite.Tokens{
+				{Type: hclsyntax.TokenIdent, Bytes: []byte(to)},
+			})
+			importBody.SetAttributeValue("id", cty.StringVal(id))
+			body.AppendNewline()
+			imported++
+
+			g.logger.WithField("resource", resourceKey(kind, resource.Metadata.Name)).Debug("Generated import block")
+		}
+	}
+
+	if imported > 0 {
+		if terraformBlock := body.FirstMatchingBlock("terraform", nil); terraformBlock != nil {
+			raiseRequiredVersion(terraformBlock.Body(), importBlocksMinimumVersion)
+		}
+	}
+
+	return nil
+}
+
+// raiseRequiredVersion sets required_version to ">= minimum" unless it already requires that version or a
+// later one. A constraint other than a plain minimum is left alone.
+func raiseRequiredVersion(terraformBody *hclwrite.Body, minimum []int) {
+	formatted := make([]string, len(minimum))
+	for i, part := range minimum {
+		formatted[i] = strconv.Itoa(part)
+	}
+	required := cty.StringVal(">= " + strings.Join(formatted, "."))
+
+	attribute := terraformBody.GetAttribute("required_version")
+	if attribute == nil {
+		terraformBody.SetAttributeValue("required_version", required)
+		return
+	}
+
+	expression, diags := hclsyntax.ParseExpression(attribute.Expr().BuildTokens(nil).Bytes(), "required_version", hcl.InitialPos)
+	if diags.HasErrors() {
+		return
+	}
+	value, diags := expression.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String {
+		return
+	}
+	match := minimumVersionPattern.FindStringSubmatch(value.AsString())
+	if match == nil {
+		return
+	}
+
+	current := strings.Split(match[1], ".")
+	for i, part := range minimum {
+		currentPart := 0
+		if i < len(current) {
+			currentPart, _ = strconv.Atoi(current[i])
+		}
+		if currentPart > part {
+			return
+		}
+		if currentPart < part {
+			terraformBody.SetAttributeValue("required_version", required)
+			return
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const importResources = `
+kind: Agent
+metadata:
+  name: support-agent
+  import: ABCDE12345
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You are a helpful customer support agent.
+---
+kind: Guardrail
+metadata:
+  name: content-safety
+  import: abcdef123456
+spec:
+  blockedInputMessaging: Blocked
+  blockedOutputsMessaging: Blocked
+  contentPolicyConfig:
+    filtersConfig:
+      - type: HATE
+        inputStrength: HIGH
+        outputStrength: HIGH
+`
+
+// generateConfiguration runs a full generation into memory and parses the resulting main.tf
+func generateConfiguration(t *testing.T, g *HCLGenerator) *hclsyntax.Body {
+	t.Helper()
+
+	var output bytes.Buffer
+	g.config.Output = &output
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	parsed, diags := hclsyntax.ParseConfig(output.Bytes(), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("generated configuration doesn't parse: %s\n%s", diags.Error(), output.Bytes())
+	}
+	return parsed.Body.(*hclsyntax.Body)
+}
+
+// importedIDs maps the target address of each import block to its ID
+func importedIDs(t *testing.T, body *hclsyntax.Body) map[string]string {
+	t.Helper()
+
+	imports := make(map[string]string)
+	for _, block := range body.Blocks {
+		if block.Type == "import" {
+			imports[referenceAttribute(t, block, "to")] = stringAttribute(t, block, "id")
+		}
+	}
+	return imports
+}
+
+func TestImportBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    map[string]string
+	}{
+		{
+			name: "default guardrail address",
+			want: map[string]string{
+				"aws_bedrockagent_agent.support_agent":             "ABCDE12345",
+				"module.content_safety.aws_bedrock_guardrail.this": "abcdef123456,DRAFT",
+			},
+		},
+		{
+			name:    "configured guardrail address",
+			address: "module.guardrail.aws_bedrock_guardrail.main",
+			want: map[string]string{
+				"aws_bedrockagent_agent.support_agent":                              "ABCDE12345",
+				"module.content_safety.module.guardrail.aws_bedrock_guardrail.main": "abcdef123456,DRAFT",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, importResources, &GeneratorConfig{GuardrailImportAddress: test.address})
+			body := generateConfiguration(t, g)
+
+			got := importedIDs(t, body)
+			if len(got) != len(test.want) {
+				t.Errorf("imports = %v, want %v", got, test.want)
+			}
+			for to, id := range test.want {
+				if got[to] != id {
+					t.Errorf("import into %s = %q, want %q", to, got[to], id)
+				}
+			}
+
+			var terraform []*hclsyntax.Block
+			for _, block := range body.Blocks {
+				if block.Type == "terraform" {
+					terraform = append(terraform, block)
+				}
+			}
+			if len(terraform) != 1 {
+				t.Fatalf("expected 1 terraform block, got %d", len(terraform))
+			}
+			if got := stringAttribute(t, terraform[0], "required_version"); got != ">= 1.5" {
+				t.Errorf("required_version = %q, want >= 1.5", got)
+			}
+		})
+	}
+}
+
+func TestImportBlocksUnsupportedKind(t *testing.T) {
+	g, _ := newTestGenerator(t, `
+kind: Lambda
+metadata:
+  name: order-lookup
+  import: order-lookup
+spec:
+  runtime: python3.11
+  handler: index.handler
+  code:
+    inline: "def handler(event, context): return event"
+---
+kind: Prompt
+metadata:
+  name: support-prompt
+  import: PROMPT12345
+spec:
+  defaultVariant: production
+  variants:
+    - name: production
+      modelId: anthropic.claude-3-sonnet-20240229-v1:0
+      templateType: TEXT
+      templateConfiguration:
+        text:
+          text: "You are a support agent. {{question}}"
+`, nil)
+
+	var output bytes.Buffer
+	g.config.Output = &output
+	err := g.Generate()
+	if err == nil || err.Error() != "failed to add import blocks: metadata.import is not supported for Prompt support-prompt" {
+		t.Errorf("Generate() = %v, want the unsupported import error", err)
+	}
+}
+
+func TestRaiseRequiredVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{name: "unset", want: ">= 1.5"},
+		{name: "lower minimum", current: ">= 1.0", want: ">= 1.5"},
+		{name: "lower patch minimum", current: ">= 1.4.7", want: ">= 1.5"},
+		{name: "same minimum", current: ">= 1.5.0", want: ">= 1.5.0"},
+		{name: "higher minor minimum", current: ">= 1.6", want: ">= 1.6"},
+		{name: "higher major minimum", current: ">=2", want: ">=2"},
+		{name: "other constraint", current: "~> 1.3", want: "~> 1.3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := hclwrite.NewEmptyFile()
+			terraformBody := file.Body().AppendNewBlock("terraform", nil).Body()
+			if test.current != "" {
+				terraformBody.SetAttributeValue("required_version", cty.StringVal(test.current))
+			}
+
+			raiseRequiredVersion(terraformBody, importBlocksMinimumVersion)
+
+			terraform := parseBody(t, file.Body()).Blocks[0]
+			if got := stringAttribute(t, terraform, "required_version"); got != test.want {
+				t.Errorf("required_version = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
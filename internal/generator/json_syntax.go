@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Output syntaxes of the generated configuration
+const (
+	OutputSyntaxHCL  = "hcl"  // main.tf, ...
+	OutputSyntaxJSON = "json" // main.tf.json, ... in Terraform's JSON configuration syntax
+)
+
+// jsonCommentKey is the property Terraform ignores as a comment in JSON configuration
+const jsonCommentKey = "//"
+
+// rawExpressionAttributes are the meta-arguments whose JSON form is a bare expression rather than a
+// "${...}" template, keyed by the block type they appear in ("" for any block)
+var rawExpressionAttributes = map[string]map[string]bool{
+	"":          {"depends_on": true, "provider": true, "providers": true},
+	"lifecycle": {"ignore_changes": true, "replace_triggered_by": true},
+	"moved":     {"from": true, "to": true},
+	"import":    {"to": true},
+	"variable":  {"type": true},
+}
+
+// validateOutputSyntax checks the configured output syntax
+func (g *HCLGenerator) validateOutputSyntax() error {
+	switch g.config.OutputSyntax {
+	case "", OutputSyntaxHCL, OutputSyntaxJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output syntax '%s', must be one of: %s, %s", g.config.OutputSyntax, OutputSyntaxHCL, OutputSyntaxJSON)
+	}
+}
+
+// outputFileName returns the name a generated .tf file is written under in the configured syntax
+func (g *HCLGenerator) outputFileName(fileName string) string {
+	if g.config.OutputSyntax == OutputSyntaxJSON {
+		return fileName + ".json"
+	}
+	return fileName
+}
+
+// encodeConfiguration returns the generated HCL in the configured output syntax
+func (g *HCLGenerator) encodeConfiguration(src []byte) ([]byte, error) {
+	if g.config.OutputSyntax != OutputSyntaxJSON {
+		return src, nil
+	}
+	return hclToJSON(src)
+}
+
+// hclToJSON converts generated HCL to Terraform's JSON configuration syntax. Blocks become nested
+// objects keyed by type and labels, literal values become JSON values and any other expression
+// becomes a "${...}" template. A leading generated file header is kept as a "//" comment.
+func hclToJSON(src []byte) ([]byte, error) {
+	file, diags := hclsyntax.ParseConfig(src, "generated.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse generated configuration: %s", diags.Error())
+	}
+
+	root, err := bodyToJSON(file.Body.(*hclsyntax.Body), src, "")
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(src, []byte(generatedFileHeader)) {
+		root[jsonCommentKey] = strings.TrimPrefix(generatedFileHeader, "# ")
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON configuration: %w", err)
+	}
+
+	// Catch conversion mistakes here rather than at terraform init
+	if _, diags := hcljson.Parse(out.Bytes(), "generated.tf.json"); diags.HasErrors() {
+		return nil, fmt.Errorf("generated JSON configuration is invalid: %s", diags.Error())
+	}
+	return out.Bytes(), nil
+}
+
+// bodyToJSON converts the attributes and nested blocks of a body
+func bodyToJSON(body *hclsyntax.Body, src []byte, blockType string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	for name, attribute := range body.Attributes {
+		raw := rawExpressionAttributes[""][name] || rawExpressionAttributes[blockType][name]
+		value, err := expressionToJSON(attribute.Expr, src, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result[name] = value
+	}
+
+	for _, block := range body.Blocks {
+		content, err := bodyToJSON(block.Body, src, block.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", block.Type, strings.Join(block.Labels, "."), err)
+		}
+
+		parent := result
+		key := block.Type
+		for _, label := range block.Labels {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[key] = child
+			}
+			parent, key = child, label
+		}
+
+		// A single block is an object, repeated blocks a list of objects
+		switch existing := parent[key].(type) {
+		case nil:
+			parent[key] = content
+		case []interface{}:
+			parent[key] = append(existing, content)
+		default:
+			parent[key] = []interface{}{existing, content}
+		}
+	}
+
+	return result, nil
+}
+
+// expressionToJSON converts an attribute expression. With raw set, references are written as bare
+// expressions as meta-arguments require.
+func expressionToJSON(expr hclsyntax.Expression, src []byte, raw bool) (interface{}, error) {
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return literalToJSON(e.Val)
+
+	case *hclsyntax.TemplateExpr:
+		if e.IsStringLiteral() {
+			value, _ := e.Value(nil)
+			if raw {
+				return value.AsString(), nil
+			}
+			return escapeTemplate(value.AsString()), nil
+		}
+		var template strings.Builder
+		for _, part := range e.Parts {
+			if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+				template.WriteString(escapeTemplate(literal.Val.AsString()))
+				continue
+			}
+			template.WriteString("${" + expressionSource(part, src) + "}")
+		}
+		return template.String(), nil
+
+	case *hclsyntax.TemplateWrapExpr:
+		return "${" + expressionSource(e.Wrapped, src) + "}", nil
+
+	case *hclsyntax.TupleConsExpr:
+		values := make([]interface{}, 0, len(e.Exprs))
+		for _, element := range e.Exprs {
+			value, err := expressionToJSON(element, src, raw)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+
+	case *hclsyntax.ObjectConsExpr:
+		values := make(map[string]interface{}, len(e.Items))
+		for _, item := range e.Items {
+			key, err := objectKey(item.KeyExpr)
+			if err != nil {
+				return nil, err
+			}
+			// Object keys are templates too, except in meta-arguments
+			if !raw {
+				key = escapeTemplate(key)
+			}
+			value, err := expressionToJSON(item.ValueExpr, src, raw)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = value
+		}
+		return values, nil
+	}
+
+	if raw {
+		return expressionSource(expr, src), nil
+	}
+	return "${" + expressionSource(expr, src) + "}", nil
+}
+
+// literalToJSON converts a literal value to its JSON form
+func literalToJSON(value cty.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	if value.Type() == cty.String {
+		return escapeTemplate(value.AsString()), nil
+	}
+
+	encoded, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode literal: %w", err)
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// objectKey returns the key of an object item, written either as a bare name or a literal string
+func objectKey(expr hclsyntax.Expression) (string, error) {
+	if keyword := hcl.ExprAsKeyword(expr); keyword != "" {
+		return keyword, nil
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+		return "", fmt.Errorf("object keys must be literal strings in JSON configuration")
+	}
+	return value.AsString(), nil
+}
+
+// expressionSource returns the HCL source of an expression
+func expressionSource(expr hclsyntax.Expression, src []byte) string {
+	return string(expr.Range().SliceBytes(src))
+}
+
+// escapeTemplate escapes a literal string so Terraform doesn't read it as a template
+func escapeTemplate(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+}
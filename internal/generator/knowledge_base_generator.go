@@ -10,6 +10,15 @@ import (
 	"bedrock-forge/internal/models"
 )
 
+// Types of the hierarchical chunking module input, which is null for data sources that don't use it
+var (
+	hierarchicalLevelType    = cty.Object(map[string]cty.Type{"max_tokens": cty.Number})
+	hierarchicalChunkingType = cty.Object(map[string]cty.Type{
+		"level_configurations": cty.List(hierarchicalLevelType),
+		"overlap_tokens":       cty.Number,
+	})
+)
+
 // generateKnowledgeBaseModule creates a module call for a KnowledgeBase resource
 func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource models.BaseResource) error {
 	knowledgeBase, ok := resource.Spec.(models.KnowledgeBaseSpec)
@@ -33,16 +42,26 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 
 	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
 
+	// An explicitly configured role replaces the one the module creates, and is generated here when auto-created
+	if knowledgeBase.IAMRole != nil {
+		var collectionArn string
+		if knowledgeBase.IAMRole.CreatesRole() && knowledgeBase.StorageConfiguration != nil {
+			var err error
+			if collectionArn, _, err = g.resolveKnowledgeBaseCollection(resource.Metadata.Name, knowledgeBase.StorageConfiguration); err != nil {
+				return err
+			}
+		}
+		if err := g.handleKnowledgeBaseExecutionRole(body, resource.Metadata.Name, knowledgeBase, collectionArn); err != nil {
+			return err
+		}
+	}
+
 	// Create module block
 	moduleBlock := body.AppendNewBlock("module", []string{resourceName})
 	moduleBody := moduleBlock.Body()
 
 	// Set module source
-	moduleSource := fmt.Sprintf("%s//modules/bedrock-knowledge-base", g.config.ModuleRegistry)
-	if g.config.ModuleVersion != "" {
-		moduleSource += fmt.Sprintf("?ref=%s", g.config.ModuleVersion)
-	}
-	moduleBody.SetAttributeValue("source", cty.StringVal(moduleSource))
+	g.setModuleSource(moduleBody, "bedrock-knowledge-base")
 
 	// Set basic attributes
 	moduleBody.SetAttributeValue("knowledge_base_name", cty.StringVal(resource.Metadata.Name))
@@ -52,6 +71,12 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 		moduleBody.SetAttributeValue("description", cty.StringVal(knowledgeBase.Description))
 	}
 
+	if knowledgeBase.IAMRole != nil {
+		if err := g.setKnowledgeBaseRoleAttribute(moduleBody, "role_arn", resource.Metadata.Name, knowledgeBase); err != nil {
+			return err
+		}
+	}
+
 	// Knowledge base configuration
 	if knowledgeBase.KnowledgeBaseConfiguration != nil {
 		kbConfigValues := make(map[string]cty.Value)
@@ -146,9 +171,13 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 		dataSourceList := make([]cty.Value, 0, len(knowledgeBase.DataSources))
 
 		for _, dataSource := range knowledgeBase.DataSources {
+			if err := dataSource.Validate(); err != nil {
+				return err
+			}
+
 			dsValues := make(map[string]cty.Value)
 			dsValues["name"] = cty.StringVal(dataSource.Name)
-			dsValues["type"] = cty.StringVal(dataSource.Type)
+			dsValues["type"] = cty.StringVal(dataSource.APIType())
 
 			// S3 configuration
 			if dataSource.S3Configuration != nil {
@@ -186,6 +215,11 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 				}))
 			}
 
+			// Connector configurations, always present so every data source has the same type
+			dsValues["confluence_configuration"] = confluenceConfigurationValue(dataSource.ConfluenceConfiguration)
+			dsValues["share_point_configuration"] = sharePointConfigurationValue(dataSource.SharePointConfiguration)
+			dsValues["web_configuration"] = webConfigurationValue(dataSource.WebConfiguration)
+
 			// Chunking configuration
 			if dataSource.ChunkingConfiguration != nil {
 				chunkingValues := make(map[string]cty.Value)
@@ -220,6 +254,25 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 					}))
 				}
 
+				if hierarchicalConfig := dataSource.ChunkingConfiguration.HierarchicalChunkingConfiguration; hierarchicalConfig != nil {
+					levels := cty.ListValEmpty(hierarchicalLevelType)
+					if len(hierarchicalConfig.LevelConfigurations) > 0 {
+						levelValues := make([]cty.Value, 0, len(hierarchicalConfig.LevelConfigurations))
+						for _, level := range hierarchicalConfig.LevelConfigurations {
+							levelValues = append(levelValues, cty.ObjectVal(map[string]cty.Value{
+								"max_tokens": cty.NumberIntVal(int64(level.MaxTokens)),
+							}))
+						}
+						levels = cty.ListVal(levelValues)
+					}
+					chunkingValues["hierarchical_chunking_configuration"] = cty.ObjectVal(map[string]cty.Value{
+						"level_configurations": levels,
+						"overlap_tokens":       cty.NumberIntVal(int64(hierarchicalConfig.OverlapTokens)),
+					})
+				} else {
+					chunkingValues["hierarchical_chunking_configuration"] = cty.NullVal(hierarchicalChunkingType)
+				}
+
 				dsValues["chunking_configuration"] = cty.ObjectVal(chunkingValues)
 			} else {
 				// Ensure chunking_configuration is always present for consistency
@@ -229,6 +282,7 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 						"max_tokens":         cty.Number,
 						"overlap_percentage": cty.Number,
 					}),
+					"hierarchical_chunking_configuration": hierarchicalChunkingType,
 					"semantic_chunking_configuration": cty.Object(map[string]cty.Type{
 						"max_tokens":                      cty.Number,
 						"buffer_size":                     cty.Number,
@@ -345,3 +399,108 @@ func (g *HCLGenerator) generateKnowledgeBaseModule(body *hclwrite.Body, resource
 	g.logger.WithField("knowledge_base", resource.Metadata.Name).Info("Generated knowledge base module")
 	return nil
 }
+
+var crawlFilterType = cty.Object(map[string]cty.Type{
+	"object_type":       cty.String,
+	"inclusion_filters": cty.List(cty.String),
+	"exclusion_filters": cty.List(cty.String),
+})
+
+// confluenceConfigurationValue converts a Confluence configuration to a module input, or a typed null
+func confluenceConfigurationValue(config *models.ConfluenceConfiguration) cty.Value {
+	if config == nil {
+		return cty.NullVal(cty.Object(map[string]cty.Type{
+			"host_url":               cty.String,
+			"host_type":              cty.String,
+			"auth_type":              cty.String,
+			"credentials_secret_arn": cty.String,
+			"filters":                cty.List(crawlFilterType),
+		}))
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"host_url":               cty.StringVal(config.HostURL),
+		"host_type":              cty.StringVal(stringOrDefault(config.HostType, "SAAS")),
+		"auth_type":              cty.StringVal(config.AuthType),
+		"credentials_secret_arn": cty.StringVal(config.CredentialsSecretArn),
+		"filters":                crawlFiltersValue(config.Filters),
+	})
+}
+
+// sharePointConfigurationValue converts a SharePoint configuration to a module input, or a typed null
+func sharePointConfigurationValue(config *models.SharePointConfiguration) cty.Value {
+	if config == nil {
+		return cty.NullVal(cty.Object(map[string]cty.Type{
+			"domain":                 cty.String,
+			"site_urls":              cty.List(cty.String),
+			"tenant_id":              cty.String,
+			"host_type":              cty.String,
+			"auth_type":              cty.String,
+			"credentials_secret_arn": cty.String,
+			"filters":                cty.List(crawlFilterType),
+		}))
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"domain":                 cty.StringVal(config.Domain),
+		"site_urls":              stringListValue(config.SiteURLs),
+		"tenant_id":              cty.StringVal(config.TenantID),
+		"host_type":              cty.StringVal(stringOrDefault(config.HostType, "ONLINE")),
+		"auth_type":              cty.StringVal(config.AuthType),
+		"credentials_secret_arn": cty.StringVal(config.CredentialsSecretArn),
+		"filters":                crawlFiltersValue(config.Filters),
+	})
+}
+
+// webConfigurationValue converts a web crawler configuration to a module input, or a typed null
+func webConfigurationValue(config *models.WebConfiguration) cty.Value {
+	if config == nil {
+		return cty.NullVal(cty.Object(map[string]cty.Type{
+			"seed_urls":         cty.List(cty.String),
+			"scope":             cty.String,
+			"rate_limit":        cty.Number,
+			"inclusion_filters": cty.List(cty.String),
+			"exclusion_filters": cty.List(cty.String),
+		}))
+	}
+
+	values := map[string]cty.Value{
+		"seed_urls":         stringListValue(config.SeedURLs),
+		"scope":             cty.NullVal(cty.String),
+		"rate_limit":        cty.NullVal(cty.Number),
+		"inclusion_filters": optionalStringListValue(config.InclusionFilters),
+		"exclusion_filters": optionalStringListValue(config.ExclusionFilters),
+	}
+	if config.Scope != "" {
+		values["scope"] = cty.StringVal(config.Scope)
+	}
+	if config.RateLimit > 0 {
+		values["rate_limit"] = cty.NumberIntVal(int64(config.RateLimit))
+	}
+
+	return cty.ObjectVal(values)
+}
+
+func crawlFiltersValue(filters []models.CrawlFilter) cty.Value {
+	if len(filters) == 0 {
+		return cty.NullVal(cty.List(crawlFilterType))
+	}
+
+	values := make([]cty.Value, 0, len(filters))
+	for _, filter := range filters {
+		values = append(values, cty.ObjectVal(map[string]cty.Value{
+			"object_type":       cty.StringVal(filter.ObjectType),
+			"inclusion_filters": optionalStringListValue(filter.InclusionFilters),
+			"exclusion_filters": optionalStringListValue(filter.ExclusionFilters),
+		}))
+	}
+	return cty.ListVal(values)
+}
+
+// optionalStringListValue returns a typed null for empty lists
+func optionalStringListValue(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.NullVal(cty.List(cty.String))
+	}
+	return stringListValue(values)
+}
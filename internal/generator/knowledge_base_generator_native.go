@@ -0,0 +1,560 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// generateKnowledgeBaseNative creates a native aws_bedrockagent_knowledge_base with its execution role and data sources
+func (g *HCLGenerator) generateKnowledgeBaseNative(body *hclwrite.Body, resource models.BaseResource) error {
+	knowledgeBase, ok := resource.Spec.(models.KnowledgeBaseSpec)
+	if !ok {
+		// Try to parse as map and convert to KnowledgeBaseSpec
+		specMap, mapOk := resource.Spec.(map[string]interface{})
+		if !mapOk {
+			return fmt.Errorf("invalid knowledge base spec format")
+		}
+
+		specJSON, err := json.Marshal(specMap)
+		if err != nil {
+			return fmt.Errorf("failed to marshal knowledge base spec: %w", err)
+		}
+
+		if err := json.Unmarshal(specJSON, &knowledgeBase); err != nil {
+			return fmt.Errorf("failed to unmarshal knowledge base spec: %w", err)
+		}
+	}
+
+	if knowledgeBase.KnowledgeBaseConfiguration == nil {
+		return fmt.Errorf("knowledge base %s must specify knowledgeBaseConfiguration", resource.Metadata.Name)
+	}
+	if knowledgeBase.StorageConfiguration == nil {
+		return fmt.Errorf("knowledge base %s must specify storageConfiguration", resource.Metadata.Name)
+	}
+
+	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
+	policyResourceName := fmt.Sprintf("%s_kb_policy", resourceName)
+
+	collectionArn, collectionDependencies, err := g.resolveKnowledgeBaseCollection(resource.Metadata.Name, knowledgeBase.StorageConfiguration)
+	if err != nil {
+		return err
+	}
+
+	if err := g.handleKnowledgeBaseExecutionRole(body, resource.Metadata.Name, knowledgeBase, collectionArn); err != nil {
+		return err
+	}
+
+	kbBlock := body.AppendNewBlock("resource", []string{"aws_bedrockagent_knowledge_base", resourceName})
+	kbBody := kbBlock.Body()
+
+	kbBody.SetAttributeValue("name", cty.StringVal(resource.Metadata.Name))
+	if err := g.setKnowledgeBaseRoleAttribute(kbBody, "role_arn", resource.Metadata.Name, knowledgeBase); err != nil {
+		return err
+	}
+
+	if knowledgeBase.Description != "" {
+		kbBody.SetAttributeValue("description", cty.StringVal(knowledgeBase.Description))
+	}
+
+	// Knowledge base configuration
+	kbConfig := knowledgeBase.KnowledgeBaseConfiguration
+	kbConfigBody := kbBody.AppendNewBlock("knowledge_base_configuration", nil).Body()
+	kbConfigBody.SetAttributeValue("type", cty.StringVal(kbConfig.Type))
+
+	if kbConfig.VectorKnowledgeBaseConfiguration != nil {
+		vectorConfig := kbConfig.VectorKnowledgeBaseConfiguration
+		vectorBody := kbConfigBody.AppendNewBlock("vector_knowledge_base_configuration", nil).Body()
+		vectorBody.SetAttributeValue("embedding_model_arn", cty.StringVal(vectorConfig.EmbeddingModelArn))
+
+		if vectorConfig.EmbeddingModelConfiguration != nil &&
+			vectorConfig.EmbeddingModelConfiguration.BedrockEmbeddingModelConfiguration != nil &&
+			vectorConfig.EmbeddingModelConfiguration.BedrockEmbeddingModelConfiguration.Dimensions > 0 {
+			embeddingBody := vectorBody.AppendNewBlock("embedding_model_configuration", nil).Body()
+			bedrockBody := embeddingBody.AppendNewBlock("bedrock_embedding_model_configuration", nil).Body()
+			bedrockBody.SetAttributeValue("dimensions", cty.NumberIntVal(int64(vectorConfig.EmbeddingModelConfiguration.BedrockEmbeddingModelConfiguration.Dimensions)))
+		}
+	}
+
+	// Storage configuration
+	storageBody := kbBody.AppendNewBlock("storage_configuration", nil).Body()
+	storageBody.SetAttributeValue("type", cty.StringVal(knowledgeBase.StorageConfiguration.Type))
+
+	var vectorIndexName string
+	var fieldMapping models.FieldMapping
+	if osConfig := knowledgeBase.StorageConfiguration.OpenSearchServerless; osConfig != nil {
+		vectorIndexName = osConfig.VectorIndexName
+		fieldMapping = osConfig.FieldMapping
+	} else if osConfig := knowledgeBase.StorageConfiguration.OpensearchServerlessConfiguration; osConfig != nil {
+		vectorIndexName = osConfig.VectorIndexName
+		fieldMapping = osConfig.FieldMapping
+	}
+
+	if collectionArn != "" {
+		osBody := storageBody.AppendNewBlock("opensearch_serverless_configuration", nil).Body()
+		setStringOrReference(osBody, "collection_arn", collectionArn)
+		osBody.SetAttributeValue("vector_index_name", cty.StringVal(vectorIndexName))
+
+		fieldMappingBody := osBody.AppendNewBlock("field_mapping", nil).Body()
+		fieldMappingBody.SetAttributeValue("vector_field", cty.StringVal(fieldMapping.VectorField))
+		fieldMappingBody.SetAttributeValue("text_field", cty.StringVal(fieldMapping.TextField))
+		fieldMappingBody.SetAttributeValue("metadata_field", cty.StringVal(fieldMapping.MetadataField))
+	}
+
+	if len(knowledgeBase.Tags) > 0 {
+		tagValues := make(map[string]cty.Value)
+		for key, value := range knowledgeBase.Tags {
+			tagValues[key] = cty.StringVal(value)
+		}
+		kbBody.SetAttributeValue("tags", cty.ObjectVal(tagValues))
+	}
+
+	// The role must be able to reach the model and collection, and the vector index must exist, before creation
+	var dependencies []string
+	if knowledgeBase.IAMRole.CreatesRole() {
+		dependencies = append(dependencies, fmt.Sprintf("aws_iam_role_policy.%s", policyResourceName))
+	}
+	appendDependsOn(kbBody, append(dependencies, collectionDependencies...))
+
+	body.AppendNewline()
+
+	for _, dataSource := range knowledgeBase.DataSources {
+		if err := g.generateDataSourceNative(body, resource.Metadata.Name, dataSource); err != nil {
+			return fmt.Errorf("failed to generate data source %s: %w", dataSource.Name, err)
+		}
+	}
+
+	g.logger.WithField("knowledge_base", resource.Metadata.Name).Info("Generated native knowledge base")
+	return nil
+}
+
+// resolveKnowledgeBaseCollection returns the collection ARN, either a literal ARN or a reference to a collection
+// in this project, and the resources the knowledge base must wait for
+func (g *HCLGenerator) resolveKnowledgeBaseCollection(kbName string, storage *models.StorageConfiguration) (string, []string, error) {
+	if storage.OpensearchServerlessConfiguration != nil {
+		return storage.OpensearchServerlessConfiguration.CollectionArn, nil, nil
+	}
+
+	osConfig := storage.OpenSearchServerless
+	if osConfig == nil {
+		return "", nil, nil
+	}
+	if osConfig.CollectionArn != nil {
+		return *osConfig.CollectionArn, nil, nil
+	}
+	if osConfig.CollectionName == nil || osConfig.CollectionName.IsEmpty() {
+		return "", nil, fmt.Errorf("knowledge base %s must specify collectionArn or collectionName", kbName)
+	}
+
+	collectionName := osConfig.CollectionName.String()
+	collectionResourceName := g.sanitizeResourceName(collectionName)
+	collectionArn := fmt.Sprintf("aws_opensearchserverless_collection.%s.arn", collectionResourceName)
+
+	parsed, exists := g.registry.GetResource(models.OpenSearchServerlessKind, collectionName)
+	if !exists {
+		return "", nil, fmt.Errorf("knowledge base %s references non-existent OpenSearch Serverless collection %s", kbName, collectionName)
+	}
+
+	dependencies := []string{fmt.Sprintf("aws_opensearchserverless_collection.%s", collectionResourceName)}
+	if collection, ok := parsed.Resource.(*models.OpenSearchServerless); ok && collection.Spec.VectorIndex != nil {
+		if collection.Spec.VectorIndex.UseProvisioner {
+			dependencies = append(dependencies, fmt.Sprintf("null_resource.%s_vector_index", collectionResourceName))
+		} else {
+			dependencies = append(dependencies, fmt.Sprintf("opensearch_index.%s_vector_index", collectionResourceName))
+		}
+	}
+
+	return collectionArn, dependencies, nil
+}
+
+// handleKnowledgeBaseExecutionRole generates the knowledge base's execution role unless an existing one is configured
+func (g *HCLGenerator) handleKnowledgeBaseExecutionRole(body *hclwrite.Body, kbName string, knowledgeBase models.KnowledgeBaseSpec, collectionArn string) error {
+	if role := knowledgeBase.IAMRole; role != nil {
+		if role.RoleArn != "" {
+			g.logger.WithField("knowledge_base", kbName).WithField("roleArn", role.RoleArn).Info("Using existing IAM role ARN")
+			return nil
+		}
+		if !role.RoleName.IsEmpty() {
+			g.logger.WithField("knowledge_base", kbName).WithField("roleName", role.RoleName.String()).Info("Using referenced IAM role")
+			return nil
+		}
+		if role.AutoCreate != nil && !*role.AutoCreate {
+			return fmt.Errorf("knowledge base %s: IAM role auto-creation disabled but no existing role ARN or reference provided", kbName)
+		}
+	}
+
+	g.generateKnowledgeBaseRoleNative(body, kbName, knowledgeBase, collectionArn)
+	return nil
+}
+
+// setKnowledgeBaseRoleAttribute sets an attribute to the ARN of the knowledge base's execution role
+func (g *HCLGenerator) setKnowledgeBaseRoleAttribute(resourceBody *hclwrite.Body, attributeName string, kbName string, knowledgeBase models.KnowledgeBaseSpec) error {
+	if role := knowledgeBase.IAMRole; role != nil {
+		if role.RoleArn != "" {
+			// Direct ARN or reference to a custom resource output
+			return g.setStringOrCustomOutput(resourceBody, attributeName, role.RoleArn)
+		}
+		if !role.RoleName.IsEmpty() {
+			resourceBody.SetAttributeRaw(attributeName, referenceTokens(fmt.Sprintf("aws_iam_role.%s.arn", g.sanitizeResourceName(role.RoleName.String()))))
+			return nil
+		}
+	}
+
+	resourceBody.SetAttributeRaw(attributeName, referenceTokens(fmt.Sprintf("aws_iam_role.%s_kb_role.arn", g.sanitizeResourceName(kbName))))
+	return nil
+}
+
+// generateKnowledgeBaseRoleNative creates the execution role the knowledge base uses to embed and ingest documents
+func (g *HCLGenerator) generateKnowledgeBaseRoleNative(body *hclwrite.Body, kbName string, knowledgeBase models.KnowledgeBaseSpec, collectionArn string) {
+	resourceName := g.sanitizeResourceName(kbName)
+	roleResourceName := fmt.Sprintf("%s_kb_role", resourceName)
+
+	roleBlock := body.AppendNewBlock("resource", []string{"aws_iam_role", roleResourceName})
+	roleBody := roleBlock.Body()
+	roleBody.SetAttributeValue("name", cty.StringVal(models.KnowledgeBaseRoleName(kbName)))
+	roleBody.SetAttributeValue("assume_role_policy", cty.StringVal(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "bedrock.amazonaws.com"
+      }
+    }
+  ]
+}`))
+	setGeneratedRoleTags(roleBody, knowledgeBase.Tags, models.RoleTypeKnowledgeBaseService)
+	body.AppendNewline()
+
+	policyBlock := body.AppendNewBlock("resource", []string{"aws_iam_role_policy", fmt.Sprintf("%s_kb_policy", resourceName)})
+	policyBody := policyBlock.Body()
+	policyBody.SetAttributeValue("name", cty.StringVal("BedrockKnowledgeBasePolicy"))
+	policyBody.SetAttributeRaw("role", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_iam_role.%s.id", roleResourceName))},
+	})
+	policyBody.SetAttributeRaw("policy", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(g.buildKnowledgeBasePolicy(knowledgeBase, collectionArn))},
+	})
+	body.AppendNewline()
+}
+
+// buildKnowledgeBasePolicy renders a jsonencode() expression granting model, collection, bucket and transformation access
+func (g *HCLGenerator) buildKnowledgeBasePolicy(knowledgeBase models.KnowledgeBaseSpec, collectionArn string) string {
+	embeddingModelArn := "arn:aws:bedrock:*::foundation-model/*"
+	if config := knowledgeBase.KnowledgeBaseConfiguration; config != nil && config.VectorKnowledgeBaseConfiguration != nil && config.VectorKnowledgeBaseConfiguration.EmbeddingModelArn != "" {
+		embeddingModelArn = config.VectorKnowledgeBaseConfiguration.EmbeddingModelArn
+	}
+
+	statements := []string{
+		policyStatement([]string{"bedrock:InvokeModel"}, []string{hclExpression(embeddingModelArn)}),
+	}
+
+	if collectionArn != "" {
+		statements = append(statements, policyStatement([]string{"aoss:APIAccessAll"}, []string{hclExpression(collectionArn)}))
+	}
+
+	sourceBuckets := make(map[string]bool)
+	intermediateBuckets := make(map[string]bool)
+	var lambdaArns []string
+	var secretArns []string
+	for _, dataSource := range knowledgeBase.DataSources {
+		if dataSource.S3Configuration != nil && dataSource.S3Configuration.BucketArn != "" {
+			sourceBuckets[dataSource.S3Configuration.BucketArn] = true
+		}
+		if secretArn := dataSource.CredentialsSecretArn(); secretArn != "" {
+			secretArns = append(secretArns, hclExpression(secretArn))
+		}
+		if transformation := dataSource.CustomTransformation; transformation != nil {
+			if lambdaArn := g.transformationLambdaArn(transformation.TransformationLambda); lambdaArn != "" {
+				lambdaArns = append(lambdaArns, hclExpression(lambdaArn))
+			}
+			if transformation.IntermediateStorage != nil && transformation.IntermediateStorage.S3Location != nil {
+				bucket := strings.SplitN(strings.TrimPrefix(transformation.IntermediateStorage.S3Location.URI, "s3://"), "/", 2)[0]
+				intermediateBuckets["arn:aws:s3:::"+bucket] = true
+			}
+		}
+	}
+
+	// Data sources are only read, intermediate storage is also written by the transformation
+	if len(sourceBuckets) > 0 || len(intermediateBuckets) > 0 {
+		allBuckets := make(map[string]bool)
+		for bucket := range sourceBuckets {
+			allBuckets[bucket] = true
+		}
+		for bucket := range intermediateBuckets {
+			allBuckets[bucket] = true
+		}
+
+		statements = append(statements,
+			policyStatement([]string{"s3:ListBucket"}, bucketResources(allBuckets, "")),
+			policyStatement([]string{"s3:GetObject"}, bucketResources(allBuckets, "/*")),
+		)
+		if len(intermediateBuckets) > 0 {
+			statements = append(statements, policyStatement([]string{"s3:PutObject"}, bucketResources(intermediateBuckets, "/*")))
+		}
+	}
+
+	if len(lambdaArns) > 0 {
+		statements = append(statements, policyStatement([]string{"lambda:InvokeFunction"}, lambdaArns))
+	}
+
+	// Confluence and SharePoint crawlers read their credentials from Secrets Manager
+	if len(secretArns) > 0 {
+		statements = append(statements, policyStatement([]string{"secretsmanager:GetSecretValue"}, secretArns))
+	}
+
+	return fmt.Sprintf("jsonencode({\n    Version = \"2012-10-17\"\n    Statement = [\n%s\n    ]\n  })", strings.Join(statements, ",\n"))
+}
+
+// generateDataSourceNative creates an aws_bedrockagent_data_source attached to a native knowledge base
+func (g *HCLGenerator) generateDataSourceNative(body *hclwrite.Body, kbName string, dataSource models.DataSource) error {
+	kbResourceName := g.sanitizeResourceName(kbName)
+	dsResourceName := fmt.Sprintf("%s_%s", kbResourceName, g.sanitizeResourceName(dataSource.Name))
+
+	dsBlock := body.AppendNewBlock("resource", []string{"aws_bedrockagent_data_source", dsResourceName})
+	dsBody := dsBlock.Body()
+
+	dsBody.SetAttributeRaw("knowledge_base_id", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_knowledge_base.%s.id", kbResourceName))},
+	})
+	dsBody.SetAttributeValue("name", cty.StringVal(dataSource.Name))
+
+	if err := dataSource.Validate(); err != nil {
+		return err
+	}
+
+	dsConfigBody := dsBody.AppendNewBlock("data_source_configuration", nil).Body()
+	dsConfigBody.SetAttributeValue("type", cty.StringVal(dataSource.APIType()))
+
+	if dataSource.S3Configuration != nil {
+		s3Body := dsConfigBody.AppendNewBlock("s3_configuration", nil).Body()
+		s3Body.SetAttributeValue("bucket_arn", cty.StringVal(dataSource.S3Configuration.BucketArn))
+		if len(dataSource.S3Configuration.InclusionPrefixes) > 0 {
+			prefixes := make([]cty.Value, 0, len(dataSource.S3Configuration.InclusionPrefixes))
+			for _, prefix := range dataSource.S3Configuration.InclusionPrefixes {
+				prefixes = append(prefixes, cty.StringVal(prefix))
+			}
+			s3Body.SetAttributeValue("inclusion_prefixes", cty.ListVal(prefixes))
+		}
+		// aws_bedrockagent_data_source has no exclusion prefixes
+		if len(dataSource.S3Configuration.ExclusionPrefixes) > 0 {
+			g.logger.WithField("data_source", dataSource.Name).Warn("Native data sources do not support exclusionPrefixes, ignoring them")
+		}
+	}
+
+	switch dataSource.APIType() {
+	case "CONFLUENCE":
+		config := dataSource.ConfluenceConfiguration
+		confluenceBody := dsConfigBody.AppendNewBlock("confluence_configuration", nil).Body()
+		sourceBody := confluenceBody.AppendNewBlock("source_configuration", nil).Body()
+		sourceBody.SetAttributeValue("auth_type", cty.StringVal(config.AuthType))
+		sourceBody.SetAttributeValue("credentials_secret_arn", cty.StringVal(config.CredentialsSecretArn))
+		sourceBody.SetAttributeValue("host_type", cty.StringVal(stringOrDefault(config.HostType, "SAAS")))
+		sourceBody.SetAttributeValue("host_url", cty.StringVal(config.HostURL))
+		appendCrawlFiltersNative(confluenceBody, config.Filters)
+	case "SHAREPOINT":
+		config := dataSource.SharePointConfiguration
+		sharePointBody := dsConfigBody.AppendNewBlock("share_point_configuration", nil).Body()
+		sourceBody := sharePointBody.AppendNewBlock("source_configuration", nil).Body()
+		sourceBody.SetAttributeValue("auth_type", cty.StringVal(config.AuthType))
+		sourceBody.SetAttributeValue("credentials_secret_arn", cty.StringVal(config.CredentialsSecretArn))
+		sourceBody.SetAttributeValue("domain", cty.StringVal(config.Domain))
+		sourceBody.SetAttributeValue("host_type", cty.StringVal(stringOrDefault(config.HostType, "ONLINE")))
+		sourceBody.SetAttributeValue("site_urls", stringListValue(config.SiteURLs))
+		sourceBody.SetAttributeValue("tenant_id", cty.StringVal(config.TenantID))
+		appendCrawlFiltersNative(sharePointBody, config.Filters)
+	case "WEB":
+		config := dataSource.WebConfiguration
+		webBody := dsConfigBody.AppendNewBlock("web_configuration", nil).Body()
+		urlBody := webBody.AppendNewBlock("source_configuration", nil).Body().AppendNewBlock("url_configuration", nil).Body()
+		for _, seedURL := range config.SeedURLs {
+			urlBody.AppendNewBlock("seed_urls", nil).Body().SetAttributeValue("url", cty.StringVal(seedURL))
+		}
+
+		if config.Scope != "" || config.RateLimit > 0 || len(config.InclusionFilters) > 0 || len(config.ExclusionFilters) > 0 {
+			crawlerBody := webBody.AppendNewBlock("crawler_configuration", nil).Body()
+			if config.RateLimit > 0 {
+				crawlerBody.AppendNewBlock("crawler_limits", nil).Body().SetAttributeValue("rate_limit", cty.NumberIntVal(int64(config.RateLimit)))
+			}
+			if len(config.ExclusionFilters) > 0 {
+				crawlerBody.SetAttributeValue("exclusion_filters", stringListValue(config.ExclusionFilters))
+			}
+			if len(config.InclusionFilters) > 0 {
+				crawlerBody.SetAttributeValue("inclusion_filters", stringListValue(config.InclusionFilters))
+			}
+			if config.Scope != "" {
+				crawlerBody.SetAttributeValue("scope", cty.StringVal(config.Scope))
+			}
+		}
+	}
+
+	// vectorIngestionConfiguration takes precedence over the top-level chunking configuration
+	chunking := dataSource.ChunkingConfiguration
+	if dataSource.VectorIngestionConfiguration != nil && dataSource.VectorIngestionConfiguration.ChunkingConfiguration != nil {
+		chunking = dataSource.VectorIngestionConfiguration.ChunkingConfiguration
+	}
+
+	if chunking != nil || dataSource.CustomTransformation != nil {
+		ingestionBody := dsBody.AppendNewBlock("vector_ingestion_configuration", nil).Body()
+
+		if chunking != nil {
+			chunkingBody := ingestionBody.AppendNewBlock("chunking_configuration", nil).Body()
+			chunkingBody.SetAttributeValue("chunking_strategy", cty.StringVal(chunking.ChunkingStrategy))
+
+			if fixed := chunking.FixedSizeChunkingConfiguration; fixed != nil {
+				fixedBody := chunkingBody.AppendNewBlock("fixed_size_chunking_configuration", nil).Body()
+				fixedBody.SetAttributeValue("max_tokens", cty.NumberIntVal(int64(fixed.MaxTokens)))
+				fixedBody.SetAttributeValue("overlap_percentage", cty.NumberIntVal(int64(fixed.OverlapPercentage)))
+			}
+
+			if semantic := chunking.SemanticChunkingConfiguration; semantic != nil {
+				semanticBody := chunkingBody.AppendNewBlock("semantic_chunking_configuration", nil).Body()
+				semanticBody.SetAttributeValue("breakpoint_percentile_threshold", cty.NumberIntVal(int64(semantic.BreakpointPercentileThreshold)))
+				semanticBody.SetAttributeValue("buffer_size", cty.NumberIntVal(int64(semantic.BufferSize)))
+				semanticBody.SetAttributeValue("max_token", cty.NumberIntVal(int64(semantic.MaxTokens)))
+			}
+
+			if hierarchical := chunking.HierarchicalChunkingConfiguration; hierarchical != nil {
+				hierarchicalBody := chunkingBody.AppendNewBlock("hierarchical_chunking_configuration", nil).Body()
+				for _, level := range hierarchical.LevelConfigurations {
+					levelBody := hierarchicalBody.AppendNewBlock("level_configuration", nil).Body()
+					levelBody.SetAttributeValue("max_tokens", cty.NumberIntVal(int64(level.MaxTokens)))
+				}
+				hierarchicalBody.SetAttributeValue("overlap_tokens", cty.NumberIntVal(int64(hierarchical.OverlapTokens)))
+			}
+		}
+
+		if transformation := dataSource.CustomTransformation; transformation != nil {
+			lambdaArn := g.transformationLambdaArn(transformation.TransformationLambda)
+			if lambdaArn == "" || transformation.IntermediateStorage == nil || transformation.IntermediateStorage.S3Location == nil {
+				return fmt.Errorf("customTransformation requires a transformation lambda and intermediateStorage.s3Location")
+			}
+
+			transformationConfigBody := ingestionBody.AppendNewBlock("custom_transformation_configuration", nil).Body()
+
+			storageBody := transformationConfigBody.AppendNewBlock("intermediate_storage", nil).Body()
+			s3LocationBody := storageBody.AppendNewBlock("s3_location", nil).Body()
+			s3LocationBody.SetAttributeValue("uri", cty.StringVal(transformation.IntermediateStorage.S3Location.URI))
+
+			transformationBody := transformationConfigBody.AppendNewBlock("transformation", nil).Body()
+			transformationBody.SetAttributeValue("step_to_apply", cty.StringVal("POST_CHUNKING"))
+			functionBody := transformationBody.AppendNewBlock("transformation_function", nil).Body()
+			lambdaBody := functionBody.AppendNewBlock("transformation_lambda_configuration", nil).Body()
+			setStringOrReference(lambdaBody, "lambda_arn", lambdaArn)
+		}
+	}
+
+	body.AppendNewline()
+
+	g.logger.WithField("knowledge_base", kbName).WithField("data_source", dataSource.Name).Debug("Generated native data source")
+	return nil
+}
+
+// transformationLambdaArn returns the ARN of a transformation Lambda, as a reference for Lambdas in this project
+func (g *HCLGenerator) transformationLambdaArn(lambda *models.TransformationLambda) string {
+	if lambda == nil {
+		return ""
+	}
+	if !lambda.Lambda.IsEmpty() && g.registry.HasResource(models.LambdaKind, lambda.Lambda.String()) {
+		return fmt.Sprintf("aws_lambda_function.%s.arn", g.sanitizeResourceName(lambda.Lambda.String()))
+	}
+	if !lambda.Lambda.IsEmpty() {
+		return lambda.Lambda.String()
+	}
+	return lambda.LambdaArn
+}
+
+// isTerraformReference reports whether a value is a reference to a generated resource rather than a literal ARN
+func isTerraformReference(value string) bool {
+	return strings.HasPrefix(value, "aws_") || strings.HasPrefix(value, "module.")
+}
+
+// setStringOrReference sets an attribute to a raw reference or a string literal
+func setStringOrReference(body *hclwrite.Body, name, value string) {
+	if isTerraformReference(value) {
+		body.SetAttributeRaw(name, hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(value)},
+		})
+		return
+	}
+	body.SetAttributeValue(name, cty.StringVal(value))
+}
+
+// hclExpression renders a value as an HCL expression, quoting literals
+func hclExpression(value string) string {
+	if isTerraformReference(value) {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// bucketResources renders sorted bucket ARNs with an optional object suffix
+func bucketResources(buckets map[string]bool, suffix string) []string {
+	arns := make([]string, 0, len(buckets))
+	for bucket := range buckets {
+		arns = append(arns, bucket)
+	}
+	sort.Strings(arns)
+
+	resources := make([]string, len(arns))
+	for i, arn := range arns {
+		resources[i] = hclExpression(arn + suffix)
+	}
+	return resources
+}
+
+// policyStatement renders an Allow statement for jsonencode()
+func policyStatement(actions, resources []string) string {
+	quotedActions := make([]string, len(actions))
+	for i, action := range actions {
+		quotedActions[i] = fmt.Sprintf("%q", action)
+	}
+
+	return fmt.Sprintf("      {\n        Effect   = \"Allow\"\n        Action   = [%s]\n        Resource = [%s]\n      }",
+		strings.Join(quotedActions, ", "), strings.Join(resources, ", "))
+}
+
+// appendCrawlFiltersNative adds the pattern filters of a Confluence or SharePoint crawler
+func appendCrawlFiltersNative(body *hclwrite.Body, filters []models.CrawlFilter) {
+	if len(filters) == 0 {
+		return
+	}
+
+	filterConfigBody := body.AppendNewBlock("crawler_configuration", nil).Body().AppendNewBlock("filter_configuration", nil).Body()
+	filterConfigBody.SetAttributeValue("type", cty.StringVal("PATTERN"))
+	patternBody := filterConfigBody.AppendNewBlock("pattern_object_filter", nil).Body()
+
+	for _, filter := range filters {
+		filterBody := patternBody.AppendNewBlock("filters", nil).Body()
+		filterBody.SetAttributeValue("object_type", cty.StringVal(filter.ObjectType))
+		if len(filter.ExclusionFilters) > 0 {
+			filterBody.SetAttributeValue("exclusion_filters", stringListValue(filter.ExclusionFilters))
+		}
+		if len(filter.InclusionFilters) > 0 {
+			filterBody.SetAttributeValue("inclusion_filters", stringListValue(filter.InclusionFilters))
+		}
+	}
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func stringListValue(values []string) cty.Value {
+	list := make([]cty.Value, 0, len(values))
+	for _, value := range values {
+		list = append(list, cty.StringVal(value))
+	}
+	return cty.ListVal(list)
+}
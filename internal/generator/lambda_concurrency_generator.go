@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// generateLambdaProvisionedConcurrency creates the alias provisioned concurrency applies to, its
+// provisioned concurrency config and, when configured, the autoscaling target with its policies
+func (g *HCLGenerator) generateLambdaProvisionedConcurrency(body *hclwrite.Body, lambdaResourceName string, config models.ProvisionedConcurrencyConfig) {
+	aliasResourceName := g.lambdaAliasResourceName(lambdaResourceName, config.AliasName())
+
+	aliasBody := body.AppendNewBlock("resource", []string{"aws_lambda_alias", aliasResourceName}).Body()
+	aliasBody.SetAttributeValue("name", cty.StringVal(config.AliasName()))
+	aliasBody.SetAttributeRaw("function_name", referenceTokens(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName)))
+	if config.Version != "" {
+		aliasBody.SetAttributeValue("function_version", cty.StringVal(config.Version))
+	} else {
+		aliasBody.SetAttributeRaw("function_version", referenceTokens(fmt.Sprintf("aws_lambda_function.%s.version", lambdaResourceName)))
+	}
+	body.AppendNewline()
+
+	concurrencyBody := body.AppendNewBlock("resource", []string{"aws_lambda_provisioned_concurrency_config", lambdaResourceName}).Body()
+	concurrencyBody.SetAttributeRaw("function_name", referenceTokens(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName)))
+	concurrencyBody.SetAttributeValue("provisioned_concurrent_executions", cty.NumberIntVal(int64(config.Executions)))
+	concurrencyBody.SetAttributeRaw("qualifier", referenceTokens(fmt.Sprintf("aws_lambda_alias.%s.name", aliasResourceName)))
+
+	autoscaling := config.Autoscaling
+	if autoscaling != nil {
+		// Autoscaling owns the executions once it is attached
+		lifecycleBody := concurrencyBody.AppendNewBlock("lifecycle", nil).Body()
+		lifecycleBody.SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple([]hclwrite.Tokens{referenceTokens("provisioned_concurrent_executions")}))
+	}
+	body.AppendNewline()
+
+	if autoscaling == nil {
+		return
+	}
+
+	resourceID := fmt.Sprintf("function:${aws_lambda_function.%s.function_name}:${aws_lambda_alias.%s.name}", lambdaResourceName, aliasResourceName)
+	targetBody := body.AppendNewBlock("resource", []string{"aws_appautoscaling_target", lambdaResourceName}).Body()
+	targetBody.SetAttributeValue("service_namespace", cty.StringVal("lambda"))
+	targetBody.SetAttributeValue("scalable_dimension", cty.StringVal("lambda:function:ProvisionedConcurrency"))
+	targetBody.SetAttributeRaw("resource_id", templateStringTokens(resourceID))
+	targetBody.SetAttributeValue("min_capacity", cty.NumberIntVal(int64(autoscaling.MinCapacity)))
+	targetBody.SetAttributeValue("max_capacity", cty.NumberIntVal(int64(autoscaling.MaxCapacity)))
+	appendDependsOn(targetBody, []string{fmt.Sprintf("aws_lambda_provisioned_concurrency_config.%s", lambdaResourceName)})
+	body.AppendNewline()
+
+	targetAttribute := func(attribute string) hclwrite.Tokens {
+		return referenceTokens(fmt.Sprintf("aws_appautoscaling_target.%s.%s", lambdaResourceName, attribute))
+	}
+
+	if autoscaling.TargetUtilization > 0 {
+		policyBody := body.AppendNewBlock("resource", []string{"aws_appautoscaling_policy", fmt.Sprintf("%s_utilization", lambdaResourceName)}).Body()
+		policyBody.SetAttributeValue("name", cty.StringVal(fmt.Sprintf("%s-provisioned-concurrency-utilization", lambdaResourceName)))
+		policyBody.SetAttributeValue("policy_type", cty.StringVal("TargetTrackingScaling"))
+		policyBody.SetAttributeRaw("service_namespace", targetAttribute("service_namespace"))
+		policyBody.SetAttributeRaw("scalable_dimension", targetAttribute("scalable_dimension"))
+		policyBody.SetAttributeRaw("resource_id", targetAttribute("resource_id"))
+
+		trackingBody := policyBody.AppendNewBlock("target_tracking_scaling_policy_configuration", nil).Body()
+		trackingBody.SetAttributeValue("target_value", cty.NumberFloatVal(autoscaling.TargetUtilization))
+		metricBody := trackingBody.AppendNewBlock("predefined_metric_specification", nil).Body()
+		metricBody.SetAttributeValue("predefined_metric_type", cty.StringVal("LambdaProvisionedConcurrencyUtilization"))
+		body.AppendNewline()
+	}
+
+	for _, schedule := range autoscaling.Schedules {
+		actionBody := body.AppendNewBlock("resource", []string{"aws_appautoscaling_scheduled_action", fmt.Sprintf("%s_%s", lambdaResourceName, g.sanitizeResourceName(schedule.Name))}).Body()
+		actionBody.SetAttributeValue("name", cty.StringVal(schedule.Name))
+		actionBody.SetAttributeRaw("service_namespace", targetAttribute("service_namespace"))
+		actionBody.SetAttributeRaw("scalable_dimension", targetAttribute("scalable_dimension"))
+		actionBody.SetAttributeRaw("resource_id", targetAttribute("resource_id"))
+		actionBody.SetAttributeValue("schedule", cty.StringVal(schedule.Schedule))
+		if schedule.Timezone != "" {
+			actionBody.SetAttributeValue("timezone", cty.StringVal(schedule.Timezone))
+		}
+
+		capacityBody := actionBody.AppendNewBlock("scalable_target_action", nil).Body()
+		capacityBody.SetAttributeValue("min_capacity", cty.NumberIntVal(int64(schedule.MinCapacity)))
+		capacityBody.SetAttributeValue("max_capacity", cty.NumberIntVal(int64(schedule.MaxCapacity)))
+		body.AppendNewline()
+	}
+}
+
+// lambdaAliasResourceName returns the Terraform resource name for a Lambda alias
+func (g *HCLGenerator) lambdaAliasResourceName(lambdaResourceName, aliasName string) string {
+	return fmt.Sprintf("%s_%s", lambdaResourceName, g.sanitizeResourceName(aliasName))
+}
+
+// provisionedConcurrencyAlias returns the Terraform resource name of the alias a Lambda in this project
+// is invoked through, or "" when it has no provisioned concurrency
+func (g *HCLGenerator) provisionedConcurrencyAlias(lambdaName string) string {
+	resource, exists := g.registry.GetResource(models.LambdaKind, lambdaName)
+	if !exists {
+		return ""
+	}
+	lambda, ok := resource.Resource.(*models.Lambda)
+	if !ok || lambda.Spec.ProvisionedConcurrency == nil {
+		return ""
+	}
+	return g.lambdaAliasResourceName(g.sanitizeResourceName(lambdaName), lambda.Spec.ProvisionedConcurrency.AliasName())
+}
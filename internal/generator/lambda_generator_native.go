@@ -3,6 +3,8 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -35,11 +37,37 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 
 	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
 
+	if err := lambda.Code.Validate(); err != nil {
+		return fmt.Errorf("invalid code configuration for Lambda %s: %w", resource.Metadata.Name, err)
+	}
+	if lambda.FunctionURL != nil {
+		if err := lambda.FunctionURL.Validate(); err != nil {
+			return fmt.Errorf("invalid function URL for Lambda %s: %w", resource.Metadata.Name, err)
+		}
+	}
+	if err := lambda.ValidateEnvironmentSources(); err != nil {
+		return fmt.Errorf("invalid environment for Lambda %s: %w", resource.Metadata.Name, err)
+	}
+	if lambda.DeadLetterConfig != nil {
+		if err := lambda.DeadLetterConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid dead letter config for Lambda %s: %w", resource.Metadata.Name, err)
+		}
+	}
+	if err := lambda.ValidateProvisionedConcurrency(); err != nil {
+		return fmt.Errorf("invalid provisioned concurrency for Lambda %s: %w", resource.Metadata.Name, err)
+	}
+
 	// Generate IAM role for Lambda execution first
 	if err := g.generateLambdaExecutionRole(body, resourceName, lambda); err != nil {
 		return fmt.Errorf("failed to generate Lambda execution role: %w", err)
 	}
 
+	// The dead-letter queue or topic is created alongside the function
+	deadLetterPolicy := ""
+	if lambda.DeadLetterConfig != nil && lambda.DeadLetterConfig.AutoCreate {
+		deadLetterPolicy = g.generateLambdaDeadLetterTarget(body, resourceName, resource.Metadata.Name, lambda)
+	}
+
 	// Create native AWS Lambda function resource
 	resourceBlock := body.AppendNewBlock("resource", []string{"aws_lambda_function", resourceName})
 	resourceBody := resourceBlock.Body()
@@ -51,7 +79,13 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 
 	// Role reference
 	if lambda.RoleArn != "" {
-		resourceBody.SetAttributeValue("role", cty.StringVal(lambda.RoleArn))
+		if err := g.setStringOrCustomOutput(resourceBody, "role", lambda.RoleArn); err != nil {
+			return fmt.Errorf("failed to resolve Lambda role: %w", err)
+		}
+	} else if IsCustomOutputReference(lambda.Role.String()) {
+		if err := g.setStringOrCustomOutput(resourceBody, "role", lambda.Role.String()); err != nil {
+			return fmt.Errorf("failed to resolve Lambda role: %w", err)
+		}
 	} else if !lambda.Role.IsEmpty() {
 		// Handle reference to IAM role
 		roleResourceName := g.sanitizeResourceName(lambda.Role.String())
@@ -72,7 +106,22 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 	}
 
 	// Code configuration
-	if lambda.Code.ZipFile != "" {
+	if lambda.Code.Inline != "" {
+		// Inline handler code written alongside the generated configuration
+		sourceDir, err := g.writeInlineLambdaCode(resourceName, lambda.Code)
+		if err != nil {
+			return fmt.Errorf("failed to write inline code: %w", err)
+		}
+
+		resourceBody.SetAttributeRaw("filename", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("data.archive_file.%s.output_path", resourceName))},
+		})
+		resourceBody.SetAttributeRaw("source_code_hash", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("data.archive_file.%s.output_base64sha256", resourceName))},
+		})
+
+		g.generateArchiveDataSource(body, resourceName, sourceDir)
+	} else if lambda.Code.ZipFile != "" {
 		// Inline code
 		resourceBody.SetAttributeValue("filename", cty.StringVal("lambda_function.zip"))
 		resourceBody.SetAttributeValue("source_code_hash", cty.StringVal("${filebase64sha256(\"lambda_function.zip\")}"))
@@ -107,12 +156,26 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 		// Build the variables block content
 		var tokens hclwrite.Tokens
 		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenOBrace, Bytes: []byte("{\n")})
-		for key, value := range envVarMap {
+		envKeys := make([]string, 0, len(envVarMap))
+		for key := range envVarMap {
+			envKeys = append(envKeys, key)
+		}
+		sort.Strings(envKeys)
+
+		for _, key := range envKeys {
+			value := envVarMap[key]
 			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte("    " + key)})
 			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenEqual, Bytes: []byte(" = ")})
 
-			// Check if this is a Terraform reference
-			if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+			// SSM parameters and secrets are read through data sources when Terraform runs
+			if source, name, ok, _ := models.ParseEnvironmentSource(value); ok {
+				reference, err := g.generateEnvironmentSourceDataSource(body, fmt.Sprintf("%s_%s", resourceName, g.sanitizeResourceName(key)), source, name, resource.Metadata.Name, key)
+				if err != nil {
+					return err
+				}
+				tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(reference)})
+			} else if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+				// Terraform reference
 				// Extract the reference without the ${} wrapper
 				refContent := value[2 : len(value)-1]
 				tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(refContent)})
@@ -173,10 +236,24 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 	}
 
 	// Advanced attributes
-	g.setLambdaNativeAdvancedAttributes(resourceBody, lambda)
+	if err := g.setLambdaNativeAdvancedAttributes(resourceBody, resourceName, lambda); err != nil {
+		return fmt.Errorf("failed to set advanced attributes for Lambda %s: %w", resource.Metadata.Name, err)
+	}
+
+	// Lambda checks that the role can send to the dead-letter target when the function is created
+	if deadLetterPolicy != "" {
+		appendDependsOn(resourceBody, []string{deadLetterPolicy})
+	}
 
 	body.AppendNewline()
 
+	if lambda.FunctionURL != nil {
+		g.generateLambdaFunctionURL(body, resourceName, *lambda.FunctionURL)
+	}
+	if lambda.ProvisionedConcurrency != nil {
+		g.generateLambdaProvisionedConcurrency(body, resourceName, *lambda.ProvisionedConcurrency)
+	}
+
 	// Generate resource-based policies for Bedrock agent access
 	if err := g.generateLambdaResourcePermissions(body, resourceName, resource.Metadata.Name, lambda); err != nil {
 		return fmt.Errorf("failed to generate Lambda resource permissions: %w", err)
@@ -186,6 +263,56 @@ func (g *HCLGenerator) generateLambdaNative(body *hclwrite.Body, resource models
 	return nil
 }
 
+// generateLambdaFunctionURL creates a function URL for a Lambda function
+func (g *HCLGenerator) generateLambdaFunctionURL(body *hclwrite.Body, lambdaResourceName string, functionURL models.FunctionURLConfig) {
+	urlBody := body.AppendNewBlock("resource", []string{"aws_lambda_function_url", lambdaResourceName}).Body()
+
+	urlBody.SetAttributeRaw("function_name", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName))},
+	})
+	urlBody.SetAttributeValue("authorization_type", cty.StringVal(functionURL.AuthType))
+	if functionURL.InvokeMode != "" {
+		urlBody.SetAttributeValue("invoke_mode", cty.StringVal(functionURL.InvokeMode))
+	}
+
+	if cors := functionURL.Cors; cors != nil {
+		corsBody := urlBody.AppendNewBlock("cors", nil).Body()
+		if cors.AllowCredentials {
+			corsBody.SetAttributeValue("allow_credentials", cty.True)
+		}
+		if len(cors.AllowHeaders) > 0 {
+			corsBody.SetAttributeValue("allow_headers", stringListValue(cors.AllowHeaders))
+		}
+		if len(cors.AllowMethods) > 0 {
+			corsBody.SetAttributeValue("allow_methods", stringListValue(cors.AllowMethods))
+		}
+		if len(cors.AllowOrigins) > 0 {
+			corsBody.SetAttributeValue("allow_origins", stringListValue(cors.AllowOrigins))
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			corsBody.SetAttributeValue("expose_headers", stringListValue(cors.ExposeHeaders))
+		}
+		if cors.MaxAge > 0 {
+			corsBody.SetAttributeValue("max_age", cty.NumberIntVal(int64(cors.MaxAge)))
+		}
+	}
+
+	body.AppendNewline()
+
+	// Unauthenticated URLs also need a resource policy allowing anyone to invoke them
+	if functionURL.AuthType == "NONE" {
+		permissionBody := body.AppendNewBlock("resource", []string{"aws_lambda_permission", fmt.Sprintf("%s_function_url_public", lambdaResourceName)}).Body()
+		permissionBody.SetAttributeValue("statement_id", cty.StringVal("FunctionURLAllowPublicAccess"))
+		permissionBody.SetAttributeValue("action", cty.StringVal("lambda:InvokeFunctionUrl"))
+		permissionBody.SetAttributeRaw("function_name", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName))},
+		})
+		permissionBody.SetAttributeValue("principal", cty.StringVal("*"))
+		permissionBody.SetAttributeValue("function_url_auth_type", cty.StringVal("NONE"))
+		body.AppendNewline()
+	}
+}
+
 // generateLambdaExecutionRole creates an IAM role for Lambda execution
 func (g *HCLGenerator) generateLambdaExecutionRole(body *hclwrite.Body, lambdaResourceName string, lambda models.LambdaSpec) error {
 	roleResourceName := fmt.Sprintf("%s_execution_role", lambdaResourceName)
@@ -207,6 +334,7 @@ func (g *HCLGenerator) generateLambdaExecutionRole(body *hclwrite.Body, lambdaRe
     }
   ]
 }`))
+	setGeneratedRoleTags(roleBody, lambda.Tags, models.RoleTypeLambdaExecution)
 
 	// Attach basic execution role policy
 	policyAttachmentBlock := body.AppendNewBlock("resource", []string{"aws_iam_role_policy_attachment", fmt.Sprintf("%s_basic", roleResourceName)})
@@ -265,6 +393,64 @@ func (g *HCLGenerator) generateLambdaExecutionRole(body *hclwrite.Body, lambdaRe
 	return nil
 }
 
+// generateLambdaDeadLetterTarget creates the SQS queue or SNS topic receiving failed asynchronous
+// invocations, and allows the generated execution role to send to it. It returns the address of the
+// role policy, or an empty string when the Lambda uses its own role.
+func (g *HCLGenerator) generateLambdaDeadLetterTarget(body *hclwrite.Body, resourceName, functionName string, lambda models.LambdaSpec) string {
+	targetType := lambda.DeadLetterConfig.TargetType()
+	targetName := fmt.Sprintf("%s_dlq", resourceName)
+
+	action := "sqs:SendMessage"
+	if targetType == models.DeadLetterTypeSNS {
+		action = "sns:Publish"
+		topicBody := body.AppendNewBlock("resource", []string{"aws_sns_topic", targetName}).Body()
+		topicBody.SetAttributeValue("name", cty.StringVal(fmt.Sprintf("%s-dlq", functionName)))
+		topicBody.SetAttributeValue("kms_master_key_id", cty.StringVal("alias/aws/sns"))
+	} else {
+		queueBody := body.AppendNewBlock("resource", []string{"aws_sqs_queue", targetName}).Body()
+		queueBody.SetAttributeValue("name", cty.StringVal(fmt.Sprintf("%s-dlq", functionName)))
+		// Keep failed events for the maximum of 14 days
+		queueBody.SetAttributeValue("message_retention_seconds", cty.NumberIntVal(1209600))
+		queueBody.SetAttributeValue("sqs_managed_sse_enabled", cty.True)
+	}
+	body.AppendNewline()
+
+	// A role supplied by the user must already allow sending to the target
+	if lambda.RoleArn != "" || !lambda.Role.IsEmpty() {
+		g.logger.WithField("lambda", functionName).Warnf("Lambda uses its own role, which must allow %s on the auto-created dead-letter target", action)
+		return ""
+	}
+
+	roleResourceName := fmt.Sprintf("%s_execution_role", resourceName)
+	policyName := fmt.Sprintf("%s_dlq_policy", roleResourceName)
+	policyBody := body.AppendNewBlock("resource", []string{"aws_iam_role_policy", policyName}).Body()
+	policyBody.SetAttributeValue("name", cty.StringVal("DeadLetterQueueAccess"))
+	policyBody.SetAttributeRaw("role", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_iam_role.%s.id", roleResourceName))},
+	})
+	policyBody.SetAttributeRaw("policy", templateStringTokens(fmt.Sprintf(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": "%s",
+      "Resource": "${%s}"
+    }
+  ]
+}`, action, deadLetterTargetArn(resourceName, targetType))))
+	body.AppendNewline()
+
+	return fmt.Sprintf("aws_iam_role_policy.%s", policyName)
+}
+
+// deadLetterTargetArn returns the expression of the ARN of an auto-created dead-letter target
+func deadLetterTargetArn(resourceName, targetType string) string {
+	if targetType == models.DeadLetterTypeSNS {
+		return fmt.Sprintf("aws_sns_topic.%s_dlq.arn", resourceName)
+	}
+	return fmt.Sprintf("aws_sqs_queue.%s_dlq.arn", resourceName)
+}
+
 // generateArchiveDataSource creates a data source for archiving Lambda source code
 func (g *HCLGenerator) generateArchiveDataSource(body *hclwrite.Body, resourceName, sourcePath string) {
 	dataBlock := body.AppendNewBlock("data", []string{"archive_file", resourceName})
@@ -277,15 +463,99 @@ func (g *HCLGenerator) generateArchiveDataSource(body *hclwrite.Body, resourceNa
 	body.AppendNewline()
 }
 
-// findAgentsReferencingLambda finds all agents that reference this Lambda function
+// generateEnvironmentSourceDataSource creates the data source reading an ${ssm:...} or ${secret:...}
+// environment value and returns the expression of its value. Labels join the Lambda and variable names,
+// so a label that another variable already produced is an error rather than a duplicate data source.
+func (g *HCLGenerator) generateEnvironmentSourceDataSource(body *hclwrite.Body, dataName, source, name, lambdaName, key string) (string, error) {
+	dataType, attribute := "aws_secretsmanager_secret_version", "secret_string"
+	if source == models.EnvironmentSourceSSM {
+		dataType, attribute = "aws_ssm_parameter", "value"
+	}
+
+	address := fmt.Sprintf("data.%s.%s", dataType, dataName)
+	owner := fmt.Sprintf("Lambda %s variable %s", lambdaName, key)
+	if other, exists := g.environmentDataSources[address]; exists {
+		return "", fmt.Errorf("%s and %s both read their value through %s, rename one of them", other, owner, address)
+	}
+	if g.environmentDataSources == nil {
+		g.environmentDataSources = make(map[string]string)
+	}
+	g.environmentDataSources[address] = owner
+
+	dataBody := body.AppendNewBlock("data", []string{dataType, dataName}).Body()
+	if source == models.EnvironmentSourceSSM {
+		dataBody.SetAttributeValue("name", cty.StringVal(name))
+		dataBody.SetAttributeValue("with_decryption", cty.True)
+	} else {
+		dataBody.SetAttributeValue("secret_id", cty.StringVal(name))
+	}
+	body.AppendNewline()
+	return fmt.Sprintf("%s.%s", address, attribute), nil
+}
+
+// writeInlineLambdaCode writes inline handler code under the output directory and returns its directory
+// relative to the output directory
+func (g *HCLGenerator) writeInlineLambdaCode(resourceName string, code models.CodeConfiguration) (string, error) {
+	filename := code.InlineFilename
+	if filename == "" {
+		filename = "index.py"
+	}
+	if filepath.IsAbs(filename) || strings.HasPrefix(filepath.Clean(filename), "..") {
+		return "", fmt.Errorf("inlineFilename must be a relative path within the function, got '%s'", filename)
+	}
+
+	sourceDir := filepath.Join("lambda_inline", resourceName)
+	if g.config.Output != nil {
+		g.logger.WithField("lambda", resourceName).Warn("Inline Lambda code is not written with streamed output")
+		return filepath.ToSlash(sourceDir), nil
+	}
+
+	filePath := filepath.Join(g.config.OutputDir, sourceDir, filename)
+	if err := g.ensureDir(filepath.Dir(filePath)); err != nil {
+		return "", err
+	}
+	if err := g.writeFile(filePath, []byte(code.Inline)); err != nil {
+		return "", err
+	}
+
+	g.logger.WithField("file", filePath).Debug("Wrote inline Lambda code")
+	return filepath.ToSlash(sourceDir), nil
+}
+
+// findAgentsReferencingLambda returns the sorted names of the agents whose inline action groups, or
+// ActionGroup resources, invoke this Lambda function. Agents defined outside the project are left out,
+// since there is no agent resource to scope a permission to.
 func (g *HCLGenerator) findAgentsReferencingLambda(lambdaName string) []string {
-	var referencingAgents []string
+	referencing := make(map[string]bool)
+
+	for _, agent := range g.registry.GetResourcesByType(models.AgentKind) {
+		spec, ok := agent.Spec.(models.AgentSpec)
+		if !ok {
+			continue
+		}
+		for _, actionGroup := range spec.ActionGroups {
+			if actionGroup.ActionGroupExecutor != nil && actionGroup.ActionGroupExecutor.Lambda.String() == lambdaName {
+				referencing[agent.Metadata.Name] = true
+			}
+		}
+	}
 
-	// This would need to be implemented to scan through all registered Agent resources
-	// and check if they reference this Lambda function in their action groups
-	// For now, return empty slice to avoid compile errors
-	// TODO: Implement proper agent scanning logic
+	for _, actionGroup := range g.registry.GetResourcesByType(models.ActionGroupKind) {
+		spec, ok := actionGroup.Spec.(models.ActionGroupSpec)
+		if !ok || spec.ActionGroupExecutor == nil || spec.ActionGroupExecutor.Lambda.String() != lambdaName {
+			continue
+		}
+		agentName := spec.AgentId.String()
+		if _, exists := g.registry.GetResource(models.AgentKind, agentName); exists {
+			referencing[agentName] = true
+		}
+	}
 
+	referencingAgents := make([]string, 0, len(referencing))
+	for agentName := range referencing {
+		referencingAgents = append(referencingAgents, agentName)
+	}
+	sort.Strings(referencingAgents)
 	return referencingAgents
 }
 
@@ -295,28 +565,7 @@ func (g *HCLGenerator) generateLambdaResourcePermissions(body *hclwrite.Body, la
 	referencingAgents := g.findAgentsReferencingLambda(lambdaName)
 
 	if len(referencingAgents) > 0 {
-		// Create agent-specific permissions
-		for _, agentName := range referencingAgents {
-			agentResourceName := g.sanitizeResourceName(agentName)
-			permissionResourceName := fmt.Sprintf("%s_allow_%s", lambdaResourceName, agentResourceName)
-
-			permissionBlock := body.AppendNewBlock("resource", []string{"aws_lambda_permission", permissionResourceName})
-			permissionBody := permissionBlock.Body()
-
-			permissionBody.SetAttributeValue("statement_id", cty.StringVal(fmt.Sprintf("AllowBedrockAgent_%s", agentResourceName)))
-			permissionBody.SetAttributeValue("action", cty.StringVal("lambda:InvokeFunction"))
-			permissionBody.SetAttributeRaw("function_name", hclwrite.Tokens{
-				{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName))},
-			})
-			permissionBody.SetAttributeValue("principal", cty.StringVal("bedrock.amazonaws.com"))
-			permissionBody.SetAttributeRaw("source_arn", hclwrite.Tokens{
-				{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_agent.%s.agent_arn", agentResourceName))},
-			})
-
-			body.AppendNewline()
-
-			g.logger.WithField("lambda", lambdaName).WithField("agent", agentName).Debug("Generated agent-specific Lambda permission")
-		}
+		g.generateAgentLambdaPermissions(body, lambdaResourceName, lambdaName, referencingAgents)
 	} else {
 		// If no agents reference this Lambda, add general Bedrock permission (unless explicitly disabled)
 		if lambda.ResourcePolicy == nil || lambda.ResourcePolicy.AllowBedrockAgents {
@@ -331,6 +580,7 @@ func (g *HCLGenerator) generateLambdaResourcePermissions(body *hclwrite.Body, la
 				{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName))},
 			})
 			permissionBody.SetAttributeValue("principal", cty.StringVal("bedrock.amazonaws.com"))
+			g.setLambdaPermissionQualifier(permissionBody, lambdaName)
 
 			body.AppendNewline()
 		}
@@ -339,8 +589,57 @@ func (g *HCLGenerator) generateLambdaResourcePermissions(body *hclwrite.Body, la
 	return nil
 }
 
+// generateAgentLambdaPermissions creates an aws_lambda_permission per agent, scoped to the agent's ARN
+func (g *HCLGenerator) generateAgentLambdaPermissions(body *hclwrite.Body, lambdaResourceName, lambdaName string, agentNames []string) {
+	// Statement IDs must be unique per function, but distinct agent names can sanitize to the same label
+	usedSuffixes := make(map[string]bool)
+
+	// Create agent-specific permissions
+	for _, agentName := range agentNames {
+		agentResourceName := g.sanitizeResourceName(agentName)
+
+		suffix := agentResourceName
+		for i := 2; usedSuffixes[suffix]; i++ {
+			suffix = fmt.Sprintf("%s_%d", agentResourceName, i)
+		}
+		if suffix != agentResourceName {
+			g.logger.WithField("lambda", lambdaName).WithField("agent", agentName).WithField("statement_id", "AllowBedrockAgent_"+suffix).
+				Warn("Agent name collides with another agent after sanitization, using a disambiguated statement ID")
+		}
+		usedSuffixes[suffix] = true
+
+		permissionResourceName := fmt.Sprintf("%s_allow_%s", lambdaResourceName, suffix)
+
+		permissionBlock := body.AppendNewBlock("resource", []string{"aws_lambda_permission", permissionResourceName})
+		permissionBody := permissionBlock.Body()
+
+		permissionBody.SetAttributeValue("statement_id", cty.StringVal(fmt.Sprintf("AllowBedrockAgent_%s", suffix)))
+		permissionBody.SetAttributeValue("action", cty.StringVal("lambda:InvokeFunction"))
+		permissionBody.SetAttributeRaw("function_name", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_lambda_function.%s.function_name", lambdaResourceName))},
+		})
+		permissionBody.SetAttributeValue("principal", cty.StringVal("bedrock.amazonaws.com"))
+		g.setLambdaPermissionQualifier(permissionBody, lambdaName)
+		permissionBody.SetAttributeRaw("source_arn", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_bedrockagent_agent.%s.agent_arn", agentResourceName))},
+		})
+
+		body.AppendNewline()
+
+		g.logger.WithField("lambda", lambdaName).WithField("agent", agentName).Debug("Generated agent-specific Lambda permission")
+	}
+}
+
+// setLambdaPermissionQualifier scopes a permission to the provisioned concurrency alias, which is what
+// action groups invoke when the Lambda has one
+func (g *HCLGenerator) setLambdaPermissionQualifier(permissionBody *hclwrite.Body, lambdaName string) {
+	if alias := g.provisionedConcurrencyAlias(lambdaName); alias != "" {
+		permissionBody.SetAttributeRaw("qualifier", referenceTokens(fmt.Sprintf("aws_lambda_alias.%s.name", alias)))
+	}
+}
+
 // setLambdaNativeAdvancedAttributes sets advanced Lambda attributes
-func (g *HCLGenerator) setLambdaNativeAdvancedAttributes(resourceBody *hclwrite.Body, lambda models.LambdaSpec) {
+func (g *HCLGenerator) setLambdaNativeAdvancedAttributes(resourceBody *hclwrite.Body, resourceName string, lambda models.LambdaSpec) error {
 	// Architectures
 	if len(lambda.Architectures) > 0 {
 		archVals := make([]cty.Value, 0, len(lambda.Architectures))
@@ -359,7 +658,13 @@ func (g *HCLGenerator) setLambdaNativeAdvancedAttributes(resourceBody *hclwrite.
 	if lambda.DeadLetterConfig != nil {
 		dlcBlock := resourceBody.AppendNewBlock("dead_letter_config", nil)
 		dlcBody := dlcBlock.Body()
-		dlcBody.SetAttributeValue("target_arn", cty.StringVal(lambda.DeadLetterConfig.TargetArn))
+		if lambda.DeadLetterConfig.AutoCreate {
+			dlcBody.SetAttributeRaw("target_arn", hclwrite.Tokens{
+				{Type: hclsyntax.TokenIdent, Bytes: []byte(deadLetterTargetArn(resourceName, lambda.DeadLetterConfig.TargetType()))},
+			})
+		} else {
+			dlcBody.SetAttributeValue("target_arn", cty.StringVal(lambda.DeadLetterConfig.TargetArn))
+		}
 	}
 
 	// Ephemeral storage
@@ -401,18 +706,22 @@ func (g *HCLGenerator) setLambdaNativeAdvancedAttributes(resourceBody *hclwrite.
 		}
 	}
 
-	// KMS key
-	if lambda.KmsKeyArn != "" {
-		resourceBody.SetAttributeValue("kms_key_arn", cty.StringVal(lambda.KmsKeyArn))
+	// KMS key, falling back to the project default when there are environment variables to encrypt
+	kmsKeyArn := lambda.KmsKeyArn
+	if kmsKeyArn == "" && len(lambda.Environment) > 0 {
+		kmsKeyArn = g.config.DefaultLambdaKmsKeyArn
+	}
+	if kmsKeyArn != "" {
+		resourceBody.SetAttributeValue("kms_key_arn", cty.StringVal(kmsKeyArn))
 	}
 
 	// Layers
 	if len(lambda.Layers) > 0 {
-		layerVals := make([]cty.Value, 0, len(lambda.Layers))
-		for _, layer := range lambda.Layers {
-			layerVals = append(layerVals, cty.StringVal(layer))
+		layerTokens, err := g.lambdaLayersTokens(lambda.Layers)
+		if err != nil {
+			return err
 		}
-		resourceBody.SetAttributeValue("layers", cty.ListVal(layerVals))
+		resourceBody.SetAttributeRaw("layers", layerTokens)
 	}
 
 	// Package type
@@ -452,6 +761,8 @@ func (g *HCLGenerator) setLambdaNativeAdvancedAttributes(resourceBody *hclwrite.
 		tracingBody := tracingBlock.Body()
 		tracingBody.SetAttributeValue("mode", cty.StringVal(lambda.TracingConfig.Mode))
 	}
+
+	return nil
 }
 
 // needsS3Permissions checks if the Lambda function needs S3 permissions based on environment variables
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+)
+
+const collidingAgentResources = `
+kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: index.handler
+  code:
+    inline: "def handler(event, context): return event"
+---
+kind: Agent
+metadata:
+  name: order-bot
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You look up orders for customers.
+  actionGroups:
+    - name: orders
+      actionGroupExecutor:
+        lambda: order-lookup
+---
+kind: Agent
+metadata:
+  name: Order_Bot
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You look up orders for support staff.
+---
+kind: ActionGroup
+metadata:
+  name: staff-orders
+spec:
+  agentId: Order_Bot
+  actionGroupExecutor:
+    lambda: {ref: order-lookup}
+---
+kind: Agent
+metadata:
+  name: billing-bot
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You answer billing questions.
+---
+kind: ActionGroup
+metadata:
+  name: billing-orders
+spec:
+  agentId: billing-bot
+  actionGroupExecutor:
+    lambda: order-lookup
+---
+kind: ActionGroup
+metadata:
+  name: external-orders
+spec:
+  agentId: ABCDEFGHIJ
+  actionGroupExecutor:
+    lambda: order-lookup
+`
+
+func TestAgentLambdaPermissionsDisambiguateCollidingAgents(t *testing.T) {
+	g, hook := newTestGenerator(t, collidingAgentResources, &GeneratorConfig{OutputDir: t.TempDir()})
+	lambda, _ := g.registry.GetResource(models.LambdaKind, "order-lookup")
+
+	// order-bot and Order_Bot both sanitize to order_bot
+	body := hclwrite.NewEmptyFile().Body()
+	if err := g.generateLambdaNative(body, models.BaseResource{Kind: models.LambdaKind, Metadata: lambda.Metadata, Spec: lambda.Resource.(*models.Lambda).Spec}); err != nil {
+		t.Fatalf("generateLambdaNative: %v", err)
+	}
+
+	permissions := findBlocks(parseBody(t, body), "resource", "aws_lambda_permission")
+	if len(permissions) != 3 {
+		t.Fatalf("expected a permission per agent in the project, got %d", len(permissions))
+	}
+
+	// Agents are sorted by name, so Order_Bot gets the statement ID first
+	want := []struct {
+		label       string
+		statementID string
+		sourceArn   string
+	}{
+		{label: "order_lookup_allow_order_bot", statementID: "AllowBedrockAgent_order_bot", sourceArn: "aws_bedrockagent_agent.order_bot.agent_arn"},
+		{label: "order_lookup_allow_billing_bot", statementID: "AllowBedrockAgent_billing_bot", sourceArn: "aws_bedrockagent_agent.billing_bot.agent_arn"},
+		{label: "order_lookup_allow_order_bot_2", statementID: "AllowBedrockAgent_order_bot_2", sourceArn: "aws_bedrockagent_agent.order_bot.agent_arn"},
+	}
+	statementIDs := make(map[string]bool)
+	for i, permission := range permissions {
+		if permission.Labels[1] != want[i].label {
+			t.Errorf("permission %d is labelled %s, want %s", i, permission.Labels[1], want[i].label)
+		}
+		statementID := stringAttribute(t, permission, "statement_id")
+		if statementID != want[i].statementID {
+			t.Errorf("permission %d has statement_id %s, want %s", i, statementID, want[i].statementID)
+		}
+		if sourceArn := referenceAttribute(t, permission, "source_arn"); sourceArn != want[i].sourceArn {
+			t.Errorf("permission %d has source_arn %s, want %s", i, sourceArn, want[i].sourceArn)
+		}
+		if statementIDs[statementID] {
+			t.Errorf("duplicate statement_id %s", statementID)
+		}
+		statementIDs[statementID] = true
+	}
+
+	var warnings []*logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "collides") {
+			warnings = append(warnings, entry)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Data["agent"] != "order-bot" {
+		t.Errorf("expected one collision warning for order-bot, got %v", warnings)
+	}
+}
+
+func TestLambdaEnvironmentDataSourceCollision(t *testing.T) {
+	content := `
+kind: Lambda
+metadata:
+  name: order
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+  environment:
+    LOOKUP_DB_HOST: "${ssm:/orders/lookup-db-host}"
+---
+kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+  environment:
+    DB_HOST: "${ssm:/orders/db-host}"
+    DB_PASSWORD: "${secret:orders/db-password}"
+`
+	// order's LOOKUP_DB_HOST and order-lookup's DB_HOST both label their data source order_lookup_db_host
+	g, _ := newTestGenerator(t, content, &GeneratorConfig{OutputDir: t.TempDir()})
+	g.config.Output = &bytes.Buffer{}
+	err := g.Generate()
+	want := "Lambda order variable LOOKUP_DB_HOST and Lambda order-lookup variable DB_HOST both read their value through data.aws_ssm_parameter.order_lookup_db_host"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Generate() = %v, want an error containing %q", err, want)
+	}
+
+	// Without the colliding variable each value gets its own data source
+	g, _ = newTestGenerator(t, strings.Replace(content, "LOOKUP_DB_HOST", "ORDER_DB_HOST", 1), &GeneratorConfig{OutputDir: t.TempDir()})
+	body := generateConfiguration(t, g)
+	var addresses []string
+	for _, block := range body.Blocks {
+		if block.Type == "data" && block.Labels[0] != "archive_file" {
+			addresses = append(addresses, strings.Join(block.Labels, "."))
+		}
+	}
+	want = "aws_ssm_parameter.order_order_db_host aws_ssm_parameter.order_lookup_db_host aws_secretsmanager_secret_version.order_lookup_db_password"
+	if got := strings.Join(addresses, " "); got != want {
+		t.Errorf("data sources = %s, want %s", got, want)
+	}
+}
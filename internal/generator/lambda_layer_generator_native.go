@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// generateLambdaLayerNative creates a native aws_lambda_layer_version resource for a LambdaLayer
+func (g *HCLGenerator) generateLambdaLayerNative(body *hclwrite.Body, resource models.BaseResource) error {
+	layer, ok := resource.Spec.(models.LambdaLayerSpec)
+	if !ok {
+		return fmt.Errorf("invalid lambda layer spec format")
+	}
+
+	if err := layer.Validate(); err != nil {
+		return fmt.Errorf("invalid LambdaLayer %s: %w", resource.Metadata.Name, err)
+	}
+
+	resourceName := g.sanitizeResourceName(resource.Metadata.Name)
+	// Lambdas zip their source as data.archive_file.<name>, so layers use a suffix to avoid clashing
+	archiveName := fmt.Sprintf("%s_layer", resourceName)
+
+	layerName := layer.LayerName
+	if layerName == "" {
+		layerName = resource.Metadata.Name
+	}
+
+	if layer.Code.Source != "" {
+		g.generateArchiveDataSource(body, archiveName, layer.Code.Source)
+	}
+
+	layerBody := body.AppendNewBlock("resource", []string{"aws_lambda_layer_version", resourceName}).Body()
+	layerBody.SetAttributeValue("layer_name", cty.StringVal(layerName))
+
+	if resource.Metadata.Description != "" {
+		layerBody.SetAttributeValue("description", cty.StringVal(resource.Metadata.Description))
+	}
+
+	if layer.Code.Source != "" {
+		layerBody.SetAttributeRaw("filename", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("data.archive_file.%s.output_path", archiveName))},
+		})
+		layerBody.SetAttributeRaw("source_code_hash", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("data.archive_file.%s.output_base64sha256", archiveName))},
+		})
+	} else {
+		layerBody.SetAttributeValue("s3_bucket", cty.StringVal(layer.Code.S3Bucket))
+		layerBody.SetAttributeValue("s3_key", cty.StringVal(layer.Code.S3Key))
+		if layer.Code.S3ObjectVersion != "" {
+			layerBody.SetAttributeValue("s3_object_version", cty.StringVal(layer.Code.S3ObjectVersion))
+		}
+	}
+
+	if len(layer.CompatibleRuntimes) > 0 {
+		layerBody.SetAttributeValue("compatible_runtimes", stringListValue(layer.CompatibleRuntimes))
+	}
+	if len(layer.CompatibleArchitectures) > 0 {
+		layerBody.SetAttributeValue("compatible_architectures", stringListValue(layer.CompatibleArchitectures))
+	}
+	if layer.LicenseInfo != "" {
+		layerBody.SetAttributeValue("license_info", cty.StringVal(layer.LicenseInfo))
+	}
+	if layer.SkipDestroy != nil {
+		layerBody.SetAttributeValue("skip_destroy", cty.BoolVal(*layer.SkipDestroy))
+	}
+
+	body.AppendNewline()
+
+	g.logger.WithField("lambda_layer", resource.Metadata.Name).Info("Generated native Lambda layer resource")
+	return nil
+}
+
+// lambdaLayersTokens returns the layers list of a Lambda: LambdaLayer references resolve to the ARN of the
+// generated layer version, ${ref:custom.<output>} to the custom output, and layer ARNs are kept as-is
+func (g *HCLGenerator) lambdaLayersTokens(layers []models.Reference) (hclwrite.Tokens, error) {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+
+	for i, layer := range layers {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+
+		customTokens, isRef, err := g.resolveCustomOutputReference(layer.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve layer %s: %w", layer, err)
+		}
+
+		switch {
+		case isRef:
+			tokens = append(tokens, customTokens...)
+		case models.IsExternalLayer(layer):
+			tokens = append(tokens, hclwrite.TokensForValue(cty.StringVal(layer.String()))...)
+		default:
+			if !g.registry.HasResource(models.LambdaLayerKind, layer.String()) {
+				return nil, fmt.Errorf("layer %s is neither a layer ARN nor a LambdaLayer resource", layer)
+			}
+			tokens = append(tokens, &hclwrite.Token{
+				Type:  hclsyntax.TokenIdent,
+				Bytes: []byte(fmt.Sprintf("aws_lambda_layer_version.%s.arn", g.sanitizeResourceName(layer.String()))),
+			})
+		}
+	}
+
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")}), nil
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// lambdaTarget is the Lambda function an action group executor invokes
+type lambdaTarget struct {
+	expression string // aws_lambda_function.<name>.arn or its alias for Lambdas in this project, otherwise the ARN
+	reference  bool
+}
+
+// tokens returns the target as an attribute value for native resources
+func (t lambdaTarget) tokens() hclwrite.Tokens {
+	if t.reference {
+		return referenceTokens(t.expression)
+	}
+	return hclwrite.TokensForValue(cty.StringVal(t.expression))
+}
+
+// template returns the target as a string value for module inputs and policy documents
+func (t lambdaTarget) template() string {
+	if t.reference {
+		return fmt.Sprintf("${%s}", t.expression)
+	}
+	return t.expression
+}
+
+// resolveLambdaTarget resolves the Lambda an action group executor invokes. A Lambda defined in this
+// project is preferred, then a Lambda function ARN from lambdaArn or lambda; anything else is an error.
+// It returns false for executors without a Lambda, such as RETURN_CONTROL ones.
+func (g *HCLGenerator) resolveLambdaTarget(owner string, executor *models.ActionGroupExecutor) (lambdaTarget, bool, error) {
+	if executor == nil || (executor.Lambda.IsEmpty() && executor.LambdaArn == "") {
+		return lambdaTarget{}, false, nil
+	}
+
+	if !executor.Lambda.IsEmpty() {
+		lambdaName := executor.Lambda.String()
+		if g.registry.HasResource(models.LambdaKind, lambdaName) {
+			// Invoke the alias provisioned concurrency is attached to, so requests use the warm environments
+			if alias := g.provisionedConcurrencyAlias(lambdaName); alias != "" {
+				return lambdaTarget{
+					expression: fmt.Sprintf("aws_lambda_alias.%s.arn", alias),
+					reference:  true,
+				}, true, nil
+			}
+			return lambdaTarget{
+				expression: fmt.Sprintf("aws_lambda_function.%s.arn", g.sanitizeResourceName(lambdaName)),
+				reference:  true,
+			}, true, nil
+		}
+		if !models.IsLambdaFunctionArn(lambdaName) {
+			return lambdaTarget{}, false, fmt.Errorf("%s: lambda %s is neither a Lambda in this project nor a Lambda function ARN", owner, lambdaName)
+		}
+		// The agent execution policy resolves inline action groups a second time
+		if !g.externalLambdaWarnings[owner] {
+			if g.externalLambdaWarnings == nil {
+				g.externalLambdaWarnings = make(map[string]bool)
+			}
+			g.externalLambdaWarnings[owner] = true
+			g.logger.WithFields(logrus.Fields{
+				"owner":      owner,
+				"lambda_arn": lambdaName,
+			}).Warn("Lambda is not defined in this project, using it as an external function ARN; set lambdaArn instead")
+		}
+		return lambdaTarget{expression: lambdaName}, true, nil
+	}
+
+	if !models.IsLambdaFunctionArn(executor.LambdaArn) {
+		return lambdaTarget{}, false, fmt.Errorf("%s: lambdaArn %s is not a Lambda function ARN", owner, executor.LambdaArn)
+	}
+	g.logger.WithFields(logrus.Fields{
+		"owner":      owner,
+		"lambda_arn": executor.LambdaArn,
+	}).Debug("Using external Lambda ARN for action group executor")
+	return lambdaTarget{expression: executor.LambdaArn}, true, nil
+}
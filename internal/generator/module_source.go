@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moduleSourceType is how Terraform installs modules from a source address
+type moduleSourceType int
+
+const (
+	moduleSourceRemote   moduleSourceType = iota // git, archives and other go-getter sources, pinned with ?ref=
+	moduleSourceRegistry                         // public or private module registry, pinned with version
+	moduleSourceLocal                            // local path, the version is ignored
+)
+
+var (
+	// [host/]namespace/name/provider, as accepted by Terraform for registry modules
+	registryIdentifierPattern = regexp.MustCompile(`^[0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?$`)
+	registryProviderPattern   = regexp.MustCompile(`^[0-9a-z]{1,64}$`)
+	registryHostPattern       = regexp.MustCompile(`^(?:[0-9A-Za-z-]+\.)+[0-9A-Za-z-]+(?::\d+)?$|^localhost(?::\d+)?$`)
+
+	// A single version constraint such as 1.2.0, >= 1.2 or ~> 1.2.0-beta
+	versionConstraintPattern = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*v?(\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.-]+)?)$`)
+)
+
+// classifyModuleSource determines how a module source address is installed and versioned
+func classifyModuleSource(source string) moduleSourceType {
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return moduleSourceLocal
+	}
+	if isRegistrySource(source) {
+		return moduleSourceRegistry
+	}
+	return moduleSourceRemote
+}
+
+// isRegistrySource reports whether source is a registry address, e.g. terraform-aws-modules/iam/aws
+// or app.terraform.io/org/bedrock/aws
+func isRegistrySource(source string) bool {
+	if strings.Contains(source, "::") || strings.Contains(source, "://") || strings.Contains(source, "?") {
+		return false
+	}
+
+	parts := strings.Split(source, "/")
+	switch len(parts) {
+	case 3:
+		// Shorthands such as github.com/org/repo are git sources, not registry addresses
+		if strings.Contains(parts[0], ".") {
+			return false
+		}
+	case 4:
+		if !registryHostPattern.MatchString(parts[0]) {
+			return false
+		}
+		parts = parts[1:]
+	default:
+		return false
+	}
+
+	return registryIdentifierPattern.MatchString(parts[0]) &&
+		registryIdentifierPattern.MatchString(parts[1]) &&
+		registryProviderPattern.MatchString(parts[2])
+}
+
+// validateModuleSource checks that the configured module version can be applied to the module registry
+func (g *HCLGenerator) validateModuleSource() error {
+	version := strings.TrimSpace(g.config.ModuleVersion)
+	if version == "" {
+		return nil
+	}
+
+	switch classifyModuleSource(g.config.ModuleRegistry) {
+	case moduleSourceRegistry:
+		if _, err := registryVersionConstraint(version); err != nil {
+			return fmt.Errorf("invalid module version for registry %s: %w", g.config.ModuleRegistry, err)
+		}
+	case moduleSourceLocal:
+		// Local modules aren't versioned; the version may just be the built-in default
+		g.logger.WithField("module_registry", g.config.ModuleRegistry).Warn("Ignoring module version for local module path")
+	default:
+		if strings.ContainsAny(version, " ,<>=~!") {
+			return fmt.Errorf("module version '%s' is a version constraint, which is only supported for registry module sources; use a git tag, branch or commit for %s", version, g.config.ModuleRegistry)
+		}
+		if strings.Contains(g.config.ModuleRegistry, "ref=") {
+			return fmt.Errorf("module registry %s already pins a ref, remove it or the module version", g.config.ModuleRegistry)
+		}
+	}
+
+	return nil
+}
+
+// registryVersionConstraint normalizes a comma-separated list of version constraints for a
+// registry module's version argument. A leading v on versions is dropped.
+func registryVersionConstraint(version string) (string, error) {
+	var constraints []string
+	for _, part := range strings.Split(version, ",") {
+		match := versionConstraintPattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			return "", fmt.Errorf("'%s' is not a valid version constraint", strings.TrimSpace(part))
+		}
+		if match[1] == "" {
+			constraints = append(constraints, match[2])
+		} else {
+			constraints = append(constraints, match[1]+" "+match[2])
+		}
+	}
+	return strings.Join(constraints, ", "), nil
+}
+
+// setModuleSource points a module block at a module in the configured registry, pinned to the
+// configured version with a version argument for registry sources and ?ref= for everything else
+func (g *HCLGenerator) setModuleSource(body *hclwrite.Body, module string) {
+	registry := g.config.ModuleRegistry
+	version := strings.TrimSpace(g.config.ModuleVersion)
+
+	switch classifyModuleSource(registry) {
+	case moduleSourceRegistry:
+		body.SetAttributeValue("source", cty.StringVal(fmt.Sprintf("%s//modules/%s", registry, module)))
+		if version != "" {
+			// Checked by validateModuleSource before generation
+			constraint, _ := registryVersionConstraint(version)
+			body.SetAttributeValue("version", cty.StringVal(constraint))
+		}
+	case moduleSourceLocal:
+		body.SetAttributeValue("source", cty.StringVal(fmt.Sprintf("%s/modules/%s", strings.TrimSuffix(registry, "/"), module)))
+	default:
+		// The subdirectory goes before any query string, e.g. git::https://host/repo.git//modules/x?ref=v1
+		base, query, _ := strings.Cut(registry, "?")
+		source := fmt.Sprintf("%s//modules/%s", base, module)
+
+		var params []string
+		if query != "" {
+			params = append(params, query)
+		}
+		if version != "" {
+			params = append(params, "ref="+version)
+		}
+		if len(params) > 0 {
+			source += "?" + strings.Join(params, "&")
+		}
+		body.SetAttributeValue("source", cty.StringVal(source))
+	}
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+func TestModuleSourceStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		version  string
+		kind     moduleSourceType
+		source   string
+		pinned   string // Expected version argument, "" when there is none
+	}{
+		{
+			name:     "public registry",
+			registry: "company/bedrock/aws",
+			version:  "v1.2.0",
+			kind:     moduleSourceRegistry,
+			source:   "company/bedrock/aws//modules/bedrock-agent",
+			pinned:   "1.2.0",
+		},
+		{
+			name:     "private registry",
+			registry: "app.terraform.io/company/bedrock/aws",
+			version:  ">= 1.2, < 2.0",
+			kind:     moduleSourceRegistry,
+			source:   "app.terraform.io/company/bedrock/aws//modules/bedrock-agent",
+			pinned:   ">= 1.2, < 2.0",
+		},
+		{
+			name:     "private registry with port",
+			registry: "registry.internal:8443/company/bedrock/aws",
+			version:  "~>1.4",
+			kind:     moduleSourceRegistry,
+			source:   "registry.internal:8443/company/bedrock/aws//modules/bedrock-agent",
+			pinned:   "~> 1.4",
+		},
+		{
+			name:     "git over https",
+			registry: "git::https://github.com/company/bedrock-terraform-modules",
+			version:  "v1.0.0",
+			kind:     moduleSourceRemote,
+			source:   "git::https://github.com/company/bedrock-terraform-modules//modules/bedrock-agent?ref=v1.0.0",
+		},
+		{
+			name:     "git over ssh with query",
+			registry: "git::ssh://git@github.com/company/modules.git?depth=1",
+			version:  "main",
+			kind:     moduleSourceRemote,
+			source:   "git::ssh://git@github.com/company/modules.git//modules/bedrock-agent?depth=1&ref=main",
+		},
+		{
+			name:     "github shorthand",
+			registry: "github.com/company/bedrock-terraform-modules",
+			version:  "v2.0.0",
+			kind:     moduleSourceRemote,
+			source:   "github.com/company/bedrock-terraform-modules//modules/bedrock-agent?ref=v2.0.0",
+		},
+		{
+			name:     "local path",
+			registry: "./modules-repo/",
+			version:  "v1.0.0",
+			kind:     moduleSourceLocal,
+			source:   "./modules-repo/modules/bedrock-agent",
+		},
+		{
+			name:     "parent local path",
+			registry: "../shared",
+			kind:     moduleSourceLocal,
+			source:   "../shared/modules/bedrock-agent",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if kind := classifyModuleSource(test.registry); kind != test.kind {
+				t.Fatalf("classifyModuleSource(%q) = %d, want %d", test.registry, kind, test.kind)
+			}
+
+			g, _ := newTestGenerator(t, "", &GeneratorConfig{ModuleRegistry: test.registry, ModuleVersion: test.version})
+			if err := g.validateModuleSource(); err != nil {
+				t.Fatalf("validateModuleSource: %v", err)
+			}
+
+			body := hclwrite.NewEmptyFile().Body()
+			moduleBody := body.AppendNewBlock("module", []string{"agent"}).Body()
+			g.setModuleSource(moduleBody, "bedrock-agent")
+
+			module := findBlocks(parseBody(t, body), "module", "agent")[0]
+			if source := stringAttribute(t, module, "source"); source != test.source {
+				t.Errorf("source = %s, want %s", source, test.source)
+			}
+			_, hasVersion := module.Body.Attributes["version"]
+			switch {
+			case test.pinned == "" && hasVersion:
+				t.Errorf("unexpected version %s", stringAttribute(t, module, "version"))
+			case test.pinned != "":
+				if version := stringAttribute(t, module, "version"); version != test.pinned {
+					t.Errorf("version = %s, want %s", version, test.pinned)
+				}
+			}
+		})
+	}
+}
+
+func TestModuleSourceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		version  string
+		err      string
+	}{
+		{
+			name:     "constraint on a git source",
+			registry: "git::https://github.com/company/bedrock-terraform-modules",
+			version:  ">= 1.2",
+			err:      "only supported for registry module sources",
+		},
+		{
+			name:     "git source already pinned",
+			registry: "git::https://github.com/company/modules.git?ref=v1",
+			version:  "v2",
+			err:      "already pins a ref",
+		},
+		{
+			name:     "invalid registry constraint",
+			registry: "company/bedrock/aws",
+			version:  "latest",
+			err:      "'latest' is not a valid version constraint",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, _ := newTestGenerator(t, "", &GeneratorConfig{ModuleRegistry: test.registry, ModuleVersion: test.version})
+			err := g.validateModuleSource()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("validateModuleSource() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"bedrock-forge/internal/models"
+)
+
+// addMovedBlocks emits moved blocks for resources renamed via metadata.previousNames, so Terraform
+// migrates their state to the new addresses instead of destroying and recreating them
+func (g *HCLGenerator) addMovedBlocks(body *hclwrite.Body, dependencyOrder []models.ResourceKind) error {
+	// Addresses generated in this run; a moved block must not start from one of them
+	activeAddresses := make(map[string]bool)
+	for _, block := range body.Blocks() {
+		if address := blockAddress(block); address != "" {
+			activeAddresses[address] = true
+		}
+	}
+
+	movedFrom := make(map[string]string)
+	for _, kind := range dependencyOrder {
+		for _, resource := range g.registry.GetResourcesByType(kind) {
+			if len(resource.Metadata.PreviousNames) == 0 {
+				continue
+			}
+
+			// Custom terraform is copied as-is, so its addresses don't follow the resource name
+			if resource.Kind == models.CustomResourcesKind {
+				g.logger.WithField("custom_resources", resource.Metadata.Name).Warn("metadata.previousNames on CustomResources is ignored, add moved blocks to the terraform files directly")
+				continue
+			}
+
+			// Renames are chained oldest first, so each address has a single move to and from it
+			currentLabel := g.sanitizeResourceName(resource.Metadata.Name)
+			labels := make([]string, 0, len(resource.Metadata.PreviousNames)+1)
+			for _, previousName := range resource.Metadata.PreviousNames {
+				labels = append(labels, g.sanitizeResourceName(previousName))
+			}
+			labels = append(labels, currentLabel)
+
+			for _, block := range g.resourceBlocks[resourceKey(resource.Kind, resource.Metadata.Name)] {
+				if block.Type() != "resource" && block.Type() != "module" {
+					continue
+				}
+
+				blockLabels := block.Labels()
+				label := blockLabels[len(blockLabels)-1]
+				if !strings.Contains(label, currentLabel) {
+					continue
+				}
+				address := blockAddress(block)
+				addressFor := func(nameLabel string) string {
+					return strings.TrimSuffix(address, label) + strings.Replace(label, currentLabel, nameLabel, 1)
+				}
+
+				for i := 0; i+1 < len(labels); i++ {
+					if labels[i] == labels[i+1] {
+						continue
+					}
+					from, to := addressFor(labels[i]), addressFor(labels[i+1])
+
+					if activeAddresses[from] {
+						return fmt.Errorf("%s %s previous name maps to %s, which is still generated", resource.Kind, resource.Metadata.Name, from)
+					}
+					if other, exists := movedFrom[from]; exists {
+						return fmt.Errorf("%s is moved to both %s and %s", from, other, to)
+					}
+					movedFrom[from] = to
+
+					appendMovedBlock(body, from, to)
+				}
+			}
+
+			g.logger.WithField("resource", resourceKey(resource.Kind, resource.Metadata.Name)).Debug("Generated moved blocks for previous names")
+		}
+	}
+
+	return nil
+}
+
+// appendMovedBlock adds a moved block from one address to another
+func appendMovedBlock(body *hclwrite.Body, from, to string) {
+	movedBody := body.AppendNewBlock("moved", nil).Body()
+	movedBody.SetAttributeRaw("from", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(from)},
+	})
+	movedBody.SetAttributeRaw("to", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(to)},
+	})
+	body.AppendNewline()
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMovedBlocks(t *testing.T) {
+	g, _ := newTestGenerator(t, `
+kind: Agent
+metadata:
+  name: support-agent
+  previousNames: [helpdesk-agent, customer-agent]
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You are a helpful customer support agent.
+`, nil)
+	body := generateConfiguration(t, g)
+
+	moves := make(map[string]string)
+	for _, block := range body.Blocks {
+		if block.Type == "moved" {
+			moves[referenceAttribute(t, block, "from")] = referenceAttribute(t, block, "to")
+		}
+	}
+
+	// Renames are chained oldest first, for the agent and the resources generated with it
+	want := map[string]string{
+		"aws_bedrockagent_agent.helpdesk_agent":      "aws_bedrockagent_agent.customer_agent",
+		"aws_bedrockagent_agent.customer_agent":      "aws_bedrockagent_agent.support_agent",
+		"aws_iam_role.helpdesk_agent_execution_role": "aws_iam_role.customer_agent_execution_role",
+		"aws_iam_role.customer_agent_execution_role": "aws_iam_role.support_agent_execution_role",
+	}
+	for from, to := range want {
+		if moves[from] != to {
+			t.Errorf("%s is moved to %q, want %s", from, moves[from], to)
+		}
+	}
+	for from := range moves {
+		if !strings.Contains(from, "helpdesk_agent") && !strings.Contains(from, "customer_agent") {
+			t.Errorf("unexpected move from %s", from)
+		}
+	}
+}
+
+func TestMovedBlocksRejectActiveAddress(t *testing.T) {
+	g, _ := newTestGenerator(t, `
+kind: Agent
+metadata:
+  name: support-agent
+  previousNames: [billing-agent]
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You are a helpful customer support agent.
+---
+kind: Agent
+metadata:
+  name: billing-agent
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+  instruction: You answer billing questions.
+`, nil)
+
+	var output bytes.Buffer
+	g.config.Output = &output
+	err := g.Generate()
+	if err == nil || !strings.Contains(err.Error(), "Agent support-agent previous name maps to") || !strings.Contains(err.Error(), "billing_agent") {
+		t.Errorf("Generate() = %v, want the active previous name error", err)
+	}
+}
@@ -3,7 +3,9 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 
@@ -36,6 +38,9 @@ func (g *HCLGenerator) generateOpenSearchServerlessModule(body *hclwrite.Body, r
 	if collectionName == "" {
 		collectionName = resource.Metadata.Name
 	}
+	if err := models.ValidateCollectionName(collectionName); err != nil {
+		return fmt.Errorf("invalid OpenSearch Serverless collection %s: %w", resource.Metadata.Name, err)
+	}
 
 	// Generate encryption policy
 	if err := g.generateEncryptionPolicy(body, resourceName, collectionName, opensearchServerless.EncryptionPolicy); err != nil {
@@ -59,7 +64,7 @@ func (g *HCLGenerator) generateOpenSearchServerlessModule(body *hclwrite.Body, r
 
 	// Generate vector index if specified
 	if opensearchServerless.VectorIndex != nil {
-		if err := g.generateVectorIndex(body, resourceName, collectionName, opensearchServerless.VectorIndex); err != nil {
+		if err := g.generateVectorIndex(body, resourceName, collectionName, resource.Metadata.Provider, opensearchServerless.VectorIndex); err != nil {
 			return fmt.Errorf("failed to generate vector index: %w", err)
 		}
 	}
@@ -70,6 +75,10 @@ func (g *HCLGenerator) generateOpenSearchServerlessModule(body *hclwrite.Body, r
 
 // generateEncryptionPolicy creates the encryption policy for the collection
 func (g *HCLGenerator) generateEncryptionPolicy(body *hclwrite.Body, resourceName, collectionName string, policy *models.EncryptionPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
 	policyName := fmt.Sprintf("%s-encryption-policy", resourceName)
 	if policy != nil && policy.Name != "" {
 		policyName = policy.Name
@@ -100,6 +109,25 @@ func (g *HCLGenerator) generateEncryptionPolicy(body *hclwrite.Body, resourceNam
 		"AWSOwnedKey": true,
 	}
 
+	// A referenced key is only known at apply time, so the document is built with jsonencode()
+	if policy != nil && !policy.KmsKeyRef.IsEmpty() {
+		tokens, _, err := g.resolveCustomOutputReference(policy.KmsKeyRef.String())
+		if err != nil {
+			return fmt.Errorf("failed to resolve kmsKeyRef: %w", err)
+		}
+		rule := hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{
+			{Name: hclwrite.TokensForIdentifier("Resource"), Value: hclwrite.TokensForValue(cty.TupleVal([]cty.Value{cty.StringVal(fmt.Sprintf("collection/%s", collectionName))}))},
+			{Name: hclwrite.TokensForIdentifier("ResourceType"), Value: hclwrite.TokensForValue(cty.StringVal("collection"))},
+		})
+		policyBody.SetAttributeRaw("policy", hclwrite.TokensForFunctionCall("jsonencode", hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{
+			{Name: hclwrite.TokensForIdentifier("AWSOwnedKey"), Value: hclwrite.TokensForValue(cty.False)},
+			{Name: hclwrite.TokensForIdentifier("KmsKeyId"), Value: tokens},
+			{Name: hclwrite.TokensForIdentifier("Rules"), Value: hclwrite.TokensForTuple([]hclwrite.Tokens{rule})},
+		})))
+		body.AppendNewline()
+		return nil
+	}
+
 	// Use custom KMS key if provided
 	if policy != nil && policy.KmsKeyId != "" {
 		policyDoc["AWSOwnedKey"] = false
@@ -254,6 +282,7 @@ func (g *HCLGenerator) generateAccessPolicy(body *hclwrite.Body, resourceName, c
 		for perm := range permissionSet {
 			permissions = append(permissions, perm)
 		}
+		sort.Strings(permissions)
 	}
 
 	// Policy document
@@ -338,11 +367,162 @@ func (g *HCLGenerator) generateCollection(body *hclwrite.Body, resourceName, col
 	return nil
 }
 
+// vectorIndexSettings holds the resolved vector index configuration with defaults applied
+type vectorIndexSettings struct {
+	vectorField   string
+	textField     string
+	metadataField string
+	dimension     int
+	engine        string
+	spaceType     string
+}
+
+// resolveVectorIndexSettings applies defaults to a vector index configuration
+func resolveVectorIndexSettings(vectorIndex *models.VectorIndexConfig) (vectorIndexSettings, error) {
+	settings := vectorIndexSettings{
+		vectorField:   "vector",
+		textField:     "text",
+		metadataField: "metadata",
+		dimension:     1536,
+		engine:        "nmslib",
+		spaceType:     "l2",
+	}
+
+	if vectorIndex.FieldMapping.VectorField != "" {
+		settings.vectorField = vectorIndex.FieldMapping.VectorField
+	}
+	if vectorIndex.FieldMapping.TextField != "" {
+		settings.textField = vectorIndex.FieldMapping.TextField
+	}
+	if vectorIndex.FieldMapping.MetadataField != "" {
+		settings.metadataField = vectorIndex.FieldMapping.MetadataField
+	}
+	if vectorIndex.Dimension < 0 {
+		return settings, fmt.Errorf("vector index dimension must be positive, got %d", vectorIndex.Dimension)
+	}
+	if vectorIndex.Dimension > 0 {
+		settings.dimension = vectorIndex.Dimension
+	}
+	if vectorIndex.Engine != "" {
+		settings.engine = vectorIndex.Engine
+	}
+	if vectorIndex.SpaceType != "" {
+		settings.spaceType = vectorIndex.SpaceType
+	}
+
+	return settings, nil
+}
+
+// mappingsJSON returns the index mappings for the vector, text and metadata fields
+func (s vectorIndexSettings) mappingsJSON() (string, error) {
+	mappings := map[string]interface{}{
+		"properties": map[string]interface{}{
+			s.vectorField: map[string]interface{}{
+				"type":      "knn_vector",
+				"dimension": s.dimension,
+				"method": map[string]interface{}{
+					"name":       "hnsw",
+					"space_type": s.spaceType,
+					"engine":     s.engine,
+				},
+			},
+			s.textField: map[string]interface{}{
+				"type": "text",
+			},
+			s.metadataField: map[string]interface{}{
+				"type": "text",
+			},
+		},
+	}
+
+	mappingsJSON, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal vector index mappings: %w", err)
+	}
+	return string(mappingsJSON), nil
+}
+
+// usesDeclarativeVectorIndex reports whether any collection creates its vector index with the opensearch provider
+func (g *HCLGenerator) usesDeclarativeVectorIndex() bool {
+	for _, resource := range g.registry.GetResourcesByType(models.OpenSearchServerlessKind) {
+		if spec, ok := resource.Spec.(models.OpenSearchServerlessSpec); ok {
+			if spec.VectorIndex != nil && !spec.VectorIndex.UseProvisioner {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // generateVectorIndex creates the vector index for the collection
-func (g *HCLGenerator) generateVectorIndex(body *hclwrite.Body, resourceName, collectionName string, vectorIndex *models.VectorIndexConfig) error {
-	// Note: Vector index creation is typically done via REST API after collection is created
-	// For now, we'll add a local-exec provisioner to create the index
+func (g *HCLGenerator) generateVectorIndex(body *hclwrite.Body, resourceName, collectionName, providerName string, vectorIndex *models.VectorIndexConfig) error {
+	settings, err := resolveVectorIndexSettings(vectorIndex)
+	if err != nil {
+		return err
+	}
+
+	if vectorIndex.UseProvisioner {
+		return g.generateVectorIndexProvisioner(body, resourceName, collectionName, vectorIndex.Name, settings)
+	}
+
+	providerAlias := fmt.Sprintf("%s_collection", resourceName)
+
+	// Region of the collection, resolved through the same AWS provider as the collection itself
+	body.AppendNewBlock("data", []string{"aws_region", resourceName})
+	body.AppendNewline()
+
+	// OpenSearch provider pointing at the collection endpoint, signing requests for AOSS
+	providerBlock := body.AppendNewBlock("provider", []string{"opensearch"})
+	providerBody := providerBlock.Body()
+	providerBody.SetAttributeValue("alias", cty.StringVal(providerAlias))
+	providerBody.SetAttributeRaw("url", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_opensearchserverless_collection.%s.collection_endpoint", resourceName))},
+	})
+	providerBody.SetAttributeRaw("aws_region", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("data.aws_region.%s.name", resourceName))},
+	})
+	providerBody.SetAttributeValue("aws_signature_service", cty.StringVal("aoss"))
+	providerBody.SetAttributeValue("sign_aws_requests", cty.True)
+	// Sign as the role the collection's named provider assumes
+	if provider, exists := g.config.Providers[providerName]; exists && provider.AssumeRole != nil {
+		providerBody.SetAttributeValue("aws_assume_role_arn", cty.StringVal(provider.AssumeRole.RoleArn))
+		if provider.AssumeRole.ExternalID != "" {
+			providerBody.SetAttributeValue("aws_assume_role_external_id", cty.StringVal(provider.AssumeRole.ExternalID))
+		}
+	}
+	providerBody.SetAttributeValue("healthcheck", cty.False)
+	body.AppendNewline()
 
+	mappings, err := settings.mappingsJSON()
+	if err != nil {
+		return err
+	}
+
+	// Declarative vector index
+	indexBlock := body.AppendNewBlock("resource", []string{"opensearch_index", fmt.Sprintf("%s_vector_index", resourceName)})
+	indexBody := indexBlock.Body()
+	indexBody.SetAttributeRaw("provider", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("opensearch.%s", providerAlias))},
+	})
+	indexBody.SetAttributeValue("name", cty.StringVal(vectorIndex.Name))
+	indexBody.SetAttributeValue("index_knn", cty.True)
+	indexBody.SetAttributeValue("index_knn_algo_param_ef_search", cty.StringVal("512"))
+	indexBody.SetAttributeValue("mappings", cty.StringVal(mappings))
+	indexBody.SetAttributeValue("force_destroy", cty.True)
+
+	// The data access policy must exist before the index can be created
+	indexBody.SetAttributeRaw("depends_on", hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")},
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("aws_opensearchserverless_access_policy.%s_access_policy", resourceName))},
+		{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")},
+	})
+
+	body.AppendNewline()
+	return nil
+}
+
+// generateVectorIndexProvisioner creates the vector index with a local-exec provisioner using the AWS CLI and curl
+func (g *HCLGenerator) generateVectorIndexProvisioner(body *hclwrite.Body, resourceName, collectionName, indexName string, settings vectorIndexSettings) error {
 	// Create null resource for index creation
 	indexBlock := body.AppendNewBlock("resource", []string{"null_resource", fmt.Sprintf("%s_vector_index", resourceName)})
 	indexBody := indexBlock.Body()
@@ -351,21 +531,6 @@ func (g *HCLGenerator) generateVectorIndex(body *hclwrite.Body, resourceName, co
 	provisionerBlock := indexBody.AppendNewBlock("provisioner", []string{"local-exec"})
 	provisionerBody := provisionerBlock.Body()
 
-	// Vector field mappings
-	vectorField := "vector"
-	textField := "text"
-	metadataField := "metadata"
-
-	if vectorIndex.FieldMapping.VectorField != "" {
-		vectorField = vectorIndex.FieldMapping.VectorField
-	}
-	if vectorIndex.FieldMapping.TextField != "" {
-		textField = vectorIndex.FieldMapping.TextField
-	}
-	if vectorIndex.FieldMapping.MetadataField != "" {
-		metadataField = vectorIndex.FieldMapping.MetadataField
-	}
-
 	// Command to create vector index
 	createIndexCommand := fmt.Sprintf(`
 aws opensearchserverless batch-get-collection --names %s --query 'collectionDetails[0].collectionEndpoint' --output text | \
@@ -380,11 +545,11 @@ xargs -I {} curl -X PUT "{}/%s" -H "Content-Type: application/json" -d '{
     "properties": {
       "%s": {
         "type": "knn_vector",
-        "dimension": 1536,
+        "dimension": %d,
         "method": {
           "name": "hnsw",
-          "space_type": "l2",
-          "engine": "nmslib"
+          "space_type": "%s",
+          "engine": "%s"
         }
       },
       "%s": {
@@ -395,7 +560,7 @@ xargs -I {} curl -X PUT "{}/%s" -H "Content-Type: application/json" -d '{
       }
     }
   }
-}'`, collectionName, vectorIndex.Name, vectorField, textField, metadataField)
+}'`, collectionName, indexName, settings.vectorField, settings.dimension, settings.spaceType, settings.engine, settings.textField, settings.metadataField)
 
 	provisionerBody.SetAttributeValue("command", cty.StringVal(createIndexCommand))
 
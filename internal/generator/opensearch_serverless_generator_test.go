@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+
+	"bedrock-forge/internal/models"
+)
+
+func TestEncryptionPolicyKmsKeyRef(t *testing.T) {
+	g, _ := newTestGenerator(t, "", nil)
+	g.customOutputs = map[string]hclwrite.Tokens{
+		"search_key_arn": hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: "aws_kms_key"},
+			hcl.TraverseAttr{Name: "search"},
+			hcl.TraverseAttr{Name: "arn"},
+		}),
+	}
+
+	body := hclwrite.NewEmptyFile().Body()
+	policy := &models.EncryptionPolicy{KmsKeyRef: models.Reference{Name: "${ref:custom.search_key_arn}"}}
+	if err := g.generateEncryptionPolicy(body, "product_docs", "product-docs", policy); err != nil {
+		t.Fatalf("generateEncryptionPolicy: %v", err)
+	}
+
+	policies := findBlocks(parseBody(t, body), "resource", "aws_opensearchserverless_security_policy")
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 security policy, got %d", len(policies))
+	}
+	call, ok := policies[0].Body.Attributes["policy"].Expr.(*hclsyntax.FunctionCallExpr)
+	if !ok || call.Name != "jsonencode" {
+		t.Fatalf("policy is not a jsonencode() call: %#v", policies[0].Body.Attributes["policy"].Expr)
+	}
+
+	// Evaluate the document with the key reference bound, as Terraform would at apply time
+	context := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"aws_kms_key": cty.ObjectVal(map[string]cty.Value{
+				"search": cty.ObjectVal(map[string]cty.Value{"arn": cty.StringVal("arn:aws:kms:us-east-1:123456789012:key/search")}),
+			}),
+		},
+		Functions: map[string]function.Function{"jsonencode": stdlib.JSONEncodeFunc},
+	}
+	document, diags := call.Value(context)
+	if diags.HasErrors() {
+		t.Fatalf("policy doesn't evaluate: %s", diags.Error())
+	}
+	want := `{"AWSOwnedKey":false,"KmsKeyId":"arn:aws:kms:us-east-1:123456789012:key/search","Rules":[{"Resource":["collection/product-docs"],"ResourceType":"collection"}]}`
+	if document.AsString() != want {
+		t.Errorf("policy = %s, want %s", document.AsString(), want)
+	}
+}
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+)
+
+// Output layouts deciding how the generated configuration is split across files
+const (
+	OutputLayoutSingle      = "single"       // everything in main.tf
+	OutputLayoutPerKind     = "per-kind"     // agents.tf, lambdas.tf, ...
+	OutputLayoutPerResource = "per-resource" // <kind>_<name>.tf
+)
+
+// generatedFileHeader marks files split off main.tf, so they can be removed when the layout changes
+const generatedFileHeader = "# Code generated by bedrock-forge. DO NOT EDIT."
+
+// kindFileNames name the files of each kind: plural for per-kind, singular as the per-resource prefix
+var kindFileNames = map[models.ResourceKind]struct{ plural, singular string }{
+	models.AgentKind:                         {"agents", "agent"},
+	models.LambdaKind:                        {"lambdas", "lambda"},
+	models.LambdaLayerKind:                   {"lambda_layers", "lambda_layer"},
+	models.ActionGroupKind:                   {"action_groups", "action_group"},
+	models.KnowledgeBaseKind:                 {"knowledge_bases", "knowledge_base"},
+	models.GuardrailKind:                     {"guardrails", "guardrail"},
+	models.PromptKind:                        {"prompts", "prompt"},
+	models.IAMRoleKind:                       {"iam_roles", "iam_role"},
+	models.OpenSearchServerlessKind:          {"opensearch_serverless", "opensearch_serverless"},
+	models.AgentKnowledgeBaseAssociationKind: {"agent_knowledge_base_associations", "agent_knowledge_base_association"},
+	models.CustomResourcesKind:               {"custom_resources", "custom_resources"},
+}
+
+// validateOutputLayout checks the configured output layout
+func (g *HCLGenerator) validateOutputLayout() error {
+	switch g.config.OutputLayout {
+	case "", OutputLayoutSingle:
+		return nil
+	case OutputLayoutPerKind, OutputLayoutPerResource:
+		if g.config.Output != nil {
+			return fmt.Errorf("output layout '%s' writes multiple files and can't be streamed, use '%s'", g.config.OutputLayout, OutputLayoutSingle)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output layout '%s', must be one of: %s, %s, %s", g.config.OutputLayout, OutputLayoutSingle, OutputLayoutPerKind, OutputLayoutPerResource)
+	}
+}
+
+// writeConfiguration writes the generated configuration in the configured layout, or main.tf to the
+// configured output stream. Split layouts keep the terraform and provider blocks in main.tf, variables
+// in variables.tf, outputs in outputs.tf, moved blocks in moved.tf and import blocks in imports.tf;
+// all files form one module, so references between them still resolve.
+func (g *HCLGenerator) writeConfiguration(mainFile *hclwrite.File) error {
+	mainFileName := g.outputFileName("main.tf")
+
+	if g.config.Output != nil {
+		content, err := g.encodeConfiguration(mainFile.Bytes())
+		if err != nil {
+			return err
+		}
+		if _, err := g.config.Output.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", mainFileName, err)
+		}
+		return nil
+	}
+
+	g.warnAboutOtherSyntaxMainFile()
+
+	if g.config.OutputLayout == "" || g.config.OutputLayout == OutputLayoutSingle {
+		outputPath := filepath.Join(g.config.OutputDir, mainFileName)
+		if err := g.writeHCLFile(outputPath, mainFile); err != nil {
+			return fmt.Errorf("failed to write %s: %w", mainFileName, err)
+		}
+		g.logger.WithField("output", outputPath).Infof("Generated %s successfully", mainFileName)
+		return nil
+	}
+
+	// Map each resource's blocks back to the resource that generated them
+	blockOwners := make(map[*hclwrite.Block]string)
+	for key, blocks := range g.resourceBlocks {
+		kind, name, _ := strings.Cut(key, "/")
+		for _, block := range blocks {
+			blockOwners[block] = g.resourceFileName(models.ResourceKind(kind), name)
+		}
+	}
+
+	files := map[string]*hclwrite.File{"main.tf": hclwrite.NewEmptyFile()}
+	fileOrder := []string{"main.tf"}
+	redirects := make(map[string]string)
+
+	for _, block := range mainFile.Body().Blocks() {
+		fileName, owned := blockOwners[block]
+		if !owned {
+			switch block.Type() {
+			case "variable":
+				fileName = "variables.tf"
+			case "output":
+				fileName = "outputs.tf"
+			case "moved":
+				fileName = "moved.tf"
+			case "import":
+				fileName = "imports.tf"
+			default:
+				fileName = "main.tf"
+			}
+		}
+
+		if redirect, ok := redirects[fileName]; ok {
+			fileName = redirect
+		}
+
+		file, exists := files[fileName]
+		if !exists {
+			// An existing file without the generated header was copied from custom resources
+			if generated, err := isGeneratedFile(filepath.Join(g.config.OutputDir, g.outputFileName(fileName))); err == nil && !generated {
+				g.logger.WithField("file", fileName).Warn("Output file already exists from custom resources, writing its blocks to main.tf")
+				redirects[fileName] = "main.tf"
+				file = files["main.tf"]
+			} else {
+				file = hclwrite.NewEmptyFile()
+				file.Body().AppendUnstructuredTokens(hclwrite.Tokens{
+					{Type: hclsyntax.TokenComment, Bytes: []byte(generatedFileHeader + "\n")},
+					{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+				})
+				files[fileName] = file
+				fileOrder = append(fileOrder, fileName)
+			}
+		}
+
+		file.Body().AppendBlock(block)
+		file.Body().AppendNewline()
+	}
+
+	for _, fileName := range fileOrder {
+		outputPath := filepath.Join(g.config.OutputDir, g.outputFileName(fileName))
+		if err := g.writeHCLFile(outputPath, files[fileName]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fileName, err)
+		}
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"output_dir": g.config.OutputDir,
+		"layout":     g.config.OutputLayout,
+		"files":      len(fileOrder),
+	}).Info("Generated Terraform files successfully")
+	return nil
+}
+
+// resourceFileName returns the file a resource's blocks are written to in a split layout
+func (g *HCLGenerator) resourceFileName(kind models.ResourceKind, name string) string {
+	names, exists := kindFileNames[kind]
+	if !exists {
+		names.plural = strings.ToLower(string(kind))
+		names.singular = names.plural
+	}
+
+	if g.config.OutputLayout == OutputLayoutPerResource {
+		return fmt.Sprintf("%s_%s.tf", names.singular, g.sanitizeResourceName(name))
+	}
+	return names.plural + ".tf"
+}
+
+// warnAboutOtherSyntaxMainFile warns when main.tf from a run in the other output syntax is still in the
+// output directory, since Terraform would load both copies of every resource
+func (g *HCLGenerator) warnAboutOtherSyntaxMainFile() {
+	other := "main.tf.json"
+	if g.config.OutputSyntax == OutputSyntaxJSON {
+		other = "main.tf"
+	}
+	if _, err := os.Stat(filepath.Join(g.config.OutputDir, other)); err == nil {
+		g.logger.WithField("file", other).Warn("Output directory still contains main configuration in the other output syntax, remove it to avoid duplicate resources")
+	}
+}
+
+// findGeneratedFiles returns the files split off main.tf by an earlier run
+func (g *HCLGenerator) findGeneratedFiles() (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(g.config.OutputDir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generated files: %w", err)
+	}
+	jsonPaths, err := filepath.Glob(filepath.Join(g.config.OutputDir, "*.tf.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generated files: %w", err)
+	}
+	paths = append(paths, jsonPaths...)
+
+	generatedFiles := make(map[string]bool)
+	for _, path := range paths {
+		generated, err := isGeneratedFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if generated {
+			generatedFiles[path] = true
+		}
+	}
+
+	return generatedFiles, nil
+}
+
+// removeStaleGeneratedFiles deletes the files split off main.tf by an earlier run that this run didn't
+// write, which would otherwise duplicate resources after a rename or a layout change. Files without
+// the generated header, e.g. ones custom resources copied over them, are kept.
+func (g *HCLGenerator) removeStaleGeneratedFiles() error {
+	for path := range g.staleFiles {
+		generated, err := isGeneratedFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !generated {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove stale generated file %s: %w", path, err)
+		}
+		g.logger.WithField("file", path).Debug("Removed stale generated file")
+	}
+	g.staleFiles = nil
+
+	return nil
+}
+
+// isGeneratedFile reports whether a file starts with the generated file header, or for JSON
+// configuration has it as its "//" comment
+func isGeneratedFile(path string) (bool, error) {
+	if strings.HasSuffix(path, ".json") {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var configuration map[string]interface{}
+		if json.Unmarshal(content, &configuration) != nil {
+			return false, nil
+		}
+		return configuration[jsonCommentKey] == strings.TrimPrefix(generatedFileHeader, "# "), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	return strings.TrimSpace(scanner.Text()) == generatedFileHeader, nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 
@@ -38,11 +40,7 @@ func (g *HCLGenerator) generatePromptModule(body *hclwrite.Body, resource models
 	moduleBody := moduleBlock.Body()
 
 	// Set module source
-	moduleSource := fmt.Sprintf("%s//modules/bedrock-prompt", g.config.ModuleRegistry)
-	if g.config.ModuleVersion != "" {
-		moduleSource += fmt.Sprintf("?ref=%s", g.config.ModuleVersion)
-	}
-	moduleBody.SetAttributeValue("source", cty.StringVal(moduleSource))
+	g.setModuleSource(moduleBody, "bedrock-prompt")
 
 	// Set basic attributes
 	moduleBody.SetAttributeValue("prompt_name", cty.StringVal(resource.Metadata.Name))
@@ -116,6 +114,17 @@ func (g *HCLGenerator) generatePromptModule(body *hclwrite.Body, resource models
 		moduleBody.SetAttributeValue("variants", cty.ListVal(variantsList))
 	}
 
+	// Versions
+	if len(prompt.Versions) > 0 {
+		versionsList := make([]cty.Value, 0, len(prompt.Versions))
+		for _, version := range prompt.Versions {
+			versionsList = append(versionsList, cty.ObjectVal(map[string]cty.Value{
+				"description": cty.StringVal(version.Description),
+			}))
+		}
+		moduleBody.SetAttributeValue("versions", cty.ListVal(versionsList))
+	}
+
 	// Tags
 	if len(prompt.Tags) > 0 {
 		tagValues := make(map[string]cty.Value)
@@ -148,6 +157,40 @@ func (g *HCLGenerator) generatePromptModule(body *hclwrite.Body, resource models
 	return nil
 }
 
+// promptOverrideVersionTokens returns the prompt version an override resolves to: a pinned version
+// number, "latest" for the version output of the prompt module, or DRAFT when unspecified
+func (g *HCLGenerator) promptOverrideVersionTokens(promptOverride models.PromptOverride) hclwrite.Tokens {
+	switch promptOverride.Version {
+	case "":
+		return hclwrite.TokensForValue(cty.StringVal("DRAFT"))
+	case "latest":
+		return hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: g.sanitizeResourceName(promptOverride.Prompt.String())},
+			hcl.TraverseAttr{Name: "version"},
+		})
+	default:
+		return hclwrite.TokensForValue(cty.StringVal(promptOverride.Version))
+	}
+}
+
+// promptOverrideTemplateTokens returns the template of the prompt version and variant an override pins,
+// from the version_templates output of the prompt module, keyed by version and then by variant
+func (g *HCLGenerator) promptOverrideTemplateTokens(promptOverride models.PromptOverride, variant string) hclwrite.Tokens {
+	tokens := hclwrite.TokensForTraversal(hcl.Traversal{
+		hcl.TraverseRoot{Name: "module"},
+		hcl.TraverseAttr{Name: g.sanitizeResourceName(promptOverride.Prompt.String())},
+		hcl.TraverseAttr{Name: "version_templates"},
+	})
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")})
+	tokens = append(tokens, g.promptOverrideVersionTokens(promptOverride)...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")})
+	tokens = append(tokens, hclwrite.TokensForValue(cty.StringVal(variant))...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+	return tokens
+}
+
 // generateTemplateConfiguration generates template configuration based on type
 func (g *HCLGenerator) generateTemplateConfiguration(templateConfig *models.TemplateConfiguration, templateType string) (cty.Value, error) {
 	templateValues := make(map[string]cty.Value)
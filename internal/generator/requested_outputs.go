@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"bedrock-forge/internal/models"
+)
+
+// outputNamePattern matches attribute names that can be traversed and used in an output label
+var outputNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// nativeResourceTypes maps natively generated kinds to the resource type whose attributes are surfaced
+var nativeResourceTypes = map[models.ResourceKind]string{
+	models.AgentKind:                         "aws_bedrockagent_agent",
+	models.LambdaKind:                        "aws_lambda_function",
+	models.LambdaLayerKind:                   "aws_lambda_layer_version",
+	models.ActionGroupKind:                   "aws_bedrockagent_agent_action_group",
+	models.KnowledgeBaseKind:                 "aws_bedrockagent_knowledge_base",
+	models.OpenSearchServerlessKind:          "aws_opensearchserverless_collection",
+	models.AgentKnowledgeBaseAssociationKind: "aws_bedrockagent_agent_knowledge_base_association",
+}
+
+// addRequestedOutputs surfaces the module outputs or resource attributes listed in metadata.outputs
+// as root outputs named <resource>_<output>
+func (g *HCLGenerator) addRequestedOutputs(body *hclwrite.Body) error {
+	existing := make(map[string]bool)
+	for _, block := range body.Blocks() {
+		if block.Type() == "output" && len(block.Labels()) > 0 {
+			existing[block.Labels()[0]] = true
+		}
+	}
+	for name := range g.customOutputs {
+		existing[name] = true
+	}
+
+	kinds := make([]string, 0)
+	for kind := range g.kindGenerators() {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		for _, resource := range g.registry.GetResourcesByType(models.ResourceKind(kind)) {
+			if len(resource.Metadata.Outputs) == 0 {
+				continue
+			}
+
+			root, err := g.outputRoot(resource.Kind)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", resource.Kind, resource.Metadata.Name, err)
+			}
+			resourceName := g.sanitizeResourceName(resource.Metadata.Name)
+
+			for _, output := range resource.Metadata.Outputs {
+				if !outputNamePattern.MatchString(output) {
+					return fmt.Errorf("%s %s: invalid output name '%s'", resource.Kind, resource.Metadata.Name, output)
+				}
+
+				outputName := fmt.Sprintf("%s_%s", resourceName, output)
+				if existing[outputName] {
+					return fmt.Errorf("%s %s: output '%s' is already declared", resource.Kind, resource.Metadata.Name, outputName)
+				}
+				existing[outputName] = true
+
+				outputBlock := body.AppendNewBlock("output", []string{outputName})
+				outputBody := outputBlock.Body()
+				outputBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("%s of the %s %s", output, resource.Metadata.Name, resource.Kind)))
+				outputBody.SetAttributeTraversal("value", hcl.Traversal{
+					hcl.TraverseRoot{Name: root},
+					hcl.TraverseAttr{Name: resourceName},
+					hcl.TraverseAttr{Name: output},
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// outputRoot returns the root of the traversal for a kind's outputs: "module" or the native resource type
+func (g *HCLGenerator) outputRoot(kind models.ResourceKind) (string, error) {
+	if kind == models.CustomResourcesKind {
+		return "", fmt.Errorf("metadata.outputs is not supported, declare outputs in the custom Terraform files instead")
+	}
+	if g.generationModeFor(kind) == GenerationModeModule {
+		return "module", nil
+	}
+	if resourceType, exists := nativeResourceTypes[kind]; exists {
+		return resourceType, nil
+	}
+	return "", fmt.Errorf("metadata.outputs is not supported for %s resources", kind)
+}
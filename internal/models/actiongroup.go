@@ -1,5 +1,19 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+)
+
+// Bedrock service limits on action groups
+const (
+	MaxActionGroupsPerAgent = 20     // Enabled action groups per agent
+	MaxAPISchemaPayloadSize = 100000 // Bytes of an inline API schema payload
+
+	// apiSchemaPayloadWarningSize is the payload size from which schemas are reported as close to the limit
+	apiSchemaPayloadWarningSize = MaxAPISchemaPayloadSize * 8 / 10
+)
+
 type ActionGroup struct {
 	Kind     ResourceKind    `yaml:"kind"`
 	Metadata Metadata        `yaml:"metadata"`
@@ -29,14 +43,49 @@ type ActionGroupExecutor struct {
 	CustomControl string    `yaml:"customControl,omitempty"`
 }
 
+// lambdaFunctionArnPattern matches Lambda function ARNs, optionally qualified with a version or alias
+var lambdaFunctionArnPattern = regexp.MustCompile(`^arn:aws[a-z-]*:lambda:[a-z0-9-]+:\d{12}:function:[A-Za-z0-9_-]{1,64}(:(\$LATEST|[A-Za-z0-9_-]{1,128}))?$`)
+
+// IsLambdaFunctionArn reports whether value is a Lambda function ARN
+func IsLambdaFunctionArn(value string) bool {
+	return lambdaFunctionArnPattern.MatchString(value)
+}
+
 type APISchema struct {
 	S3      *S3APISchema `yaml:"s3,omitempty"`
 	Payload string       `yaml:"payload,omitempty"`
+
+	// Schema fragments, relative to the ActionGroup directory, merged into the uploaded document.
+	// Glob patterns are expanded in sorted order.
+	SchemaFiles []string `yaml:"schemaFiles,omitempty"`
 }
 
 type S3APISchema struct {
 	S3BucketName string `yaml:"s3BucketName"`
 	S3ObjectKey  string `yaml:"s3ObjectKey"`
+	Managed      bool   `yaml:"managed,omitempty"` // Published outside bedrock-forge, used verbatim
+}
+
+// IsManaged reports whether the schema is an existing S3 object that must not be extracted or repackaged
+func (s *APISchema) IsManaged() bool {
+	return s != nil && s.S3 != nil && s.S3.Managed
+}
+
+// ValidatePayloadSize checks an inline payload against the Bedrock size limit. It returns a warning when
+// the payload is within 20% of the limit and an error when it exceeds it.
+func (s *APISchema) ValidatePayloadSize() (warning string, err error) {
+	if s == nil {
+		return "", nil
+	}
+
+	size := len(s.Payload)
+	switch {
+	case size > MaxAPISchemaPayloadSize:
+		return "", fmt.Errorf("inline API schema payload is %d bytes, which exceeds the Bedrock limit of %d bytes; publish the schema to S3 instead", size, MaxAPISchemaPayloadSize)
+	case size >= apiSchemaPayloadWarningSize:
+		return fmt.Sprintf("inline API schema payload is %d bytes, close to the Bedrock limit of %d bytes", size, MaxAPISchemaPayloadSize), nil
+	}
+	return "", nil
 }
 
 type FunctionSchema struct {
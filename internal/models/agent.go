@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
 type Agent struct {
 	Kind     ResourceKind `yaml:"kind"`
 	Metadata Metadata     `yaml:"metadata"`
@@ -7,17 +14,22 @@ type Agent struct {
 }
 
 type AgentSpec struct {
-	FoundationModel       string               `yaml:"foundationModel"`
-	Instruction           string               `yaml:"instruction"`
-	Description           string               `yaml:"description,omitempty"`
-	IdleSessionTTL        int                  `yaml:"idleSessionTtl,omitempty"`
-	CustomerEncryptionKey string               `yaml:"customerEncryptionKey,omitempty"`
-	Tags                  map[string]string    `yaml:"tags,omitempty"`
-	Guardrail             *GuardrailConfig     `yaml:"guardrail,omitempty"`
-	ActionGroups          []InlineActionGroup  `yaml:"actionGroups,omitempty"`
-	PromptOverrides       []PromptOverride     `yaml:"promptOverrides,omitempty"`
-	MemoryConfiguration   *MemoryConfiguration `yaml:"memoryConfiguration,omitempty"`
-	Aliases               []AgentAlias         `yaml:"aliases,omitempty"`
+	FoundationModel        string               `yaml:"foundationModel,omitempty"`
+	FoundationModelProfile string               `yaml:"foundationModelProfile,omitempty"` // Inference profile ID or ARN, instead of FoundationModel
+	ProvisionedThroughput  string               `yaml:"provisionedThroughput,omitempty"`  // Provisioned model throughput ARN or ${ref:custom.<outputName>}, instead of FoundationModel
+	Instruction            string               `yaml:"instruction"`
+	Description            string               `yaml:"description,omitempty"`
+	IdleSessionTTL         int                  `yaml:"idleSessionTtl,omitempty"`
+	CustomerEncryptionKey  string               `yaml:"customerEncryptionKey,omitempty"`
+	Tags                   map[string]string    `yaml:"tags,omitempty"`
+	Guardrail              *GuardrailConfig     `yaml:"guardrail,omitempty"`
+	ActionGroups           []InlineActionGroup  `yaml:"actionGroups,omitempty"`
+	PromptOverrides        []PromptOverride     `yaml:"promptOverrides,omitempty"`
+	MemoryConfiguration    *MemoryConfiguration `yaml:"memoryConfiguration,omitempty"`
+	Aliases                []AgentAlias         `yaml:"aliases,omitempty"`
+	Logging                *AgentLoggingConfig  `yaml:"logging,omitempty"`
+	AgentCollaboration     *AgentCollaboration  `yaml:"agentCollaboration,omitempty"`
+	Versioning             *AgentVersioning     `yaml:"versioning,omitempty"`
 
 	// IAM Role configuration - allows users to specify existing roles or customize auto-generated ones
 	IAMRole *IAMRoleConfig `yaml:"iamRole,omitempty"`
@@ -28,6 +40,77 @@ type AgentSpec struct {
 	Timeouts               *AgentTimeouts `yaml:"timeouts,omitempty"`
 }
 
+// EffectiveFoundationModel returns the value used for the agent's foundation_model
+func (s AgentSpec) EffectiveFoundationModel() string {
+	if s.ProvisionedThroughput != "" {
+		return s.ProvisionedThroughput
+	}
+	if s.FoundationModelProfile != "" {
+		return s.FoundationModelProfile
+	}
+	return s.FoundationModel
+}
+
+// InferenceProfileArn returns the ARN of the inference profile the agent invokes, or "" when it
+// invokes a foundation model directly. Profile IDs are matched in any region and account.
+func (s AgentSpec) InferenceProfileArn() string {
+	model := s.EffectiveFoundationModel()
+	if isInferenceProfileArn(model) {
+		return model
+	}
+	if s.FoundationModelProfile != "" && !strings.HasPrefix(model, "arn:") {
+		return fmt.Sprintf("arn:aws:bedrock:*:*:inference-profile/%s", model)
+	}
+	return ""
+}
+
+// ValidateFoundationModel checks that exactly one of foundationModel, foundationModelProfile and
+// provisionedThroughput is set
+func (s AgentSpec) ValidateFoundationModel() error {
+	set := 0
+	for _, value := range []string{s.FoundationModel, s.FoundationModelProfile, s.ProvisionedThroughput} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("agent foundationModel, foundationModelProfile and provisionedThroughput are mutually exclusive")
+	}
+	if set == 0 {
+		return fmt.Errorf("agent foundationModel, foundationModelProfile or provisionedThroughput is required")
+	}
+	if strings.HasPrefix(s.FoundationModelProfile, "arn:") && !isInferenceProfileArn(s.FoundationModelProfile) {
+		return fmt.Errorf("agent foundationModelProfile '%s' is not an inference profile ARN", s.FoundationModelProfile)
+	}
+	if s.ProvisionedThroughput != "" && !IsProvisionedModelArn(s.ProvisionedThroughput) && !strings.HasPrefix(s.ProvisionedThroughput, "${ref:custom.") {
+		return fmt.Errorf("agent provisionedThroughput '%s' must be a provisioned model ARN or a ${ref:custom.<outputName>} reference", s.ProvisionedThroughput)
+	}
+	return nil
+}
+
+const (
+	// DefaultMaxInstructionLength is the longest agent instruction Bedrock accepts for most models
+	DefaultMaxInstructionLength = 4000
+
+	// MinInstructionLength is the shortest agent instruction Bedrock accepts
+	MinInstructionLength = 40
+)
+
+// InstructionLength returns the length of the instruction in characters, as Bedrock counts it
+func (s AgentSpec) InstructionLength() int {
+	return utf8.RuneCountInString(s.Instruction)
+}
+
+// IsProvisionedModelArn reports whether value is the ARN of a provisioned model throughput
+func IsProvisionedModelArn(value string) bool {
+	return strings.HasPrefix(value, "arn:") && strings.Contains(value, ":provisioned-model/")
+}
+
+func isInferenceProfileArn(value string) bool {
+	return strings.HasPrefix(value, "arn:") &&
+		(strings.Contains(value, ":inference-profile/") || strings.Contains(value, ":application-inference-profile/"))
+}
+
 type GuardrailConfig struct {
 	Name    Reference `yaml:"name"`
 	Version string    `yaml:"version,omitempty"`
@@ -52,17 +135,160 @@ type PromptOverride struct {
 	Prompt        Reference `yaml:"prompt,omitempty"`    // Reference to Prompt resource
 	PromptVariant string    `yaml:"promptVariant,omitempty"`
 	Variant       string    `yaml:"variant,omitempty"`
+	Version       string    `yaml:"version,omitempty"` // Prompt version number or "latest", defaults to DRAFT
 }
 
+// VariantName returns the prompt variant the override pins, from variant or the older promptVariant
+func (o PromptOverride) VariantName() string {
+	if o.Variant != "" {
+		return o.Variant
+	}
+	return o.PromptVariant
+}
+
+// MaxMemoryStorageDays is the longest Bedrock keeps agent memory
+const MaxMemoryStorageDays = 365
+
 type MemoryConfiguration struct {
-	EnabledMemoryTypes []string `yaml:"enabledMemoryTypes"`
-	StorageDays        int      `yaml:"storageDays,omitempty"`
+	EnabledMemoryTypes          []string                     `yaml:"enabledMemoryTypes"`
+	StorageDays                 int                          `yaml:"storageDays,omitempty"` // 0-365
+	SessionSummaryConfiguration *SessionSummaryConfiguration `yaml:"sessionSummaryConfiguration,omitempty"`
+}
+
+// SessionSummaryConfiguration controls how many summarized sessions are kept in memory
+type SessionSummaryConfiguration struct {
+	MaxRecentSessions int `yaml:"maxRecentSessions"`
+}
+
+// Validate checks memory types and storage limits against what Bedrock accepts
+func (m MemoryConfiguration) Validate() error {
+	if len(m.EnabledMemoryTypes) == 0 {
+		return fmt.Errorf("memory configuration requires enabledMemoryTypes")
+	}
+	for _, memoryType := range m.EnabledMemoryTypes {
+		if memoryType != "SESSION_SUMMARY" {
+			return fmt.Errorf("unsupported memory type '%s', must be SESSION_SUMMARY", memoryType)
+		}
+	}
+
+	if m.StorageDays < 0 || m.StorageDays > MaxMemoryStorageDays {
+		return fmt.Errorf("memory storageDays must be between 0 and %d, got %d", MaxMemoryStorageDays, m.StorageDays)
+	}
+
+	if m.SessionSummaryConfiguration != nil && m.SessionSummaryConfiguration.MaxRecentSessions < 1 {
+		return fmt.Errorf("memory maxRecentSessions must be at least 1, got %d", m.SessionSummaryConfiguration.MaxRecentSessions)
+	}
+
+	return nil
 }
 
 type AgentAlias struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description,omitempty"`
-	Tags        map[string]string `yaml:"tags,omitempty"`
+	Name                 string                      `yaml:"name"`
+	Description          string                      `yaml:"description,omitempty"`
+	RoutingConfiguration []AliasRoutingConfiguration `yaml:"routingConfiguration,omitempty"`
+	Guardrail            *GuardrailConfig            `yaml:"guardrail,omitempty"` // Guardrail callers of this alias apply, in place of the agent's
+	Tags                 map[string]string           `yaml:"tags,omitempty"`
+}
+
+// AgentVersioning controls when changes prepare the agent again and which version aliases route to
+type AgentVersioning struct {
+	PrepareOnActionGroupChange *bool    `yaml:"prepareOnActionGroupChange,omitempty"` // Default: true
+	PrepareOnGuardrailChange   *bool    `yaml:"prepareOnGuardrailChange,omitempty"`   // Default: true
+	AliasVersion               string   `yaml:"aliasVersion,omitempty"`               // Version aliases without routingConfiguration route to
+	ProductionAliases          []string `yaml:"productionAliases,omitempty"`          // Aliases that must not route to DRAFT, default: prod and production
+}
+
+var (
+	agentVersionPattern    = regexp.MustCompile(`^[1-9][0-9]*$`)
+	productionAliasPattern = regexp.MustCompile(`(?i)^(prod|production)$`)
+)
+
+// Validate checks that the alias version is a numbered version
+func (v *AgentVersioning) Validate() error {
+	if v == nil || v.AliasVersion == "" {
+		return nil
+	}
+	if !agentVersionPattern.MatchString(v.AliasVersion) {
+		return fmt.Errorf("versioning aliasVersion must be a numbered agent version such as \"1\", got %q", v.AliasVersion)
+	}
+	return nil
+}
+
+// ValidatePreparation rejects prepareOnGuardrailChange: false on an agent that is still prepared.
+// The guardrail is an attribute of the agent, and Bedrock prepares the agent on every update, so
+// guardrail changes can only skip the prepare when the agent sets prepareAgent: false itself.
+func (s AgentSpec) ValidatePreparation() error {
+	if s.Versioning.PreparesOnGuardrailChange() || s.Guardrail == nil {
+		return nil
+	}
+	if s.PrepareAgent == nil || *s.PrepareAgent {
+		return fmt.Errorf("versioning prepareOnGuardrailChange: false requires prepareAgent: false, since guardrail changes update the agent and every update prepares it")
+	}
+	return nil
+}
+
+// PreparesOnActionGroupChange reports whether action group changes prepare the agent
+func (v *AgentVersioning) PreparesOnActionGroupChange() bool {
+	return v == nil || v.PrepareOnActionGroupChange == nil || *v.PrepareOnActionGroupChange
+}
+
+// PreparesOnGuardrailChange reports whether guardrail changes prepare the agent
+func (v *AgentVersioning) PreparesOnGuardrailChange() bool {
+	return v == nil || v.PrepareOnGuardrailChange == nil || *v.PrepareOnGuardrailChange
+}
+
+// IsProductionAlias reports whether an alias serves production traffic
+func (v *AgentVersioning) IsProductionAlias(name string) bool {
+	if v == nil || len(v.ProductionAliases) == 0 {
+		return productionAliasPattern.MatchString(name)
+	}
+	for _, alias := range v.ProductionAliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AliasRouting returns the versions an alias routes to: its own routingConfiguration, or the
+// versioning aliasVersion. An empty result lets Bedrock create a new version from the prepared DRAFT.
+func (s AgentSpec) AliasRouting(alias AgentAlias) []AliasRoutingConfiguration {
+	if len(alias.RoutingConfiguration) > 0 {
+		return alias.RoutingConfiguration
+	}
+	if s.Versioning != nil && s.Versioning.AliasVersion != "" {
+		return []AliasRoutingConfiguration{{AgentVersion: s.Versioning.AliasVersion}}
+	}
+	return nil
+}
+
+// AgentCollaboration makes an agent a supervisor that orchestrates collaborator agents
+type AgentCollaboration struct {
+	CollaborationType string              `yaml:"collaborationType"` // SUPERVISOR, SUPERVISOR_ROUTER or DISABLED
+	Collaborators     []AgentCollaborator `yaml:"collaborators,omitempty"`
+}
+
+// AgentCollaborator associates another agent's alias with a supervisor agent
+type AgentCollaborator struct {
+	Agent                    Reference `yaml:"agent,omitempty"`    // Reference to Agent resource
+	Alias                    string    `yaml:"alias,omitempty"`    // Alias of the referenced agent, defaults to its first alias
+	AliasArn                 string    `yaml:"aliasArn,omitempty"` // External agent alias ARN
+	Name                     string    `yaml:"name,omitempty"`     // Collaborator name, defaults to the agent name
+	CollaborationInstruction string    `yaml:"collaborationInstruction"`
+	RelayConversationHistory bool      `yaml:"relayConversationHistory,omitempty"`
+}
+
+// AliasRoutingConfiguration maps an alias to a specific agent version
+type AliasRoutingConfiguration struct {
+	AgentVersion          string `yaml:"agentVersion"`
+	ProvisionedThroughput string `yaml:"provisionedThroughput,omitempty"` // Provisioned throughput ARN
+}
+
+// AgentLoggingConfig enables CloudWatch logging of model invocations for an agent
+type AgentLoggingConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	LogGroupName  string `yaml:"logGroupName,omitempty"`  // Default: /aws/bedrock/agents/<agent-name>
+	RetentionDays int    `yaml:"retentionDays,omitempty"` // Default: never expire
 }
 
 // AgentTimeouts represents timeout configuration for agent operations
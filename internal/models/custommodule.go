@@ -23,4 +23,26 @@ type CustomResourcesSpec struct {
 
 	// Variables to pass to the Terraform configuration
 	Variables map[string]interface{} `yaml:"variables,omitempty"`
+
+	// Variables declared with an explicit type, description or validation rules
+	VariableDefinitions map[string]CustomVariable `yaml:"variableDefinitions,omitempty"`
+}
+
+// CustomVariable declares a Terraform variable for custom resources
+type CustomVariable struct {
+	// Terraform type constraint, e.g. list(string); inferred from the default if omitted
+	Type string `yaml:"type,omitempty"`
+
+	Description string      `yaml:"description,omitempty"`
+	Default     interface{} `yaml:"default,omitempty"`
+	Sensitive   bool        `yaml:"sensitive,omitempty"`
+
+	// Validation rules Terraform checks against the variable value
+	Validations []CustomVariableValidation `yaml:"validations,omitempty"`
+}
+
+// CustomVariableValidation is a validation block of a custom resources variable
+type CustomVariableValidation struct {
+	Condition    string `yaml:"condition"`    // Terraform expression, e.g. length(var.name) > 0
+	ErrorMessage string `yaml:"errorMessage"` // Shown when the condition is false
 }
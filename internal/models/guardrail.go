@@ -1,5 +1,7 @@
 package models
 
+import "fmt"
+
 type Guardrail struct {
 	Kind     ResourceKind  `yaml:"kind"`
 	Metadata Metadata      `yaml:"metadata"`
@@ -16,6 +18,89 @@ type GuardrailSpec struct {
 	Tags                             map[string]string                 `yaml:"tags,omitempty"`
 }
 
+// Guardrail policy types, as named in security policies
+const (
+	GuardrailPolicyContent              = "CONTENT"
+	GuardrailPolicySensitiveInformation = "SENSITIVE_INFORMATION"
+	GuardrailPolicyContextualGrounding  = "CONTEXTUAL_GROUNDING"
+	GuardrailPolicyTopic                = "TOPIC"
+	GuardrailPolicyWord                 = "WORD"
+)
+
+// GuardrailPolicyTypes lists every guardrail policy type
+var GuardrailPolicyTypes = []string{
+	GuardrailPolicyContent,
+	GuardrailPolicySensitiveInformation,
+	GuardrailPolicyContextualGrounding,
+	GuardrailPolicyTopic,
+	GuardrailPolicyWord,
+}
+
+// PolicyTypes returns the policy types the guardrail configures with at least one entry
+func (s GuardrailSpec) PolicyTypes() []string {
+	var types []string
+	if s.ContentPolicyConfig != nil && len(s.ContentPolicyConfig.FiltersConfig) > 0 {
+		types = append(types, GuardrailPolicyContent)
+	}
+	if s.SensitiveInformationPolicyConfig != nil && len(s.SensitiveInformationPolicyConfig.PiiEntitiesConfig) > 0 {
+		types = append(types, GuardrailPolicySensitiveInformation)
+	}
+	if s.ContextualGroundingPolicyConfig != nil && len(s.ContextualGroundingPolicyConfig.FiltersConfig) > 0 {
+		types = append(types, GuardrailPolicyContextualGrounding)
+	}
+	if s.TopicPolicyConfig != nil && len(s.TopicPolicyConfig.TopicsConfig) > 0 {
+		types = append(types, GuardrailPolicyTopic)
+	}
+	if s.WordPolicyConfig != nil && (len(s.WordPolicyConfig.WordsConfig) > 0 || len(s.WordPolicyConfig.ManagedWordListsConfig) > 0) {
+		types = append(types, GuardrailPolicyWord)
+	}
+	return types
+}
+
+// Validate checks the guardrail policies against the values Bedrock accepts and returns the first
+// problem prefixed with its field
+func (s GuardrailSpec) Validate() error {
+	if errs := s.PolicyErrors(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// PolicyErrors checks the guardrail policies against the values Bedrock accepts and returns every
+// problem with the field it was found in
+func (s GuardrailSpec) PolicyErrors() []FieldError {
+	var errs []FieldError
+	if s.ContentPolicyConfig != nil {
+		for i, filter := range s.ContentPolicyConfig.FiltersConfig {
+			if err := filter.Validate(); err != nil {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("contentPolicyConfig.filtersConfig[%d]", i), Err: err})
+			}
+		}
+	}
+	if s.SensitiveInformationPolicyConfig != nil {
+		for i, entity := range s.SensitiveInformationPolicyConfig.PiiEntitiesConfig {
+			if err := entity.Validate(); err != nil {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("sensitiveInformationPolicyConfig.piiEntitiesConfig[%d]", i), Err: err})
+			}
+		}
+	}
+	if s.ContextualGroundingPolicyConfig != nil {
+		for i, filter := range s.ContextualGroundingPolicyConfig.FiltersConfig {
+			if err := filter.Validate(); err != nil {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("contextualGroundingPolicyConfig.filtersConfig[%d]", i), Err: err})
+			}
+		}
+	}
+	if s.TopicPolicyConfig != nil {
+		for i, topic := range s.TopicPolicyConfig.TopicsConfig {
+			if err := topic.Validate(); err != nil {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("topicPolicyConfig.topicsConfig[%d]", i), Err: err})
+			}
+		}
+	}
+	return errs
+}
+
 type ContentPolicyConfig struct {
 	FiltersConfig []ContentFilter `yaml:"filtersConfig"`
 }
@@ -26,6 +111,35 @@ type ContentFilter struct {
 	OutputStrength string `yaml:"outputStrength"`
 }
 
+// Validate checks the filter type and strengths against the values Bedrock accepts
+func (f ContentFilter) Validate() error {
+	switch f.Type {
+	case "SEXUAL", "VIOLENCE", "HATE", "INSULTS", "MISCONDUCT", "PROMPT_ATTACK":
+	default:
+		return fmt.Errorf("invalid content filter type '%s', must be one of: SEXUAL, VIOLENCE, HATE, INSULTS, MISCONDUCT, PROMPT_ATTACK", f.Type)
+	}
+	if !isContentFilterStrength(f.InputStrength) {
+		return fmt.Errorf("invalid inputStrength '%s' for %s filter, must be one of: NONE, LOW, MEDIUM, HIGH", f.InputStrength, f.Type)
+	}
+	if !isContentFilterStrength(f.OutputStrength) {
+		return fmt.Errorf("invalid outputStrength '%s' for %s filter, must be one of: NONE, LOW, MEDIUM, HIGH", f.OutputStrength, f.Type)
+	}
+	// Prompt attacks are only detected in the input
+	if f.Type == "PROMPT_ATTACK" && f.OutputStrength != "NONE" {
+		return fmt.Errorf("outputStrength of PROMPT_ATTACK filter must be NONE, got '%s'", f.OutputStrength)
+	}
+	return nil
+}
+
+func isContentFilterStrength(strength string) bool {
+	switch strength {
+	case "NONE", "LOW", "MEDIUM", "HIGH":
+		return true
+	default:
+		return false
+	}
+}
+
 type SensitiveInformationPolicyConfig struct {
 	PiiEntitiesConfig []PiiEntity `yaml:"piiEntitiesConfig"`
 }
@@ -35,6 +149,16 @@ type PiiEntity struct {
 	Action string `yaml:"action"`
 }
 
+// Validate checks the PII entity action
+func (e PiiEntity) Validate() error {
+	switch e.Action {
+	case "BLOCK", "ANONYMIZE":
+		return nil
+	default:
+		return fmt.Errorf("invalid action '%s' for %s entity, must be one of: BLOCK, ANONYMIZE", e.Action, e.Type)
+	}
+}
+
 type ContextualGroundingPolicyConfig struct {
 	FiltersConfig []ContextualGroundingFilter `yaml:"filtersConfig"`
 }
@@ -44,6 +168,19 @@ type ContextualGroundingFilter struct {
 	Threshold float64 `yaml:"threshold"`
 }
 
+// Validate checks the filter type and that the threshold is between 0 and 1
+func (f ContextualGroundingFilter) Validate() error {
+	switch f.Type {
+	case "GROUNDING", "RELEVANCE":
+	default:
+		return fmt.Errorf("invalid contextual grounding filter type '%s', must be one of: GROUNDING, RELEVANCE", f.Type)
+	}
+	if f.Threshold < 0 || f.Threshold > 1 {
+		return fmt.Errorf("threshold of %s filter must be between 0.0 and 1.0, got %g", f.Type, f.Threshold)
+	}
+	return nil
+}
+
 type TopicPolicyConfig struct {
 	TopicsConfig []Topic `yaml:"topicsConfig"`
 }
@@ -55,9 +192,19 @@ type Topic struct {
 	Type       string   `yaml:"type"`
 }
 
+// Validate checks the topic type
+func (t Topic) Validate() error {
+	if t.Type != "DENY" {
+		return fmt.Errorf("invalid type '%s' for topic %s, must be DENY", t.Type, t.Name)
+	}
+	return nil
+}
+
 type WordPolicyConfig struct {
 	WordsConfig            []Word            `yaml:"wordsConfig,omitempty"`
 	ManagedWordListsConfig []ManagedWordList `yaml:"managedWordListsConfig,omitempty"`
+	// WordsFile is a newline-delimited or CSV word list, relative to the YAML file
+	WordsFile string `yaml:"wordsFile,omitempty"`
 }
 
 type Word struct {
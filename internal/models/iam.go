@@ -1,6 +1,13 @@
 package models
 
-// IAMRoleConfig provides flexible IAM role management for agents
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IAMRoleConfig provides flexible IAM role management for agents and knowledge bases. The scope and
+// assume role condition options only apply to agents.
 type IAMRoleConfig struct {
 	// For auto-generated roles (default: true)
 	AutoCreate *bool `yaml:"autoCreate,omitempty"`
@@ -13,6 +20,68 @@ type IAMRoleConfig struct {
 
 	// Additional policies to attach to auto-generated roles
 	AdditionalPolicies []IAMPolicyReference `yaml:"additionalPolicies,omitempty"`
+
+	// Scope the auto-generated role to the agent's foundation model instead of all models
+	ScopeModelAccess bool `yaml:"scopeModelAccess,omitempty"`
+
+	// Scope the auto-generated role to the knowledge bases associated with the agent instead of all
+	ScopeKnowledgeBaseAccess bool `yaml:"scopeKnowledgeBaseAccess,omitempty"`
+
+	// Conditions on the trust policy of the auto-generated role, against the confused deputy problem
+	AssumeRoleConditions *AssumeRoleConditions `yaml:"assumeRoleConditions,omitempty"`
+}
+
+// AssumeRoleConditions limits the requests Bedrock may assume an auto-generated role for
+type AssumeRoleConditions struct {
+	SourceAccount string `yaml:"sourceAccount,omitempty"` // aws:SourceAccount, default: the configured deployment account
+	SourceArn     string `yaml:"sourceArn,omitempty"`     // aws:SourceArn, matched with ArnLike so wildcards are allowed
+}
+
+var sourceAccountPattern = regexp.MustCompile(`^\d{12}$`)
+
+// Validate checks the source account and ARN formats
+func (c *AssumeRoleConditions) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.SourceAccount != "" && !sourceAccountPattern.MatchString(c.SourceAccount) {
+		return fmt.Errorf("assumeRoleConditions sourceAccount '%s' must be a 12-digit AWS account ID", c.SourceAccount)
+	}
+	if c.SourceArn != "" && !strings.HasPrefix(c.SourceArn, "arn:") {
+		return fmt.Errorf("assumeRoleConditions sourceArn '%s' must be an ARN", c.SourceArn)
+	}
+	return nil
+}
+
+// CreatesRole reports whether an execution role is auto-generated for this configuration, which is
+// the default when no existing role is given
+func (c *IAMRoleConfig) CreatesRole() bool {
+	if c == nil {
+		return true
+	}
+	if c.RoleArn != "" || !c.RoleName.IsEmpty() {
+		return false
+	}
+	return c.AutoCreate == nil || *c.AutoCreate
+}
+
+// RoleType tag values of auto-generated IAM roles
+const (
+	RoleTypeAgentExecution       = "AgentExecution"
+	RoleTypeLambdaExecution      = "LambdaExecution"
+	RoleTypeKnowledgeBaseService = "KnowledgeBaseService"
+)
+
+// GeneratedRoleTags returns the tags of an auto-generated IAM role: the tags of the resource it is
+// generated for, plus its RoleType and ManagedBy
+func GeneratedRoleTags(parentTags map[string]string, roleType string) map[string]string {
+	tags := make(map[string]string, len(parentTags)+2)
+	for key, value := range parentTags {
+		tags[key] = value
+	}
+	tags["RoleType"] = roleType
+	tags["ManagedBy"] = "bedrock-forge"
+	return tags
 }
 
 type IAMRole struct {
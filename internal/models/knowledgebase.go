@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 type KnowledgeBase struct {
 	Kind     ResourceKind      `yaml:"kind"`
 	Metadata Metadata          `yaml:"metadata"`
@@ -12,6 +17,33 @@ type KnowledgeBaseSpec struct {
 	StorageConfiguration       *StorageConfiguration       `yaml:"storageConfiguration,omitempty"`
 	DataSources                []DataSource                `yaml:"dataSources,omitempty"`
 	Tags                       map[string]string           `yaml:"tags,omitempty"`
+
+	// Execution role: auto-generated with access to the embedding model, collection and data source
+	// buckets by default, or an existing role ARN or IAMRole resource
+	IAMRole *IAMRoleConfig `yaml:"iamRole,omitempty"`
+}
+
+// KnowledgeBaseRoleName returns the name of the service role generated for a knowledge base
+func KnowledgeBaseRoleName(kbName string) string {
+	return fmt.Sprintf("%s-kb-role", kbName)
+}
+
+// ExecutionRoleName returns the name of the role the knowledge base runs as, or "" when it is only
+// known at apply time, e.g. a role ARN that references another resource
+func (s KnowledgeBaseSpec) ExecutionRoleName(kbName string) string {
+	if s.IAMRole.CreatesRole() {
+		return KnowledgeBaseRoleName(kbName)
+	}
+	if !s.IAMRole.RoleName.IsEmpty() {
+		return s.IAMRole.RoleName.String()
+	}
+
+	// arn:<partition>:iam::<account>:role/<path/><name>
+	parts := strings.SplitN(s.IAMRole.RoleArn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || !strings.HasPrefix(parts[5], "role/") {
+		return ""
+	}
+	return parts[5][strings.LastIndex(parts[5], "/")+1:]
 }
 
 type KnowledgeBaseConfiguration struct {
@@ -56,6 +88,9 @@ type DataSource struct {
 	Name                         string                        `yaml:"name"`
 	Type                         string                        `yaml:"type"`
 	S3Configuration              *S3Configuration              `yaml:"s3Configuration,omitempty"`
+	ConfluenceConfiguration      *ConfluenceConfiguration      `yaml:"confluenceConfiguration,omitempty"`
+	SharePointConfiguration      *SharePointConfiguration      `yaml:"sharePointConfiguration,omitempty"`
+	WebConfiguration             *WebConfiguration             `yaml:"webConfiguration,omitempty"`
 	ChunkingConfiguration        *ChunkingConfiguration        `yaml:"chunkingConfiguration,omitempty"`
 	VectorIngestionConfiguration *VectorIngestionConfiguration `yaml:"vectorIngestionConfiguration,omitempty"`
 	CustomTransformation         *CustomTransformation         `yaml:"customTransformation,omitempty"`
@@ -67,10 +102,128 @@ type S3Configuration struct {
 	ExclusionPrefixes []string `yaml:"exclusionPrefixes,omitempty"`
 }
 
+// ConfluenceConfiguration crawls a Confluence instance using credentials from Secrets Manager
+type ConfluenceConfiguration struct {
+	HostURL              string        `yaml:"hostUrl"`
+	HostType             string        `yaml:"hostType,omitempty"` // SAAS (default)
+	AuthType             string        `yaml:"authType"`           // BASIC or OAUTH2_CLIENT_CREDENTIALS
+	CredentialsSecretArn string        `yaml:"credentialsSecretArn"`
+	Filters              []CrawlFilter `yaml:"filters,omitempty"`
+}
+
+// SharePointConfiguration crawls SharePoint Online sites using credentials from Secrets Manager
+type SharePointConfiguration struct {
+	Domain               string        `yaml:"domain"`
+	SiteURLs             []string      `yaml:"siteUrls"`
+	TenantID             string        `yaml:"tenantId"`
+	HostType             string        `yaml:"hostType,omitempty"` // ONLINE (default)
+	AuthType             string        `yaml:"authType"`           // OAUTH2_CLIENT_CREDENTIALS or OAUTH2_SHAREPOINT_APP_ONLY_CLIENT_CREDENTIALS
+	CredentialsSecretArn string        `yaml:"credentialsSecretArn"`
+	Filters              []CrawlFilter `yaml:"filters,omitempty"`
+}
+
+// CrawlFilter limits which objects of a type (e.g. Page, Attachment) are crawled, by regex
+type CrawlFilter struct {
+	ObjectType       string   `yaml:"objectType"`
+	InclusionFilters []string `yaml:"inclusionFilters,omitempty"`
+	ExclusionFilters []string `yaml:"exclusionFilters,omitempty"`
+}
+
+// WebConfiguration crawls public web pages starting from seed URLs
+type WebConfiguration struct {
+	SeedURLs         []string `yaml:"seedUrls"`
+	Scope            string   `yaml:"scope,omitempty"`     // HOST_ONLY or SUBDOMAINS; defaults to the seed URL's host and path
+	RateLimit        int      `yaml:"rateLimit,omitempty"` // Pages per minute per host
+	InclusionFilters []string `yaml:"inclusionFilters,omitempty"`
+	ExclusionFilters []string `yaml:"exclusionFilters,omitempty"`
+}
+
+// APIType returns the Bedrock data source type, accepting any casing (e.g. "Web" or "WEB")
+func (d DataSource) APIType() string {
+	switch strings.ToUpper(d.Type) {
+	case "S3", "CONFLUENCE", "SHAREPOINT", "WEB":
+		return strings.ToUpper(d.Type)
+	default:
+		return d.Type
+	}
+}
+
+// CredentialsSecretArn returns the Secrets Manager secret the data source authenticates with, if any
+func (d DataSource) CredentialsSecretArn() string {
+	switch d.APIType() {
+	case "CONFLUENCE":
+		if d.ConfluenceConfiguration != nil {
+			return d.ConfluenceConfiguration.CredentialsSecretArn
+		}
+	case "SHAREPOINT":
+		if d.SharePointConfiguration != nil {
+			return d.SharePointConfiguration.CredentialsSecretArn
+		}
+	}
+	return ""
+}
+
+// Validate checks that the configuration required by the data source type is present
+func (d DataSource) Validate() error {
+	switch d.APIType() {
+	case "CONFLUENCE":
+		config := d.ConfluenceConfiguration
+		if config == nil {
+			return fmt.Errorf("data source %s: confluenceConfiguration is required for type CONFLUENCE", d.Name)
+		}
+		if config.HostURL == "" {
+			return fmt.Errorf("data source %s: confluenceConfiguration.hostUrl is required", d.Name)
+		}
+		if config.AuthType != "BASIC" && config.AuthType != "OAUTH2_CLIENT_CREDENTIALS" {
+			return fmt.Errorf("data source %s: confluenceConfiguration.authType must be BASIC or OAUTH2_CLIENT_CREDENTIALS", d.Name)
+		}
+		if config.CredentialsSecretArn == "" {
+			return fmt.Errorf("data source %s: confluenceConfiguration.credentialsSecretArn is required", d.Name)
+		}
+		return validateCrawlFilters(d.Name, config.Filters)
+	case "SHAREPOINT":
+		config := d.SharePointConfiguration
+		if config == nil {
+			return fmt.Errorf("data source %s: sharePointConfiguration is required for type SHAREPOINT", d.Name)
+		}
+		if config.Domain == "" || len(config.SiteURLs) == 0 {
+			return fmt.Errorf("data source %s: sharePointConfiguration.domain and siteUrls are required", d.Name)
+		}
+		if config.AuthType != "OAUTH2_CLIENT_CREDENTIALS" && config.AuthType != "OAUTH2_SHAREPOINT_APP_ONLY_CLIENT_CREDENTIALS" {
+			return fmt.Errorf("data source %s: sharePointConfiguration.authType must be OAUTH2_CLIENT_CREDENTIALS or OAUTH2_SHAREPOINT_APP_ONLY_CLIENT_CREDENTIALS", d.Name)
+		}
+		if config.TenantID == "" || config.CredentialsSecretArn == "" {
+			return fmt.Errorf("data source %s: sharePointConfiguration.tenantId and credentialsSecretArn are required", d.Name)
+		}
+		return validateCrawlFilters(d.Name, config.Filters)
+	case "WEB":
+		config := d.WebConfiguration
+		if config == nil || len(config.SeedURLs) == 0 {
+			return fmt.Errorf("data source %s: webConfiguration.seedUrls is required for type WEB", d.Name)
+		}
+		switch config.Scope {
+		case "", "HOST_ONLY", "SUBDOMAINS":
+		default:
+			return fmt.Errorf("data source %s: webConfiguration.scope must be HOST_ONLY or SUBDOMAINS", d.Name)
+		}
+	}
+	return nil
+}
+
+func validateCrawlFilters(dataSourceName string, filters []CrawlFilter) error {
+	for _, filter := range filters {
+		if filter.ObjectType == "" {
+			return fmt.Errorf("data source %s: filters[].objectType is required", dataSourceName)
+		}
+	}
+	return nil
+}
+
 type ChunkingConfiguration struct {
-	ChunkingStrategy               string                          `yaml:"chunkingStrategy"`
-	FixedSizeChunkingConfiguration *FixedSizeChunkingConfiguration `yaml:"fixedSizeChunkingConfiguration,omitempty"`
-	SemanticChunkingConfiguration  *SemanticChunkingConfiguration  `yaml:"semanticChunkingConfiguration,omitempty"`
+	ChunkingStrategy                  string                             `yaml:"chunkingStrategy"`
+	FixedSizeChunkingConfiguration    *FixedSizeChunkingConfiguration    `yaml:"fixedSizeChunkingConfiguration,omitempty"`
+	HierarchicalChunkingConfiguration *HierarchicalChunkingConfiguration `yaml:"hierarchicalChunkingConfiguration,omitempty"`
+	SemanticChunkingConfiguration     *SemanticChunkingConfiguration     `yaml:"semanticChunkingConfiguration,omitempty"`
 }
 
 type FixedSizeChunkingConfiguration struct {
@@ -78,6 +231,15 @@ type FixedSizeChunkingConfiguration struct {
 	OverlapPercentage int `yaml:"overlapPercentage"`
 }
 
+type HierarchicalChunkingConfiguration struct {
+	LevelConfigurations []HierarchicalChunkingLevel `yaml:"levelConfigurations"` // Parent level, then child level
+	OverlapTokens       int                         `yaml:"overlapTokens"`
+}
+
+type HierarchicalChunkingLevel struct {
+	MaxTokens int `yaml:"maxTokens"`
+}
+
 type SemanticChunkingConfiguration struct {
 	MaxTokens                     int `yaml:"maxTokens"`
 	BufferSize                    int `yaml:"bufferSize"`
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
 type Lambda struct {
 	Kind     ResourceKind `yaml:"kind"`
 	Metadata Metadata     `yaml:"metadata"`
@@ -17,6 +24,10 @@ type LambdaSpec struct {
 	Tags                map[string]string     `yaml:"tags,omitempty"`
 	VpcConfig           *VpcConfig            `yaml:"vpcConfig,omitempty"`
 	ResourcePolicy      *LambdaResourcePolicy `yaml:"resourcePolicy,omitempty"`
+	FunctionURL         *FunctionURLConfig    `yaml:"functionUrl,omitempty"`
+
+	// Initialized execution environments for a published version, served through an alias
+	ProvisionedConcurrency *ProvisionedConcurrencyConfig `yaml:"provisionedConcurrency,omitempty"`
 
 	// Missing critical Terraform attributes
 	Role                           Reference         `yaml:"role,omitempty"`                 // Reference to IAM role or ARN
@@ -28,7 +39,7 @@ type LambdaSpec struct {
 	FileSystemConfig               *FileSystemConfig `yaml:"fileSystemConfig,omitempty"`     // EFS config
 	ImageConfig                    *ImageConfig      `yaml:"imageConfig,omitempty"`          // Container image config
 	KmsKeyArn                      string            `yaml:"kmsKeyArn,omitempty"`            // KMS key for encryption
-	Layers                         []string          `yaml:"layers,omitempty"`               // Lambda layer ARNs
+	Layers                         []Reference       `yaml:"layers,omitempty"`               // Layer ARNs or LambdaLayer references
 	PackageType                    string            `yaml:"packageType,omitempty"`          // Zip or Image
 	Publish                        *bool             `yaml:"publish,omitempty"`              // Create version on update
 	ReplaceSecurityGroupsOnDestroy *bool             `yaml:"replaceSecurityGroupsOnDestroy,omitempty"`
@@ -54,12 +65,215 @@ type LambdaResourcePolicyStmt struct {
 	Condition map[string]interface{} `yaml:"condition,omitempty"`
 }
 
+// FunctionURLConfig exposes the function over HTTPS through a Lambda function URL
+type FunctionURLConfig struct {
+	AuthType   string           `yaml:"authType"`             // NONE or AWS_IAM
+	InvokeMode string           `yaml:"invokeMode,omitempty"` // BUFFERED (default) or RESPONSE_STREAM
+	Cors       *FunctionURLCors `yaml:"cors,omitempty"`
+}
+
+type FunctionURLCors struct {
+	AllowCredentials bool     `yaml:"allowCredentials,omitempty"`
+	AllowHeaders     []string `yaml:"allowHeaders,omitempty"`
+	AllowMethods     []string `yaml:"allowMethods,omitempty"`
+	AllowOrigins     []string `yaml:"allowOrigins,omitempty"`
+	ExposeHeaders    []string `yaml:"exposeHeaders,omitempty"`
+	MaxAge           int      `yaml:"maxAge,omitempty"` // Seconds, at most 86400
+}
+
+// Validate checks the auth type, invoke mode and CORS settings of a function URL
+func (c FunctionURLConfig) Validate() error {
+	switch c.AuthType {
+	case "NONE", "AWS_IAM":
+	case "":
+		return fmt.Errorf("function URL requires authType NONE or AWS_IAM")
+	default:
+		return fmt.Errorf("invalid function URL authType '%s', must be one of: NONE, AWS_IAM", c.AuthType)
+	}
+
+	switch c.InvokeMode {
+	case "", "BUFFERED", "RESPONSE_STREAM":
+	default:
+		return fmt.Errorf("invalid function URL invokeMode '%s', must be one of: BUFFERED, RESPONSE_STREAM", c.InvokeMode)
+	}
+
+	if c.Cors != nil && (c.Cors.MaxAge < 0 || c.Cors.MaxAge > 86400) {
+		return fmt.Errorf("function URL cors maxAge must be between 0 and 86400 seconds, got %d", c.Cors.MaxAge)
+	}
+
+	return nil
+}
+
+// ProvisionedConcurrencyConfig keeps execution environments initialized for an alias of a published
+// version. Action groups invoke the alias, so they are served by the provisioned environments.
+type ProvisionedConcurrencyConfig struct {
+	Executions  int                                `yaml:"executions"`
+	Alias       string                             `yaml:"alias,omitempty"`   // Alias to create, default: live
+	Version     string                             `yaml:"version,omitempty"` // Published version the alias points to, default: the latest
+	Autoscaling *ProvisionedConcurrencyAutoscaling `yaml:"autoscaling,omitempty"`
+}
+
+// ProvisionedConcurrencyAutoscaling scales provisioned concurrency with Application Auto Scaling, by
+// utilization, on a schedule or both
+type ProvisionedConcurrencyAutoscaling struct {
+	MinCapacity       int                              `yaml:"minCapacity"`
+	MaxCapacity       int                              `yaml:"maxCapacity"`
+	TargetUtilization float64                          `yaml:"targetUtilization,omitempty"` // Share of provisioned concurrency in use to track, e.g. 0.7
+	Schedules         []ProvisionedConcurrencySchedule `yaml:"schedules,omitempty"`
+}
+
+// ProvisionedConcurrencySchedule sets the capacity range from a point in time
+type ProvisionedConcurrencySchedule struct {
+	Name        string `yaml:"name"`
+	Schedule    string `yaml:"schedule"`           // cron(...), rate(...) or at(...)
+	Timezone    string `yaml:"timezone,omitempty"` // Default: UTC
+	MinCapacity int    `yaml:"minCapacity"`
+	MaxCapacity int    `yaml:"maxCapacity"`
+}
+
+// DefaultProvisionedConcurrencyAlias is the alias created for provisioned concurrency when none is named
+const DefaultProvisionedConcurrencyAlias = "live"
+
+var lambdaVersionPattern = regexp.MustCompile(`^[1-9][0-9]*$`)
+
+// AliasName returns the name of the alias provisioned concurrency is configured on
+func (c ProvisionedConcurrencyConfig) AliasName() string {
+	if c.Alias == "" {
+		return DefaultProvisionedConcurrencyAlias
+	}
+	return c.Alias
+}
+
+// ValidateProvisionedConcurrency checks that provisioned concurrency has a published version to apply
+// to, fits within the reserved concurrency and has a consistent autoscaling configuration
+func (s LambdaSpec) ValidateProvisionedConcurrency() error {
+	c := s.ProvisionedConcurrency
+	if c == nil {
+		return nil
+	}
+
+	if s.Publish == nil || !*s.Publish {
+		return fmt.Errorf("provisioned concurrency requires publish: true, it applies to a published version")
+	}
+	if c.Executions < 1 {
+		return fmt.Errorf("provisioned concurrency executions must be at least 1, got %d", c.Executions)
+	}
+	if s.ReservedConcurrency > 0 && c.Executions > s.ReservedConcurrency {
+		return fmt.Errorf("provisioned concurrency executions %d exceed reservedConcurrency %d", c.Executions, s.ReservedConcurrency)
+	}
+	if c.Version != "" && !lambdaVersionPattern.MatchString(c.Version) {
+		return fmt.Errorf("provisioned concurrency version must be a published version number, got '%s'", c.Version)
+	}
+
+	a := c.Autoscaling
+	if a == nil {
+		return nil
+	}
+	if err := validateCapacityRange(a.MinCapacity, a.MaxCapacity); err != nil {
+		return fmt.Errorf("provisioned concurrency autoscaling: %w", err)
+	}
+	if s.ReservedConcurrency > 0 && a.MaxCapacity > s.ReservedConcurrency {
+		return fmt.Errorf("provisioned concurrency autoscaling maxCapacity %d exceeds reservedConcurrency %d", a.MaxCapacity, s.ReservedConcurrency)
+	}
+	if a.TargetUtilization == 0 && len(a.Schedules) == 0 {
+		return fmt.Errorf("provisioned concurrency autoscaling requires targetUtilization, schedules or both")
+	}
+	if a.TargetUtilization < 0 || a.TargetUtilization >= 1 {
+		return fmt.Errorf("provisioned concurrency autoscaling targetUtilization must be between 0 and 1, got %g", a.TargetUtilization)
+	}
+	for _, schedule := range a.Schedules {
+		if schedule.Name == "" {
+			return fmt.Errorf("provisioned concurrency schedules require a name")
+		}
+		if !strings.HasPrefix(schedule.Schedule, "cron(") && !strings.HasPrefix(schedule.Schedule, "rate(") && !strings.HasPrefix(schedule.Schedule, "at(") {
+			return fmt.Errorf("provisioned concurrency schedule %s must be a cron(...), rate(...) or at(...) expression, got '%s'", schedule.Name, schedule.Schedule)
+		}
+		if err := validateCapacityRange(schedule.MinCapacity, schedule.MaxCapacity); err != nil {
+			return fmt.Errorf("provisioned concurrency schedule %s: %w", schedule.Name, err)
+		}
+		if s.ReservedConcurrency > 0 && schedule.MaxCapacity > s.ReservedConcurrency {
+			return fmt.Errorf("provisioned concurrency schedule %s maxCapacity %d exceeds reservedConcurrency %d", schedule.Name, schedule.MaxCapacity, s.ReservedConcurrency)
+		}
+	}
+
+	return nil
+}
+
+// validateCapacityRange checks an autoscaling capacity range
+func validateCapacityRange(minCapacity, maxCapacity int) error {
+	if minCapacity < 0 || maxCapacity < 1 || minCapacity > maxCapacity {
+		return fmt.Errorf("capacity must satisfy 0 <= minCapacity <= maxCapacity and maxCapacity >= 1, got %d-%d", minCapacity, maxCapacity)
+	}
+	return nil
+}
+
 type CodeConfiguration struct {
 	Source          string `yaml:"source"`
 	ZipFile         string `yaml:"zipFile,omitempty"`
 	S3Bucket        string `yaml:"s3Bucket,omitempty"`
 	S3Key           string `yaml:"s3Key,omitempty"`
 	S3ObjectVersion string `yaml:"s3ObjectVersion,omitempty"`
+
+	// Inline handler code for small functions, written to InlineFilename (default: index.py)
+	Inline         string `yaml:"inline,omitempty"`
+	InlineFilename string `yaml:"inlineFilename,omitempty"`
+
+	// Additional packaging exclude patterns for this Lambda, added to the global patterns
+	ExcludePatterns []string `yaml:"excludePatterns,omitempty"`
+}
+
+// IsSourceDirectory returns true if Source points at local code rather than naming another code option
+func (c CodeConfiguration) IsSourceDirectory() bool {
+	switch c.Source {
+	case "", "zip", "s3", "inline":
+		return false
+	}
+	return true
+}
+
+// codeSourceOptions maps the source values that name a code option to that option's field
+var codeSourceOptions = map[string]string{
+	"zip":    "zipFile",
+	"s3":     "s3Bucket",
+	"inline": "inline",
+}
+
+// Validate checks that exactly one code option is specified, that a source naming an option names the
+// one that is set, and that S3 code has both a bucket and a key
+func (c CodeConfiguration) Validate() error {
+	var options []string
+	if c.Inline != "" {
+		options = append(options, "inline")
+	}
+	if c.ZipFile != "" {
+		options = append(options, "zipFile")
+	}
+	if c.S3Bucket != "" {
+		options = append(options, "s3Bucket")
+	}
+	if c.IsSourceDirectory() {
+		options = append(options, "source")
+	}
+
+	switch len(options) {
+	case 0:
+		return fmt.Errorf("lambda code requires one of inline, zipFile, s3Bucket or source")
+	case 1:
+	default:
+		return fmt.Errorf("lambda code must specify exactly one of inline, zipFile, s3Bucket or source, got: %s", strings.Join(options, ", "))
+	}
+
+	if field, ok := codeSourceOptions[c.Source]; ok && field != options[0] {
+		return fmt.Errorf("lambda code source %q conflicts with %s, set %s or remove source", c.Source, options[0], field)
+	}
+
+	if c.S3Bucket != "" && c.S3Key == "" {
+		return fmt.Errorf("lambda code s3Bucket requires s3Key")
+	}
+	if c.S3Bucket == "" && (c.S3Key != "" || c.S3ObjectVersion != "") {
+		return fmt.Errorf("lambda code s3Key and s3ObjectVersion require s3Bucket")
+	}
+	return nil
 }
 
 type VpcConfig struct {
@@ -69,7 +283,42 @@ type VpcConfig struct {
 
 // New supporting types for additional Lambda attributes
 type DeadLetterConfig struct {
-	TargetArn string `yaml:"targetArn"`
+	TargetArn  string `yaml:"targetArn,omitempty"`
+	AutoCreate bool   `yaml:"autoCreate,omitempty"` // Create a queue or topic as the target
+	Type       string `yaml:"type,omitempty"`       // sqs (default) or sns, with autoCreate
+}
+
+// Dead-letter target types created by autoCreate
+const (
+	DeadLetterTypeSQS = "sqs"
+	DeadLetterTypeSNS = "sns"
+)
+
+// TargetType returns the type of the auto-created target, defaulting to sqs
+func (c DeadLetterConfig) TargetType() string {
+	if c.Type == "" {
+		return DeadLetterTypeSQS
+	}
+	return c.Type
+}
+
+// Validate checks that exactly one of targetArn and autoCreate is set and the target type
+func (c DeadLetterConfig) Validate() error {
+	switch {
+	case c.TargetArn != "" && c.AutoCreate:
+		return fmt.Errorf("dead letter config must specify only one of targetArn or autoCreate")
+	case c.TargetArn == "" && !c.AutoCreate:
+		return fmt.Errorf("dead letter config requires targetArn or autoCreate")
+	case c.Type != "" && !c.AutoCreate:
+		return fmt.Errorf("dead letter config type only applies with autoCreate")
+	}
+
+	switch c.Type {
+	case "", DeadLetterTypeSQS, DeadLetterTypeSNS:
+		return nil
+	default:
+		return fmt.Errorf("invalid dead letter config type '%s', must be one of: sqs, sns", c.Type)
+	}
 }
 
 type EphemeralStorage struct {
@@ -100,3 +349,56 @@ type LambdaTimeouts struct {
 type TracingConfig struct {
 	Mode string `yaml:"mode"` // Active or PassThrough
 }
+
+// Sources of Lambda environment values resolved from AWS when Terraform runs
+const (
+	EnvironmentSourceSSM    = "ssm"    // ${ssm:/path/to/param}
+	EnvironmentSourceSecret = "secret" // ${secret:secret-name}
+)
+
+var (
+	environmentSourcePattern = regexp.MustCompile(`^\$\{(ssm|secret):(.*)\}$`)
+	ssmParameterNamePattern  = regexp.MustCompile(`^(/[A-Za-z0-9_.-]+)+$|^[A-Za-z0-9_.-]+$`)
+	secretNamePattern        = regexp.MustCompile(`^[A-Za-z0-9/_+=.@-]+$`)
+)
+
+// ParseEnvironmentSource returns the source and the parameter or secret name of an ${ssm:...} or
+// ${secret:...} environment value. ok is false for any other value.
+func ParseEnvironmentSource(value string) (source, name string, ok bool, err error) {
+	matches := environmentSourcePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return "", "", false, nil
+	}
+	source, name = matches[1], matches[2]
+
+	switch source {
+	case EnvironmentSourceSSM:
+		// Names beginning with aws or ssm are reserved, with or without a leading slash
+		reserved := strings.ToLower(strings.TrimPrefix(name, "/"))
+		if len(name) > 2048 || !ssmParameterNamePattern.MatchString(name) ||
+			strings.HasPrefix(reserved, "aws") || strings.HasPrefix(reserved, "ssm") {
+			return source, name, true, fmt.Errorf("invalid SSM parameter name '%s', expected a name or /path/to/param of letters, digits, '.', '_' and '-' not starting with aws or ssm", name)
+		}
+	case EnvironmentSourceSecret:
+		if len(name) > 512 || !secretNamePattern.MatchString(name) {
+			return source, name, true, fmt.Errorf("invalid secret name '%s', expected up to 512 letters, digits, '/', '_', '+', '=', '.', '@' and '-'", name)
+		}
+	}
+	return source, name, true, nil
+}
+
+// ValidateEnvironmentSources checks the ${ssm:...} and ${secret:...} references in environment values
+func (s LambdaSpec) ValidateEnvironmentSources() error {
+	keys := make([]string, 0, len(s.Environment))
+	for key := range s.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, _, _, err := ParseEnvironmentSource(s.Environment[key]); err != nil {
+			return fmt.Errorf("environment variable %s: %w", key, err)
+		}
+	}
+	return nil
+}
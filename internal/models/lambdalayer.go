@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LambdaLayer represents a Lambda layer version that Lambdas in the project can reference by name
+type LambdaLayer struct {
+	Kind     ResourceKind    `yaml:"kind"`
+	Metadata Metadata        `yaml:"metadata"`
+	Spec     LambdaLayerSpec `yaml:"spec"`
+}
+
+type LambdaLayerSpec struct {
+	LayerName               string          `yaml:"layerName,omitempty"` // Defaults to the resource name
+	Code                    LambdaLayerCode `yaml:"code"`
+	CompatibleRuntimes      []string        `yaml:"compatibleRuntimes,omitempty"`
+	CompatibleArchitectures []string        `yaml:"compatibleArchitectures,omitempty"` // x86_64, arm64
+	LicenseInfo             string          `yaml:"licenseInfo,omitempty"`
+	SkipDestroy             *bool           `yaml:"skipDestroy,omitempty"` // Keep old versions when the layer changes
+}
+
+// LambdaLayerCode is the content of a layer: a local directory zipped at plan time, or an existing S3 object
+type LambdaLayerCode struct {
+	Source          string `yaml:"source,omitempty"`
+	S3Bucket        string `yaml:"s3Bucket,omitempty"`
+	S3Key           string `yaml:"s3Key,omitempty"`
+	S3ObjectVersion string `yaml:"s3ObjectVersion,omitempty"`
+}
+
+// Validate checks that exactly one of a source directory and an S3 location is specified
+func (c LambdaLayerCode) Validate() error {
+	switch {
+	case c.Source == "" && c.S3Bucket == "":
+		return fmt.Errorf("lambda layer code requires one of source or s3Bucket")
+	case c.Source != "" && c.S3Bucket != "":
+		return fmt.Errorf("lambda layer code must specify exactly one of source or s3Bucket")
+	case c.S3Bucket != "" && c.S3Key == "":
+		return fmt.Errorf("lambda layer code s3Bucket requires s3Key")
+	}
+	return nil
+}
+
+// Validate checks the layer code and compatible architectures
+func (s LambdaLayerSpec) Validate() error {
+	if err := s.Code.Validate(); err != nil {
+		return err
+	}
+	for _, architecture := range s.CompatibleArchitectures {
+		switch architecture {
+		case "x86_64", "arm64":
+		default:
+			return fmt.Errorf("invalid compatible architecture '%s', must be one of: x86_64, arm64", architecture)
+		}
+	}
+	return nil
+}
+
+// IsExternalLayer reports whether a Lambda layers entry is a layer ARN or a ${ref:custom.<output>}
+// reference rather than the name of a LambdaLayer resource
+func IsExternalLayer(layer Reference) bool {
+	return strings.HasPrefix(layer.String(), "arn:") || strings.HasPrefix(layer.String(), "${ref:")
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // OpenSearchServerless represents an OpenSearch Serverless collection with required security policies
 type OpenSearchServerless struct {
 	Kind     ResourceKind             `yaml:"kind"`
@@ -7,6 +12,31 @@ type OpenSearchServerless struct {
 	Spec     OpenSearchServerlessSpec `yaml:"spec"`
 }
 
+// EffectiveCollectionName returns the collection name used in AWS, which defaults to the resource name
+func (o OpenSearchServerless) EffectiveCollectionName() string {
+	if o.Spec.CollectionName != "" {
+		return o.Spec.CollectionName
+	}
+	return o.Metadata.Name
+}
+
+// ValidateCollectionName checks the OpenSearch Serverless collection naming rules: 3-28 characters,
+// lowercase letters, numbers and hyphens, starting with a letter
+func ValidateCollectionName(name string) error {
+	if len(name) < 3 || len(name) > 28 {
+		return fmt.Errorf("collection name '%s' must be between 3 and 28 characters, got %d", name, len(name))
+	}
+	if name[0] < 'a' || name[0] > 'z' {
+		return fmt.Errorf("collection name '%s' must start with a lowercase letter", name)
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return fmt.Errorf("collection name '%s' may only contain lowercase letters, numbers and hyphens, found '%c'", name, c)
+		}
+	}
+	return nil
+}
+
 type OpenSearchServerlessSpec struct {
 	// Collection configuration
 	CollectionName string `yaml:"collectionName"`
@@ -30,6 +60,24 @@ type EncryptionPolicy struct {
 	Description string `yaml:"description,omitempty"`
 	Type        string `yaml:"type,omitempty"`     // Default: "encryption"
 	KmsKeyId    string `yaml:"kmsKeyId,omitempty"` // Optional, uses AWS managed key if not provided
+
+	// KmsKeyRef references a key managed in the same configuration, as ${ref:custom.<outputName>}
+	KmsKeyRef Reference `yaml:"kmsKeyRef,omitempty"`
+}
+
+// Validate checks that at most one customer managed key is configured
+func (p *EncryptionPolicy) Validate() error {
+	if p == nil || p.KmsKeyRef.IsEmpty() {
+		return nil
+	}
+	if p.KmsKeyId != "" {
+		return fmt.Errorf("encryptionPolicy kmsKeyId and kmsKeyRef are mutually exclusive")
+	}
+	// There is no KMS key kind, so keys managed in the same plan come from CustomResources outputs
+	if !strings.HasPrefix(p.KmsKeyRef.String(), "${ref:custom.") {
+		return fmt.Errorf("encryptionPolicy kmsKeyRef '%s' must reference a CustomResources output as ${ref:custom.<outputName>}", p.KmsKeyRef.String())
+	}
+	return nil
 }
 
 type NetworkPolicy struct {
@@ -59,9 +107,39 @@ type AccessPolicy struct {
 	AutoConfigureForBedrock bool `yaml:"autoConfigureForBedrock,omitempty"`
 }
 
+// GrantsRole reports whether the policy gives the IAM role named roleName access, either through
+// Bedrock auto-configuration or by listing one of the role's ARNs as a principal
+func (p *AccessPolicy) GrantsRole(roleName string) bool {
+	if p == nil {
+		return false
+	}
+	if p.AutoConfigureForBedrock {
+		return true
+	}
+
+	for _, principal := range p.Principals {
+		// arn:<partition>:iam::<account>:role/<path/><name>
+		parts := strings.SplitN(principal, ":", 6)
+		if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" {
+			continue
+		}
+		resource := parts[5]
+		if resource == "role/"+roleName || (strings.HasPrefix(resource, "role/") && strings.HasSuffix(resource, "/"+roleName)) {
+			return true
+		}
+	}
+	return false
+}
+
 type VectorIndexConfig struct {
 	Name         string             `yaml:"name"`
 	FieldMapping VectorFieldMapping `yaml:"fieldMapping"`
+	Dimension    int                `yaml:"dimension,omitempty"` // Default: 1536
+	Engine       string             `yaml:"engine,omitempty"`    // Default: "nmslib"
+	SpaceType    string             `yaml:"spaceType,omitempty"` // Default: "l2"
+
+	// Create the index with a local-exec provisioner (AWS CLI + curl) instead of the opensearch provider
+	UseProvisioner bool `yaml:"useProvisioner,omitempty"`
 }
 
 type VectorFieldMapping struct {
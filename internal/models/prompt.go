@@ -1,5 +1,13 @@
 package models
 
+import (
+	"regexp"
+	"sort"
+)
+
+// promptPlaceholderPattern matches {{variable}} placeholders in prompt templates
+var promptPlaceholderPattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
 type Prompt struct {
 	Kind     ResourceKind `yaml:"kind"`
 	Metadata Metadata     `yaml:"metadata"`
@@ -12,12 +20,31 @@ type PromptSpec struct {
 	CustomerEncryptionKeyArn string                `yaml:"customerEncryptionKeyArn,omitempty"`
 	InputVariables           []PromptInputVariable `yaml:"inputVariables,omitempty"`
 	Variants                 []PromptVariant       `yaml:"variants"`
+	Versions                 []PromptVersion       `yaml:"versions,omitempty"`
 	Tags                     map[string]string     `yaml:"tags,omitempty"`
 
 	// Missing Terraform attributes
 	Timeouts *PromptTimeouts `yaml:"timeouts,omitempty"`
 }
 
+// HasVariant reports whether name is one of the prompt's variants or its default variant
+func (s PromptSpec) HasVariant(name string) bool {
+	if name == s.DefaultVariant {
+		return true
+	}
+	for _, variant := range s.Variants {
+		if variant.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PromptVersion is an immutable snapshot of the prompt. Versions are numbered from 1 in declaration order.
+type PromptVersion struct {
+	Description string `yaml:"description,omitempty"`
+}
+
 type PromptInputVariable struct {
 	Name string `yaml:"name"`
 }
@@ -31,6 +58,55 @@ type PromptVariant struct {
 	GenAiResource          *GenAiResourceConfig    `yaml:"genAiResource,omitempty"`
 }
 
+// TemplatePlaceholders returns the sorted, distinct {{variable}} placeholders used in the variant's template
+func (v PromptVariant) TemplatePlaceholders() []string {
+	if v.TemplateConfiguration == nil {
+		return nil
+	}
+
+	var texts []string
+	if text := v.TemplateConfiguration.Text; text != nil {
+		texts = append(texts, text.Text)
+	}
+	if chat := v.TemplateConfiguration.Chat; chat != nil {
+		for _, message := range chat.Messages {
+			for _, content := range message.Content {
+				texts = append(texts, content.Text)
+			}
+		}
+		for _, system := range chat.System {
+			texts = append(texts, system.Text)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var placeholders []string
+	for _, text := range texts {
+		for _, match := range promptPlaceholderPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				placeholders = append(placeholders, match[1])
+			}
+		}
+	}
+	sort.Strings(placeholders)
+	return placeholders
+}
+
+// TemplateInputVariables returns the input variables declared on the variant's template
+func (v PromptVariant) TemplateInputVariables() []TemplateInputVariable {
+	if v.TemplateConfiguration == nil {
+		return nil
+	}
+	if v.TemplateConfiguration.Text != nil {
+		return v.TemplateConfiguration.Text.InputVariables
+	}
+	if v.TemplateConfiguration.Chat != nil {
+		return v.TemplateConfiguration.Chat.InputVariables
+	}
+	return nil
+}
+
 type GenAiResourceConfig struct {
 	Agent *AgentResourceConfig `yaml:"agent,omitempty"`
 }
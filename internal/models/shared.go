@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"regexp"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -10,6 +12,7 @@ type ResourceKind string
 const (
 	AgentKind                         ResourceKind = "Agent"
 	LambdaKind                        ResourceKind = "Lambda"
+	LambdaLayerKind                   ResourceKind = "LambdaLayer"
 	ActionGroupKind                   ResourceKind = "ActionGroup"
 	KnowledgeBaseKind                 ResourceKind = "KnowledgeBase"
 	GuardrailKind                     ResourceKind = "Guardrail"
@@ -18,6 +21,9 @@ const (
 	AgentKnowledgeBaseAssociationKind ResourceKind = "AgentKnowledgeBaseAssociation"
 	CustomResourcesKind               ResourceKind = "CustomResources"
 	OpenSearchServerlessKind          ResourceKind = "OpenSearchServerless"
+
+	// DefaultsKind is not a resource; its spec is merged into subsequent resources in the same file
+	DefaultsKind ResourceKind = "Defaults"
 )
 
 type BaseResource struct {
@@ -29,10 +35,57 @@ type BaseResource struct {
 }
 
 type Metadata struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Name          string            `yaml:"name"`
+	Description   string            `yaml:"description,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Outputs       []string          `yaml:"outputs,omitempty"` // Module or resource attributes surfaced as root outputs
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+	Region        string            `yaml:"region,omitempty"`
+	Provider      string            `yaml:"provider,omitempty"`      // Named provider from bedrock-forge.yaml, e.g. assuming a role in another account
+	DependsOn     []Reference       `yaml:"dependsOn,omitempty"`     // Explicit ordering on other resources of any kind
+	PreviousNames []string          `yaml:"previousNames,omitempty"` // Former names, moved to the current name in Terraform state
+	Import        string            `yaml:"import,omitempty"`        // ID of an existing AWS resource to adopt into Terraform state
+}
+
+var (
+	agentIDPattern         = regexp.MustCompile(`^[0-9A-Z]{10}$`)
+	lambdaNamePattern      = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	guardrailImportPattern = regexp.MustCompile(`^[a-z0-9]{12}(,(DRAFT|[0-9]+))?$`)
+)
+
+// FieldError is a problem with the value of a spec field, e.g. contentPolicyConfig.filtersConfig[0]
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateImportID checks the format of a metadata.import ID for the kinds that support importing
+func ValidateImportID(kind ResourceKind, id string) error {
+	switch kind {
+	case AgentKind:
+		if !agentIDPattern.MatchString(id) {
+			return fmt.Errorf("import ID '%s' must be an agent ID of 10 uppercase letters and digits", id)
+		}
+	case LambdaKind:
+		if !lambdaNamePattern.MatchString(id) {
+			return fmt.Errorf("import ID '%s' must be a Lambda function name of up to 64 letters, digits, hyphens and underscores", id)
+		}
+	case GuardrailKind:
+		if !guardrailImportPattern.MatchString(id) {
+			return fmt.Errorf("import ID '%s' must be a guardrail ID of 12 lowercase letters and digits, optionally followed by ,DRAFT or ,<version>", id)
+		}
+	default:
+		return fmt.Errorf("metadata.import is not supported for %s, only Agent, Lambda and Guardrail resources can be imported", kind)
+	}
+	return nil
 }
 
 // Reference represents a reference to another resource, supporting both:
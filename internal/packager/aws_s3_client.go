@@ -0,0 +1,188 @@
+package packager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/sirupsen/logrus"
+)
+
+// AWSS3Config holds the configuration for uploading artifacts to AWS S3
+type AWSS3Config struct {
+	Region   string // Falls back to the region of the standard AWS configuration, e.g. AWS_REGION
+	Profile  string // Shared config profile, falls back to AWS_PROFILE / "default"
+	SSEMode  string // Default server-side encryption: AES256 or aws:kms, implied by KMSKeyID
+	KMSKeyID string // Optional KMS key ARN for SSE-KMS encryption
+	Endpoint string // Optional endpoint override, e.g. for S3-compatible storage
+}
+
+// PutObjectAPIClient is the part of the S3 API the client uploads with
+type PutObjectAPIClient interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// AWSS3Client uploads artifacts to AWS S3 with PutObject
+type AWSS3Client struct {
+	logger *logrus.Logger
+	config AWSS3Config
+	api    PutObjectAPIClient
+}
+
+// NewAWSS3Client creates an S3 client from the standard AWS configuration: environment variables,
+// shared config and credentials files (including SSO and assume-role profiles), web identity and
+// container or instance roles
+func NewAWSS3Client(logger *logrus.Logger, config AWSS3Config) (*AWSS3Client, error) {
+	if err := ValidateSSEMode(config.SSEMode, config.KMSKeyID); err != nil {
+		return nil, err
+	}
+	if config.SSEMode == "" && config.KMSKeyID != "" {
+		config.SSEMode = SSEModeKMS
+	}
+
+	ctx := context.Background()
+
+	var options []func(*awsconfig.LoadOptions) error
+	if config.Region != "" {
+		options = append(options, awsconfig.WithRegion(config.Region))
+	}
+	if config.Profile != "" {
+		options = append(options, awsconfig.WithSharedConfigProfile(config.Profile))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("AWS region is required, set it explicitly, via AWS_REGION or in the profile")
+	}
+	config.Region = awsConfig.Region
+
+	// Fail before packaging anything rather than on the first upload
+	if _, err := awsConfig.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+	}
+
+	api := s3.NewFromConfig(awsConfig, func(options *s3.Options) {
+		// Uploads are retried by the packager's RetryPolicy
+		options.Retryer = aws.NopRetryer{}
+		if config.Endpoint != "" {
+			// Custom endpoints use path-style addressing
+			options.BaseEndpoint = aws.String(config.Endpoint)
+			options.UsePathStyle = true
+		}
+	})
+
+	return NewAWSS3ClientWithAPI(logger, config, api), nil
+}
+
+// NewAWSS3ClientWithAPI creates an S3 client uploading through api, e.g. a configured s3.Client or a fake
+func NewAWSS3ClientWithAPI(logger *logrus.Logger, config AWSS3Config, api PutObjectAPIClient) *AWSS3Client {
+	return &AWSS3Client{
+		logger: logger,
+		config: config,
+		api:    api,
+	}
+}
+
+// UploadFile uploads a file to S3 with the client's default encryption
+func (c *AWSS3Client) UploadFile(bucket, key string, filePath string) (string, error) {
+	return c.UploadFileWithOptions(bucket, key, filePath, c.defaultUploadOptions())
+}
+
+// UploadFileWithOptions uploads a file to S3, encrypted as the options specify
+func (c *AWSS3Client) UploadFileWithOptions(bucket, key string, filePath string, options UploadOptions) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	return c.putObject(bucket, key, file, info.Size(), "application/zip", options)
+}
+
+// UploadContent uploads content to S3 with the client's default encryption
+func (c *AWSS3Client) UploadContent(bucket, key string, content []byte, contentType string) (string, error) {
+	return c.putObject(bucket, key, bytes.NewReader(content), int64(len(content)), contentType, c.defaultUploadOptions())
+}
+
+// defaultUploadOptions returns the encryption configured for the client
+func (c *AWSS3Client) defaultUploadOptions() UploadOptions {
+	return UploadOptions{SSEMode: c.config.SSEMode, KMSKeyID: c.config.KMSKeyID}
+}
+
+// putObject uploads body, which is seekable so the SDK can compute its checksum
+func (c *AWSS3Client) putObject(bucket, key string, body io.ReadSeeker, size int64, contentType string, options UploadOptions) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	}
+	if options.SSEMode != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(options.SSEMode)
+	}
+	if options.SSEMode == SSEModeKMS && options.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(options.KMSKeyID)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"bucket": bucket,
+		"key":    key,
+		"size":   size,
+		"sse":    options.SSEMode,
+	}).Debug("Uploading to S3")
+
+	if _, err := c.api.PutObject(context.Background(), input); err != nil {
+		return "", uploadError(bucket, key, err)
+	}
+
+	s3URI := fmt.Sprintf("s3://%s/%s", bucket, key)
+	c.logger.WithFields(logrus.Fields{
+		"bucket": bucket,
+		"key":    key,
+		"uri":    s3URI,
+	}).Info("S3 object uploaded")
+
+	return s3URI, nil
+}
+
+// uploadError converts an error response from S3 into an S3UploadError, so the retry policy can tell
+// throttling and server errors from permanent failures
+func uploadError(bucket, key string, err error) error {
+	var responseErr *awshttp.ResponseError
+	if !errors.As(err, &responseErr) {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", bucket, key, err)
+	}
+
+	statusCode := responseErr.HTTPStatusCode()
+	message := responseErr.Err.Error()
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		message = fmt.Sprintf("%s: %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+	}
+
+	return &S3UploadError{
+		Bucket:     bucket,
+		Key:        key,
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Message:    message,
+	}
+}
@@ -0,0 +1,263 @@
+package packager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/sirupsen/logrus"
+)
+
+// s3Request is a request received by the fake S3 endpoint
+type s3Request struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// fakeS3Endpoint serves PutObject, answering with status for every request
+func fakeS3Endpoint(t *testing.T, status int) (*httptest.Server, func() []s3Request) {
+	t.Helper()
+
+	var mutex sync.Mutex
+	var requests []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		requests = append(requests, s3Request{method: r.Method, path: r.URL.Path, header: r.Header.Clone(), body: body})
+		mutex.Unlock()
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []s3Request {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]s3Request(nil), requests...)
+	}
+}
+
+// isolateAWSConfig points the standard AWS configuration at static test credentials only
+func isolateAWSConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestAWSS3ClientUploadsWithEncryption(t *testing.T) {
+	isolateAWSConfig(t)
+	server, requests := fakeS3Endpoint(t, http.StatusOK)
+
+	client, err := NewAWSS3Client(testLogger(), AWSS3Config{
+		Endpoint: server.URL,
+		KMSKeyID: "arn:aws:kms:us-east-1:123456789012:key/test",
+	})
+	if err != nil {
+		t.Fatalf("NewAWSS3Client: %v", err)
+	}
+
+	uri, err := client.UploadContent("artifacts", "schemas/orders.json", []byte(`{"openapi":"3.0.0"}`), "application/json")
+	if err != nil {
+		t.Fatalf("UploadContent: %v", err)
+	}
+	if uri != "s3://artifacts/schemas/orders.json" {
+		t.Errorf("uri = %s", uri)
+	}
+
+	received := requests()
+	if len(received) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(received))
+	}
+	request := received[0]
+	if request.method != http.MethodPut || request.path != "/artifacts/schemas/orders.json" {
+		t.Errorf("request = %s %s, want a path-style PUT", request.method, request.path)
+	}
+	if got := request.header.Get("X-Amz-Server-Side-Encryption"); got != SSEModeKMS {
+		t.Errorf("server-side encryption = %q, want %q", got, SSEModeKMS)
+	}
+	if got := request.header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"); got != "arn:aws:kms:us-east-1:123456789012:key/test" {
+		t.Errorf("KMS key ID = %q", got)
+	}
+	if got := request.header.Get("Authorization"); !strings.HasPrefix(got, "AWS4-HMAC-SHA256 Credential=AKIDTEST/") {
+		t.Errorf("Authorization = %q, want a SigV4 signature with the environment credentials", got)
+	}
+	if !strings.Contains(string(request.body), `{"openapi":"3.0.0"}`) {
+		t.Errorf("body = %q", request.body)
+	}
+}
+
+func TestAWSS3ClientUploadsFile(t *testing.T) {
+	isolateAWSConfig(t)
+	server, requests := fakeS3Endpoint(t, http.StatusOK)
+
+	client, err := NewAWSS3Client(testLogger(), AWSS3Config{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewAWSS3Client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "lambda.zip")
+	if err := os.WriteFile(path, []byte("zip content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UploadFileWithOptions("artifacts", "lambdas/order-lookup.zip", path, UploadOptions{SSEMode: SSEModeAES256}); err != nil {
+		t.Fatalf("UploadFileWithOptions: %v", err)
+	}
+
+	request := requests()[0]
+	if got := request.header.Get("Content-Type"); got != "application/zip" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := request.header.Get("X-Amz-Server-Side-Encryption"); got != SSEModeAES256 {
+		t.Errorf("server-side encryption = %q, want %q", got, SSEModeAES256)
+	}
+	if request.header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id") != "" {
+		t.Error("AES256 uploads must not send a KMS key ID")
+	}
+}
+
+func TestAWSS3ClientUsesSharedConfigProfile(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_REGION", "")
+
+	config := "[profile deploy]\nregion = eu-west-1\n"
+	credentials := "[deploy]\naws_access_key_id = AKIDPROFILE\naws_secret_access_key = secret\n"
+	if err := os.WriteFile(os.Getenv("AWS_CONFIG_FILE"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte(credentials), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server, requests := fakeS3Endpoint(t, http.StatusOK)
+	client, err := NewAWSS3Client(testLogger(), AWSS3Config{Profile: "deploy", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewAWSS3Client: %v", err)
+	}
+	if client.config.Region != "eu-west-1" {
+		t.Errorf("region = %s, want the profile region eu-west-1", client.config.Region)
+	}
+
+	if _, err := client.UploadContent("artifacts", "key", []byte("content"), "text/plain"); err != nil {
+		t.Fatalf("UploadContent: %v", err)
+	}
+	if got := requests()[0].header.Get("Authorization"); !strings.Contains(got, "Credential=AKIDPROFILE/") || !strings.Contains(got, "/eu-west-1/s3/") {
+		t.Errorf("Authorization = %q, want the profile credentials and region", got)
+	}
+}
+
+func TestAWSS3ClientRequiresRegion(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_REGION", "")
+
+	if _, err := NewAWSS3Client(testLogger(), AWSS3Config{}); err == nil || !strings.Contains(err.Error(), "region is required") {
+		t.Fatalf("expected a missing region error, got %v", err)
+	}
+}
+
+func TestAWSS3ClientUploadErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{name: "throttled", status: http.StatusServiceUnavailable, retryable: true},
+		{name: "server error", status: http.StatusInternalServerError, retryable: true},
+		{name: "access denied", status: http.StatusForbidden, retryable: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isolateAWSConfig(t)
+			server, requests := fakeS3Endpoint(t, test.status)
+
+			client, err := NewAWSS3Client(testLogger(), AWSS3Config{Endpoint: server.URL})
+			if err != nil {
+				t.Fatalf("NewAWSS3Client: %v", err)
+			}
+
+			_, err = client.UploadContent("artifacts", "key", []byte("content"), "text/plain")
+			var uploadErr *S3UploadError
+			if !errors.As(err, &uploadErr) {
+				t.Fatalf("expected an S3UploadError, got %v", err)
+			}
+			if uploadErr.StatusCode != test.status {
+				t.Errorf("status code = %d, want %d", uploadErr.StatusCode, test.status)
+			}
+			if uploadErr.Retryable() != test.retryable {
+				t.Errorf("retryable = %v, want %v", uploadErr.Retryable(), test.retryable)
+			}
+			// Retries are left to the packager's RetryPolicy
+			if len(requests()) != 1 {
+				t.Errorf("expected 1 request, got %d", len(requests()))
+			}
+		})
+	}
+}
+
+// fakePutObjectAPI records PutObject inputs
+type fakePutObjectAPI struct {
+	inputs []*s3.PutObjectInput
+	bodies []string
+}
+
+func (f *fakePutObjectAPI) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.inputs = append(f.inputs, params)
+	f.bodies = append(f.bodies, string(body))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestAWSS3ClientDefaultEncryption(t *testing.T) {
+	api := &fakePutObjectAPI{}
+	client := NewAWSS3ClientWithAPI(testLogger(), AWSS3Config{SSEMode: SSEModeAES256}, api)
+
+	if _, err := client.UploadContent("artifacts", "key", []byte("content"), "text/plain"); err != nil {
+		t.Fatalf("UploadContent: %v", err)
+	}
+
+	input := api.inputs[0]
+	if aws.ToString(input.Bucket) != "artifacts" || aws.ToString(input.Key) != "key" {
+		t.Errorf("uploaded to %s/%s", aws.ToString(input.Bucket), aws.ToString(input.Key))
+	}
+	if string(input.ServerSideEncryption) != SSEModeAES256 || input.SSEKMSKeyId != nil {
+		t.Errorf("encryption = %q, key %v", input.ServerSideEncryption, input.SSEKMSKeyId)
+	}
+	if aws.ToInt64(input.ContentLength) != int64(len("content")) || api.bodies[0] != "content" {
+		t.Errorf("body = %q, length %d", api.bodies[0], aws.ToInt64(input.ContentLength))
+	}
+}
@@ -0,0 +1,59 @@
+package packager
+
+import "fmt"
+
+// Server-side encryption modes for uploaded artifacts
+const (
+	SSEModeAES256 = "AES256"  // S3 managed keys
+	SSEModeKMS    = "aws:kms" // KMS keys, the AWS managed aws/s3 key unless a key ID is set
+)
+
+// UploadOptions controls how an object is stored in S3
+type UploadOptions struct {
+	SSEMode  string // "", AES256 or aws:kms; empty leaves encryption to the bucket default
+	KMSKeyID string // Optional KMS key for aws:kms
+}
+
+// ValidateSSEMode checks a server-side encryption mode and KMS key combination
+func ValidateSSEMode(sseMode, kmsKeyID string) error {
+	switch sseMode {
+	case "", SSEModeKMS:
+		return nil
+	case SSEModeAES256:
+		if kmsKeyID != "" {
+			return fmt.Errorf("a KMS key ID requires SSE mode %s, not %s", SSEModeKMS, SSEModeAES256)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported SSE mode '%s', must be one of: %s, %s", sseMode, SSEModeAES256, SSEModeKMS)
+	}
+}
+
+// uploadOptions returns the options packages are uploaded with. A KMS key ID without an SSE mode
+// implies aws:kms.
+func (c *PackagerConfig) uploadOptions() (UploadOptions, error) {
+	if err := ValidateSSEMode(c.SSEMode, c.KMSKeyID); err != nil {
+		return UploadOptions{}, err
+	}
+
+	options := UploadOptions{SSEMode: c.SSEMode, KMSKeyID: c.KMSKeyID}
+	if options.SSEMode == "" && options.KMSKeyID != "" {
+		options.SSEMode = SSEModeKMS
+	}
+	if c.RequireEncryption && options.SSEMode == "" {
+		return UploadOptions{}, fmt.Errorf("the security policy requires encryption at rest, set an SSE mode (%s or %s) for uploaded packages", SSEModeAES256, SSEModeKMS)
+	}
+	return options, nil
+}
+
+// encryptionHeaders returns the S3 request headers for the options
+func (o UploadOptions) encryptionHeaders() map[string]string {
+	headers := make(map[string]string)
+	if o.SSEMode != "" {
+		headers["X-Amz-Server-Side-Encryption"] = o.SSEMode
+	}
+	if o.SSEMode == SSEModeKMS && o.KMSKeyID != "" {
+		headers["X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"] = o.KMSKeyID
+	}
+	return headers
+}
@@ -3,11 +3,16 @@ package packager
 import (
 	"archive/zip"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -16,12 +21,18 @@ import (
 	"bedrock-forge/internal/registry"
 )
 
+// errLambdaDirectoryNotFound is returned when no lambda.yml defines a Lambda
+var errLambdaDirectoryNotFound = errors.New("lambda directory not found")
+
 // LambdaPackager handles packaging Lambda functions and uploading to S3
 type LambdaPackager struct {
 	logger   *logrus.Logger
 	registry *registry.ResourceRegistry
 	s3Client S3Client
 	config   *PackagerConfig
+
+	// reused are packages from an earlier run kept instead of packaging their Lambdas again
+	reused map[string]*LambdaPackage
 }
 
 // PackagerConfig holds configuration for the packager
@@ -30,11 +41,19 @@ type PackagerConfig struct {
 	S3KeyPrefix     string
 	TempDir         string
 	ExcludePatterns []string
+	Retry           RetryPolicy // Applied to S3 uploads
+	Concurrency     int         // Lambdas packaged at once, default: the number of CPUs
+
+	// Server-side encryption of uploaded Lambda packages
+	SSEMode           string // AES256 or aws:kms, implied by KMSKeyID
+	KMSKeyID          string // Optional KMS key ARN for aws:kms
+	RequireEncryption bool   // Fail instead of uploading without an SSE mode
 }
 
 // S3Client interface for uploading artifacts
 type S3Client interface {
 	UploadFile(bucket, key string, filePath string) (string, error)
+	UploadFileWithOptions(bucket, key string, filePath string, options UploadOptions) (string, error)
 	UploadContent(bucket, key string, content []byte, contentType string) (string, error)
 }
 
@@ -67,6 +86,10 @@ func NewLambdaPackager(logger *logrus.Logger, registry *registry.ResourceRegistr
 		config.TempDir = "/tmp/bedrock-forge"
 	}
 
+	if config.Concurrency <= 0 {
+		config.Concurrency = runtime.NumCPU()
+	}
+
 	return &LambdaPackager{
 		logger:   logger,
 		registry: registry,
@@ -75,11 +98,32 @@ func NewLambdaPackager(logger *logrus.Logger, registry *registry.ResourceRegistr
 	}
 }
 
-// PackageAllLambdas discovers and packages all Lambda functions
-func (p *LambdaPackager) PackageAllLambdas(baseDir string) (map[string]*LambdaPackage, error) {
+// ReusePackages keeps packages from an earlier run, by Lambda name, instead of packaging those Lambdas again
+func (p *LambdaPackager) ReusePackages(packages map[string]*LambdaPackage) {
+	p.reused = packages
+}
+
+// lambdaPackageJob is a Lambda whose directory was found and still needs packaging
+type lambdaPackageJob struct {
+	name            string
+	dir             string
+	excludePatterns []string
+}
+
+// PackageAllLambdas discovers and packages all Lambda functions, up to Concurrency at once. A Lambda
+// that fails doesn't stop the others; all failures are returned together once every Lambda is done.
+func (p *LambdaPackager) PackageAllLambdas() (map[string]*LambdaPackage, error) {
 	p.logger.Info("Starting Lambda packaging process...")
 
+	// Check encryption up front rather than failing every package
+	uploadOptions, err := p.config.uploadOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	packages := make(map[string]*LambdaPackage)
+	failures := make(map[string]error)
+	var jobs []lambdaPackageJob
 
 	// Get all Lambda resources from registry
 	lambdas := p.registry.GetResourcesByType(models.LambdaKind)
@@ -97,85 +141,112 @@ func (p *LambdaPackager) PackageAllLambdas(baseDir string) (map[string]*LambdaPa
 			continue
 		}
 
-		// Find Lambda directory
-		lambdaDir, err := p.findLambdaDirectory(baseDir, lambda.Metadata.Name)
-		if err != nil {
-			p.logger.WithError(err).WithField("lambda", lambda.Metadata.Name).Error("Failed to find Lambda directory")
+		if pkg, ok := p.reused[lambda.Metadata.Name]; ok {
+			packages[lambda.Metadata.Name] = pkg
+			p.logger.WithField("lambda", lambda.Metadata.Name).Info("Lambda is unchanged, reusing its package")
 			continue
 		}
 
-		// Package the Lambda
-		pkg, err := p.packageLambda(lambda.Metadata.Name, lambdaDir)
+		// Find Lambda directory
+		lambdaDir, err := p.findLambdaDirectory(lambda.Metadata.Name)
 		if err != nil {
-			p.logger.WithError(err).WithField("lambda", lambda.Metadata.Name).Error("Failed to package Lambda")
+			p.logger.WithError(err).WithField("lambda", lambda.Metadata.Name).Error("Failed to find Lambda directory")
 			continue
 		}
 
-		packages[lambda.Metadata.Name] = pkg
-		p.logger.WithFields(logrus.Fields{
-			"lambda": lambda.Metadata.Name,
-			"size":   pkg.Size,
-			"s3_uri": pkg.S3URI,
-		}).Info("Successfully packaged Lambda")
+		jobs = append(jobs, lambdaPackageJob{
+			name:            lambda.Metadata.Name,
+			dir:             lambdaDir,
+			excludePatterns: p.mergeExcludePatterns(lambdaSpec.Code.ExcludePatterns),
+		})
 	}
 
+	p.runPackageJobs(jobs, uploadOptions, packages, failures)
+
 	p.logger.WithField("count", len(packages)).Info("Lambda packaging completed")
+
+	if len(failures) > 0 {
+		return packages, packageFailuresError(failures)
+	}
 	return packages, nil
 }
 
-// findLambdaDirectory locates the directory containing the Lambda code
-func (p *LambdaPackager) findLambdaDirectory(baseDir, lambdaName string) (string, error) {
-	var lambdaDir string
-
-	err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Look for lambda.yml files
-		if !info.IsDir() && (filepath.Base(path) == "lambda.yml" || filepath.Base(path) == "lambda.yaml") {
-			// Check if this lambda.yml is for our target Lambda
-			if p.isTargetLambda(path, lambdaName) {
-				lambdaDir = filepath.Dir(path)
-				return filepath.SkipDir // Found it, stop searching
+// runPackageJobs packages the jobs on a pool of Concurrency workers, recording each result in packages
+// or failures
+func (p *LambdaPackager) runPackageJobs(jobs []lambdaPackageJob, uploadOptions UploadOptions, packages map[string]*LambdaPackage, failures map[string]error) {
+	queue := make(chan lambdaPackageJob)
+	var mutex sync.Mutex
+	var workers sync.WaitGroup
+
+	for i := 0; i < min(p.config.Concurrency, len(jobs)); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range queue {
+				pkg, err := p.packageLambda(job.name, job.dir, job.excludePatterns, uploadOptions)
+
+				mutex.Lock()
+				if err != nil {
+					p.logger.WithError(err).WithField("lambda", job.name).Error("Failed to package Lambda")
+					failures[job.name] = fmt.Errorf("failed to package Lambda %s: %w", job.name, err)
+				} else {
+					packages[job.name] = pkg
+					p.logger.WithFields(logrus.Fields{
+						"lambda": job.name,
+						"size":   pkg.Size,
+						"s3_uri": pkg.S3URI,
+					}).Info("Successfully packaged Lambda")
+				}
+				mutex.Unlock()
 			}
-		}
-
-		return nil
-	})
+		}()
+	}
 
-	if err != nil {
-		return "", fmt.Errorf("error walking directory: %w", err)
+	for _, job := range jobs {
+		queue <- job
 	}
+	close(queue)
+	workers.Wait()
+}
 
-	if lambdaDir == "" {
-		return "", fmt.Errorf("lambda directory not found for %s", lambdaName)
+// packageFailuresError joins the failures of several Lambdas, sorted by Lambda name
+func packageFailuresError(failures map[string]error) error {
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	return lambdaDir, nil
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = failures[name]
+	}
+	return errors.Join(errs...)
 }
 
-// isTargetLambda checks if a lambda.yml file corresponds to the target Lambda
-func (p *LambdaPackager) isTargetLambda(yamlPath, targetName string) bool {
-	// This is a simplified check - in a real implementation,
-	// we'd parse the YAML and check the metadata.name field
-	dir := filepath.Dir(yamlPath)
-	dirName := filepath.Base(dir)
-
-	// Check if directory name matches Lambda name
-	return strings.EqualFold(dirName, targetName) || strings.EqualFold(dirName, strings.ReplaceAll(targetName, "_", "-"))
+// findLambdaDirectory locates the directory containing the Lambda code: the one holding the YAML file
+// the registry parsed the Lambda from, so templated names resolve as they do everywhere else
+func (p *LambdaPackager) findLambdaDirectory(lambdaName string) (string, error) {
+	resource, exists := p.registry.GetResource(models.LambdaKind, lambdaName)
+	if !exists {
+		return "", fmt.Errorf("%w for %s", errLambdaDirectoryNotFound, lambdaName)
+	}
+	return filepath.Dir(resource.FilePath), nil
 }
 
 // packageLambda creates a ZIP package of the Lambda function
-func (p *LambdaPackager) packageLambda(lambdaName, lambdaDir string) (*LambdaPackage, error) {
+func (p *LambdaPackager) packageLambda(lambdaName, lambdaDir string, excludePatterns []string, uploadOptions UploadOptions) (*LambdaPackage, error) {
 	p.logger.WithFields(logrus.Fields{
 		"lambda": lambdaName,
 		"dir":    lambdaDir,
 	}).Debug("Packaging Lambda function")
 
-	// Create temp directory for packaging
-	tempDir := filepath.Join(p.config.TempDir, lambdaName)
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
+	// Create a temp directory of its own, Lambdas are packaged concurrently
+	if err := os.MkdirAll(p.config.TempDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	tempDir, err := os.MkdirTemp(p.config.TempDir, "lambda-")
+	if err != nil {
 		return nil, fmt.Errorf("failed to create temp directory: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
@@ -192,7 +263,7 @@ func (p *LambdaPackager) packageLambda(lambdaName, lambdaDir string) (*LambdaPac
 	defer zipWriter.Close()
 
 	// Add files to ZIP
-	err = p.addDirectoryToZip(zipWriter, lambdaDir, "")
+	err = p.addDirectoryToZip(zipWriter, lambdaDir, "", excludePatterns)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add files to ZIP: %w", err)
 	}
@@ -218,7 +289,9 @@ func (p *LambdaPackager) packageLambda(lambdaName, lambdaDir string) (*LambdaPac
 	s3Key := p.generateS3Key(lambdaName, hash)
 
 	// Upload to S3
-	s3URI, err := p.s3Client.UploadFile(p.config.S3Bucket, s3Key, zipPath)
+	s3URI, err := p.config.Retry.withRetry(p.logger, p.config.S3Bucket, s3Key, func() (string, error) {
+		return p.s3Client.UploadFileWithOptions(p.config.S3Bucket, s3Key, zipPath, uploadOptions)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload to S3: %w", err)
 	}
@@ -235,7 +308,7 @@ func (p *LambdaPackager) packageLambda(lambdaName, lambdaDir string) (*LambdaPac
 }
 
 // addDirectoryToZip recursively adds directory contents to ZIP
-func (p *LambdaPackager) addDirectoryToZip(zipWriter *zip.Writer, sourceDir, basePath string) error {
+func (p *LambdaPackager) addDirectoryToZip(zipWriter *zip.Writer, sourceDir, basePath string, excludePatterns []string) error {
 	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -248,7 +321,7 @@ func (p *LambdaPackager) addDirectoryToZip(zipWriter *zip.Writer, sourceDir, bas
 		}
 
 		// Skip excluded files
-		if p.shouldExcludeFile(relPath, info) {
+		if p.shouldExcludeFile(relPath, info, excludePatterns) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -281,18 +354,38 @@ func (p *LambdaPackager) addDirectoryToZip(zipWriter *zip.Writer, sourceDir, bas
 	})
 }
 
-// shouldExcludeFile checks if a file should be excluded from packaging
-func (p *LambdaPackager) shouldExcludeFile(relPath string, info os.FileInfo) bool {
+// mergeExcludePatterns combines the global exclude patterns with a Lambda's own patterns.
+// Per-Lambda patterns are additive and never remove a global pattern.
+func (p *LambdaPackager) mergeExcludePatterns(lambdaPatterns []string) []string {
+	patterns := make([]string, 0, len(p.config.ExcludePatterns)+len(lambdaPatterns))
+	patterns = append(patterns, p.config.ExcludePatterns...)
+	return append(patterns, lambdaPatterns...)
+}
+
+// shouldExcludeFile checks if a file should be excluded from packaging.
+// Patterns without a slash match the file name at any depth (e.g. "*.pyc", "__pycache__"),
+// patterns with a slash match the path relative to the Lambda directory and support "**"
+// for any number of directories (e.g. "**/test/*", "build/**"). A trailing slash is ignored,
+// so "tests/" behaves like "tests".
+func (p *LambdaPackager) shouldExcludeFile(relPath string, info os.FileInfo, excludePatterns []string) bool {
 	fileName := info.Name()
+	slashPath := filepath.ToSlash(relPath)
+
+	for _, pattern := range excludePatterns {
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
 
-	for _, pattern := range p.config.ExcludePatterns {
-		// Simple pattern matching (could be enhanced with glob patterns)
-		if strings.HasSuffix(pattern, "*") {
-			prefix := strings.TrimSuffix(pattern, "*")
-			if strings.HasPrefix(fileName, prefix) {
+		if !strings.Contains(pattern, "/") {
+			if matched, err := path.Match(pattern, fileName); err == nil && matched {
 				return true
 			}
-		} else if pattern == fileName || pattern == relPath {
+			continue
+		}
+
+		if matchGlobSegments(strings.Split(pattern, "/"), strings.Split(slashPath, "/")) {
 			return true
 		}
 	}
@@ -300,6 +393,44 @@ func (p *LambdaPackager) shouldExcludeFile(relPath string, info os.FileInfo) boo
 	return false
 }
 
+// matchGlobSegments matches path segments against pattern segments, where "**" matches zero or more segments
+func matchGlobSegments(patternSegments, pathSegments []string) bool {
+	for len(patternSegments) > 0 {
+		segment := patternSegments[0]
+
+		if segment == "**" {
+			// Collapse consecutive "**" segments
+			rest := patternSegments[1:]
+			for len(rest) > 0 && rest[0] == "**" {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(pathSegments); i++ {
+				if matchGlobSegments(rest, pathSegments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(pathSegments) == 0 {
+			return false
+		}
+
+		matched, err := path.Match(segment, pathSegments[0])
+		if err != nil || !matched {
+			return false
+		}
+
+		patternSegments = patternSegments[1:]
+		pathSegments = pathSegments[1:]
+	}
+
+	return len(pathSegments) == 0
+}
+
 // calculateFileHash calculates SHA256 hash of a file
 func (p *LambdaPackager) calculateFileHash(filePath string) (string, error) {
 	file, err := os.Open(filePath)
@@ -0,0 +1,53 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeFileInfo is the os.FileInfo of a file or directory that doesn't exist on disk
+type fakeFileInfo struct {
+	name string
+	dir  bool
+}
+
+func (f fakeFileInfo) Name() string       { return f.name }
+func (f fakeFileInfo) Size() int64        { return 0 }
+func (f fakeFileInfo) Mode() os.FileMode  { return 0644 }
+func (f fakeFileInfo) ModTime() time.Time { return time.Time{} }
+func (f fakeFileInfo) IsDir() bool        { return f.dir }
+func (f fakeFileInfo) Sys() interface{}   { return nil }
+
+func TestShouldExcludeFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		relPath  string
+		dir      bool
+		patterns []string
+		excluded bool
+	}{
+		{name: "pyc at the root", relPath: "handler.pyc", patterns: []string{"*.pyc"}, excluded: true},
+		{name: "pyc in a package", relPath: filepath.Join("orders", "models", "order.pyc"), patterns: []string{"*.pyc"}, excluded: true},
+		{name: "py kept by pyc pattern", relPath: filepath.Join("orders", "order.py"), patterns: []string{"*.pyc"}, excluded: false},
+		{name: "nested pycache directory", relPath: filepath.Join("orders", "models", "__pycache__"), dir: true, patterns: []string{"__pycache__"}, excluded: true},
+		{name: "pycache with trailing slash", relPath: filepath.Join("orders", "__pycache__"), dir: true, patterns: []string{"__pycache__/"}, excluded: true},
+		{name: "anchored pattern in its directory", relPath: filepath.Join("src", "tests", "test_orders.py"), patterns: []string{"src/tests/*"}, excluded: true},
+		{name: "anchored pattern in another directory", relPath: filepath.Join("lib", "src", "tests", "test_orders.py"), patterns: []string{"src/tests/*"}, excluded: false},
+		{name: "anchored pattern with ./ prefix", relPath: filepath.Join("build", "out.js"), patterns: []string{"./build/*"}, excluded: true},
+		{name: "double star under a directory", relPath: filepath.Join("build", "cache", "deep", "out.js"), patterns: []string{"build/**"}, excluded: true},
+		{name: "double star at any depth", relPath: filepath.Join("a", "b", "test", "fixture.json"), patterns: []string{"**/test/*"}, excluded: true},
+		{name: "no patterns", relPath: "handler.py", excluded: false},
+	}
+
+	packager := &LambdaPackager{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info := fakeFileInfo{name: filepath.Base(test.relPath), dir: test.dir}
+			if got := packager.shouldExcludeFile(test.relPath, info, test.patterns); got != test.excluded {
+				t.Errorf("shouldExcludeFile(%q, %v) = %v, want %v", test.relPath, test.patterns, got, test.excluded)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package packager_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/packager"
+	"bedrock-forge/internal/parser"
+	"bedrock-forge/internal/registry"
+	"bedrock-forge/internal/testutil"
+)
+
+func discardLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// writeLambda writes a directory-based Lambda with its lambda.yml and source files under baseDir
+func writeLambda(t testing.TB, baseDir, name, excludePatterns string, files map[string]string) {
+	t.Helper()
+
+	dir := filepath.Join(baseDir, name)
+	definition := fmt.Sprintf(`kind: Lambda
+metadata:
+  name: %s
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    source: directory
+%s`, name, excludePatterns)
+	files["lambda.yml"] = definition
+
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// loadRegistry parses every lambda.yml under baseDir into a registry
+func loadRegistry(t testing.TB, baseDir string) *registry.ResourceRegistry {
+	t.Helper()
+
+	logger := discardLogger()
+	resourceRegistry := registry.NewResourceRegistry(logger)
+	yamlParser := parser.NewYAMLParser(logger)
+
+	paths, err := filepath.Glob(filepath.Join(baseDir, "*", "lambda.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		resources, err := yamlParser.ParseFile(path)
+		if err != nil {
+			t.Fatalf("ParseFile %s: %v", path, err)
+		}
+		for _, resource := range resources {
+			if err := resourceRegistry.AddResource(resource); err != nil {
+				t.Fatalf("AddResource: %v", err)
+			}
+		}
+	}
+	return resourceRegistry
+}
+
+// zipEntries returns the sorted file names in a ZIP archive
+func zipEntries(t testing.TB, content []byte) []string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("invalid ZIP: %v", err)
+	}
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPackageAllLambdasMergesExcludePatterns(t *testing.T) {
+	baseDir := t.TempDir()
+	writeLambda(t, baseDir, "order-lookup", "    excludePatterns: [\"tests/\", \"*.log\"]\n", map[string]string{
+		"app.py":                            "def handler(event, context): return event",
+		"orders/__init__.py":                "",
+		"orders/__pycache__/orders.cpython": "bytecode", // Global pattern
+		"orders/models.pyc":                 "bytecode", // Global pattern
+		"tests/test_app.py":                 "def test(): pass",
+		"debug.log":                         "log",
+	})
+
+	s3Client := testutil.NewMockS3Client()
+	lambdaPackager := packager.NewLambdaPackager(discardLogger(), loadRegistry(t, baseDir), s3Client, &packager.PackagerConfig{
+		S3Bucket: "artifacts",
+		TempDir:  t.TempDir(),
+		ExcludePatterns: []string{
+			"*.yml", "__pycache__", "*.pyc",
+		},
+	})
+
+	packages, err := lambdaPackager.PackageAllLambdas()
+	if err != nil {
+		t.Fatalf("PackageAllLambdas: %v", err)
+	}
+
+	upload := s3Client.AssertUploaded(t, packages["order-lookup"].S3Key)
+	want := []string{"app.py", "orders/__init__.py"}
+	if got := zipEntries(t, upload.Content); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("package contains %v, want %v: global and per-Lambda patterns must both apply", got, want)
+	}
+}
+
+func TestPackageAllLambdasUsesRegistryDirectory(t *testing.T) {
+	baseDir := t.TempDir()
+	files := map[string]string{
+		filepath.Join("order-lookup", "lambda.yml"): `kind: Lambda
+metadata:
+  name: order-lookup-${{ .environment }}
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    source: directory
+`,
+		filepath.Join("order-lookup", "app.py"): "def handler(event, context): return event",
+	}
+	for path, content := range files {
+		path = filepath.Join(baseDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := discardLogger()
+	resourceRegistry := registry.NewResourceRegistry(logger)
+	yamlParser := parser.NewYAMLParser(logger)
+	yamlParser.SetTemplateVars(map[string]interface{}{"environment": "prod"})
+	for path := range files {
+		if filepath.Ext(path) != ".yml" {
+			continue
+		}
+		resources, err := yamlParser.ParseFile(filepath.Join(baseDir, path))
+		if err != nil {
+			t.Fatalf("ParseFile %s: %v", path, err)
+		}
+		for _, resource := range resources {
+			if err := resourceRegistry.AddResource(resource); err != nil {
+				t.Fatalf("AddResource: %v", err)
+			}
+		}
+	}
+
+	s3Client := testutil.NewMockS3Client()
+	lambdaPackager := packager.NewLambdaPackager(logger, resourceRegistry, s3Client, &packager.PackagerConfig{
+		S3Bucket: "artifacts",
+		TempDir:  t.TempDir(),
+	})
+
+	packages, err := lambdaPackager.PackageAllLambdas()
+	if err != nil {
+		t.Fatalf("PackageAllLambdas: %v", err)
+	}
+
+	if len(packages) != 1 || packages["order-lookup-prod"] == nil {
+		t.Fatalf("packaged %v, want order-lookup-prod", packages)
+	}
+	upload := s3Client.AssertUploaded(t, packages["order-lookup-prod"].S3Key)
+	if got := zipEntries(t, upload.Content); fmt.Sprint(got) != "[app.py]" {
+		t.Errorf("package contains %v, want [app.py]", got)
+	}
+}
+
+func TestPackageAllLambdasRetriesUploads(t *testing.T) {
+	forbidden := &packager.S3UploadError{
+		Bucket:     "artifacts",
+		StatusCode: http.StatusForbidden,
+		Status:     "403 Forbidden",
+		Message:    "Access Denied",
+	}
+
+	tests := []struct {
+		name     string
+		failures map[int]error // Call number -> error, nil for a 503
+		calls    int
+		uploaded bool
+	}{
+		{
+			name:     "no failures",
+			calls:    1,
+			uploaded: true,
+		},
+		{
+			name:     "transient failure retried",
+			failures: map[int]error{1: nil},
+			calls:    2,
+			uploaded: true,
+		},
+		{
+			name:     "failure on the last allowed attempt",
+			failures: map[int]error{1: nil, 2: nil},
+			calls:    3,
+			uploaded: true,
+		},
+		{
+			name:     "attempts exhausted",
+			failures: map[int]error{1: nil, 2: nil, 3: nil},
+			calls:    3,
+		},
+		{
+			name:     "permanent failure not retried",
+			failures: map[int]error{1: forbidden},
+			calls:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			writeLambda(t, baseDir, "order-lookup", "", map[string]string{
+				"app.py": "def handler(event, context): return event",
+			})
+
+			s3Client := testutil.NewMockS3Client()
+			for call, err := range test.failures {
+				s3Client.FailOnCall(call, err)
+			}
+			lambdaPackager := packager.NewLambdaPackager(discardLogger(), loadRegistry(t, baseDir), s3Client, &packager.PackagerConfig{
+				S3Bucket: "artifacts",
+				TempDir:  t.TempDir(),
+				Retry:    packager.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			})
+
+			packages, err := lambdaPackager.PackageAllLambdas()
+			if calls := s3Client.Calls(); calls != test.calls {
+				t.Errorf("made %d upload calls, want %d", calls, test.calls)
+			}
+
+			if !test.uploaded {
+				var uploadErr *packager.S3UploadError
+				if !errors.As(err, &uploadErr) {
+					t.Fatalf("PackageAllLambdas() = %v, want an S3UploadError", err)
+				}
+				if _, ok := packages["order-lookup"]; ok {
+					t.Errorf("failed Lambda was reported as packaged")
+				}
+				s3Client.AssertUploadCount(t, 0)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("PackageAllLambdas: %v", err)
+			}
+			s3Client.AssertUploadCount(t, 1)
+			s3Client.AssertUploaded(t, packages["order-lookup"].S3Key)
+		})
+	}
+}
+
+// slowS3Client adds a fixed latency to each file upload, standing in for the network round trip that
+// concurrent packaging overlaps
+type slowS3Client struct {
+	*testutil.MockS3Client
+	latency time.Duration
+}
+
+func (c *slowS3Client) UploadFileWithOptions(bucket, key string, filePath string, options packager.UploadOptions) (string, error) {
+	time.Sleep(c.latency)
+	return c.MockS3Client.UploadFileWithOptions(bucket, key, filePath, options)
+}
+
+func BenchmarkPackageAllLambdas(b *testing.B) {
+	const lambdaCount = 16
+
+	baseDir := b.TempDir()
+	for i := 0; i < lambdaCount; i++ {
+		files := map[string]string{
+			"app.py": "def handler(event, context):\n    return event\n",
+		}
+		for j := 0; j < 20; j++ {
+			files[fmt.Sprintf("lib/module_%02d.py", j)] = strings.Repeat(fmt.Sprintf("VALUE_%d = %d\n", j, j), 500)
+		}
+		writeLambda(b, baseDir, fmt.Sprintf("function-%02d", i), "", files)
+	}
+	resourceRegistry := loadRegistry(b, baseDir)
+
+	levels := []int{1, 4}
+	if runtime.NumCPU() > 4 {
+		levels = append(levels, runtime.NumCPU())
+	}
+
+	for _, concurrency := range levels {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			s3Client := &slowS3Client{MockS3Client: testutil.NewMockS3Client(), latency: 10 * time.Millisecond}
+			lambdaPackager := packager.NewLambdaPackager(discardLogger(), resourceRegistry, s3Client, &packager.PackagerConfig{
+				S3Bucket:    "artifacts",
+				TempDir:     b.TempDir(),
+				Concurrency: concurrency,
+			})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				packages, err := lambdaPackager.PackageAllLambdas()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(packages) != lambdaCount {
+					b.Fatalf("packaged %d Lambdas, want %d", len(packages), lambdaCount)
+				}
+			}
+		})
+	}
+}
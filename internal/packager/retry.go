@@ -0,0 +1,96 @@
+package packager
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetryPolicy controls how transient S3 upload failures are retried. The zero value uses
+// DefaultRetryPolicy; set MaxAttempts to 1 to disable retries.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts, including the first
+	BaseDelay   time.Duration // Delay before the first retry, doubled for every further retry
+	MaxDelay    time.Duration // Upper bound for a single delay
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// S3UploadError is returned when S3 rejects an upload with a non-success status
+type S3UploadError struct {
+	Bucket     string
+	Key        string
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *S3UploadError) Error() string {
+	return fmt.Sprintf("failed to upload s3://%s/%s: %s: %s", e.Bucket, e.Key, e.Status, e.Message)
+}
+
+// Retryable reports whether the upload may succeed if repeated, i.e. on throttling and server errors
+func (e *S3UploadError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// isRetryableUploadError reports whether an upload error is transient
+func isRetryableUploadError(err error) bool {
+	var uploadErr *S3UploadError
+	if errors.As(err, &uploadErr) {
+		return uploadErr.Retryable()
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// withRetry calls upload until it succeeds, fails with a permanent error or runs out of attempts
+func (p RetryPolicy) withRetry(logger *logrus.Logger, bucket, key string, upload func() (string, error)) (string, error) {
+	if p.MaxAttempts == 0 {
+		p = DefaultRetryPolicy()
+	}
+
+	for attempt := 1; ; attempt++ {
+		s3URI, err := upload()
+		if err == nil || attempt >= p.MaxAttempts || !isRetryableUploadError(err) {
+			return s3URI, err
+		}
+
+		delay := p.backoff(attempt)
+		logger.WithError(err).WithFields(logrus.Fields{
+			"bucket":  bucket,
+			"key":     key,
+			"attempt": attempt,
+			"delay":   delay,
+		}).Debug("Retrying S3 upload")
+
+		time.Sleep(delay)
+	}
+}
+
+// backoff returns the delay after the given attempt: exponential, capped, with equal jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
@@ -1,23 +1,35 @@
 package packager
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 
 	"bedrock-forge/internal/models"
 	"bedrock-forge/internal/registry"
 )
 
+// errSchemaNotFound is returned when an ActionGroup directory has no manual schema file
+var errSchemaNotFound = errors.New("no manual schema file found")
+
 // SchemaExtractor handles OpenAPI schema extraction and uploading
 type SchemaExtractor struct {
 	logger   *logrus.Logger
 	registry *registry.ResourceRegistry
 	s3Client S3Client
 	config   *PackagerConfig
+
+	// reused are packages from an earlier run kept instead of extracting their schemas again
+	reused map[string]*SchemaPackage
 }
 
 // SchemaPackage represents an OpenAPI schema package
@@ -28,6 +40,7 @@ type SchemaPackage struct {
 	S3Bucket    string
 	S3Key       string
 	S3URI       string
+	Hash        string // SHA256 of the schema content
 	Source      string // "manual", "fastapi", "chalice"
 }
 
@@ -41,6 +54,12 @@ func NewSchemaExtractor(logger *logrus.Logger, registry *registry.ResourceRegist
 	}
 }
 
+// ReusePackages keeps schema packages from an earlier run, by ActionGroup name, instead of extracting
+// those schemas again
+func (e *SchemaExtractor) ReusePackages(packages map[string]*SchemaPackage) {
+	e.reused = packages
+}
+
 // ExtractAllSchemas discovers and processes all OpenAPI schemas
 func (e *SchemaExtractor) ExtractAllSchemas(baseDir string) (map[string]*SchemaPackage, error) {
 	e.logger.Info("Starting OpenAPI schema extraction...")
@@ -63,6 +82,18 @@ func (e *SchemaExtractor) ExtractAllSchemas(baseDir string) (map[string]*SchemaP
 			continue
 		}
 
+		// Managed schemas are published by another pipeline and referenced as-is
+		if actionGroupSpec.APISchema.IsManaged() {
+			e.logger.WithField("action_group", actionGroup.Metadata.Name).Debug("ActionGroup uses a managed S3 schema, skipping extraction")
+			continue
+		}
+
+		if pkg, ok := e.reused[actionGroup.Metadata.Name]; ok {
+			packages[actionGroup.Metadata.Name] = pkg
+			e.logger.WithField("action_group", actionGroup.Metadata.Name).Info("ActionGroup is unchanged, reusing its schema package")
+			continue
+		}
+
 		// Find action group directory
 		actionGroupDir, err := e.findActionGroupDirectory(baseDir, actionGroup.Metadata.Name)
 		if err != nil {
@@ -71,8 +102,12 @@ func (e *SchemaExtractor) ExtractAllSchemas(baseDir string) (map[string]*SchemaP
 		}
 
 		// Extract schema
-		pkg, err := e.extractSchema(actionGroup.Metadata.Name, actionGroupDir)
+		pkg, err := e.extractSchema(actionGroup.Metadata.Name, actionGroupDir, actionGroupSpec.APISchema.SchemaFiles)
 		if err != nil {
+			// A present but malformed schema would only fail once the agent uses it
+			if !errors.Is(err, errSchemaNotFound) {
+				return nil, fmt.Errorf("failed to package schema for ActionGroup %s: %w", actionGroup.Metadata.Name, err)
+			}
 			e.logger.WithError(err).WithField("action_group", actionGroup.Metadata.Name).Error("Failed to extract schema")
 			continue
 		}
@@ -133,53 +168,156 @@ func (e *SchemaExtractor) isTargetActionGroup(yamlPath, targetName string) bool
 }
 
 // extractSchema extracts OpenAPI schema from manual files only
-func (e *SchemaExtractor) extractSchema(actionGroupName, actionGroupDir string) (*SchemaPackage, error) {
+func (e *SchemaExtractor) extractSchema(actionGroupName, actionGroupDir string, schemaFiles []string) (*SchemaPackage, error) {
 	e.logger.WithFields(logrus.Fields{
 		"action_group": actionGroupName,
 		"dir":          actionGroupDir,
 	}).Debug("Extracting OpenAPI schema")
 
 	// Only support manual OpenAPI schema files
-	if schema, err := e.extractManualSchema(actionGroupDir); err == nil {
-		return e.packageSchema(actionGroupName, schema, "manual")
+	schema, err := e.extractManualSchema(actionGroupDir, schemaFiles)
+	if err != nil {
+		if errors.Is(err, errSchemaNotFound) {
+			return nil, fmt.Errorf("no manual OpenAPI schema found for ActionGroup %s: %w", actionGroupName, err)
+		}
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("no manual OpenAPI schema found for ActionGroup %s", actionGroupName)
+	return e.packageSchema(actionGroupName, schema, "manual")
 }
 
-// extractManualSchema reads manually created OpenAPI schema files
-func (e *SchemaExtractor) extractManualSchema(dir string) ([]byte, error) {
+// extractManualSchema reads manually created OpenAPI schema files. Explicit schemaFiles are merged
+// into one document; otherwise the conventional schema file is merged with any fragments in the
+// schemas directory.
+func (e *SchemaExtractor) extractManualSchema(dir string, schemaFiles []string) ([]byte, error) {
+	if len(schemaFiles) > 0 {
+		files, err := resolveSchemaFiles(dir, schemaFiles)
+		if err != nil {
+			return nil, err
+		}
+		return e.mergeSchemaFiles(files)
+	}
+
+	var files []string
+
 	// Look for common OpenAPI schema file names
-	schemaFiles := []string{
+	conventionalFiles := []string{
 		"openapi.json", "openapi.yaml", "openapi.yml",
 		"schema.json", "schema.yaml", "schema.yml",
 		"api.json", "api.yaml", "api.yml",
 	}
 
-	for _, fileName := range schemaFiles {
+	for _, fileName := range conventionalFiles {
 		filePath := filepath.Join(dir, fileName)
 		if _, err := os.Stat(filePath); err == nil {
-			content, err := os.ReadFile(filePath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read schema file %s: %w", filePath, err)
-			}
+			files = append(files, filePath)
+			break
+		}
+	}
 
-			// If it's YAML, we should convert to JSON for consistency
-			// For now, we'll just return the content as-is
-			return content, nil
+	fragments, err := findSchemaFragments(dir)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, fragments...)
+
+	if len(files) == 0 {
+		return nil, errSchemaNotFound
+	}
+	return e.mergeSchemaFiles(files)
+}
+
+// mergeSchemaFiles reads a single schema file as is, or merges several into one JSON document
+func (e *SchemaExtractor) mergeSchemaFiles(files []string) ([]byte, error) {
+	if len(files) == 1 {
+		return readSchemaFile(files[0])
+	}
+
+	e.logger.WithField("files", files).Debug("Merging OpenAPI schema fragments")
+
+	fragments := make([]*schemaFragment, 0, len(files))
+	for _, filePath := range files {
+		fragment, err := loadSchemaFragment(filePath)
+		if err != nil {
+			return nil, err
 		}
+		fragments = append(fragments, fragment)
 	}
 
-	return nil, fmt.Errorf("no manual schema file found")
+	schema, err := mergeSchemaFragments(fragments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge schema files: %w", err)
+	}
+	if err := validateOpenAPISchema(schema); err != nil {
+		return nil, fmt.Errorf("invalid merged OpenAPI schema from %s: %w", strings.Join(files, ", "), err)
+	}
+	return schema, nil
+}
+
+// readSchemaFile reads and validates a complete schema file, converting YAML to JSON
+func readSchemaFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file %s: %w", filePath, err)
+	}
+
+	if err := validateOpenAPISchema(content); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI schema %s: %w", filePath, err)
+	}
+
+	// Bedrock expects JSON schemas, so YAML schemas are converted
+	if ext := filepath.Ext(filePath); ext == ".yaml" || ext == ".yml" {
+		jsonContent, err := yamlToJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert schema file %s to JSON: %w", filePath, err)
+		}
+		return jsonContent, nil
+	}
+
+	return content, nil
+}
+
+// validateOpenAPISchema checks that content is a JSON or YAML OpenAPI document with at least one path
+func validateOpenAPISchema(content []byte) error {
+	// YAML is a superset of JSON, so one parser handles both formats
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("failed to parse schema as JSON or YAML: %w", err)
+	}
+	if document == nil {
+		return fmt.Errorf("schema is empty")
+	}
+
+	_, hasOpenAPI := document["openapi"]
+	_, hasSwagger := document["swagger"]
+	if !hasOpenAPI && !hasSwagger {
+		return fmt.Errorf("schema must declare an 'openapi' or 'swagger' version field")
+	}
+
+	paths, ok := document["paths"].(map[string]interface{})
+	if !ok || len(paths) == 0 {
+		return fmt.Errorf("schema must define at least one path under 'paths'")
+	}
+
+	for path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path '%s' must start with '/'", path)
+		}
+	}
+
+	return nil
 }
 
 // packageSchema packages and uploads a schema to S3
 func (e *SchemaExtractor) packageSchema(actionGroupName string, schema []byte, source string) (*SchemaPackage, error) {
-	// Generate S3 key
-	s3Key := fmt.Sprintf("%s/schemas/%s/openapi.json", e.config.S3KeyPrefix, actionGroupName)
+	// Generate a content-addressed S3 key so changed schemas never overwrite deployed ones
+	hash := sha256Hex(schema)
+	s3Key := e.generateS3Key(actionGroupName, hash)
 
 	// Upload to S3
-	s3URI, err := e.s3Client.UploadContent(e.config.S3Bucket, s3Key, schema, "application/json")
+	s3URI, err := e.config.Retry.withRetry(e.logger, e.config.S3Bucket, s3Key, func() (string, error) {
+		return e.s3Client.UploadContent(e.config.S3Bucket, s3Key, schema, "application/json")
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload schema to S3: %w", err)
 	}
@@ -191,6 +329,106 @@ func (e *SchemaExtractor) packageSchema(actionGroupName string, schema []byte, s
 		S3Bucket:    e.config.S3Bucket,
 		S3Key:       s3Key,
 		S3URI:       s3URI,
+		Hash:        hash,
 		Source:      source,
 	}, nil
 }
+
+// generateS3Key creates a content-addressed S3 key for the schema
+func (e *SchemaExtractor) generateS3Key(actionGroupName, hash string) string {
+	return fmt.Sprintf("%s/schemas/%s/openapi-%s.json",
+		e.config.S3KeyPrefix, actionGroupName, hash[:16])
+}
+
+// yamlToJSON converts a YAML document to indented JSON, preserving the order of mapping keys
+func yamlToJSON(content []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return nodeToJSON(&document)
+}
+
+// nodeToJSON converts a YAML node to indented JSON
+func nodeToJSON(node *yaml.Node) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writeJSONNode(&buffer, node); err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buffer.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %w", err)
+	}
+	indented.WriteString("\n")
+
+	return indented.Bytes(), nil
+}
+
+// writeJSONNode writes a YAML node as compact JSON
+func writeJSONNode(buffer *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buffer.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buffer, node.Content[0])
+
+	case yaml.AliasNode:
+		return writeJSONNode(buffer, node.Alias)
+
+	case yaml.MappingNode:
+		buffer.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			// JSON keys are always strings, e.g. response codes like 200
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buffer.Write(key)
+			buffer.WriteByte(':')
+			if err := writeJSONNode(buffer, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+
+	case yaml.SequenceNode:
+		buffer.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSONNode(buffer, item); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("failed to decode value at line %d: %w", node.Line, err)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode value at line %d: %w", node.Line, err)
+		}
+		buffer.Write(encoded)
+
+	default:
+		return fmt.Errorf("unsupported YAML node at line %d", node.Line)
+	}
+
+	return nil
+}
+
+// sha256Hex returns the hex-encoded SHA256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,196 @@
+package packager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaFragmentsDir is the directory next to action-group.yml whose schema files are merged into
+// the uploaded document
+const schemaFragmentsDir = "schemas"
+
+// schemaFragment is a parsed schema file, which may hold only part of a document
+type schemaFragment struct {
+	path string
+	root *yaml.Node // Top-level mapping
+}
+
+// resolveSchemaFiles expands schemaFiles patterns relative to the ActionGroup directory. Each pattern
+// must match at least one file.
+func resolveSchemaFiles(dir string, patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema file pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("schema file %s not found in %s", pattern, dir)
+		}
+
+		sort.Strings(matches)
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// findSchemaFragments returns the JSON and YAML files in the schemas directory, sorted by name
+func findSchemaFragments(dir string) ([]string, error) {
+	fragmentsDir := filepath.Join(dir, schemaFragmentsDir)
+	entries, err := os.ReadDir(fragmentsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema fragments directory %s: %w", fragmentsDir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(fragmentsDir, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// loadSchemaFragment parses a JSON or YAML schema file
+func loadSchemaFragment(filePath string) (*schemaFragment, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file %s: %w", filePath, err)
+	}
+
+	// YAML is a superset of JSON, so one parser handles both formats
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file %s as JSON or YAML: %w", filePath, err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("schema file %s must contain a mapping", filePath)
+	}
+
+	return &schemaFragment{path: filePath, root: document.Content[0]}, nil
+}
+
+// mergeSchemaFragments merges fragments into one JSON document, keeping the order they define keys in.
+// Paths and components are unioned. A path, component or other top-level field that two fragments
+// define differently is an error; identical definitions are allowed.
+func mergeSchemaFragments(fragments []*schemaFragment) ([]byte, error) {
+	merged := &yaml.Node{Kind: yaml.MappingNode}
+	origins := make(map[string]string)
+
+	for _, fragment := range fragments {
+		for i := 0; i+1 < len(fragment.root.Content); i += 2 {
+			key, value := fragment.root.Content[i], fragment.root.Content[i+1]
+
+			var err error
+			switch key.Value {
+			case "paths":
+				err = mergeSchemaSection(merged, "paths", value, "path ", fragment.path, origins)
+
+			case "components":
+				if value.Kind != yaml.MappingNode {
+					return nil, fmt.Errorf("'components' in %s must be a mapping", fragment.path)
+				}
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					section := value.Content[j].Value
+					components := mappingSection(merged, "components")
+					err = mergeSchemaSection(components, section, value.Content[j+1], fmt.Sprintf("components.%s ", section), fragment.path, origins)
+					if err != nil {
+						break
+					}
+				}
+
+			default:
+				err = mergeSchemaEntry(merged, key, value, fmt.Sprintf("'%s'", key.Value), fragment.path, origins)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nodeToJSON(merged)
+}
+
+// mergeSchemaSection adds the entries of a source mapping to the named section of target
+func mergeSchemaSection(target *yaml.Node, name string, source *yaml.Node, label, file string, origins map[string]string) error {
+	if source.Kind != yaml.MappingNode {
+		return fmt.Errorf("'%s' in %s must be a mapping", name, file)
+	}
+
+	section := mappingSection(target, name)
+	for i := 0; i+1 < len(source.Content); i += 2 {
+		key := source.Content[i]
+		if err := mergeSchemaEntry(section, key, source.Content[i+1], label+key.Value, file, origins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSchemaEntry adds a key to target unless an identical definition is already there
+func mergeSchemaEntry(target, key, value *yaml.Node, label, file string, origins map[string]string) error {
+	if existing := mappingValue(target, key.Value); existing != nil {
+		if !sameSchemaNodes(existing, value) {
+			return fmt.Errorf("%s is defined differently in %s and %s", label, origins[label], file)
+		}
+		return nil
+	}
+
+	target.Content = append(target.Content, key, value)
+	origins[label] = file
+	return nil
+}
+
+// mappingSection returns the mapping under key, adding an empty one if it doesn't exist
+func mappingSection(node *yaml.Node, key string) *yaml.Node {
+	if value := mappingValue(node, key); value != nil {
+		return value
+	}
+
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sameSchemaNodes reports whether two nodes hold the same data, regardless of formatting
+func sameSchemaNodes(a, b *yaml.Node) bool {
+	var left, right interface{}
+	if err := a.Decode(&left); err != nil {
+		return false
+	}
+	if err := b.Decode(&right); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
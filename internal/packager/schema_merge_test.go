@@ -0,0 +1,202 @@
+package packager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMergeSchemaFragments(t *testing.T) {
+	const ordersPath = `
+paths:
+  /orders/{id}:
+    get:
+      operationId: getOrder
+`
+	tests := []struct {
+		name      string
+		fragments map[string]string // File name -> content, merged in name order
+		wantPaths []string
+		wantErr   []string
+	}{
+		{
+			name: "disjoint paths",
+			fragments: map[string]string{
+				"a-orders.yaml":  ordersPath,
+				"b-refunds.json": `{"paths": {"/refunds": {"post": {"operationId": "createRefund"}}}}`,
+			},
+			wantPaths: []string{"/orders/{id}", "/refunds"},
+		},
+		{
+			name: "identical duplicate path",
+			fragments: map[string]string{
+				"a-orders.yaml": ordersPath,
+				// The same definition in JSON is the same data
+				"b-orders.json": `{"paths": {"/orders/{id}": {"get": {"operationId": "getOrder"}}}}`,
+			},
+			wantPaths: []string{"/orders/{id}"},
+		},
+		{
+			name: "conflicting path",
+			fragments: map[string]string{
+				"a-orders.yaml": ordersPath,
+				"b-orders.yaml": `
+paths:
+  /orders/{id}:
+    get:
+      operationId: fetchOrder
+`,
+			},
+			wantErr: []string{"path /orders/{id} is defined differently in", "a-orders.yaml and", "b-orders.yaml"},
+		},
+		{
+			name: "conflicting component",
+			fragments: map[string]string{
+				"a-order.yaml": `
+components:
+  schemas:
+    Order:
+      type: object
+`,
+				"b-order.yaml": `
+components:
+  schemas:
+    Order:
+      type: string
+`,
+			},
+			wantErr: []string{"components.schemas Order is defined differently in", "a-order.yaml and", "b-order.yaml"},
+		},
+		{
+			name: "conflicting top-level field",
+			fragments: map[string]string{
+				"a-info.yaml": "openapi: 3.0.0\n",
+				"b-info.yaml": "openapi: 3.1.0\n",
+			},
+			wantErr: []string{"'openapi' is defined differently in", "a-info.yaml and", "b-info.yaml"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var fragments []*schemaFragment
+			for _, name := range sortedKeys(test.fragments) {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte(test.fragments[name]), 0644); err != nil {
+					t.Fatal(err)
+				}
+				fragment, err := loadSchemaFragment(path)
+				if err != nil {
+					t.Fatalf("loadSchemaFragment: %v", err)
+				}
+				fragments = append(fragments, fragment)
+			}
+
+			merged, err := mergeSchemaFragments(fragments)
+			if test.wantErr != nil {
+				if err == nil {
+					t.Fatalf("mergeSchemaFragments() succeeded, want an error")
+				}
+				for _, want := range test.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("mergeSchemaFragments() = %v, want it to contain %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeSchemaFragments: %v", err)
+			}
+
+			var document struct {
+				Paths map[string]interface{} `json:"paths"`
+			}
+			if err := json.Unmarshal(merged, &document); err != nil {
+				t.Fatalf("merged schema isn't JSON: %v\n%s", err, merged)
+			}
+			if got := sortedKeys(document.Paths); !reflect.DeepEqual(got, test.wantPaths) {
+				t.Errorf("merged paths = %v, want %v", got, test.wantPaths)
+			}
+		})
+	}
+}
+
+func TestResolveSchemaFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"openapi.yaml", "paths/refunds.yaml", "paths/orders.yaml", "paths/customers.json"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("paths: {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "matches sorted within a pattern",
+			patterns: []string{"paths/*"},
+			want:     []string{"paths/customers.json", "paths/orders.yaml", "paths/refunds.yaml"},
+		},
+		{
+			name:     "patterns in the order given",
+			patterns: []string{"paths/*.yaml", "openapi.yaml"},
+			want:     []string{"paths/orders.yaml", "paths/refunds.yaml", "openapi.yaml"},
+		},
+		{
+			name:     "files matched twice are merged once",
+			patterns: []string{"openapi.yaml", "paths/orders.yaml", "*.yaml", "paths/*.yaml"},
+			want:     []string{"openapi.yaml", "paths/orders.yaml", "paths/refunds.yaml"},
+		},
+		{
+			name:     "pattern without matches",
+			patterns: []string{"openapi.yaml", "schemas/*.json"},
+			wantErr:  "schema file schemas/*.json not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := resolveSchemaFiles(dir, test.patterns)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("resolveSchemaFiles() = %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSchemaFiles: %v", err)
+			}
+
+			got := make([]string, len(files))
+			for i, file := range files {
+				relative, _ := filepath.Rel(dir, file)
+				got[i] = filepath.ToSlash(relative)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resolveSchemaFiles() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envPattern matches ${env:VAR_NAME} and ${env:VAR_NAME:-default}
+var envPattern = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// blockScalarPattern matches a line whose value starts a literal or folded block scalar
+var blockScalarPattern = regexp.MustCompile(`(^|[:-])[ \t]*[|>][0-9+-]*[ \t]*$`)
+
+// interpolateEnv substitutes environment variable references in the values of YAML content.
+// References in comments are left as they are, so commented-out settings don't need their
+// variables. Variables that are unset and have no default produce an error naming each variable.
+func interpolateEnv(content []byte, filePath string) ([]byte, error) {
+	var unresolved []string
+	substitute := func(text string) string {
+		return envPattern.ReplaceAllStringFunc(text, func(match string) string {
+			groups := envPattern.FindStringSubmatch(match)
+			name := groups[1]
+
+			if value, exists := os.LookupEnv(name); exists {
+				return value
+			}
+
+			// groups[2] is only non-empty when the ":-" fallback syntax is used
+			if len(groups[2]) > 0 {
+				return groups[3]
+			}
+
+			unresolved = append(unresolved, name)
+			return match
+		})
+	}
+
+	var result strings.Builder
+	var quote byte
+	// blockIndent is the indentation of the line that started the current block scalar, or -1
+	blockIndent := -1
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// Block scalar lines are values, even when they start with #
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indent > blockIndent {
+				result.WriteString(substitute(line))
+				continue
+			}
+			blockIndent = -1
+		}
+
+		value, comment := splitComment(line, &quote)
+		result.WriteString(substitute(value))
+		result.WriteString(comment)
+
+		if quote == 0 && blockScalarPattern.MatchString(strings.TrimRight(value, " \t\r\n")) {
+			blockIndent = indent
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved environment variable(s) %s in %s", strings.Join(unresolved, ", "), filePath)
+	}
+
+	return []byte(result.String()), nil
+}
+
+// splitComment splits a YAML line before its comment. quote holds the quote character of a
+// quoted scalar that continues from the previous line and is updated for the next one.
+func splitComment(line string, quote *byte) (string, string) {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case *quote == '"' && c == '\\':
+			i++
+		case *quote == '\'' && c == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case *quote != 0:
+			if c == *quote {
+				*quote = 0
+			}
+		case c == '"' || c == '\'':
+			// Quotes only start a scalar, so the apostrophe in "it's" doesn't open one
+			if i == 0 || strings.IndexByte(" \t[{,:-", line[i-1]) >= 0 {
+				*quote = c
+			}
+		case c == '#':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return line[:i], line[i:]
+			}
+		}
+	}
+	return line, ""
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("ACCOUNT_ID", "123456789012")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		err     string
+	}{
+		{
+			name:    "value and default",
+			content: "ACCOUNT_ID: ${env:ACCOUNT_ID}\nBUCKET: \"${env:DOCS_BUCKET:-docs}\"\n",
+			want:    "ACCOUNT_ID: 123456789012\nBUCKET: \"docs\"\n",
+		},
+		{
+			name:    "comment line",
+			content: "# REGION: ${env:UNSET_REGION}\nACCOUNT_ID: ${env:ACCOUNT_ID}\n",
+			want:    "# REGION: ${env:UNSET_REGION}\nACCOUNT_ID: 123456789012\n",
+		},
+		{
+			name:    "trailing comment",
+			content: "ACCOUNT_ID: ${env:ACCOUNT_ID} # or ${env:UNSET_ACCOUNT}\n",
+			want:    "ACCOUNT_ID: 123456789012 # or ${env:UNSET_ACCOUNT}\n",
+		},
+		{
+			name:    "hash in quoted value",
+			content: "name: 'it''s #${env:ACCOUNT_ID}' # ${env:UNSET_NAME}\n",
+			want:    "name: 'it''s #123456789012' # ${env:UNSET_NAME}\n",
+		},
+		{
+			name:    "hash in block scalar",
+			content: "instruction: |\n  # Account ${env:ACCOUNT_ID}\n\n  Answer questions.\n# ${env:UNSET_INSTRUCTION}\n",
+			want:    "instruction: |\n  # Account 123456789012\n\n  Answer questions.\n# ${env:UNSET_INSTRUCTION}\n",
+		},
+		{
+			name:    "unset variable",
+			content: "ACCOUNT_ID: ${env:UNSET_ACCOUNT}\n",
+			err:     "unresolved environment variable(s) UNSET_ACCOUNT in resources.yml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := interpolateEnv([]byte(test.content), "resources.yml")
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("interpolateEnv() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("interpolateEnv: %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("interpolateEnv() =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyOverlay deep-merges an overlay document over a base resource of the same kind and name. It returns
+// the re-parsed resource and the overlay fields that were applied. Nested maps are merged key by key,
+// while lists and scalars from the overlay replace the base value.
+func (p *YAMLParser) ApplyOverlay(base, overlay *ParsedResource) (*ParsedResource, []string, error) {
+	var baseDoc, overlayDoc map[string]interface{}
+	if err := yaml.Unmarshal(base.RawContent, &baseDoc); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal base resource: %w", err)
+	}
+	if err := yaml.Unmarshal(overlay.RawContent, &overlayDoc); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal overlay: %w", err)
+	}
+
+	merged, err := yaml.Marshal(mergeDefaults(baseDoc, overlayDoc))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal merged resource: %w", err)
+	}
+
+	// Defaults of the last parsed file must not leak into the merged document
+	p.defaults = nil
+
+	// Relative paths in the spec keep resolving against the base file
+	resource, err := p.parseDocument(merged, base.FilePath, 0, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields []string
+	collectOverlayFields("", overlayDoc, &fields)
+	sort.Strings(fields)
+	return resource, fields, nil
+}
+
+// collectOverlayFields appends the paths of the values set by an overlay, skipping the kind and name
+// that only identify the base resource
+func collectOverlayFields(prefix string, values map[string]interface{}, fields *[]string) {
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch path {
+		case "kind", "apiVersion", "metadata.name":
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			collectOverlayFields(path, nested, fields)
+			continue
+		}
+		*fields = append(*fields, path)
+	}
+}
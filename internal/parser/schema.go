@@ -0,0 +1,460 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"bedrock-forge/internal/models"
+)
+
+// Schema is the subset of JSON Schema used to check resource documents before they are unmarshaled
+type Schema struct {
+	Type                 string             `json:"type,omitempty"` // "" accepts any value
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// kindTypes are the model types resource documents of each kind unmarshal into
+var kindTypes = map[models.ResourceKind]reflect.Type{
+	models.AgentKind:                         reflect.TypeOf(models.Agent{}),
+	models.LambdaKind:                        reflect.TypeOf(models.Lambda{}),
+	models.LambdaLayerKind:                   reflect.TypeOf(models.LambdaLayer{}),
+	models.ActionGroupKind:                   reflect.TypeOf(models.ActionGroup{}),
+	models.KnowledgeBaseKind:                 reflect.TypeOf(models.KnowledgeBase{}),
+	models.GuardrailKind:                     reflect.TypeOf(models.Guardrail{}),
+	models.PromptKind:                        reflect.TypeOf(models.Prompt{}),
+	models.IAMRoleKind:                       reflect.TypeOf(models.IAMRole{}),
+	models.CustomResourcesKind:               reflect.TypeOf(models.CustomResources{}),
+	models.OpenSearchServerlessKind:          reflect.TypeOf(models.OpenSearchServerless{}),
+	models.AgentKnowledgeBaseAssociationKind: reflect.TypeOf(models.AgentKnowledgeBaseAssociation{}),
+}
+
+// requiredFields lists the fields each kind can't do without, as dotted paths where [] steps into list items.
+// Fields that only matter in combination with others are left to the resource validators.
+var requiredFields = map[models.ResourceKind][]string{
+	models.AgentKind:       {"spec.instruction"},
+	models.LambdaKind:      {"spec.runtime", "spec.handler"},
+	models.ActionGroupKind: {"spec.actionGroupExecutor"},
+	models.PromptKind:      {"spec.variants[].name", "spec.variants[].modelId"},
+	models.IAMRoleKind:     {"spec.assumeRolePolicy", "spec.assumeRolePolicy.version"},
+}
+
+var referenceType = reflect.TypeOf(models.Reference{})
+
+// KindSchema returns the JSON Schema of resource documents of a kind, generated from the model types
+func KindSchema(kind models.ResourceKind) (*Schema, error) {
+	resourceType, ok := kindTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource kind: %s", kind)
+	}
+
+	schema := schemaForType(resourceType, make(map[reflect.Type]bool))
+	schema.Properties["apiVersion"] = &Schema{Type: "string"}
+	schema.Required = []string{"kind", "metadata"}
+	schema.Properties["metadata"].Required = []string{"name"}
+
+	for _, path := range requiredFields[kind] {
+		if err := markRequired(schema, path); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+// schemaForType derives a schema from the yaml tags of a model type
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// References are a name or a {ref: name} object
+	if t == referenceType {
+		return &Schema{AnyOf: []*Schema{
+			{Type: "string"},
+			{Type: "object", Properties: map[string]*Schema{"ref": {Type: "string"}}, Required: []string{"ref"}},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), visiting)}
+	case reflect.Struct:
+		// Recursive types are only checked down to their first repetition
+		if visiting[t] {
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			schema.Properties[name] = schemaForType(field.Type, visiting)
+		}
+		return schema
+	}
+
+	// interface{} and anything else accepts any value
+	return &Schema{}
+}
+
+// markRequired adds the last element of a dotted path to the required fields of its parent object
+func markRequired(schema *Schema, path string) error {
+	parts := strings.Split(path, ".")
+	current := schema
+	for _, part := range parts[:len(parts)-1] {
+		name := strings.TrimSuffix(part, "[]")
+		next, ok := current.Properties[name]
+		if !ok {
+			return fmt.Errorf("required field %s is not in the schema", path)
+		}
+		if strings.HasSuffix(part, "[]") {
+			next = next.Items
+		}
+		current = next
+	}
+
+	name := parts[len(parts)-1]
+	if _, ok := current.Properties[name]; !ok {
+		return fmt.Errorf("required field %s is not in the schema", path)
+	}
+	current.Required = append(current.Required, name)
+	return nil
+}
+
+// schemaError is a problem found in a document, at a line of the parsed file
+type schemaError struct {
+	line    int
+	path    string
+	message string
+}
+
+func (e schemaError) String() string {
+	return fmt.Sprintf("line %d: %s: %s", e.line, e.path, e.message)
+}
+
+// schemaValidator checks a document node against a schema
+type schemaValidator struct {
+	// firstLine is the file line the document starts at
+	firstLine int
+
+	// defaults are the Defaults spec merged into the document, which may supply required fields
+	defaults map[string]interface{}
+
+	// partial skips required fields, for overlays that only set what they change
+	partial bool
+
+	errors []schemaError
+
+	// unknownFields are fields the schema doesn't define, errors only with strict fields
+	unknownFields []schemaError
+}
+
+// validateDocument checks a resource document against the schema of its kind. Type mismatches and
+// missing required fields are reported with the file line they occur at; unknown fields are too with
+// strict fields, and are otherwise logged as warnings.
+func (p *YAMLParser) validateDocument(content []byte, kind models.ResourceKind, filePath string, firstLine int) error {
+	schema, err := KindSchema(kind)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("failed to parse document: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	validator := &schemaValidator{firstLine: firstLine, defaults: p.defaults, partial: p.partial}
+	validator.validate(document.Content[0], schema, "")
+
+	if p.strictFields {
+		validator.errors = append(validator.errors, validator.unknownFields...)
+		sort.SliceStable(validator.errors, func(i, j int) bool {
+			return validator.errors[i].line < validator.errors[j].line
+		})
+	} else {
+		for _, unknownField := range validator.unknownFields {
+			p.logger.WithFields(logrus.Fields{
+				"file": filePath,
+				"line": unknownField.line,
+			}).Warnf("Ignoring %s: %s, use --strict-fields to reject it", unknownField.path, unknownField.message)
+		}
+	}
+
+	if len(validator.errors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(validator.errors))
+	for i, schemaErr := range validator.errors {
+		messages[i] = schemaErr.String()
+	}
+	return fmt.Errorf("%s does not match its schema: %s", kind, strings.Join(messages, "; "))
+}
+
+func (v *schemaValidator) addError(node *yaml.Node, path, message string) {
+	if path == "" {
+		path = "document"
+	}
+	v.errors = append(v.errors, schemaError{line: v.firstLine + node.Line - 1, path: path, message: message})
+}
+
+// validate checks a node, and recursively its children, against a schema
+func (v *schemaValidator) validate(node *yaml.Node, schema *Schema, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// Null values are left unset
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	if len(schema.AnyOf) > 0 {
+		for _, option := range schema.AnyOf {
+			if nodeMatchesType(node, option.Type) {
+				v.validate(node, option, path)
+				return
+			}
+		}
+		v.addError(node, path, fmt.Sprintf("expected %s, got %s", anyOfTypes(schema.AnyOf), nodeType(node)))
+		return
+	}
+
+	if schema.Type == "" {
+		return
+	}
+	if !nodeMatchesType(node, schema.Type) {
+		v.addError(node, path, fmt.Sprintf("expected %s, got %s", schema.Type, nodeType(node)))
+		return
+	}
+
+	switch schema.Type {
+	case "array":
+		for i, item := range node.Content {
+			v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "object":
+		v.validateObject(node, schema, path)
+	}
+}
+
+// validateObject checks the keys of a mapping node against the properties of an object schema
+func (v *schemaValidator) validateObject(node *yaml.Node, schema *Schema, path string) {
+	present := make(map[string]bool)
+	for _, pair := range mappingPairs(node) {
+		key, value := pair[0], pair[1]
+		present[key.Value] = true
+
+		fieldPath := key.Value
+		if path != "" {
+			fieldPath = path + "." + key.Value
+		}
+
+		if schema.Properties != nil {
+			property, ok := schema.Properties[key.Value]
+			if !ok {
+				message := "unknown field"
+				if suggestion := closestName(key.Value, schema.Properties); suggestion != "" {
+					message += fmt.Sprintf(", did you mean %s?", suggestion)
+				}
+				v.unknownFields = append(v.unknownFields, schemaError{line: v.firstLine + key.Line - 1, path: fieldPath, message: message})
+				continue
+			}
+			v.validate(value, property, fieldPath)
+		} else if schema.AdditionalProperties != nil {
+			v.validate(value, schema.AdditionalProperties, fieldPath)
+		}
+	}
+
+	if v.partial {
+		return
+	}
+	for _, name := range schema.Required {
+		if present[name] || v.hasDefault(path, name) {
+			continue
+		}
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		v.addError(node, fieldPath, "required field is missing")
+	}
+}
+
+// hasDefault reports whether the Defaults spec sets a field of the spec or of an object nested in it
+func (v *schemaValidator) hasDefault(path, name string) bool {
+	if v.defaults == nil || (path != "spec" && !strings.HasPrefix(path, "spec.")) {
+		return false
+	}
+
+	values := v.defaults
+	if path != "spec" {
+		for _, part := range strings.Split(strings.TrimPrefix(path, "spec."), ".") {
+			nested, ok := values[part].(map[string]interface{})
+			if !ok {
+				return false
+			}
+			values = nested
+		}
+	}
+	_, ok := values[name]
+	return ok
+}
+
+// mappingPairs returns the key and value nodes of a mapping, following << merge keys
+func mappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	var pairs [][2]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			merged := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				merged = value.Content
+			}
+			for _, mergedNode := range merged {
+				if mergedNode.Kind == yaml.AliasNode {
+					mergedNode = mergedNode.Alias
+				}
+				if mergedNode.Kind == yaml.MappingNode {
+					pairs = append(pairs, mappingPairs(mergedNode)...)
+				}
+			}
+			continue
+		}
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+	}
+	return pairs
+}
+
+// nodeMatchesType reports whether a node can be unmarshaled into a value of a schema type. Like the
+// YAML decoder, strings accept any scalar.
+func nodeMatchesType(node *yaml.Node, schemaType string) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		return node.Kind == yaml.ScalarNode
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float")
+	}
+	return false
+}
+
+// nodeType describes the type of a node for error messages
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return fmt.Sprintf("string %q", node.Value)
+}
+
+// anyOfTypes lists the types accepted by anyOf options
+func anyOfTypes(options []*Schema) string {
+	types := make([]string, len(options))
+	for i, option := range options {
+		types[i] = option.Type
+	}
+	return strings.Join(types, " or ")
+}
+
+// closestName returns the property name most likely meant by a misspelled field, or "" when none is close
+func closestName(name string, properties map[string]*Schema) string {
+	names := make([]string, 0, len(properties))
+	for property := range properties {
+		names = append(names, property)
+	}
+	sort.Strings(names)
+
+	closest := ""
+	bestDistance := 0
+	for _, property := range names {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(property))
+		if closest == "" || distance < bestDistance {
+			closest, bestDistance = property, distance
+		}
+	}
+
+	// Only suggest names that differ by a typo rather than being a different word
+	if closest == "" || bestDistance > max(2, len(name)/4) {
+		return ""
+	}
+	return closest
+}
+
+// editDistance computes the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"bedrock-forge/internal/models"
+)
+
+// Template delimiters. Plain {{ }} is taken by Bedrock prompt variables and ${...} by
+// Terraform interpolation, so template actions are written as ${{ .name }}.
+const (
+	templateLeftDelim  = "${{"
+	templateRightDelim = "}}"
+)
+
+// documentKindPattern matches the top-level kind of a YAML document
+var documentKindPattern = regexp.MustCompile(`(?m)^kind:[ \t]*["']?([^"'\s#]*)`)
+
+// documentSeparatorPattern matches a line holding only the YAML document separator. The trailing
+// whitespace stays on the line, so documents keep the line numbers they have in the file.
+var documentSeparatorPattern = regexp.MustCompile(`(?m)^---[ \t]*\r?$`)
+
+// LoadTemplateVars builds the template context from a values file and key=value pairs.
+// Pairs take precedence over entries in the file.
+func LoadTemplateVars(varFile string, vars []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if varFile != "" {
+		data, err := os.ReadFile(varFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read var file %s: %w", varFile, err)
+		}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse var file %s: %w", varFile, err)
+		}
+		if values == nil {
+			values = make(map[string]interface{})
+		}
+	}
+
+	for _, pair := range vars {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid var '%s', must be key=value", pair)
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// SetTemplateVars sets the values available to ${{ }} template actions
+func (p *YAMLParser) SetTemplateVars(vars map[string]interface{}) {
+	p.templateVars = vars
+}
+
+// renderTemplate renders ${{ }} actions in YAML content. Files without actions are returned
+// unchanged; references to missing variables fail with their file, line and column. Documents
+// without a forge kind, such as GitHub Actions workflows whose expressions share the delimiters,
+// are left as they are.
+func renderTemplate(content []byte, filePath string, vars map[string]interface{}) ([]byte, error) {
+	if !bytes.Contains(content, []byte(templateLeftDelim)) {
+		return content, nil
+	}
+
+	// Escaping keeps the file in one template, so errors still report lines of the file
+	text := string(content)
+	var escaped strings.Builder
+	start := 0
+	separators := append(documentSeparatorPattern.FindAllStringIndex(text, -1), []int{len(text), len(text)})
+	for _, separator := range separators {
+		doc := text[start:separator[0]]
+		if !isForgeDocument(doc) {
+			doc = strings.ReplaceAll(doc, templateLeftDelim, templateLeftDelim+`"`+templateLeftDelim+`"`+templateRightDelim)
+		}
+		escaped.WriteString(doc)
+		escaped.WriteString(text[separator[0]:separator[1]])
+		start = separator[1]
+	}
+
+	tmpl, err := template.New(filePath).
+		Delims(templateLeftDelim, templateRightDelim).
+		Option("missingkey=error").
+		Parse(escaped.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isForgeDocument reports whether a document declares a resource kind, or one set by a template action
+func isForgeDocument(doc string) bool {
+	match := documentKindPattern.FindStringSubmatch(doc)
+	if match == nil {
+		return false
+	}
+
+	kind := models.ResourceKind(match[1])
+	if _, known := kindTypes[kind]; known || kind == models.DefaultsKind {
+		return true
+	}
+	return strings.HasPrefix(match[1], templateLeftDelim)
+}
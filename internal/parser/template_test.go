@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// workflowDocument is a GitHub Actions workflow, whose expressions use the template delimiters
+const workflowDocument = `name: Deploy Bedrock Agents
+on:
+  workflow_dispatch:
+jobs:
+  deploy:
+    uses: your-org/bedrock-forge/.github/workflows/bedrock-forge-deploy.yml@main
+    with:
+      environment: ${{ github.event.inputs.environment || 'dev' }}
+`
+
+func TestRenderTemplateSkipsDocumentsWithoutForgeKind(t *testing.T) {
+	content := workflowDocument + `---
+kind: Lambda
+metadata:
+  name: order-lookup-${{ .environment }}
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`
+
+	rendered, err := renderTemplate([]byte(content), "deploy.yml", map[string]interface{}{"environment": "prod"})
+	if err != nil {
+		t.Fatalf("renderTemplate: %v", err)
+	}
+
+	documents := strings.Split(string(rendered), "---")
+	if documents[0] != workflowDocument {
+		t.Errorf("workflow document changed:\n%s", documents[0])
+	}
+	if !strings.Contains(documents[1], "name: order-lookup-prod") {
+		t.Errorf("Lambda document not rendered:\n%s", documents[1])
+	}
+}
+
+func TestParseContentWithWorkflowFile(t *testing.T) {
+	// Rendering the whole file failed with: function "github" not defined
+	resources, err := newTestParser().ParseContent([]byte(workflowDocument), "team-example.yml")
+	if err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("expected no resources, got %d", len(resources))
+	}
+}
+
+func TestRenderTemplateForgeDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		err     string
+	}{
+		{
+			name:    "quoted kind",
+			content: "kind: \"Agent\"\nmetadata:\n  name: support-${{ .environment }}\n",
+			want:    "name: support-prod",
+		},
+		{
+			name:    "templated kind",
+			content: "kind: ${{ .kind }}\nmetadata:\n  name: support\n",
+			want:    "kind: Agent",
+		},
+		{
+			name:    "Defaults",
+			content: "kind: Defaults\nspec:\n  tags:\n    Environment: ${{ .environment }}\n",
+			want:    "Environment: prod",
+		},
+		{
+			name:    "separator inside a value",
+			content: "kind: Agent\nmetadata:\n  name: support\n  description: orders --- returns\nspec:\n  instruction: Support for ${{ .environment }}\n",
+			want:    "instruction: Support for prod",
+		},
+		{
+			name:    "separator with trailing whitespace",
+			content: workflowDocument + "--- \t\nkind: Lambda\nmetadata:\n  name: order-lookup-${{ .environment }}\n",
+			want:    "name: order-lookup-prod",
+		},
+		{
+			name:    "missing variable after a workflow",
+			content: workflowDocument + "---\nkind: Lambda\nmetadata:\n  name: order-lookup-${{ .region }}\n",
+			err:     "deploy.yml:12:",
+		},
+	}
+
+	vars := map[string]interface{}{"environment": "prod", "kind": "Agent"}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := renderTemplate([]byte(test.content), "deploy.yml", vars)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("renderTemplate() = %v, want an error at %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderTemplate: %v", err)
+			}
+			if !strings.Contains(string(rendered), test.want) {
+				t.Errorf("rendered content doesn't contain %q:\n%s", test.want, rendered)
+			}
+		})
+	}
+}
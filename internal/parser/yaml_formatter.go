@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formatIndent is the indentation used for formatted resource files
+const formatIndent = 2
+
+// Canonical key orders; keys not listed keep their original order after these
+var (
+	resourceKeyOrder = []string{"kind", "apiVersion", "metadata", "spec"}
+	metadataKeyOrder = []string{"name", "description", "labels", "annotations", "region", "provider", "dependsOn", "outputs", "previousNames", "import"}
+)
+
+// blockScalarPlaceholder stands in for a literal block scalar while encoding, see replaceBlockScalars
+const blockScalarPlaceholder = "__bedrock_forge_block_scalar_%d__"
+
+// FormatYAML rewrites resource documents with a canonical key order and stable indentation.
+// Comments are kept with the keys they are attached to, blank lines between entries are kept,
+// and literal block scalars stay block scalars.
+func FormatYAML(content []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+	var documents []*yaml.Node
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+
+		if root := documentRoot(&document); root != nil && root.Kind == yaml.MappingNode {
+			orderMappingKeys(root, resourceKeyOrder)
+			if metadata := mappingValue(root, "metadata"); metadata != nil && metadata.Kind == yaml.MappingNode {
+				orderMappingKeys(metadata, metadataKeyOrder)
+			}
+		}
+
+		documents = append(documents, &document)
+	}
+
+	var blockScalars []*yaml.Node
+	for _, document := range documents {
+		blockScalars = replaceBlockScalars(document, blockScalars)
+	}
+
+	encoded, err := encodeDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := restoreBlankLines(documents, blockScalars, strings.Split(string(content), "\n"), encoded)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(restoreBlockScalars(formatted, blockScalars)), nil
+}
+
+func encodeDocuments(documents []*yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(formatIndent)
+
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return "", fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// replaceBlockScalars swaps literal block scalars for plain placeholders, appending the originals to
+// blockScalars. The encoder falls back to a quoted string for values it can't write as a block, e.g.
+// code with trailing spaces, so restoreBlockScalars writes them instead. Folded scalars the encoder
+// can't fold are written as literal blocks too.
+func replaceBlockScalars(node *yaml.Node, blockScalars []*yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.ScalarNode {
+		block := false
+		switch {
+		case node.Style&yaml.LiteralStyle != 0:
+			block = true
+		case node.Style&yaml.FoldedStyle != 0:
+			block = strings.Contains(node.Value, "\t") || strings.Contains(node.Value, " \n")
+		}
+		if block && canWriteLiteral(node.Value) {
+			original := *node
+			blockScalars = append(blockScalars, &original)
+			node.Value = fmt.Sprintf(blockScalarPlaceholder, len(blockScalars)-1)
+			node.Style = 0
+		}
+		return blockScalars
+	}
+
+	for _, child := range node.Content {
+		blockScalars = replaceBlockScalars(child, blockScalars)
+	}
+	return blockScalars
+}
+
+// canWriteLiteral reports whether a value can be written as a literal block scalar with an implicit
+// indentation: it has printable content and its first line doesn't start with a space
+func canWriteLiteral(value string) bool {
+	if strings.TrimSpace(value) == "" || strings.HasPrefix(strings.TrimLeft(value, "\n"), " ") {
+		return false
+	}
+	for _, r := range value {
+		if r != '\n' && r != '\t' && !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreBlockScalars writes the block scalars replaced by replaceBlockScalars in place of their placeholders
+func restoreBlockScalars(encoded string, blockScalars []*yaml.Node) string {
+	if len(blockScalars) == 0 {
+		return encoded
+	}
+
+	var lines []string
+	for _, line := range strings.Split(encoded, "\n") {
+		index := -1
+		for i := range blockScalars {
+			if strings.Contains(line, fmt.Sprintf(blockScalarPlaceholder, i)) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			lines = append(lines, line)
+			continue
+		}
+
+		placeholder := fmt.Sprintf(blockScalarPlaceholder, index)
+		prefix, suffix, _ := strings.Cut(line, placeholder)
+		value := blockScalars[index].Value
+
+		// Chomping indicator for the trailing line breaks of the value
+		indicator := "|"
+		switch {
+		case !strings.HasSuffix(value, "\n"):
+			indicator = "|-"
+		case strings.HasSuffix(value, "\n\n"):
+			indicator = "|+"
+			value = strings.TrimSuffix(value, "\n")
+		default:
+			value = strings.TrimSuffix(value, "\n")
+		}
+		lines = append(lines, prefix+indicator+suffix)
+
+		indent := strings.Repeat(" ", blockIndent(prefix))
+		for _, valueLine := range strings.Split(value, "\n") {
+			if valueLine == "" {
+				lines = append(lines, "")
+				continue
+			}
+			lines = append(lines, indent+valueLine)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// blockIndent returns the indentation of the content of a block scalar following prefix, e.g.
+// "  instruction: " or "  - "
+func blockIndent(prefix string) int {
+	column := len(prefix) - len(strings.TrimLeft(prefix, " "))
+	rest := prefix[column:]
+	for strings.HasPrefix(rest, "- ") {
+		column += 2
+		rest = rest[2:]
+	}
+	if rest == "" {
+		// A sequence entry, indented past its dash
+		return column
+	}
+	return column + formatIndent
+}
+
+// restoreBlankLines keeps a single blank line before the entries of a mapping or sequence that
+// followed a blank line in the source. The encoder drops them, so the encoded documents are decoded
+// again to find where the entries ended up.
+func restoreBlankLines(documents, blockScalars []*yaml.Node, sourceLines []string, encoded string) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(encoded))
+
+	blankBefore := make(map[int]bool)
+	for _, document := range documents {
+		var formatted yaml.Node
+		if err := decoder.Decode(&formatted); err != nil {
+			return "", fmt.Errorf("failed to decode formatted YAML: %w", err)
+		}
+		markBlankLines(document, &formatted, blockScalars, sourceLines, blankBefore)
+	}
+	if len(blankBefore) == 0 {
+		return encoded, nil
+	}
+
+	lineNumbers := make([]int, 0, len(blankBefore))
+	for line := range blankBefore {
+		lineNumbers = append(lineNumbers, line)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lineNumbers)))
+
+	lines := strings.Split(encoded, "\n")
+	for _, line := range lineNumbers {
+		lines = append(lines[:line-1], append([]string{""}, lines[line-1:]...)...)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// markBlankLines walks a source node and its formatted counterpart together, recording the formatted
+// lines that need a blank line before them
+func markBlankLines(source, formatted *yaml.Node, blockScalars []*yaml.Node, sourceLines []string, blankBefore map[int]bool) {
+	if source.Kind != formatted.Kind || len(source.Content) != len(formatted.Content) || source.Style&yaml.FlowStyle != 0 {
+		return
+	}
+
+	step := 1
+	if source.Kind == yaml.MappingNode {
+		step = 2
+	}
+	if source.Kind == yaml.MappingNode || source.Kind == yaml.SequenceNode {
+		// The first entry follows its parent key, and blank lines after a kept block scalar are its content
+		for i := step; i < len(source.Content); i += step {
+			previous := source.Content[i-1]
+			var index int
+			if _, err := fmt.Sscanf(previous.Value, blockScalarPlaceholder, &index); err == nil && index < len(blockScalars) {
+				previous = blockScalars[index]
+			}
+			if previous.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 && strings.HasSuffix(previous.Value, "\n\n") {
+				continue
+			}
+			line := entryStartLine(source.Content[i])
+			if line >= 2 && line-2 < len(sourceLines) && strings.TrimSpace(sourceLines[line-2]) == "" {
+				blankBefore[entryStartLine(formatted.Content[i])] = true
+			}
+		}
+	}
+
+	for i := range source.Content {
+		markBlankLines(source.Content[i], formatted.Content[i], blockScalars, sourceLines, blankBefore)
+	}
+}
+
+// entryStartLine returns the line a mapping key or sequence entry starts at, including its head comment
+func entryStartLine(node *yaml.Node) int {
+	line := node.Line
+	if node.HeadComment != "" {
+		line -= strings.Count(node.HeadComment, "\n") + 1
+	}
+	if (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) && len(node.Content) > 0 {
+		if first := entryStartLine(node.Content[0]); first < line {
+			line = first
+		}
+	}
+	return line
+}
+
+func documentRoot(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	return document.Content[0]
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// orderMappingKeys moves the given keys to the front of a mapping, in order
+func orderMappingKeys(mapping *yaml.Node, order []string) {
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i
+	}
+
+	ordered := make([][]*yaml.Node, len(order))
+	var rest []*yaml.Node
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if r, ok := rank[key.Value]; ok && ordered[r] == nil {
+			ordered[r] = []*yaml.Node{key, value}
+			continue
+		}
+		rest = append(rest, key, value)
+	}
+
+	content := make([]*yaml.Node, 0, len(mapping.Content))
+	for _, pair := range ordered {
+		content = append(content, pair...)
+	}
+	mapping.Content = append(content, rest...)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the formatter tests")
+
+// decodeAll decodes every document of content into plain values
+func decodeAll(t *testing.T, content []byte) []interface{} {
+	t.Helper()
+
+	var documents []interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document interface{}
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return documents
+		}
+		if err != nil {
+			t.Fatalf("failed to decode:\n%s\n%v", content, err)
+		}
+		documents = append(documents, document)
+	}
+}
+
+func TestFormatYAMLGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "format", "*.yml"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no formatter test inputs: %v", err)
+	}
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatted, err := FormatYAML(content)
+			if err != nil {
+				t.Fatalf("FormatYAML: %v", err)
+			}
+
+			golden := strings.TrimSuffix(input, ".yml") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(golden, formatted, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(formatted, want) {
+				t.Errorf("formatted output differs from %s:\n%s", golden, formatted)
+			}
+
+			if !reflect.DeepEqual(decodeAll(t, formatted), decodeAll(t, content)) {
+				t.Errorf("formatting changed the values:\n%s", formatted)
+			}
+
+			again, err := FormatYAML(formatted)
+			if err != nil {
+				t.Fatalf("FormatYAML of formatted output: %v", err)
+			}
+			if !bytes.Equal(again, formatted) {
+				t.Errorf("formatting isn't stable, formatting again gives:\n%s", again)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +16,19 @@ import (
 
 type YAMLParser struct {
 	logger *logrus.Logger
+
+	// defaults holds the spec of the most recent Defaults document in the file being parsed
+	defaults map[string]interface{}
+
+	// templateVars are the values rendered into ${{ }} template actions
+	templateVars map[string]interface{}
+
+	// partial is set while parsing overlays, which leave out fields that aren't changed
+	partial bool
+
+	// strictFields rejects fields the resource types don't define, including ones merged in from
+	// Defaults, instead of warning about them
+	strictFields bool
 }
 
 func NewYAMLParser(logger *logrus.Logger) *YAMLParser {
@@ -22,6 +37,28 @@ func NewYAMLParser(logger *logrus.Logger) *YAMLParser {
 	}
 }
 
+// DocumentError is a document of a resource kind that failed to parse. Unlike YAML without resources,
+// which is skipped with a warning, such documents must fail the command: dropping them would remove
+// their resources from the generated configuration.
+type DocumentError struct {
+	FilePath string
+	Index    int
+	Kind     models.ResourceKind
+	Name     string
+	Err      error
+}
+
+func (e *DocumentError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: document %d (%s): %v", e.FilePath, e.Index, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: document %d (%s %s): %v", e.FilePath, e.Index, e.Kind, e.Name, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
 type ParsedResource struct {
 	Kind       models.ResourceKind
 	Metadata   models.Metadata
@@ -47,17 +84,59 @@ func (p *YAMLParser) ParseFile(filePath string) ([]*ParsedResource, error) {
 	return p.ParseContent(content, filePath)
 }
 
+// SetStrictFields makes parsing fail on fields the resource types don't define instead of warning and
+// dropping them
+func (p *YAMLParser) SetStrictFields(strict bool) {
+	p.strictFields = strict
+}
+
+// ParseOverlayFile parses a file of overlays, which only need to set the fields they change
+func (p *YAMLParser) ParseOverlayFile(filePath string) ([]*ParsedResource, error) {
+	p.partial = true
+	defer func() { p.partial = false }()
+
+	return p.ParseFile(filePath)
+}
+
+// ParseContent parses the resources in content. Documents of a resource kind that fail to parse are
+// returned as DocumentErrors along with the resources that did parse; other documents, such as YAML
+// without a kind, are skipped with a warning.
 func (p *YAMLParser) ParseContent(content []byte, filePath string) ([]*ParsedResource, error) {
 	resources := make([]*ParsedResource, 0)
+	var documentErrs []error
 
-	documents := strings.Split(string(content), "---")
+	// Defaults only apply within a single file
+	p.defaults = nil
+
+	// Render templates and substitute ${env:VAR} references before splitting documents
+	content, err := renderTemplate(content, filePath, p.templateVars)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = interpolateEnv(content, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := documentSeparatorPattern.Split(string(content), -1)
+	line := 1
 	for i, doc := range documents {
+		// Schema errors are reported at lines of the file rather than of the document
+		firstLine := line + strings.Count(doc[:len(doc)-len(strings.TrimLeft(doc, " \t\r\n"))], "\n")
+		line += strings.Count(doc, "\n")
+
 		doc = strings.TrimSpace(doc)
 		if doc == "" {
 			continue
 		}
 
-		resource, err := p.parseDocument([]byte(doc), filePath, i)
+		resource, err := p.parseDocument([]byte(doc), filePath, i, firstLine)
+		var documentErr *DocumentError
+		if errors.As(err, &documentErr) {
+			documentErrs = append(documentErrs, err)
+			continue
+		}
 		if err != nil {
 			p.logger.WithError(err).WithFields(logrus.Fields{
 				"file":     filePath,
@@ -76,10 +155,10 @@ func (p *YAMLParser) ParseContent(content []byte, filePath string) ([]*ParsedRes
 		"count": len(resources),
 	}).Debug("Parsed resources from file")
 
-	return resources, nil
+	return resources, errors.Join(documentErrs...)
 }
 
-func (p *YAMLParser) parseDocument(content []byte, filePath string, docIndex int) (*ParsedResource, error) {
+func (p *YAMLParser) parseDocument(content []byte, filePath string, docIndex int, firstLine int) (*ParsedResource, error) {
 	var base models.BaseResource
 	if err := yaml.Unmarshal(content, &base); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal base resource: %w", err)
@@ -89,6 +168,36 @@ func (p *YAMLParser) parseDocument(content []byte, filePath string, docIndex int
 		return nil, fmt.Errorf("resource kind is required")
 	}
 
+	if _, known := kindTypes[base.Kind]; !known && base.Kind != models.DefaultsKind {
+		return nil, fmt.Errorf("unsupported resource kind: %s", base.Kind)
+	}
+
+	resource, err := p.parseResourceDocument(content, base, filePath, firstLine)
+	if err != nil {
+		return nil, &DocumentError{FilePath: filePath, Index: docIndex, Kind: base.Kind, Name: base.Metadata.Name, Err: err}
+	}
+	return resource, nil
+}
+
+// parseResourceDocument parses a document of a supported kind, or stores the spec of a Defaults document
+func (p *YAMLParser) parseResourceDocument(content []byte, base models.BaseResource, filePath string, firstLine int) (*ParsedResource, error) {
+	if base.Kind == models.DefaultsKind {
+		return nil, p.setDefaults(content, filePath)
+	}
+
+	// Struct unmarshaling silently drops misspelled fields, so check the document first
+	if err := p.validateDocument(content, base.Kind, filePath, firstLine); err != nil {
+		return nil, err
+	}
+
+	if p.defaults != nil {
+		merged, err := p.applyDefaults(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply defaults: %w", err)
+		}
+		content = merged
+	}
+
 	parsedResource := &ParsedResource{
 		Kind:       base.Kind,
 		Metadata:   base.Metadata,
@@ -99,70 +208,83 @@ func (p *YAMLParser) parseDocument(content []byte, filePath string, docIndex int
 	switch base.Kind {
 	case models.AgentKind:
 		var agent models.Agent
-		if err := yaml.Unmarshal(content, &agent); err != nil {
+		if err := p.decodeResource(content, &agent); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal Agent: %w", err)
 		}
 		parsedResource.Resource = &agent
 
 	case models.LambdaKind:
 		var lambda models.Lambda
-		if err := yaml.Unmarshal(content, &lambda); err != nil {
+		if err := p.decodeResource(content, &lambda); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal Lambda: %w", err)
 		}
+		// Generators check the code options in a fixed order, so conflicting options would go unnoticed
+		if !p.partial {
+			if err := lambda.Spec.Code.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid Lambda code: %w", err)
+			}
+		}
 		parsedResource.Resource = &lambda
 
+	case models.LambdaLayerKind:
+		var lambdaLayer models.LambdaLayer
+		if err := p.decodeResource(content, &lambdaLayer); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal LambdaLayer: %w", err)
+		}
+		parsedResource.Resource = &lambdaLayer
+
 	case models.ActionGroupKind:
 		var actionGroup models.ActionGroup
-		if err := yaml.Unmarshal(content, &actionGroup); err != nil {
+		if err := p.decodeResource(content, &actionGroup); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal ActionGroup: %w", err)
 		}
 		parsedResource.Resource = &actionGroup
 
 	case models.KnowledgeBaseKind:
 		var knowledgeBase models.KnowledgeBase
-		if err := yaml.Unmarshal(content, &knowledgeBase); err != nil {
+		if err := p.decodeResource(content, &knowledgeBase); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal KnowledgeBase: %w", err)
 		}
 		parsedResource.Resource = &knowledgeBase
 
 	case models.GuardrailKind:
 		var guardrail models.Guardrail
-		if err := yaml.Unmarshal(content, &guardrail); err != nil {
+		if err := p.decodeResource(content, &guardrail); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal Guardrail: %w", err)
 		}
 		parsedResource.Resource = &guardrail
 
 	case models.PromptKind:
 		var prompt models.Prompt
-		if err := yaml.Unmarshal(content, &prompt); err != nil {
+		if err := p.decodeResource(content, &prompt); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal Prompt: %w", err)
 		}
 		parsedResource.Resource = &prompt
 
 	case models.IAMRoleKind:
 		var iamRole models.IAMRole
-		if err := yaml.Unmarshal(content, &iamRole); err != nil {
+		if err := p.decodeResource(content, &iamRole); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal IAMRole: %w", err)
 		}
 		parsedResource.Resource = &iamRole
 
 	case models.CustomResourcesKind:
 		var customResources models.CustomResources
-		if err := yaml.Unmarshal(content, &customResources); err != nil {
+		if err := p.decodeResource(content, &customResources); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal CustomResources: %w", err)
 		}
 		parsedResource.Resource = &customResources
 
 	case models.OpenSearchServerlessKind:
 		var opensearchServerless models.OpenSearchServerless
-		if err := yaml.Unmarshal(content, &opensearchServerless); err != nil {
+		if err := p.decodeResource(content, &opensearchServerless); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal OpenSearchServerless: %w", err)
 		}
 		parsedResource.Resource = &opensearchServerless
 
 	case models.AgentKnowledgeBaseAssociationKind:
 		var association models.AgentKnowledgeBaseAssociation
-		if err := yaml.Unmarshal(content, &association); err != nil {
+		if err := p.decodeResource(content, &association); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal AgentKnowledgeBaseAssociation: %w", err)
 		}
 		parsedResource.Resource = &association
@@ -174,6 +296,84 @@ func (p *YAMLParser) parseDocument(content []byte, filePath string, docIndex int
 	return parsedResource, nil
 }
 
+// decodeResource unmarshals a resource document, rejecting undefined fields with strict fields set
+func (p *YAMLParser) decodeResource(content []byte, out interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(p.strictFields)
+	if err := decoder.Decode(out); err != nil {
+		if p.strictFields && strings.Contains(err.Error(), " not found in type ") {
+			// Lines of documents merged with Defaults don't match the file
+			if p.defaults != nil {
+				return fmt.Errorf("unknown fields after merging Defaults: %w", err)
+			}
+			return fmt.Errorf("unknown fields: %w", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// setDefaults stores the spec of a Defaults document for the remaining documents in the file
+func (p *YAMLParser) setDefaults(content []byte, filePath string) error {
+	var doc struct {
+		Spec map[string]interface{} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal Defaults: %w", err)
+	}
+
+	p.defaults = doc.Spec
+
+	p.logger.WithFields(logrus.Fields{
+		"file": filePath,
+		"keys": len(doc.Spec),
+	}).Debug("Loaded defaults for subsequent documents")
+
+	return nil
+}
+
+// applyDefaults deep-merges the current defaults into the document spec.
+// Values set on the resource always take precedence over defaults; nested maps
+// are merged key by key, while lists and scalars from the resource replace the default.
+func (p *YAMLParser) applyDefaults(content []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	spec, ok := doc["spec"].(map[string]interface{})
+	if !ok {
+		if doc["spec"] != nil {
+			return nil, fmt.Errorf("spec must be a mapping to apply defaults")
+		}
+		spec = make(map[string]interface{})
+	}
+
+	doc["spec"] = mergeDefaults(p.defaults, spec)
+
+	return yaml.Marshal(doc)
+}
+
+// mergeDefaults returns values deep-merged on top of defaults
+func mergeDefaults(defaults, values map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaults)+len(values))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+
+	for key, value := range values {
+		defaultMap, defaultIsMap := merged[key].(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if defaultIsMap && valueIsMap {
+			merged[key] = mergeDefaults(defaultMap, valueMap)
+			continue
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
+
 func (p *YAMLParser) ValidateResource(resource *ParsedResource) error {
 	if resource.Kind == "" {
 		return fmt.Errorf("resource kind is required")
@@ -188,6 +388,8 @@ func (p *YAMLParser) ValidateResource(resource *ParsedResource) error {
 		return p.validateAgent(resource.Resource.(*models.Agent))
 	case models.LambdaKind:
 		return p.validateLambda(resource.Resource.(*models.Lambda))
+	case models.LambdaLayerKind:
+		return p.validateLambdaLayer(resource.Resource.(*models.LambdaLayer))
 	case models.ActionGroupKind:
 		return p.validateActionGroup(resource.Resource.(*models.ActionGroup))
 	case models.KnowledgeBaseKind:
@@ -240,13 +442,19 @@ func (p *YAMLParser) validateOptionalReference(ref models.Reference, fieldName s
 }
 
 func (p *YAMLParser) validateAgent(agent *models.Agent) error {
-	if agent.Spec.FoundationModel == "" {
-		return fmt.Errorf("agent foundationModel is required")
+	if err := agent.Spec.ValidateFoundationModel(); err != nil {
+		return err
 	}
 	if agent.Spec.Instruction == "" {
 		return fmt.Errorf("agent instruction is required")
 	}
 
+	if agent.Spec.MemoryConfiguration != nil {
+		if err := agent.Spec.MemoryConfiguration.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Validate guardrail reference
 	if agent.Spec.Guardrail != nil {
 		if err := p.validateOptionalReference(agent.Spec.Guardrail.Name, "guardrail"); err != nil {
@@ -268,6 +476,9 @@ func (p *YAMLParser) validateAgent(agent *models.Agent) error {
 				return err
 			}
 		}
+		if err := p.validateAPISchema(actionGroup.APISchema, fmt.Sprintf("action group[%d] apiSchema", i)); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -280,12 +491,29 @@ func (p *YAMLParser) validateLambda(lambda *models.Lambda) error {
 	if lambda.Spec.Handler == "" {
 		return fmt.Errorf("lambda handler is required")
 	}
-	if lambda.Spec.Code.Source == "" {
-		return fmt.Errorf("lambda code.source is required")
+	if err := lambda.Spec.Code.Validate(); err != nil {
+		return err
+	}
+	if lambda.Spec.FunctionURL != nil {
+		if err := lambda.Spec.FunctionURL.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := lambda.Spec.ValidateEnvironmentSources(); err != nil {
+		return err
+	}
+	if lambda.Spec.DeadLetterConfig != nil {
+		if err := lambda.Spec.DeadLetterConfig.Validate(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func (p *YAMLParser) validateLambdaLayer(lambdaLayer *models.LambdaLayer) error {
+	return lambdaLayer.Spec.Validate()
+}
+
 func (p *YAMLParser) validateActionGroup(actionGroup *models.ActionGroup) error {
 	if actionGroup.Spec.ActionGroupExecutor == nil {
 		return fmt.Errorf("actionGroup executor is required")
@@ -301,6 +529,17 @@ func (p *YAMLParser) validateActionGroup(actionGroup *models.ActionGroup) error
 		return err
 	}
 
+	return p.validateAPISchema(actionGroup.Spec.APISchema, "apiSchema")
+}
+
+// validateAPISchema checks that a managed S3 schema points at a complete location
+func (p *YAMLParser) validateAPISchema(schema *models.APISchema, field string) error {
+	if !schema.IsManaged() {
+		return nil
+	}
+	if schema.S3.S3BucketName == "" || schema.S3.S3ObjectKey == "" {
+		return fmt.Errorf("%s.s3 with managed: true requires s3BucketName and s3ObjectKey", field)
+	}
 	return nil
 }
 
@@ -311,6 +550,11 @@ func (p *YAMLParser) validateKnowledgeBase(kb *models.KnowledgeBase) error {
 	if kb.Spec.StorageConfiguration == nil {
 		return fmt.Errorf("knowledgeBase storage configuration is required")
 	}
+	for _, dataSource := range kb.Spec.DataSources {
+		if err := dataSource.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -324,7 +568,7 @@ func (p *YAMLParser) validateGuardrail(guardrail *models.Guardrail) error {
 	if !hasPolicy {
 		return fmt.Errorf("guardrail must have at least one policy configuration")
 	}
-	return nil
+	return guardrail.Spec.Validate()
 }
 
 func (p *YAMLParser) validatePrompt(prompt *models.Prompt) error {
@@ -368,10 +612,12 @@ func (p *YAMLParser) validateCustomResources(customResources *models.CustomResou
 }
 
 func (p *YAMLParser) validateOpenSearchServerless(opensearchServerless *models.OpenSearchServerless) error {
-	if opensearchServerless.Spec.CollectionName == "" {
-		return fmt.Errorf("OpenSearch Serverless collectionName is required")
+	// collectionName defaults to the resource name, so validate the name that will actually be used
+	if err := models.ValidateCollectionName(opensearchServerless.EffectiveCollectionName()); err != nil {
+		return err
 	}
-	return nil
+
+	return opensearchServerless.Spec.EncryptionPolicy.Validate()
 }
 
 func (p *YAMLParser) validateAgentKnowledgeBaseAssociation(association *models.AgentKnowledgeBaseAssociation) error {
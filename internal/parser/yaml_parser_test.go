@@ -0,0 +1,289 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"bedrock-forge/internal/models"
+)
+
+func newTestParser() *YAMLParser {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewYAMLParser(logger)
+}
+
+// parseLambdas parses content and returns its Lambdas by name
+func parseLambdas(t *testing.T, parser *YAMLParser, content string) map[string]*models.Lambda {
+	t.Helper()
+
+	resources, err := parser.ParseContent([]byte(content), "lambdas.yml")
+	if err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+
+	lambdas := make(map[string]*models.Lambda)
+	for _, resource := range resources {
+		lambda, ok := resource.Resource.(*models.Lambda)
+		if !ok {
+			t.Fatalf("unexpected %s resource", resource.Kind)
+		}
+		lambdas[lambda.Metadata.Name] = lambda
+	}
+	return lambdas
+}
+
+const defaultsContent = `kind: Defaults
+spec:
+  runtime: python3.11
+  timeout: 30
+  architectures: [x86_64]
+  tags:
+    Team: platform
+    CostCenter: "1000"
+---
+kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+  tags:
+    CostCenter: "2000"
+    Service: orders
+---
+kind: Lambda
+metadata:
+  name: product-search
+spec:
+  runtime: nodejs20.x
+  handler: index.handler
+  timeout: 60
+  architectures: [arm64]
+  code:
+    inline: "exports.handler = async (event) => event"
+`
+
+func TestDefaultsMergeTags(t *testing.T) {
+	lambdas := parseLambdas(t, newTestParser(), defaultsContent)
+
+	orderLookup, ok := lambdas["order-lookup"]
+	if !ok {
+		t.Fatalf("order-lookup was not parsed, got %v", lambdas)
+	}
+	want := map[string]string{
+		"Team":       "platform", // From Defaults
+		"CostCenter": "2000",     // Resource value wins
+		"Service":    "orders",   // Resource only
+	}
+	if !reflect.DeepEqual(orderLookup.Spec.Tags, want) {
+		t.Errorf("tags = %v, want %v", orderLookup.Spec.Tags, want)
+	}
+
+	// Resources without tags of their own get the default tags
+	productSearch := lambdas["product-search"]
+	if want := map[string]string{"Team": "platform", "CostCenter": "1000"}; !reflect.DeepEqual(productSearch.Spec.Tags, want) {
+		t.Errorf("tags = %v, want %v", productSearch.Spec.Tags, want)
+	}
+}
+
+func TestDefaultsResourceValuesWin(t *testing.T) {
+	lambdas := parseLambdas(t, newTestParser(), defaultsContent)
+
+	tests := []struct {
+		name          string
+		runtime       string
+		timeout       int
+		architectures []string
+	}{
+		// Unset fields come from Defaults
+		{name: "order-lookup", runtime: "python3.11", timeout: 30, architectures: []string{"x86_64"}},
+		// Scalars and lists set on the resource replace the defaults
+		{name: "product-search", runtime: "nodejs20.x", timeout: 60, architectures: []string{"arm64"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := lambdas[test.name].Spec
+			if spec.Runtime != test.runtime {
+				t.Errorf("runtime = %s, want %s", spec.Runtime, test.runtime)
+			}
+			if spec.Timeout != test.timeout {
+				t.Errorf("timeout = %d, want %d", spec.Timeout, test.timeout)
+			}
+			if !reflect.DeepEqual(spec.Architectures, test.architectures) {
+				t.Errorf("architectures = %v, want %v", spec.Architectures, test.architectures)
+			}
+		})
+	}
+}
+
+func TestDefaultsApplyWithinOneFile(t *testing.T) {
+	parser := newTestParser()
+	parseLambdas(t, parser, defaultsContent)
+
+	lambdas := parseLambdas(t, parser, `kind: Lambda
+metadata:
+  name: standalone
+spec:
+  runtime: python3.12
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`)
+
+	standalone := lambdas["standalone"]
+	if standalone.Spec.Tags != nil || standalone.Spec.Timeout != 0 {
+		t.Errorf("defaults of another file leaked: tags %v, timeout %d", standalone.Spec.Tags, standalone.Spec.Timeout)
+	}
+}
+
+const unknownFieldContent = `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  timout: 30
+  code:
+    inline: "def handler(event, context): return event"
+`
+
+func TestUnknownFieldsWarnWithoutStrictFields(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	lambdas := parseLambdas(t, NewYAMLParser(logger), unknownFieldContent)
+	if _, ok := lambdas["order-lookup"]; !ok {
+		t.Fatalf("order-lookup was not parsed, got %v", lambdas)
+	}
+
+	var warnings []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	want := "Ignoring spec.timout: unknown field, did you mean timeout?, use --strict-fields to reject it"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("expected warning %q, got %v", want, warnings)
+	}
+}
+
+func TestStrictFieldsRejectUnknownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "misspelled field",
+			content: unknownFieldContent,
+			err:     "line 7: spec.timout: unknown field, did you mean timeout?",
+		},
+		{
+			name: "field merged in from Defaults",
+			content: `kind: Defaults
+spec:
+  runtime: python3.11
+  retentionDays: 14
+---
+kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+`,
+			err: "document 1 (Lambda order-lookup): failed to unmarshal Lambda: unknown fields after merging Defaults",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := newTestParser()
+			parser.SetStrictFields(true)
+
+			resources, err := parser.ParseContent([]byte(tc.content), "lambdas.yml")
+			if len(resources) != 0 {
+				t.Fatalf("expected the Lambda to be rejected, got %d resources", len(resources))
+			}
+			var documentErr *DocumentError
+			if !errors.As(err, &documentErr) || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("ParseContent() = %v, want a DocumentError containing %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInvalidResourceDocumentsAreErrors(t *testing.T) {
+	content := `kind: Agent
+metadata:
+  name: support
+spec:
+  foundationModel: anthropic.claude-3-sonnet-20240229-v1:0
+---
+kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    inline: "def handler(event, context): return event"
+---
+name: Not a resource, e.g. a workflow
+on: push
+`
+
+	resources, err := newTestParser().ParseContent([]byte(content), "resources.yml")
+
+	// The valid Lambda is still returned, the document without a kind is skipped
+	if len(resources) != 1 || resources[0].Metadata.Name != "order-lookup" {
+		t.Errorf("expected only order-lookup to parse, got %d resources", len(resources))
+	}
+
+	var documentErr *DocumentError
+	if !errors.As(err, &documentErr) {
+		t.Fatalf("ParseContent() = %v, want a DocumentError", err)
+	}
+	if documentErr.Index != 0 || documentErr.Kind != models.AgentKind || documentErr.Name != "support" {
+		t.Errorf("DocumentError is for document %d %s %s, want document 0 Agent support", documentErr.Index, documentErr.Kind, documentErr.Name)
+	}
+	if !strings.Contains(err.Error(), "spec.instruction: required field is missing") {
+		t.Errorf("error doesn't name the missing field: %v", err)
+	}
+}
+
+func TestConflictingLambdaCodeSourcesAreErrors(t *testing.T) {
+	content := `kind: Lambda
+metadata:
+  name: order-lookup
+spec:
+  runtime: python3.11
+  handler: app.handler
+  code:
+    zipFile: dist/order-lookup.zip
+    s3Bucket: artifacts
+    s3Key: order-lookup.zip
+`
+
+	resources, err := newTestParser().ParseContent([]byte(content), "lambdas.yml")
+	if len(resources) != 0 {
+		t.Fatalf("expected the Lambda to be rejected, got %d resources", len(resources))
+	}
+	var documentErr *DocumentError
+	want := "exactly one of inline, zipFile, s3Bucket or source, got: zipFile, s3Bucket"
+	if !errors.As(err, &documentErr) || documentErr.Name != "order-lookup" || !strings.Contains(err.Error(), want) {
+		t.Errorf("ParseContent() = %v, want a DocumentError for order-lookup containing %q", err, want)
+	}
+}
@@ -0,0 +1,129 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+)
+
+// ResourceReference identifies another resource referenced by a resource spec
+type ResourceReference struct {
+	Kind  models.ResourceKind `json:"kind,omitempty"`
+	Name  string              `json:"name"`
+	Field string              `json:"field"`
+}
+
+// GetResourceReferences returns the immediate references a resource makes to other resources
+func (r *ResourceRegistry) GetResourceReferences(resource *parser.ParsedResource) []ResourceReference {
+	var refs []ResourceReference
+
+	add := func(kind models.ResourceKind, ref models.Reference, field string) {
+		if ref.IsEmpty() {
+			return
+		}
+		refs = append(refs, ResourceReference{Kind: kind, Name: ref.String(), Field: field})
+	}
+
+	switch res := resource.Resource.(type) {
+	case *models.Agent:
+		if res.Spec.Guardrail != nil {
+			add(models.GuardrailKind, res.Spec.Guardrail.Name, "spec.guardrail.name")
+		}
+		for _, alias := range res.Spec.Aliases {
+			if alias.Guardrail != nil {
+				add(models.GuardrailKind, alias.Guardrail.Name, "spec.aliases.guardrail.name")
+			}
+		}
+		for _, promptOverride := range res.Spec.PromptOverrides {
+			add(models.PromptKind, promptOverride.Prompt, "spec.promptOverrides.prompt")
+		}
+		for _, ag := range res.Spec.ActionGroups {
+			if ag.ActionGroupExecutor != nil {
+				add(models.LambdaKind, ag.ActionGroupExecutor.Lambda, "spec.actionGroups.actionGroupExecutor.lambda")
+			}
+		}
+		if res.Spec.IAMRole != nil {
+			add(models.IAMRoleKind, res.Spec.IAMRole.RoleName, "spec.iamRole.roleName")
+		}
+		if res.Spec.AgentCollaboration != nil {
+			for _, collaborator := range res.Spec.AgentCollaboration.Collaborators {
+				add(models.AgentKind, collaborator.Agent, "spec.agentCollaboration.collaborators.agent")
+			}
+		}
+
+	case *models.Lambda:
+		// ARNs and ${ref:custom.<output>} references don't point at IAMRole resources
+		if role := res.Spec.Role.String(); !strings.HasPrefix(role, "arn:") && !strings.HasPrefix(role, "${ref:") {
+			add(models.IAMRoleKind, res.Spec.Role, "spec.role")
+		}
+		for _, layer := range res.Spec.Layers {
+			if !models.IsExternalLayer(layer) {
+				add(models.LambdaLayerKind, layer, "spec.layers")
+			}
+		}
+
+	case *models.ActionGroup:
+		add(models.AgentKind, res.Spec.AgentId, "spec.agentId")
+		if res.Spec.ActionGroupExecutor != nil {
+			add(models.LambdaKind, res.Spec.ActionGroupExecutor.Lambda, "spec.actionGroupExecutor.lambda")
+		}
+
+	case *models.KnowledgeBase:
+		if res.Spec.StorageConfiguration != nil && res.Spec.StorageConfiguration.OpenSearchServerless != nil {
+			if collectionName := res.Spec.StorageConfiguration.OpenSearchServerless.CollectionName; collectionName != nil {
+				add(models.OpenSearchServerlessKind, *collectionName, "spec.storageConfiguration.openSearchServerless.collectionName")
+			}
+		}
+		for _, dataSource := range res.Spec.DataSources {
+			if dataSource.CustomTransformation != nil && dataSource.CustomTransformation.TransformationLambda != nil {
+				add(models.LambdaKind, dataSource.CustomTransformation.TransformationLambda.Lambda, "spec.dataSources.customTransformation.transformationLambda.lambda")
+			}
+		}
+
+	case *models.AgentKnowledgeBaseAssociation:
+		if !res.Spec.AgentName.IsEmpty() {
+			add(models.AgentKind, res.Spec.AgentName, "spec.agentName")
+		} else {
+			add(models.AgentKind, res.Spec.AgentId, "spec.agentId")
+		}
+		if !res.Spec.KnowledgeBaseName.IsEmpty() {
+			add(models.KnowledgeBaseKind, res.Spec.KnowledgeBaseName, "spec.knowledgeBaseName")
+		} else {
+			add(models.KnowledgeBaseKind, res.Spec.KnowledgeBaseId, "spec.knowledgeBaseId")
+		}
+
+	case *models.CustomResources:
+		for _, depRef := range res.Spec.DependsOn {
+			// The kind of a dependsOn reference is resolved by name
+			add(r.findKindByName(depRef.String()), depRef, "spec.dependsOn")
+		}
+	}
+
+	for _, depRef := range resource.Metadata.DependsOn {
+		add(r.findKindByName(depRef.String()), depRef, "metadata.dependsOn")
+	}
+
+	return refs
+}
+
+// findKindByName returns the kind of the first resource registered with the given name
+func (r *ResourceRegistry) findKindByName(name string) models.ResourceKind {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	kinds := make([]string, 0, len(r.resources))
+	for kind := range r.resources {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		if _, exists := r.resources[models.ResourceKind(kind)][name]; exists {
+			return models.ResourceKind(kind)
+		}
+	}
+
+	return ""
+}
@@ -2,6 +2,9 @@ package registry
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -144,6 +147,16 @@ func (r *ResourceRegistry) ValidateDependencies() []error {
 			}
 		}
 
+		for _, alias := range agent.Spec.Aliases {
+			if alias.Guardrail == nil || alias.Guardrail.Name.IsEmpty() {
+				continue
+			}
+			guardrailName := alias.Guardrail.Name.String()
+			if _, exists := r.resources[models.GuardrailKind][guardrailName]; !exists {
+				errors = append(errors, fmt.Errorf("agent %s alias %s references non-existent guardrail %s", agent.Metadata.Name, alias.Name, guardrailName))
+			}
+		}
+
 		// Knowledge bases are now handled through separate association resources
 
 		// Action groups are now inline definitions within the agent
@@ -152,19 +165,28 @@ func (r *ResourceRegistry) ValidateDependencies() []error {
 			if ag.ActionGroupExecutor != nil {
 				if !ag.ActionGroupExecutor.Lambda.IsEmpty() {
 					lambdaName := ag.ActionGroupExecutor.Lambda.String()
-					if _, exists := r.resources[models.LambdaKind][lambdaName]; !exists {
+					if _, exists := r.resources[models.LambdaKind][lambdaName]; !exists && !models.IsLambdaFunctionArn(lambdaName) {
 						errors = append(errors, fmt.Errorf("agent %s action group %s references non-existent lambda %s", agent.Metadata.Name, ag.Name, lambdaName))
 					}
 				}
-				// LambdaArn references are external and don't need validation
+				// LambdaArn references and Lambda ARNs are external and don't need validation
 			}
 		}
 
 		for _, promptOverride := range agent.Spec.PromptOverrides {
 			if !promptOverride.Prompt.IsEmpty() {
 				promptName := promptOverride.Prompt.String()
-				if _, exists := r.resources[models.PromptKind][promptName]; !exists {
+				promptResource, exists := r.resources[models.PromptKind][promptName]
+				if !exists {
 					errors = append(errors, fmt.Errorf("agent %s references non-existent prompt %s", agent.Metadata.Name, promptName))
+					continue
+				}
+				prompt := promptResource.Resource.(*models.Prompt)
+				if err := validatePromptVersion(prompt, promptOverride.Version); err != nil {
+					errors = append(errors, fmt.Errorf("agent %s prompt override %s: %w", agent.Metadata.Name, promptOverride.PromptType, err))
+				}
+				if variant := promptOverride.VariantName(); variant != "" && !prompt.Spec.HasVariant(variant) {
+					errors = append(errors, fmt.Errorf("agent %s prompt override %s references variant %s, which prompt %s does not define", agent.Metadata.Name, promptOverride.PromptType, variant, promptName))
 				}
 			}
 		}
@@ -187,16 +209,276 @@ func (r *ResourceRegistry) ValidateDependencies() []error {
 			// If lambda name is specified, validate it exists in the registry
 			if !actionGroup.Spec.ActionGroupExecutor.Lambda.IsEmpty() {
 				lambdaName := actionGroup.Spec.ActionGroupExecutor.Lambda.String()
-				if _, exists := r.resources[models.LambdaKind][lambdaName]; !exists {
+				if _, exists := r.resources[models.LambdaKind][lambdaName]; !exists && !models.IsLambdaFunctionArn(lambdaName) {
 					errors = append(errors, fmt.Errorf("action group %s references non-existent lambda %s", actionGroup.Metadata.Name, lambdaName))
 				}
 			}
 		}
 	}
 
+	lambdas := r.resources[models.LambdaKind]
+	for _, lambdaResource := range lambdas {
+		lambda := lambdaResource.Resource.(*models.Lambda)
+		for _, layer := range lambda.Spec.Layers {
+			if layer.IsEmpty() || models.IsExternalLayer(layer) {
+				continue
+			}
+			if _, exists := r.resources[models.LambdaLayerKind][layer.String()]; !exists {
+				errors = append(errors, fmt.Errorf("lambda %s references non-existent lambda layer %s", lambda.Metadata.Name, layer.String()))
+			}
+		}
+	}
+
+	errors = append(errors, r.validateActionGroupCount()...)
+	errors = append(errors, r.validateAgentCollaboration()...)
+
+	// metadata.dependsOn may point at a resource of any kind
+	for _, kindResources := range r.resources {
+		for _, resource := range kindResources {
+			for _, depRef := range resource.Metadata.DependsOn {
+				if depRef.IsEmpty() {
+					continue
+				}
+
+				depName := depRef.String()
+				found := false
+				for _, resources := range r.resources {
+					if _, exists := resources[depName]; exists {
+						found = true
+						break
+					}
+				}
+				if !found {
+					errors = append(errors, fmt.Errorf("%s %s depends on non-existent resource %s", resource.Kind, resource.Metadata.Name, depName))
+				}
+			}
+		}
+	}
+
+	errors = append(errors, r.validatePreviousNames()...)
+	errors = append(errors, r.validateImports()...)
+
 	return errors
 }
 
+// validateImports checks metadata.import IDs and that no existing resource is imported twice.
+// The caller must hold the read lock.
+func (r *ResourceRegistry) validateImports() []error {
+	var errors []error
+	importedBy := make(map[string]string)
+
+	for kind, kindResources := range r.resources {
+		for name, resource := range kindResources {
+			id := resource.Metadata.Import
+			if id == "" {
+				continue
+			}
+
+			if err := models.ValidateImportID(kind, id); err != nil {
+				errors = append(errors, fmt.Errorf("%s %s: %w", kind, name, err))
+				continue
+			}
+
+			key := fmt.Sprintf("%s/%s", kind, id)
+			if other, exists := importedBy[key]; exists {
+				errors = append(errors, fmt.Errorf("%s %s and %s both import %s", kind, other, name, id))
+				continue
+			}
+			importedBy[key] = name
+		}
+	}
+
+	return errors
+}
+
+// validatePreviousNames checks that metadata.previousNames don't name an active resource of the same kind
+// and that no former name is claimed by two resources of one kind. Names are only unique within a kind,
+// so resources of other kinds are never compared. The caller must hold the read lock.
+func (r *ResourceRegistry) validatePreviousNames() []error {
+	var errors []error
+
+	kinds := make([]string, 0, len(r.resources))
+	for kind := range r.resources {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		kindResources := r.resources[models.ResourceKind(kind)]
+		names := make([]string, 0, len(kindResources))
+		for name := range kindResources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		claimedBy := make(map[string]string)
+		for _, name := range names {
+			for _, previousName := range kindResources[name].Metadata.PreviousNames {
+				if _, exists := kindResources[previousName]; exists {
+					errors = append(errors, fmt.Errorf("%s %s lists previous name %s, which is the name of an active %s", kind, name, previousName, kind))
+					continue
+				}
+
+				if other, exists := claimedBy[previousName]; exists && other != name {
+					errors = append(errors, fmt.Errorf("%s %s and %s both list previous name %s", kind, other, name, previousName))
+					continue
+				}
+				claimedBy[previousName] = name
+			}
+		}
+	}
+
+	return errors
+}
+
+// validateActionGroupCount checks that no agent has more inline and standalone action groups than Bedrock
+// allows. The caller must hold the read lock.
+func (r *ResourceRegistry) validateActionGroupCount() []error {
+	counts := make(map[string]int)
+	for name, agentResource := range r.resources[models.AgentKind] {
+		counts[name] = len(agentResource.Resource.(*models.Agent).Spec.ActionGroups)
+	}
+	for _, actionGroupResource := range r.resources[models.ActionGroupKind] {
+		agentName := actionGroupResource.Resource.(*models.ActionGroup).Spec.AgentId.String()
+		// Action groups of agents managed outside this project can't be counted
+		if _, exists := counts[agentName]; exists {
+			counts[agentName]++
+		}
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errors []error
+	for _, name := range names {
+		if counts[name] > models.MaxActionGroupsPerAgent {
+			errors = append(errors, fmt.Errorf("agent %s has %d action groups (inline and standalone), which exceeds the Bedrock limit of %d", name, counts[name], models.MaxActionGroupsPerAgent))
+		}
+	}
+	return errors
+}
+
+// validateAgentCollaboration checks collaborator references for self-references, missing agents and cycles.
+// The caller must hold the read lock.
+func (r *ResourceRegistry) validateAgentCollaboration() []error {
+	var errors []error
+	collaborators := make(map[string][]string)
+
+	agents := r.resources[models.AgentKind]
+	for name, agentResource := range agents {
+		agent := agentResource.Resource.(*models.Agent)
+		if agent.Spec.AgentCollaboration == nil {
+			continue
+		}
+
+		switch agent.Spec.AgentCollaboration.CollaborationType {
+		case "SUPERVISOR", "SUPERVISOR_ROUTER", "DISABLED":
+		default:
+			errors = append(errors, fmt.Errorf("agent %s has invalid collaborationType '%s', must be one of: SUPERVISOR, SUPERVISOR_ROUTER, DISABLED", name, agent.Spec.AgentCollaboration.CollaborationType))
+		}
+
+		for _, collaborator := range agent.Spec.AgentCollaboration.Collaborators {
+			if collaborator.Agent.IsEmpty() {
+				if collaborator.AliasArn == "" {
+					errors = append(errors, fmt.Errorf("agent %s has a collaborator without agent or aliasArn", name))
+				}
+				continue
+			}
+
+			collaboratorName := collaborator.Agent.String()
+			if collaboratorName == name {
+				errors = append(errors, fmt.Errorf("agent %s cannot be its own collaborator", name))
+				continue
+			}
+			if _, exists := agents[collaboratorName]; !exists {
+				errors = append(errors, fmt.Errorf("agent %s references non-existent collaborator agent %s", name, collaboratorName))
+				continue
+			}
+			collaborators[name] = append(collaborators[name], collaboratorName)
+		}
+	}
+
+	if cycle := findCollaborationCycle(collaborators); len(cycle) > 0 {
+		errors = append(errors, fmt.Errorf("agent collaboration cycle detected: %s", strings.Join(cycle, " -> ")))
+	}
+
+	return errors
+}
+
+// validatePromptVersion checks that a prompt override's version exists on the referenced prompt
+func validatePromptVersion(prompt *models.Prompt, version string) error {
+	if version == "" || version == "DRAFT" || version == "latest" {
+		return nil
+	}
+
+	number, err := strconv.Atoi(version)
+	if err != nil || number < 1 {
+		return fmt.Errorf("invalid version %q for prompt %s, expected a version number, DRAFT or latest", version, prompt.Metadata.Name)
+	}
+
+	// Prompts without declared versions may have been versioned outside this project
+	if len(prompt.Spec.Versions) > 0 && number > len(prompt.Spec.Versions) {
+		return fmt.Errorf("prompt %s declares %d versions, version %d does not exist", prompt.Metadata.Name, len(prompt.Spec.Versions), number)
+	}
+
+	return nil
+}
+
+// findCollaborationCycle returns the first supervisor/collaborator cycle, starting and ending with the same agent
+func findCollaborationCycle(collaborators map[string][]string) []string {
+	supervisors := make([]string, 0, len(collaborators))
+	for name := range collaborators {
+		supervisors = append(supervisors, name)
+	}
+	sort.Strings(supervisors)
+
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = inStack
+		stack = append(stack, name)
+
+		for _, next := range collaborators[name] {
+			switch state[next] {
+			case inStack:
+				for i, stacked := range stack {
+					if stacked == next {
+						return append(append([]string{}, stack[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, name := range supervisors {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *ResourceRegistry) Clear() {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -236,6 +518,10 @@ func (r *ResourceRegistry) GetResourcesByType(kind models.ResourceKind) []models
 				if lambda, ok := resource.Resource.(*models.Lambda); ok {
 					spec = lambda.Spec
 				}
+			case models.LambdaLayerKind:
+				if lambdaLayer, ok := resource.Resource.(*models.LambdaLayer); ok {
+					spec = lambdaLayer.Spec
+				}
 			case models.ActionGroupKind:
 				if actionGroup, ok := resource.Resource.(*models.ActionGroup); ok {
 					spec = actionGroup.Spec
@@ -278,5 +564,39 @@ func (r *ResourceRegistry) GetResourcesByType(kind models.ResourceKind) []models
 			})
 		}
 	}
+
+	// Sort by name so generated output is stable across runs
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Metadata.Name < result[j].Metadata.Name
+	})
+
 	return result
 }
+
+// ApplyOverlay replaces a resource with the result of merging an overlay resource over it. Overlays can
+// only change resources that exist in the base configuration.
+func (r *ResourceRegistry) ApplyOverlay(overlay *parser.ParsedResource, yamlParser *parser.YAMLParser) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	name := overlay.Metadata.Name
+	base, exists := r.resources[overlay.Kind][name]
+	if !exists {
+		return fmt.Errorf("overlay defines %s %s, which does not exist in the base configuration", overlay.Kind, name)
+	}
+
+	merged, fields, err := yamlParser.ApplyOverlay(base, overlay)
+	if err != nil {
+		return fmt.Errorf("failed to apply overlay to %s %s: %w", overlay.Kind, name, err)
+	}
+	r.resources[overlay.Kind][name] = merged
+
+	r.logger.WithFields(logrus.Fields{
+		"kind":       overlay.Kind,
+		"name":       name,
+		"overlay":    overlay.FilePath,
+		"overridden": strings.Join(fields, ", "),
+	}).Info("Applied overlay")
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+)
+
+func TestValidatePreviousNames(t *testing.T) {
+	r := NewResourceRegistry(logrus.New())
+	add := func(kind models.ResourceKind, name string, previousNames ...string) {
+		t.Helper()
+		resource := &parser.ParsedResource{Kind: kind, Metadata: models.Metadata{Name: name, PreviousNames: previousNames}}
+		if err := r.AddResource(resource); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A Lambda may take a name an Agent used to have
+	add(models.AgentKind, "support", "helpdesk")
+	add(models.LambdaKind, "helpdesk", "support")
+	// Prompts share names with other kinds, but not their former names
+	add(models.PromptKind, "greeting", "old-greeting")
+	add(models.PromptKind, "welcome", "old-greeting", "greeting")
+
+	var got []string
+	for _, err := range r.validatePreviousNames() {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"Prompt greeting and welcome both list previous name old-greeting",
+		"Prompt welcome lists previous name greeting, which is the name of an active Prompt",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("validatePreviousNames() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("error %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
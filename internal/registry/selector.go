@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/parser"
+)
+
+// labelRequirement is a single key=value or key!=value term of a selector
+type labelRequirement struct {
+	Key     string
+	Value   string
+	Negated bool
+}
+
+// LabelSelector matches resources by metadata.labels. All requirements must match.
+type LabelSelector struct {
+	requirements []labelRequirement
+}
+
+// ParseLabelSelector parses a comma-separated selector such as "team=payments,tier!=experimental"
+func ParseLabelSelector(selector string) (*LabelSelector, error) {
+	result := &LabelSelector{}
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		requirement := labelRequirement{}
+		if key, value, found := strings.Cut(term, "!="); found {
+			requirement = labelRequirement{Key: key, Value: value, Negated: true}
+		} else if key, value, found := strings.Cut(term, "="); found {
+			requirement = labelRequirement{Key: key, Value: value}
+		} else {
+			return nil, fmt.Errorf("invalid selector term '%s', expected key=value or key!=value", term)
+		}
+
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+		if requirement.Key == "" {
+			return nil, fmt.Errorf("invalid selector term '%s', label key is empty", term)
+		}
+
+		result.requirements = append(result.requirements, requirement)
+	}
+
+	if len(result.requirements) == 0 {
+		return nil, fmt.Errorf("selector is empty")
+	}
+
+	return result, nil
+}
+
+// Matches reports whether the labels satisfy every requirement of the selector
+func (s *LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.requirements {
+		value, exists := labels[requirement.Key]
+		if requirement.Negated {
+			if exists && value == requirement.Value {
+				return false
+			}
+		} else if !exists || value != requirement.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterBySelector removes every resource that neither matches the selector nor is a transitive
+// dependency of a matching resource, and returns the number of resources kept
+func (r *ResourceRegistry) FilterBySelector(selector *LabelSelector) int {
+	type resourceKey struct {
+		kind models.ResourceKind
+		name string
+	}
+
+	keep := make(map[resourceKey]bool)
+	var queue []*parser.ParsedResource
+
+	for kind, kindResources := range r.GetAllResources() {
+		for name, resource := range kindResources {
+			if selector.Matches(resource.Metadata.Labels) {
+				keep[resourceKey{kind, name}] = true
+				queue = append(queue, resource)
+			}
+		}
+	}
+
+	// Follow references so the selected resources still generate valid output
+	for len(queue) > 0 {
+		resource := queue[0]
+		queue = queue[1:]
+
+		for _, ref := range r.GetResourceReferences(resource) {
+			key := resourceKey{ref.Kind, ref.Name}
+			if keep[key] {
+				continue
+			}
+			if dependency, exists := r.GetResource(ref.Kind, ref.Name); exists {
+				keep[key] = true
+				queue = append(queue, dependency)
+			}
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for kind, kindResources := range r.resources {
+		for name := range kindResources {
+			if !keep[resourceKey{kind, name}] {
+				delete(kindResources, name)
+			}
+		}
+	}
+
+	return len(keep)
+}
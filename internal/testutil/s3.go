@@ -0,0 +1,166 @@
+// Package testutil provides in-memory fakes and assertion helpers for unit testing code that talks to AWS
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"bedrock-forge/internal/packager"
+)
+
+// Upload is an object recorded by MockS3Client
+type Upload struct {
+	Bucket      string
+	Key         string
+	Content     []byte
+	Hash        string // SHA256 of Content, hex encoded
+	ContentType string // Empty for file uploads
+	Options     packager.UploadOptions
+}
+
+// MockS3Client is an in-memory packager.S3Client that records every upload and can be told to fail
+// specific calls, to exercise retry logic
+type MockS3Client struct {
+	mutex    sync.Mutex
+	uploads  []Upload
+	calls    int
+	failures map[int]error // Call number, counting from 1 -> error returned instead of uploading
+}
+
+var _ packager.S3Client = (*MockS3Client)(nil)
+
+// NewMockS3Client creates a mock S3 client with no recorded uploads
+func NewMockS3Client() *MockS3Client {
+	return &MockS3Client{
+		failures: make(map[int]error),
+	}
+}
+
+// FailOnCall makes the nth upload call, counting from 1 across all upload methods, return err. A nil
+// err fails with a retryable 503 Service Unavailable.
+func (c *MockS3Client) FailOnCall(n int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.failures[n] = err
+}
+
+// UploadFile records the content of a local file
+func (c *MockS3Client) UploadFile(bucket, key string, filePath string) (string, error) {
+	return c.UploadFileWithOptions(bucket, key, filePath, packager.UploadOptions{})
+}
+
+// UploadFileWithOptions records the content of a local file along with its upload options
+func (c *MockS3Client) UploadFileWithOptions(bucket, key string, filePath string, options packager.UploadOptions) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return c.record(Upload{Bucket: bucket, Key: key, Content: content, Options: options})
+}
+
+// UploadContent records content
+func (c *MockS3Client) UploadContent(bucket, key string, content []byte, contentType string) (string, error) {
+	return c.record(Upload{Bucket: bucket, Key: key, Content: content, ContentType: contentType})
+}
+
+// record counts a call and stores the upload unless the call is set to fail
+func (c *MockS3Client) record(upload Upload) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.calls++
+	if err, fail := c.failures[c.calls]; fail {
+		if err == nil {
+			err = &packager.S3UploadError{
+				Bucket:     upload.Bucket,
+				Key:        upload.Key,
+				StatusCode: http.StatusServiceUnavailable,
+				Status:     "503 Service Unavailable",
+				Message:    fmt.Sprintf("mock failure on call %d", c.calls),
+			}
+		}
+		return "", err
+	}
+
+	hash := sha256.Sum256(upload.Content)
+	upload.Hash = hex.EncodeToString(hash[:])
+	upload.Content = append([]byte(nil), upload.Content...)
+	c.uploads = append(c.uploads, upload)
+
+	return fmt.Sprintf("s3://%s/%s", upload.Bucket, upload.Key), nil
+}
+
+// Calls returns the number of upload calls, including failed ones
+func (c *MockS3Client) Calls() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.calls
+}
+
+// Uploads returns the successful uploads in the order they were made
+func (c *MockS3Client) Uploads() []Upload {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]Upload(nil), c.uploads...)
+}
+
+// Upload returns the latest successful upload to key
+func (c *MockS3Client) Upload(key string) (Upload, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := len(c.uploads) - 1; i >= 0; i-- {
+		if c.uploads[i].Key == key {
+			return c.uploads[i], true
+		}
+	}
+	return Upload{}, false
+}
+
+// Keys returns the sorted keys that were uploaded
+func (c *MockS3Client) Keys() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	seen := make(map[string]bool)
+	var keys []string
+	for _, upload := range c.uploads {
+		if !seen[upload.Key] {
+			seen[upload.Key] = true
+			keys = append(keys, upload.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AssertUploaded fails the test unless key was uploaded, and returns its latest upload
+func (c *MockS3Client) AssertUploaded(t testing.TB, key string) Upload {
+	t.Helper()
+	upload, ok := c.Upload(key)
+	if !ok {
+		t.Fatalf("expected an upload to %s, uploaded keys: %v", key, c.Keys())
+	}
+	return upload
+}
+
+// AssertNotUploaded fails the test if key was uploaded
+func (c *MockS3Client) AssertNotUploaded(t testing.TB, key string) {
+	t.Helper()
+	if _, ok := c.Upload(key); ok {
+		t.Fatalf("expected no upload to %s", key)
+	}
+}
+
+// AssertUploadCount fails the test unless exactly n uploads succeeded
+func (c *MockS3Client) AssertUploadCount(t testing.TB, n int) {
+	t.Helper()
+	if uploads := c.Uploads(); len(uploads) != n {
+		t.Fatalf("expected %d uploads, got %d: %v", n, len(uploads), c.Keys())
+	}
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/registry"
+)
+
+// validateCollectionAccess returns an error for every knowledge base whose auto-created OpenSearch
+// Serverless collection has a data access policy that doesn't grant the knowledge base's role. AOSS
+// then rejects the role's requests, which Bedrock only reports as failed ingestion jobs.
+func (v *Validator) validateCollectionAccess(reg *registry.ResourceRegistry) []ValidationError {
+	errors := []ValidationError{}
+
+	names := reg.ListResourceNames(models.KnowledgeBaseKind)
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource, _ := reg.GetResource(models.KnowledgeBaseKind, name)
+		kb, ok := resource.Resource.(*models.KnowledgeBase)
+		if !ok || kb.Spec.StorageConfiguration == nil {
+			continue
+		}
+
+		reference := kb.Spec.StorageConfiguration.OpenSearchServerless
+		if reference == nil || reference.CollectionArn != nil || reference.CollectionName == nil || reference.CollectionName.IsEmpty() {
+			continue
+		}
+
+		// Missing collections are reported as dependency errors
+		collectionResource, exists := reg.GetResource(models.OpenSearchServerlessKind, reference.CollectionName.String())
+		if !exists {
+			continue
+		}
+		collection, ok := collectionResource.Resource.(*models.OpenSearchServerless)
+		if !ok {
+			continue
+		}
+
+		// Roles given by a reference are only named at apply time
+		roleName := kb.Spec.ExecutionRoleName(name)
+		if roleName == "" || collection.Spec.AccessPolicy.GrantsRole(roleName) {
+			continue
+		}
+
+		errors = append(errors, ValidationError{
+			Type:     "collection_access",
+			Message:  fmt.Sprintf("OpenSearchServerless %s's access policy doesn't grant the knowledge base role %s; add its ARN to spec.accessPolicy.principals or set spec.accessPolicy.autoConfigureForBedrock", collection.Metadata.Name, roleName),
+			Resource: fmt.Sprintf("KnowledgeBase/%s", name),
+			Field:    "spec.storageConfiguration.openSearchServerless.collectionName",
+			Severity: "error",
+		})
+	}
+
+	return errors
+}
@@ -0,0 +1,221 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"bedrock-forge/internal/models"
+)
+
+// builtinFoundationModels lists the Bedrock foundation model IDs known to bedrock-forge
+var builtinFoundationModels = []string{
+	"anthropic.claude-instant-v1",
+	"anthropic.claude-v2",
+	"anthropic.claude-v2:1",
+	"anthropic.claude-3-haiku-20240307-v1:0",
+	"anthropic.claude-3-sonnet-20240229-v1:0",
+	"anthropic.claude-3-opus-20240229-v1:0",
+	"anthropic.claude-3-5-haiku-20241022-v1:0",
+	"anthropic.claude-3-5-sonnet-20240620-v1:0",
+	"anthropic.claude-3-5-sonnet-20241022-v2:0",
+	"anthropic.claude-3-7-sonnet-20250219-v1:0",
+	"anthropic.claude-sonnet-4-20250514-v1:0",
+	"anthropic.claude-opus-4-20250514-v1:0",
+	"amazon.nova-micro-v1:0",
+	"amazon.nova-lite-v1:0",
+	"amazon.nova-pro-v1:0",
+	"amazon.nova-premier-v1:0",
+	"amazon.titan-text-express-v1",
+	"amazon.titan-text-lite-v1",
+	"amazon.titan-text-premier-v1:0",
+	"meta.llama3-8b-instruct-v1:0",
+	"meta.llama3-70b-instruct-v1:0",
+	"meta.llama3-1-8b-instruct-v1:0",
+	"meta.llama3-1-70b-instruct-v1:0",
+	"meta.llama3-1-405b-instruct-v1:0",
+	"meta.llama3-2-1b-instruct-v1:0",
+	"meta.llama3-2-3b-instruct-v1:0",
+	"meta.llama3-2-11b-instruct-v1:0",
+	"meta.llama3-2-90b-instruct-v1:0",
+	"meta.llama3-3-70b-instruct-v1:0",
+	"mistral.mistral-7b-instruct-v0:2",
+	"mistral.mixtral-8x7b-instruct-v0:1",
+	"mistral.mistral-large-2402-v1:0",
+	"mistral.mistral-large-2407-v1:0",
+	"mistral.mistral-small-2402-v1:0",
+	"cohere.command-r-v1:0",
+	"cohere.command-r-plus-v1:0",
+	"ai21.jamba-1-5-mini-v1:0",
+	"ai21.jamba-1-5-large-v1:0",
+	"deepseek.r1-v1:0",
+}
+
+// inferenceProfilePrefix matches cross-region inference profile prefixes such as "us." or "apac."
+var inferenceProfilePrefix = regexp.MustCompile(`^(us|eu|apac|us-gov|global)\.`)
+
+// DefaultKnownFoundationModels returns a copy of the built-in known foundation model IDs
+func DefaultKnownFoundationModels() []string {
+	return append([]string{}, builtinFoundationModels...)
+}
+
+// validateFoundationModel validates an agent's foundation model against the configured model policy
+func (v *SecurityValidator) validateFoundationModel(agent *models.Agent) []ValidationError {
+	errors := []ValidationError{}
+
+	config := v.config.FoundationModelValidation
+	if config == nil {
+		return errors
+	}
+
+	modelID := agent.Spec.EffectiveFoundationModel()
+	if modelID == "" {
+		return errors
+	}
+
+	field := "spec.foundationModel"
+	if agent.Spec.FoundationModelProfile != "" {
+		field = "spec.foundationModelProfile"
+	}
+	if agent.Spec.ProvisionedThroughput != "" {
+		field = "spec.provisionedThroughput"
+	}
+
+	resourceName := fmt.Sprintf("Agent/%s", agent.Metadata.Name)
+
+	// Check denied models
+	for _, deniedPattern := range config.DeniedModels {
+		if matched, _ := regexp.MatchString(deniedPattern, modelID); matched {
+			errors = append(errors, ValidationError{
+				Type:     "security_policy",
+				Message:  fmt.Sprintf("Foundation model '%s' is denied by pattern '%s'", modelID, deniedPattern),
+				Resource: resourceName,
+				Field:    field,
+				Severity: "error",
+			})
+		}
+	}
+
+	// Check allowed models
+	if len(config.AllowedModels) > 0 {
+		allowed := false
+		for _, allowedPattern := range config.AllowedModels {
+			if matched, _ := regexp.MatchString(allowedPattern, modelID); matched {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			errors = append(errors, ValidationError{
+				Type:     "security_policy",
+				Message:  fmt.Sprintf("Foundation model '%s' is not in the allowed models list", modelID),
+				Resource: resourceName,
+				Field:    field,
+				Severity: "error",
+			})
+		}
+	}
+
+	// ARNs (provisioned throughput, custom models, application inference profiles) and inference
+	// profile IDs are not checked against known IDs
+	if strings.HasPrefix(modelID, "arn:") || agent.Spec.FoundationModelProfile != "" || agent.Spec.ProvisionedThroughput != "" {
+		return errors
+	}
+
+	knownModels := config.knownModels()
+	if !config.isKnownModel(modelID, knownModels) {
+		message := fmt.Sprintf("Foundation model '%s' does not match any known Bedrock model ID", modelID)
+		if suggestion := closestModelID(modelID, knownModels); suggestion != "" {
+			message += fmt.Sprintf("; did you mean '%s'?", suggestion)
+		}
+
+		errors = append(errors, ValidationError{
+			Type:     "security_policy",
+			Message:  message,
+			Resource: resourceName,
+			Field:    field,
+			Severity: "error",
+		})
+	}
+
+	return errors
+}
+
+// knownModels returns the effective list of known model IDs
+func (c *FoundationModelValidation) knownModels() []string {
+	knownModels := c.KnownModels
+	if len(knownModels) == 0 {
+		knownModels = builtinFoundationModels
+	}
+	return append(append([]string{}, knownModels...), c.AdditionalKnownModels...)
+}
+
+// isKnownModel checks a model ID, with or without an inference profile prefix, against known IDs and patterns
+func (c *FoundationModelValidation) isKnownModel(modelID string, knownModels []string) bool {
+	baseID := inferenceProfilePrefix.ReplaceAllString(modelID, "")
+
+	for _, known := range knownModels {
+		if modelID == known || baseID == known {
+			return true
+		}
+	}
+
+	for _, pattern := range c.KnownModelPatterns {
+		if matched, _ := regexp.MatchString(pattern, modelID); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closestModelID returns the known model ID closest to the given ID, preferring IDs
+// that extend it (e.g. a missing date or version suffix) over the smallest edit distance
+func closestModelID(modelID string, knownModels []string) string {
+	closest := ""
+	bestDistance := -1
+
+	baseID := inferenceProfilePrefix.ReplaceAllString(modelID, "")
+	for _, known := range knownModels {
+		if strings.HasPrefix(known, baseID) && (closest == "" || len(known) < len(closest)) {
+			closest = known
+		}
+	}
+	if closest != "" {
+		return closest
+	}
+
+	for _, known := range knownModels {
+		distance := levenshteinDistance(modelID, known)
+		if bestDistance < 0 || distance < bestDistance {
+			bestDistance = distance
+			closest = known
+		}
+	}
+
+	return closest
+}
+
+// levenshteinDistance computes the edit distance between two strings
+func levenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
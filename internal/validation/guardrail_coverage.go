@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/registry"
+)
+
+// DataClassificationTag is the tag whose value selects the guardrail policies an agent requires
+const DataClassificationTag = "DataClassification"
+
+// validateGuardrailPolicyTypes checks the policy types configured per data classification
+func validateGuardrailPolicyTypes(byClassification map[string][]string) error {
+	for classification, policyTypes := range byClassification {
+		for _, policyType := range policyTypes {
+			if !containsString(models.GuardrailPolicyTypes, policyType) {
+				return fmt.Errorf("invalid guardrail policy type '%s' for classification '%s', must be one of: %s", policyType, classification, strings.Join(models.GuardrailPolicyTypes, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateGuardrailCoverage returns an error for every agent whose DataClassification tag requires
+// guardrail policy types that its guardrail doesn't configure
+func (v *SecurityValidator) validateGuardrailCoverage(reg *registry.ResourceRegistry) []ValidationError {
+	errors := []ValidationError{}
+	if v.config.AgentSecurity == nil || len(v.config.AgentSecurity.RequiredGuardrailTypesByClassification) == 0 {
+		return errors
+	}
+
+	names := reg.ListResourceNames(models.AgentKind)
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource, _ := reg.GetResource(models.AgentKind, name)
+		agent, ok := resource.Resource.(*models.Agent)
+		if !ok {
+			continue
+		}
+
+		classification := agent.Spec.Tags[DataClassificationTag]
+		required := v.requiredGuardrailTypes(classification)
+		if len(required) == 0 {
+			continue
+		}
+		resourceName := fmt.Sprintf("Agent/%s", name)
+
+		if agent.Spec.Guardrail == nil || agent.Spec.Guardrail.Name.IsEmpty() {
+			errors = append(errors, ValidationError{
+				Type:     "guardrail_coverage",
+				Message:  fmt.Sprintf("Agents classified %s require a guardrail with %s policies", classification, strings.Join(required, ", ")),
+				Resource: resourceName,
+				Field:    "spec.guardrail",
+				Severity: "error",
+			})
+			continue
+		}
+
+		// Missing guardrails are reported by dependency validation
+		guardrailResource, exists := reg.GetResource(models.GuardrailKind, agent.Spec.Guardrail.Name.String())
+		if !exists {
+			continue
+		}
+		guardrail, ok := guardrailResource.Resource.(*models.Guardrail)
+		if !ok {
+			continue
+		}
+
+		configured := guardrail.Spec.PolicyTypes()
+		var missing []string
+		for _, policyType := range required {
+			if !containsString(configured, policyType) {
+				missing = append(missing, policyType)
+			}
+		}
+		if len(missing) > 0 {
+			errors = append(errors, ValidationError{
+				Type:     "guardrail_coverage",
+				Message:  fmt.Sprintf("Agents classified %s require guardrail policies %s, but guardrail %s does not configure %s", classification, strings.Join(required, ", "), guardrail.Metadata.Name, strings.Join(missing, ", ")),
+				Resource: resourceName,
+				Field:    "spec.guardrail.name",
+				Severity: "error",
+			})
+		}
+	}
+
+	return errors
+}
+
+// requiredGuardrailTypes returns the policy types configured for a data classification, matched case-insensitively
+func (v *SecurityValidator) requiredGuardrailTypes(classification string) []string {
+	if classification == "" {
+		return nil
+	}
+	for configured, policyTypes := range v.config.AgentSecurity.RequiredGuardrailTypesByClassification {
+		if strings.EqualFold(configured, classification) {
+			return policyTypes
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
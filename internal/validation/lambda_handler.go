@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// pythonHandlerPattern matches module.function, where the module may be a dotted package path
+	pythonHandlerPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+	// nodeHandlerPattern matches file.export, where the file may include a relative directory
+	nodeHandlerPattern = regexp.MustCompile(`^([A-Za-z0-9_.\-]+/)*[A-Za-z0-9_\-]+\.[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+	// javaHandlerPattern matches package.Class::method
+	javaHandlerPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)+::[A-Za-z_$][A-Za-z0-9_$]*$`)
+)
+
+// validateLambdaHandler checks that a handler matches the format expected by the runtime family.
+// Runtimes without a known handler format are not checked.
+func validateLambdaHandler(runtime, handler string) error {
+	var family, expected string
+	var pattern *regexp.Regexp
+
+	switch {
+	case strings.HasPrefix(runtime, "python"):
+		family, expected, pattern = "Python", "module.function (e.g. app.lambda_handler)", pythonHandlerPattern
+	case strings.HasPrefix(runtime, "nodejs"):
+		family, expected, pattern = "Node.js", "file.export (e.g. index.handler)", nodeHandlerPattern
+	case strings.HasPrefix(runtime, "java"):
+		family, expected, pattern = "Java", "package.Class::method (e.g. com.example.Handler::handleRequest)", javaHandlerPattern
+	default:
+		return nil
+	}
+
+	if !pattern.MatchString(handler) {
+		return fmt.Errorf("handler '%s' is not valid for %s runtime '%s', expected %s", handler, family, runtime, expected)
+	}
+
+	// index.handler is valid Python syntax but almost always a Node.js handler copied across
+	if family == "Python" && strings.HasPrefix(handler, "index.") {
+		return fmt.Errorf("handler '%s' looks like a Node.js handler but runtime '%s' is Python, expected %s", handler, runtime, expected)
+	}
+
+	return nil
+}
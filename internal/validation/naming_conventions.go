@@ -128,6 +128,9 @@ func (v *NamingValidator) ValidateResourceName(resource interface{}, context *Va
 	case *models.Lambda:
 		metadata = r.Metadata
 		resourceType = "Lambda"
+	case *models.LambdaLayer:
+		metadata = r.Metadata
+		resourceType = "LambdaLayer"
 	case *models.ActionGroup:
 		metadata = r.Metadata
 		resourceType = "ActionGroup"
@@ -305,6 +308,10 @@ type ValidationContext struct {
 	Environment string
 	Project     string
 	Region      string
+	// DefaultLambdaKmsKeyArn is the project-wide KMS key applied to Lambdas with environment variables
+	DefaultLambdaKmsKeyArn string
+	// Providers are the named providers declared in bedrock-forge.yaml that metadata.provider may select
+	Providers map[string]bool
 }
 
 // ValidationError represents a naming convention validation error
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"fmt"
+
+	"bedrock-forge/internal/models"
+	"bedrock-forge/internal/registry"
+)
+
+// AllowUnreferencedAnnotation opts a resource out of orphan detection when set to "true"
+const AllowUnreferencedAnnotation = "bedrock-forge.io/allow-unreferenced"
+
+// orphanCheckedKinds are the kinds that only make sense when another resource references them.
+// Agents and CustomResources are top-level, and action groups and associations are leaves.
+var orphanCheckedKinds = []models.ResourceKind{
+	models.LambdaKind,
+	models.LambdaLayerKind,
+	models.PromptKind,
+	models.GuardrailKind,
+	models.KnowledgeBaseKind,
+	models.OpenSearchServerlessKind,
+}
+
+// findOrphanedResources returns a warning for every resource that no other resource references
+func (v *Validator) findOrphanedResources(reg *registry.ResourceRegistry) []ValidationError {
+	referenced := make(map[string]bool)
+	for _, kindResources := range reg.GetAllResources() {
+		for _, resource := range kindResources {
+			for _, ref := range reg.GetResourceReferences(resource) {
+				referenced[fmt.Sprintf("%s/%s", ref.Kind, ref.Name)] = true
+			}
+		}
+	}
+
+	var warnings []ValidationError
+	for _, kind := range orphanCheckedKinds {
+		for _, resource := range reg.GetResourcesByType(kind) {
+			id := fmt.Sprintf("%s/%s", kind, resource.Metadata.Name)
+			if referenced[id] || resource.Metadata.Annotations[AllowUnreferencedAnnotation] == "true" {
+				continue
+			}
+
+			warnings = append(warnings, ValidationError{
+				Type:     "orphan",
+				Message:  fmt.Sprintf("%s %s is not referenced by any other resource (set annotation %s: \"true\" to allow)", kind, resource.Metadata.Name, AllowUnreferencedAnnotation),
+				Resource: id,
+				Field:    "metadata.name",
+				Severity: "warning",
+			})
+		}
+	}
+
+	return warnings
+}
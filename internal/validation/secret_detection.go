@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// SecretDetectionConfig configures the heuristic that flags Lambda environment values that look like
+// credentials. Zero thresholds use the defaults of DefaultSecretDetection.
+type SecretDetectionConfig struct {
+	// Minimum length of a base64-like token before its entropy is checked
+	MinLength int `yaml:"minLength,omitempty"`
+
+	// Minimum Shannon entropy in bits per character of a base64-like token
+	MinEntropy float64 `yaml:"minEntropy,omitempty"`
+
+	// Minimum length of a hex token before its entropy is checked
+	MinHexLength int `yaml:"minHexLength,omitempty"`
+
+	// Minimum Shannon entropy in bits per character of a hex token
+	MinHexEntropy float64 `yaml:"minHexEntropy,omitempty"`
+
+	// Values matching any of these patterns are never flagged
+	AllowedValuePatterns []string `yaml:"allowedValuePatterns,omitempty"`
+
+	// Compiled AllowedValuePatterns
+	allowedValuePatterns []*regexp.Regexp
+}
+
+var (
+	awsAccessKeyPattern = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	jwtPattern          = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	hexTokenPattern     = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	base64TokenPattern  = regexp.MustCompile(`^[A-Za-z0-9+/_-]+={0,2}$`)
+)
+
+// DefaultSecretDetection returns the secret detection thresholds used by the built-in profiles
+func DefaultSecretDetection() *SecretDetectionConfig {
+	return &SecretDetectionConfig{
+		MinLength:     20,
+		MinEntropy:    4.0,
+		MinHexLength:  32,
+		MinHexEntropy: 3.0,
+	}
+}
+
+// compilePatterns compiles the allowed value patterns
+func (c *SecretDetectionConfig) compilePatterns() error {
+	c.allowedValuePatterns = nil
+	for _, pattern := range c.AllowedValuePatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid allowed value pattern '%s': %w", pattern, err)
+		}
+		c.allowedValuePatterns = append(c.allowedValuePatterns, compiled)
+	}
+	return nil
+}
+
+// withDefaults fills in the thresholds that aren't configured
+func (c *SecretDetectionConfig) withDefaults() SecretDetectionConfig {
+	defaults := DefaultSecretDetection()
+	config := *c
+	if config.MinLength <= 0 {
+		config.MinLength = defaults.MinLength
+	}
+	if config.MinEntropy <= 0 {
+		config.MinEntropy = defaults.MinEntropy
+	}
+	if config.MinHexLength <= 0 {
+		config.MinHexLength = defaults.MinHexLength
+	}
+	if config.MinHexEntropy <= 0 {
+		config.MinHexEntropy = defaults.MinHexEntropy
+	}
+	return config
+}
+
+// detectSecret returns a description of the kind of secret a value looks like, or "" if it doesn't
+func (c *SecretDetectionConfig) detectSecret(value string) string {
+	config := c.withDefaults()
+
+	// References and templates are resolved at deploy time
+	if value == "" || strings.Contains(value, "${") {
+		return ""
+	}
+	for _, pattern := range config.allowedValuePatterns {
+		if pattern.MatchString(value) {
+			return ""
+		}
+	}
+
+	switch {
+	case awsAccessKeyPattern.MatchString(value):
+		return "an AWS access key ID"
+	case jwtPattern.MatchString(value):
+		return "a JSON Web Token"
+	case hexTokenPattern.MatchString(value):
+		if len(value) >= config.MinHexLength && shannonEntropy(value) >= config.MinHexEntropy {
+			return fmt.Sprintf("a high-entropy hex token (%d characters)", len(value))
+		}
+	case base64TokenPattern.MatchString(value):
+		// Identifiers such as table names are long too, but rarely mix letters and digits this randomly
+		if len(value) >= config.MinLength && strings.ContainsAny(value, "0123456789") &&
+			strings.IndexFunc(value, isASCIILetter) >= 0 && shannonEntropy(value) >= config.MinEntropy {
+			return fmt.Sprintf("a high-entropy token (%d characters)", len(value))
+		}
+	}
+	return ""
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	length := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func isASCIILetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
@@ -27,6 +27,9 @@ type SecurityPolicyConfig struct {
 
 	// Network security requirements
 	NetworkSecurity *NetworkSecurityValidation `yaml:"networkSecurity,omitempty"`
+
+	// Foundation model ID validation
+	FoundationModelValidation *FoundationModelValidation `yaml:"foundationModelValidation,omitempty"`
 }
 
 // IAMPolicyValidation defines IAM policy validation rules
@@ -64,6 +67,9 @@ type LambdaSecurityValidation struct {
 	// Forbidden environment variable patterns
 	ForbiddenEnvPatterns []string `yaml:"forbiddenEnvPatterns,omitempty"`
 
+	// Flag environment values that look like credentials, e.g. access keys, tokens and JWTs
+	SecretDetection *SecretDetectionConfig `yaml:"secretDetection,omitempty"`
+
 	// Required security headers for HTTP-triggered functions
 	RequiredSecurityHeaders []string `yaml:"requiredSecurityHeaders,omitempty"`
 
@@ -78,6 +84,9 @@ type LambdaSecurityValidation struct {
 
 	// Require encryption for environment variables
 	RequireEnvEncryption bool `yaml:"requireEnvEncryption,omitempty"`
+
+	// Reject function URLs without authentication instead of warning about them
+	ForbidPublicFunctionURLs bool `yaml:"forbidPublicFunctionURLs,omitempty"`
 }
 
 // AgentSecurityValidation defines Bedrock agent security requirements
@@ -88,6 +97,9 @@ type AgentSecurityValidation struct {
 	// Required guardrail configurations
 	RequiredGuardrailTypes []string `yaml:"requiredGuardrailTypes,omitempty"`
 
+	// Guardrail policy types required for agents by their DataClassification tag, e.g. confidential: [SENSITIVE_INFORMATION]
+	RequiredGuardrailTypesByClassification map[string][]string `yaml:"requiredGuardrailTypesByClassification,omitempty"`
+
 	// Maximum idle session timeout
 	MaxIdleSessionTTL int `yaml:"maxIdleSessionTTL,omitempty"`
 
@@ -149,6 +161,24 @@ type NetworkSecurityValidation struct {
 	RequireVPCFlowLogs bool `yaml:"requireVPCFlowLogs,omitempty"`
 }
 
+// FoundationModelValidation defines which foundation model IDs agents may use
+type FoundationModelValidation struct {
+	// Allowed model ID patterns (regex); when set, models must match one of them
+	AllowedModels []string `yaml:"allowedModels,omitempty"`
+
+	// Denied model ID patterns (regex)
+	DeniedModels []string `yaml:"deniedModels,omitempty"`
+
+	// Known model IDs, replacing the built-in list when set
+	KnownModels []string `yaml:"knownModels,omitempty"`
+
+	// Additional known model IDs appended to the built-in list
+	AdditionalKnownModels []string `yaml:"additionalKnownModels,omitempty"`
+
+	// Additional known model ID patterns (regex), e.g. for custom or imported models
+	KnownModelPatterns []string `yaml:"knownModelPatterns,omitempty"`
+}
+
 // SecurityValidator validates resources against security policies
 type SecurityValidator struct {
 	config *SecurityPolicyConfig
@@ -156,6 +186,17 @@ type SecurityValidator struct {
 
 // NewSecurityValidator creates a new security validator
 func NewSecurityValidator(config *SecurityPolicyConfig) (*SecurityValidator, error) {
+	if config.AgentSecurity != nil {
+		if err := validateGuardrailPolicyTypes(config.AgentSecurity.RequiredGuardrailTypesByClassification); err != nil {
+			return nil, err
+		}
+	}
+	if config.LambdaSecurity != nil && config.LambdaSecurity.SecretDetection != nil {
+		if err := config.LambdaSecurity.SecretDetection.compilePatterns(); err != nil {
+			return nil, fmt.Errorf("failed to compile secret detection patterns: %w", err)
+		}
+	}
+
 	return &SecurityValidator{
 		config: config,
 	}, nil
@@ -168,8 +209,9 @@ func (v *SecurityValidator) ValidateResourceSecurity(resource interface{}, conte
 	switch r := resource.(type) {
 	case *models.Agent:
 		errors = append(errors, v.validateAgentSecurity(r)...)
+		errors = append(errors, v.validateFoundationModel(r)...)
 	case *models.Lambda:
-		errors = append(errors, v.validateLambdaSecurity(r)...)
+		errors = append(errors, v.validateLambdaSecurity(r, context)...)
 	case *models.KnowledgeBase:
 		errors = append(errors, v.validateKnowledgeBaseSecurity(r)...)
 	case *models.IAMRole:
@@ -225,10 +267,10 @@ func (v *SecurityValidator) validateAgentSecurity(agent *models.Agent) []Validat
 
 	// Check forbidden models
 	for _, forbiddenModel := range config.ForbiddenModels {
-		if strings.Contains(agent.Spec.FoundationModel, forbiddenModel) {
+		if strings.Contains(agent.Spec.EffectiveFoundationModel(), forbiddenModel) {
 			errors = append(errors, ValidationError{
 				Type:     "security_policy",
-				Message:  fmt.Sprintf("Foundation model '%s' contains forbidden pattern '%s'", agent.Spec.FoundationModel, forbiddenModel),
+				Message:  fmt.Sprintf("Foundation model '%s' contains forbidden pattern '%s'", agent.Spec.EffectiveFoundationModel(), forbiddenModel),
 				Resource: resourceName,
 				Field:    "spec.foundationModel",
 				Severity: "error",
@@ -251,7 +293,7 @@ func (v *SecurityValidator) validateAgentSecurity(agent *models.Agent) []Validat
 }
 
 // validateLambdaSecurity validates Lambda function security requirements
-func (v *SecurityValidator) validateLambdaSecurity(lambda *models.Lambda) []ValidationError {
+func (v *SecurityValidator) validateLambdaSecurity(lambda *models.Lambda, context *ValidationContext) []ValidationError {
 	errors := []ValidationError{}
 
 	if v.config.LambdaSecurity == nil {
@@ -314,6 +356,33 @@ func (v *SecurityValidator) validateLambdaSecurity(lambda *models.Lambda) []Vali
 		}
 	}
 
+	// Check environment variable encryption, which a project-wide default key also satisfies
+	if config.RequireEnvEncryption && len(lambda.Spec.Environment) > 0 && lambda.Spec.KmsKeyArn == "" &&
+		(context == nil || context.DefaultLambdaKmsKeyArn == "") {
+		errors = append(errors, ValidationError{
+			Type:     "security_policy",
+			Message:  "Lambda environment variables must be encrypted with a customer managed KMS key, set spec.kmsKeyArn or a project-wide lambdaKmsKeyArn",
+			Resource: resourceName,
+			Field:    "spec.kmsKeyArn",
+			Severity: "error",
+		})
+	}
+
+	// Function URLs with authType NONE can be invoked by anyone on the internet
+	if lambda.Spec.FunctionURL != nil && lambda.Spec.FunctionURL.AuthType == "NONE" {
+		severity := "warning"
+		if config.ForbidPublicFunctionURLs {
+			severity = "error"
+		}
+		errors = append(errors, ValidationError{
+			Type:     "security_policy",
+			Message:  "Lambda function URL has authType NONE and is publicly invocable, use AWS_IAM unless the function authenticates requests itself",
+			Resource: resourceName,
+			Field:    "spec.functionUrl.authType",
+			Severity: severity,
+		})
+	}
+
 	// Check environment variable patterns
 	for envName, envValue := range lambda.Spec.Environment {
 		for _, forbiddenPattern := range config.ForbiddenEnvPatterns {
@@ -336,6 +405,19 @@ func (v *SecurityValidator) validateLambdaSecurity(lambda *models.Lambda) []Vali
 				})
 			}
 		}
+
+		// The value itself is not repeated in the message
+		if config.SecretDetection != nil {
+			if secret := config.SecretDetection.detectSecret(envValue); secret != "" {
+				errors = append(errors, ValidationError{
+					Type:     "security_policy",
+					Message:  fmt.Sprintf("Environment variable '%s' looks like it contains %s, store secrets in SSM Parameter Store or Secrets Manager and pass the parameter name instead", envName, secret),
+					Resource: resourceName,
+					Field:    fmt.Sprintf("spec.environment.%s", envName),
+					Severity: "error",
+				})
+			}
+		}
 	}
 
 	return errors
@@ -357,7 +439,8 @@ func (v *SecurityValidator) validateKnowledgeBaseSecurity(kb *models.KnowledgeBa
 		for _, dataSource := range kb.Spec.DataSources {
 			typeAllowed := false
 			for _, allowedType := range config.AllowedDataSourceTypes {
-				if dataSource.Type == allowedType {
+				// Policies may spell types as in the console, e.g. "SharePoint" for SHAREPOINT
+				if strings.EqualFold(dataSource.Type, allowedType) {
 					typeAllowed = true
 					break
 				}
@@ -560,8 +643,9 @@ func DefaultSecurityPolicies() *SecurityPolicyConfig {
 			ForbiddenEnvPatterns: []string{
 				"(?i)(password|secret|key|token)",
 			},
-			MaxTimeout:    900, // 15 minutes
-			MaxMemorySize: 3008,
+			SecretDetection: DefaultSecretDetection(),
+			MaxTimeout:      900, // 15 minutes
+			MaxMemorySize:   3008,
 			AllowedRuntimes: []string{
 				"python3.11", "python3.10", "python3.9",
 				"nodejs18.x", "nodejs16.x",
@@ -574,11 +658,16 @@ func DefaultSecurityPolicies() *SecurityPolicyConfig {
 			MaxIdleSessionTTL:          3600, // 1 hour
 			RequireCustomerEncryption:  false,
 			RequireMemoryConfiguration: false,
+			RequiredGuardrailTypesByClassification: map[string][]string{
+				"confidential": {models.GuardrailPolicySensitiveInformation},
+				"restricted":   {models.GuardrailPolicySensitiveInformation},
+			},
 		},
 		KnowledgeBaseSecurity: &KnowledgeBaseSecurityValidation{
 			AllowedDataSourceTypes: []string{"S3", "Web", "Confluence", "SharePoint"},
 			RequireAccessLogging:   false,
 		},
+		FoundationModelValidation: &FoundationModelValidation{},
 	}
 }
 
@@ -612,9 +701,11 @@ func EnterpriseSecurityPolicies() *SecurityPolicyConfig {
 				"(?i)(password|secret|key|token|api_key|auth)",
 				"(?i)(prod|production).*(?i)(pass|secret)",
 			},
-			MaxTimeout:           300, // 5 minutes
-			MaxMemorySize:        1024,
-			RequireEnvEncryption: true,
+			SecretDetection:          DefaultSecretDetection(),
+			MaxTimeout:               300, // 5 minutes
+			MaxMemorySize:            1024,
+			RequireEnvEncryption:     true,
+			ForbidPublicFunctionURLs: true,
 			AllowedRuntimes: []string{
 				"python3.11", "python3.10",
 				"nodejs18.x",
@@ -627,6 +718,10 @@ func EnterpriseSecurityPolicies() *SecurityPolicyConfig {
 			MaxIdleSessionTTL:          1800, // 30 minutes
 			RequireCustomerEncryption:  true,
 			RequireMemoryConfiguration: true,
+			RequiredGuardrailTypesByClassification: map[string][]string{
+				"confidential": {models.GuardrailPolicySensitiveInformation},
+				"restricted":   {models.GuardrailPolicyContent, models.GuardrailPolicySensitiveInformation, models.GuardrailPolicyContextualGrounding},
+			},
 			ForbiddenModels: []string{
 				"anthropic.claude-instant",
 				"meta.llama2",
@@ -654,5 +749,6 @@ func EnterpriseSecurityPolicies() *SecurityPolicyConfig {
 				"22", "3389", "1433", "3306", "5432",
 			},
 		},
+		FoundationModelValidation: &FoundationModelValidation{},
 	}
 }
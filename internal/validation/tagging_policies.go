@@ -24,6 +24,10 @@ type TaggingPolicyConfig struct {
 
 	// Tag value validation rules
 	TagValidation map[string]*TagValidationRule `yaml:"tagValidation,omitempty"`
+
+	// Tagging requirements for the IAM roles auto-generated for agents, Lambdas and knowledge bases,
+	// which carry the tags of their resource plus RoleType and ManagedBy
+	GeneratedRoles *TaggingRequirements `yaml:"generatedRoles,omitempty"`
 }
 
 // TaggingRequirements defines what tags are required
@@ -182,9 +186,33 @@ func (v *TaggingValidator) ValidateResourceTags(resource interface{}, context *V
 		}
 	}
 
+	// Auto-generated roles are tagged from the resource they belong to
+	if roleType := generatedRoleType(resource); roleType != "" && v.config.GeneratedRoles != nil {
+		roleTags := models.GeneratedRoleTags(tags, roleType)
+		for _, err := range v.validateTagsAgainstRequirement(roleTags, v.config.GeneratedRoles, resourceType, metadata.Name, context) {
+			err.Message = fmt.Sprintf("Generated %s role: %s", roleType, err.Message)
+			errors = append(errors, err)
+		}
+	}
+
 	return errors
 }
 
+// generatedRoleType returns the RoleType of the IAM role generated for a resource, or "" when none is
+func generatedRoleType(resource interface{}) string {
+	switch r := resource.(type) {
+	case *models.Agent:
+		if r.Spec.IAMRole.CreatesRole() {
+			return models.RoleTypeAgentExecution
+		}
+	case *models.Lambda:
+		return models.RoleTypeLambdaExecution
+	case *models.KnowledgeBase:
+		return models.RoleTypeKnowledgeBaseService
+	}
+	return ""
+}
+
 // getApplicableRequirements returns the tagging requirements that apply to a resource
 func (v *TaggingValidator) getApplicableRequirements(resourceType string, context *ValidationContext) []*TaggingRequirements {
 	requirements := []*TaggingRequirements{}
@@ -3,7 +3,9 @@ package validation
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
+	"bedrock-forge/internal/models"
 	"bedrock-forge/internal/parser"
 	"bedrock-forge/internal/registry"
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,12 @@ type ValidationConfig struct {
 	TaggingPolicies   *TaggingPolicyConfig    `yaml:"taggingPolicies,omitempty"`
 	SecurityPolicies  *SecurityPolicyConfig   `yaml:"securityPolicies,omitempty"`
 	EnabledValidators []string                `yaml:"enabledValidators,omitempty"`
+
+	// MaxInstructionLength is the longest agent instruction in characters, default: models.DefaultMaxInstructionLength
+	MaxInstructionLength int `yaml:"maxInstructionLength,omitempty"`
+
+	// LambdaHandlerSeverity is the severity of Lambda handler/runtime mismatches: warning (default) or error
+	LambdaHandlerSeverity string `yaml:"lambdaHandlerSeverity,omitempty"`
 }
 
 // Validator coordinates all validation activities
@@ -101,6 +109,21 @@ func (v *Validator) ValidateRegistry(reg *registry.ResourceRegistry, context *Va
 		})
 	}
 
+	// Agents with a high data classification must have the guardrail policies it mandates
+	if v.securityValidator != nil && v.isValidatorEnabled("security") {
+		result.Errors = append(result.Errors, v.securityValidator.validateGuardrailCoverage(reg)...)
+	}
+
+	// Knowledge bases can't ingest into collections whose access policy leaves out their role
+	if v.isValidatorEnabled("collection_access") {
+		result.Errors = append(result.Errors, v.validateCollectionAccess(reg)...)
+	}
+
+	// Warn about resources nothing points at
+	if v.isValidatorEnabled("orphans") {
+		result.Warnings = append(result.Warnings, v.findOrphanedResources(reg)...)
+	}
+
 	result.ValidResources = result.TotalResources - len(result.Errors)
 	result.Success = len(result.Errors) == 0
 
@@ -131,6 +154,189 @@ func (v *Validator) ValidateResource(resource *parser.ParsedResource, context *V
 		errors = append(errors, securityErrors...)
 	}
 
+	if context != nil {
+		errors = append(errors, resourceProviderErrors(resource, context.Providers)...)
+	}
+
+	// Lambda handler format validation
+	if lambda, ok := resource.Resource.(*models.Lambda); ok {
+		if err := validateLambdaHandler(lambda.Spec.Runtime, lambda.Spec.Handler); err != nil {
+			severity := v.config.LambdaHandlerSeverity
+			if severity == "" {
+				severity = "warning"
+			}
+			errors = append(errors, ValidationError{
+				Type:     "lambda_handler",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+				Field:    "spec.handler",
+				Severity: severity,
+			})
+		}
+	}
+
+	if lambda, ok := resource.Resource.(*models.Lambda); ok && lambda.Spec.FunctionURL != nil {
+		if err := lambda.Spec.FunctionURL.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "function_url",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+				Field:    "spec.functionUrl",
+				Severity: "error",
+			})
+		}
+	}
+
+	if lambda, ok := resource.Resource.(*models.Lambda); ok {
+		if err := lambda.Spec.ValidateEnvironmentSources(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "lambda_environment",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+				Field:    "spec.environment",
+				Severity: "error",
+			})
+		}
+		if lambda.Spec.DeadLetterConfig != nil {
+			if err := lambda.Spec.DeadLetterConfig.Validate(); err != nil {
+				errors = append(errors, ValidationError{
+					Type:     "dead_letter_config",
+					Message:  err.Error(),
+					Resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+					Field:    "spec.deadLetterConfig",
+					Severity: "error",
+				})
+			}
+		}
+		if err := lambda.Spec.ValidateProvisionedConcurrency(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "provisioned_concurrency",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Lambda/%s", lambda.Metadata.Name),
+				Field:    "spec.provisionedConcurrency",
+				Severity: "error",
+			})
+		}
+	}
+
+	if lambdaLayer, ok := resource.Resource.(*models.LambdaLayer); ok {
+		if err := lambdaLayer.Spec.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "lambda_layer",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("LambdaLayer/%s", lambdaLayer.Metadata.Name),
+				Field:    "spec",
+				Severity: "error",
+			})
+		}
+	}
+
+	// Oversized inline schemas are rejected by Bedrock at apply time
+	if agent, ok := resource.Resource.(*models.Agent); ok {
+		for i, actionGroup := range agent.Spec.ActionGroups {
+			errors = append(errors, apiSchemaSizeErrors(actionGroup.APISchema, fmt.Sprintf("Agent/%s", agent.Metadata.Name), fmt.Sprintf("spec.actionGroups[%d].apiSchema.payload", i))...)
+		}
+	}
+	if actionGroup, ok := resource.Resource.(*models.ActionGroup); ok {
+		errors = append(errors, apiSchemaSizeErrors(actionGroup.Spec.APISchema, fmt.Sprintf("ActionGroup/%s", actionGroup.Metadata.Name), "spec.apiSchema.payload")...)
+	}
+
+	// An agent needs exactly one of a foundation model and an inference profile
+	if agent, ok := resource.Resource.(*models.Agent); ok {
+		if err := agent.Spec.ValidateFoundationModel(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "foundation_model",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Agent/%s", agent.Metadata.Name),
+				Field:    "spec.foundationModelProfile",
+				Severity: "error",
+			})
+		}
+	}
+
+	if agent, ok := resource.Resource.(*models.Agent); ok && agent.Spec.MemoryConfiguration != nil {
+		if err := agent.Spec.MemoryConfiguration.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "memory_configuration",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Agent/%s", agent.Metadata.Name),
+				Field:    "spec.memoryConfiguration",
+				Severity: "error",
+			})
+		}
+	}
+
+	if agent, ok := resource.Resource.(*models.Agent); ok && agent.Spec.IAMRole != nil {
+		if err := agent.Spec.IAMRole.AssumeRoleConditions.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "iam_role",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("Agent/%s", agent.Metadata.Name),
+				Field:    "spec.iamRole.assumeRoleConditions",
+				Severity: "error",
+			})
+		}
+	}
+
+	// Bedrock rejects instructions outside its length limits at apply time
+	if agent, ok := resource.Resource.(*models.Agent); ok {
+		errors = append(errors, agentInstructionErrors(agent, v.config.MaxInstructionLength)...)
+	}
+
+	// Production aliases must serve a numbered version, DRAFT changes with every apply
+	if agent, ok := resource.Resource.(*models.Agent); ok {
+		errors = append(errors, agentVersioningErrors(agent)...)
+	}
+
+	// Guardrail policy values are copied verbatim, so typos would only fail at apply time
+	if guardrail, ok := resource.Resource.(*models.Guardrail); ok {
+		errors = append(errors, guardrailPolicyErrors(guardrail)...)
+	}
+
+	// Undeclared placeholders are substituted with empty strings at runtime
+	if prompt, ok := resource.Resource.(*models.Prompt); ok {
+		errors = append(errors, promptVariableErrors(prompt)...)
+	}
+
+	// Data sources need the configuration and credentials of their type
+	if kb, ok := resource.Resource.(*models.KnowledgeBase); ok {
+		for i, dataSource := range kb.Spec.DataSources {
+			if err := dataSource.Validate(); err != nil {
+				errors = append(errors, ValidationError{
+					Type:     "data_source",
+					Message:  err.Error(),
+					Resource: fmt.Sprintf("KnowledgeBase/%s", kb.Metadata.Name),
+					Field:    fmt.Sprintf("spec.dataSources[%d]", i),
+					Severity: "error",
+				})
+			}
+			errors = append(errors, chunkingConfigurationErrors(kb, i)...)
+		}
+		errors = append(errors, knowledgeBaseRoleErrors(kb)...)
+	}
+
+	// OpenSearch Serverless collection naming rules, checked on the name that will actually be used
+	if collection, ok := resource.Resource.(*models.OpenSearchServerless); ok {
+		if err := models.ValidateCollectionName(collection.EffectiveCollectionName()); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "collection_name",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("OpenSearchServerless/%s", collection.Metadata.Name),
+				Field:    "spec.collectionName",
+				Severity: "error",
+			})
+		}
+		if err := collection.Spec.EncryptionPolicy.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     "encryption_policy",
+				Message:  err.Error(),
+				Resource: fmt.Sprintf("OpenSearchServerless/%s", collection.Metadata.Name),
+				Field:    "spec.encryptionPolicy",
+				Severity: "error",
+			})
+		}
+	}
+
 	// Add file path context to errors
 	for i := range errors {
 		if errors[i].Resource == "" {
@@ -141,6 +347,344 @@ func (v *Validator) ValidateResource(resource *parser.ParsedResource, context *V
 	return errors
 }
 
+// apiSchemaSizeErrors reports an inline API schema payload over, or close to, the Bedrock size limit
+func apiSchemaSizeErrors(schema *models.APISchema, resource, field string) []ValidationError {
+	warning, err := schema.ValidatePayloadSize()
+	switch {
+	case err != nil:
+		return []ValidationError{{Type: "api_schema_size", Message: err.Error(), Resource: resource, Field: field, Severity: "error"}}
+	case warning != "":
+		return []ValidationError{{Type: "api_schema_size", Message: warning, Resource: resource, Field: field, Severity: "warning"}}
+	}
+	return nil
+}
+
+// guardrailPolicyErrors reports every guardrail policy entry with a value Bedrock doesn't accept
+func guardrailPolicyErrors(guardrail *models.Guardrail) []ValidationError {
+	var errors []ValidationError
+	for _, err := range guardrail.Spec.PolicyErrors() {
+		errors = append(errors, ValidationError{
+			Type:     "guardrail_policy",
+			Message:  err.Err.Error(),
+			Resource: fmt.Sprintf("Guardrail/%s", guardrail.Metadata.Name),
+			Field:    "spec." + err.Field,
+			Severity: "error",
+		})
+	}
+	return errors
+}
+
+// resourceProviderErrors reports a metadata.provider that isn't declared in the project, or that is
+// combined with metadata.region, which the named provider sets instead
+func resourceProviderErrors(resource *parser.ParsedResource, providers map[string]bool) []ValidationError {
+	var errors []ValidationError
+	provider := resource.Metadata.Provider
+	if provider == "" {
+		return errors
+	}
+	resourceName := fmt.Sprintf("%s/%s", resource.Kind, resource.Metadata.Name)
+
+	if !providers[provider] {
+		errors = append(errors, ValidationError{
+			Type:     "provider",
+			Message:  fmt.Sprintf("provider '%s' is not declared in the providers of bedrock-forge.yaml", provider),
+			Resource: resourceName,
+			Field:    "metadata.provider",
+			Severity: "error",
+		})
+	}
+	if resource.Metadata.Region != "" {
+		errors = append(errors, ValidationError{
+			Type:     "provider",
+			Message:  "metadata.region can't be combined with metadata.provider, set the region on the provider instead",
+			Resource: resourceName,
+			Field:    "metadata.region",
+			Severity: "error",
+		})
+	}
+
+	return errors
+}
+
+// agentVersioningErrors reports an invalid versioning configuration and production aliases routing to DRAFT
+func agentVersioningErrors(agent *models.Agent) []ValidationError {
+	var errors []ValidationError
+	resourceName := fmt.Sprintf("Agent/%s", agent.Metadata.Name)
+
+	if err := agent.Spec.Versioning.Validate(); err != nil {
+		errors = append(errors, ValidationError{
+			Type:     "agent_versioning",
+			Message:  err.Error(),
+			Resource: resourceName,
+			Field:    "spec.versioning.aliasVersion",
+			Severity: "error",
+		})
+	}
+
+	if err := agent.Spec.ValidatePreparation(); err != nil {
+		errors = append(errors, ValidationError{
+			Type:     "agent_versioning",
+			Message:  err.Error(),
+			Resource: resourceName,
+			Field:    "spec.versioning.prepareOnGuardrailChange",
+			Severity: "error",
+		})
+	}
+
+	for i, alias := range agent.Spec.Aliases {
+		if !agent.Spec.Versioning.IsProductionAlias(alias.Name) {
+			continue
+		}
+		// aliasVersion is validated as a numbered version, so only explicit routing can name DRAFT
+		for j, routing := range alias.RoutingConfiguration {
+			if !strings.EqualFold(routing.AgentVersion, "DRAFT") {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				Type:     "agent_versioning",
+				Message:  fmt.Sprintf("production alias %s routes to DRAFT, which changes whenever the agent is prepared; route it to a numbered version", alias.Name),
+				Resource: resourceName,
+				Field:    fmt.Sprintf("spec.aliases[%d].routingConfiguration[%d].agentVersion", i, j),
+				Severity: "error",
+			})
+		}
+	}
+
+	return errors
+}
+
+// knowledgeBaseRoleErrors reports a knowledge base without an execution role, and S3 data sources an
+// auto-created role's policy can't grant read access to, since it lists their bucketArn verbatim
+func knowledgeBaseRoleErrors(kb *models.KnowledgeBase) []ValidationError {
+	var errors []ValidationError
+	resourceName := fmt.Sprintf("KnowledgeBase/%s", kb.Metadata.Name)
+	role := kb.Spec.IAMRole
+
+	if !role.CreatesRole() {
+		if role.RoleArn == "" && role.RoleName.IsEmpty() {
+			errors = append(errors, ValidationError{
+				Type:     "iam_role",
+				Message:  "IAM role auto-creation is disabled but no roleArn or roleName is provided",
+				Resource: resourceName,
+				Field:    "spec.iamRole",
+				Severity: "error",
+			})
+		}
+		return errors
+	}
+
+	for i, dataSource := range kb.Spec.DataSources {
+		if dataSource.S3Configuration == nil {
+			continue
+		}
+		bucketArn := dataSource.S3Configuration.BucketArn
+		if isBucketArn(bucketArn) || strings.HasPrefix(bucketArn, "aws_") || strings.HasPrefix(bucketArn, "module.") {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Type:     "iam_role",
+			Message:  fmt.Sprintf("data source %s: bucketArn %q is not a bucket ARN (arn:aws:s3:::<bucket>), so the auto-created role's policy doesn't grant access to its documents", dataSource.Name, bucketArn),
+			Resource: resourceName,
+			Field:    fmt.Sprintf("spec.dataSources[%d].s3Configuration.bucketArn", i),
+			Severity: "error",
+		})
+	}
+
+	return errors
+}
+
+// isBucketArn reports whether value is the ARN of an S3 bucket, rather than of an object or prefix
+func isBucketArn(value string) bool {
+	// arn:<partition>:s3:::<bucket>
+	parts := strings.SplitN(value, ":", 6)
+	return len(parts) == 6 && parts[0] == "arn" && parts[2] == "s3" && parts[3] == "" && parts[4] == "" &&
+		parts[5] != "" && !strings.Contains(parts[5], "/")
+}
+
+// agentInstructionErrors reports an instruction longer than maxLength characters, or the default when
+// maxLength is 0, and warns about one so short it is probably a mistake
+func agentInstructionErrors(agent *models.Agent, maxLength int) []ValidationError {
+	if maxLength <= 0 {
+		maxLength = models.DefaultMaxInstructionLength
+	}
+
+	length := agent.Spec.InstructionLength()
+	var message, severity string
+	switch {
+	case length > maxLength:
+		message = fmt.Sprintf("agent instruction is %d characters, the maximum is %d", length, maxLength)
+		severity = "error"
+	case length > 0 && length < models.MinInstructionLength:
+		message = fmt.Sprintf("agent instruction is only %d characters, Bedrock requires at least %d; it may be incomplete", length, models.MinInstructionLength)
+		severity = "warning"
+	default:
+		return nil
+	}
+
+	return []ValidationError{{
+		Type:     "agent_instruction",
+		Message:  message,
+		Resource: fmt.Sprintf("Agent/%s", agent.Metadata.Name),
+		Field:    "spec.instruction",
+		Severity: severity,
+	}}
+}
+
+// chunkingConfigurationErrors reports chunking parameters of a data source outside the ranges Bedrock
+// accepts, which would otherwise only be rejected when the data source is created
+func chunkingConfigurationErrors(kb *models.KnowledgeBase, index int) []ValidationError {
+	dataSource := kb.Spec.DataSources[index]
+	fields := []string{fmt.Sprintf("spec.dataSources[%d].chunkingConfiguration", index)}
+	configurations := []*models.ChunkingConfiguration{dataSource.ChunkingConfiguration}
+	if dataSource.VectorIngestionConfiguration != nil {
+		fields = append(fields, fmt.Sprintf("spec.dataSources[%d].vectorIngestionConfiguration.chunkingConfiguration", index))
+		configurations = append(configurations, dataSource.VectorIngestionConfiguration.ChunkingConfiguration)
+	}
+
+	var errors []ValidationError
+	add := func(field, message string, args ...interface{}) {
+		errors = append(errors, ValidationError{
+			Type:     "chunking_configuration",
+			Message:  fmt.Sprintf("data source %s: %s", dataSource.Name, fmt.Sprintf(message, args...)),
+			Resource: fmt.Sprintf("KnowledgeBase/%s", kb.Metadata.Name),
+			Field:    field,
+			Severity: "error",
+		})
+	}
+
+	for i, chunking := range configurations {
+		field := fields[i]
+		if chunking == nil {
+			continue
+		}
+
+		switch chunking.ChunkingStrategy {
+		case "FIXED_SIZE":
+			if chunking.FixedSizeChunkingConfiguration == nil {
+				add(field+".fixedSizeChunkingConfiguration", "fixedSizeChunkingConfiguration is required for chunking strategy FIXED_SIZE")
+			}
+		case "SEMANTIC":
+			if chunking.SemanticChunkingConfiguration == nil {
+				add(field+".semanticChunkingConfiguration", "semanticChunkingConfiguration is required for chunking strategy SEMANTIC")
+			}
+		case "HIERARCHICAL":
+			if chunking.HierarchicalChunkingConfiguration == nil {
+				add(field+".hierarchicalChunkingConfiguration", "hierarchicalChunkingConfiguration is required for chunking strategy HIERARCHICAL")
+			}
+		case "NONE":
+		default:
+			add(field+".chunkingStrategy", "chunkingStrategy must be one of FIXED_SIZE, HIERARCHICAL, SEMANTIC or NONE, got '%s'", chunking.ChunkingStrategy)
+		}
+
+		if fixed := chunking.FixedSizeChunkingConfiguration; fixed != nil {
+			fixedField := field + ".fixedSizeChunkingConfiguration"
+			if fixed.MaxTokens < 1 {
+				add(fixedField+".maxTokens", "maxTokens must be positive, got %d", fixed.MaxTokens)
+			}
+			if fixed.OverlapPercentage < 1 || fixed.OverlapPercentage > 99 {
+				add(fixedField+".overlapPercentage", "overlapPercentage must be between 1 and 99, got %d", fixed.OverlapPercentage)
+			}
+		}
+
+		if hierarchical := chunking.HierarchicalChunkingConfiguration; hierarchical != nil {
+			hierarchicalField := field + ".hierarchicalChunkingConfiguration"
+			if len(hierarchical.LevelConfigurations) != 2 {
+				add(hierarchicalField+".levelConfigurations", "levelConfigurations must define a parent and a child level, got %d levels", len(hierarchical.LevelConfigurations))
+			}
+			for j, level := range hierarchical.LevelConfigurations {
+				if level.MaxTokens < 1 {
+					add(fmt.Sprintf("%s.levelConfigurations[%d].maxTokens", hierarchicalField, j), "maxTokens must be positive, got %d", level.MaxTokens)
+				}
+			}
+			if hierarchical.OverlapTokens < 1 {
+				add(hierarchicalField+".overlapTokens", "overlapTokens must be positive, got %d", hierarchical.OverlapTokens)
+			}
+			// Overlapping by more than a whole chunk would repeat every chunk in the next one
+			for j, level := range hierarchical.LevelConfigurations {
+				if level.MaxTokens > 0 && hierarchical.OverlapTokens > level.MaxTokens {
+					add(hierarchicalField+".overlapTokens", "overlapTokens %d exceeds maxTokens %d of levelConfigurations[%d]", hierarchical.OverlapTokens, level.MaxTokens, j)
+				}
+			}
+		}
+
+		if semantic := chunking.SemanticChunkingConfiguration; semantic != nil {
+			semanticField := field + ".semanticChunkingConfiguration"
+			if semantic.MaxTokens < 1 {
+				add(semanticField+".maxTokens", "maxTokens must be positive, got %d", semantic.MaxTokens)
+			}
+			if semantic.BufferSize < 0 || semantic.BufferSize > 1 {
+				add(semanticField+".bufferSize", "bufferSize must be 0 or 1, got %d", semantic.BufferSize)
+			}
+			if semantic.BreakpointPercentileThreshold < 50 || semantic.BreakpointPercentileThreshold > 99 {
+				add(semanticField+".breakpointPercentileThreshold", "breakpointPercentileThreshold must be between 50 and 99, got %d", semantic.BreakpointPercentileThreshold)
+			}
+		}
+	}
+
+	return errors
+}
+
+// promptVariableErrors reports template placeholders that aren't declared as input variables of the
+// variant or the prompt, and declared input variables that no template uses
+func promptVariableErrors(prompt *models.Prompt) []ValidationError {
+	var errors []ValidationError
+	resourceName := fmt.Sprintf("Prompt/%s", prompt.Metadata.Name)
+
+	promptVariables := make(map[string]bool)
+	for _, variable := range prompt.Spec.InputVariables {
+		promptVariables[variable.Name] = true
+	}
+	usedByAnyVariant := make(map[string]bool)
+
+	for i, variant := range prompt.Spec.Variants {
+		used := make(map[string]bool)
+		for _, placeholder := range variant.TemplatePlaceholders() {
+			used[placeholder] = true
+			usedByAnyVariant[placeholder] = true
+		}
+
+		declared := make(map[string]bool)
+		for _, variable := range variant.TemplateInputVariables() {
+			declared[variable.Name] = true
+			if !used[variable.Name] {
+				errors = append(errors, ValidationError{
+					Type:     "prompt_variables",
+					Message:  fmt.Sprintf("variant %s declares input variable %s, which its template does not use", variant.Name, variable.Name),
+					Resource: resourceName,
+					Field:    fmt.Sprintf("spec.variants[%d].templateConfiguration", i),
+					Severity: "warning",
+				})
+			}
+		}
+
+		for _, placeholder := range variant.TemplatePlaceholders() {
+			if !declared[placeholder] && !promptVariables[placeholder] {
+				errors = append(errors, ValidationError{
+					Type:     "prompt_variables",
+					Message:  fmt.Sprintf("variant %s uses {{%s}}, which is not declared in its inputVariables or the prompt's", variant.Name, placeholder),
+					Resource: resourceName,
+					Field:    fmt.Sprintf("spec.variants[%d].templateConfiguration", i),
+					Severity: "error",
+				})
+			}
+		}
+	}
+
+	for _, variable := range prompt.Spec.InputVariables {
+		if !usedByAnyVariant[variable.Name] {
+			errors = append(errors, ValidationError{
+				Type:     "prompt_variables",
+				Message:  fmt.Sprintf("input variable %s is not used by any variant", variable.Name),
+				Resource: resourceName,
+				Field:    "spec.inputVariables",
+				Severity: "warning",
+			})
+		}
+	}
+
+	return errors
+}
+
 // isValidatorEnabled checks if a validator is enabled
 func (v *Validator) isValidatorEnabled(validatorType string) bool {
 	if len(v.config.EnabledValidators) == 0 {
@@ -212,7 +756,7 @@ func DefaultValidationConfig() *ValidationConfig {
 		NamingConventions: DefaultNamingConventions(),
 		TaggingPolicies:   DefaultTaggingPolicies(),
 		SecurityPolicies:  DefaultSecurityPolicies(),
-		EnabledValidators: []string{"naming", "tagging", "security"},
+		EnabledValidators: []string{"naming", "tagging", "security", "collection_access"},
 	}
 }
 
@@ -222,6 +766,6 @@ func EnterpriseValidationConfig() *ValidationConfig {
 		NamingConventions: EnterpriseNamingConventions(),
 		TaggingPolicies:   EnterpriseTaggingPolicies(),
 		SecurityPolicies:  EnterpriseSecurityPolicies(),
-		EnabledValidators: []string{"naming", "tagging", "security"},
+		EnabledValidators: []string{"naming", "tagging", "security", "collection_access"},
 	}
 }
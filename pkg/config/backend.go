@@ -0,0 +1,59 @@
+package config
+
+import "fmt"
+
+// BackendConfig holds the Terraform state backend configuration
+type BackendConfig struct {
+	Type string `yaml:"type"` // s3, remote, local
+
+	// S3 backend
+	Bucket        string `yaml:"bucket,omitempty"`
+	Key           string `yaml:"key,omitempty"`
+	Region        string `yaml:"region,omitempty"`
+	DynamoDBTable string `yaml:"dynamodbTable,omitempty"`
+	Encrypt       *bool  `yaml:"encrypt,omitempty"`
+	KmsKeyId      string `yaml:"kmsKeyId,omitempty"`
+
+	// Remote backend
+	Hostname        string `yaml:"hostname,omitempty"`
+	Organization    string `yaml:"organization,omitempty"`
+	WorkspaceName   string `yaml:"workspaceName,omitempty"`
+	WorkspacePrefix string `yaml:"workspacePrefix,omitempty"`
+
+	// Local backend
+	Path string `yaml:"path,omitempty"`
+}
+
+// Validate checks that the required attributes for the backend type are present
+func (b *BackendConfig) Validate() error {
+	switch b.Type {
+	case "s3":
+		if b.Bucket == "" {
+			return fmt.Errorf("s3 backend requires 'bucket'")
+		}
+		if b.Key == "" {
+			return fmt.Errorf("s3 backend requires 'key'")
+		}
+		if b.Region == "" {
+			return fmt.Errorf("s3 backend requires 'region'")
+		}
+	case "remote":
+		if b.Organization == "" {
+			return fmt.Errorf("remote backend requires 'organization'")
+		}
+		if b.WorkspaceName == "" && b.WorkspacePrefix == "" {
+			return fmt.Errorf("remote backend requires either 'workspaceName' or 'workspacePrefix'")
+		}
+		if b.WorkspaceName != "" && b.WorkspacePrefix != "" {
+			return fmt.Errorf("remote backend cannot set both 'workspaceName' and 'workspacePrefix'")
+		}
+	case "local":
+		// All attributes are optional for the local backend
+	case "":
+		return fmt.Errorf("backend type is required")
+	default:
+		return fmt.Errorf("unsupported backend type '%s', must be one of: s3, remote, local", b.Type)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -15,10 +16,17 @@ const (
 	LogLevelError LogLevel = "error"
 )
 
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
 func SetupLogger(level LogLevel) *logrus.Logger {
 	logger := logrus.New()
 
-	logger.SetOutput(os.Stdout)
+	logger.SetOutput(os.Stderr)
 	logger.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02T15:04:05Z07:00",
 	})
@@ -41,7 +49,7 @@ func SetupLogger(level LogLevel) *logrus.Logger {
 
 func SetupSimpleLogger() *logrus.Logger {
 	logger := logrus.New()
-	logger.SetOutput(os.Stdout)
+	logger.SetOutput(os.Stderr)
 	logger.SetFormatter(&logrus.TextFormatter{
 		DisableColors: false,
 		FullTimestamp: true,
@@ -49,3 +57,35 @@ func SetupSimpleLogger() *logrus.Logger {
 	logger.SetLevel(logrus.InfoLevel)
 	return logger
 }
+
+// ConfigureLogger switches an existing logger to the given format and level
+func ConfigureLogger(logger *logrus.Logger, format LogFormat, level LogLevel) error {
+	switch format {
+	case "", LogFormatText:
+		logger.SetFormatter(&logrus.TextFormatter{
+			DisableColors: false,
+			FullTimestamp: true,
+		})
+	case LogFormatJSON:
+		logger.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02T15:04:05Z07:00",
+		})
+	default:
+		return fmt.Errorf("unsupported log format '%s', must be one of: text, json", format)
+	}
+
+	switch level {
+	case "", LogLevelInfo:
+		logger.SetLevel(logrus.InfoLevel)
+	case LogLevelDebug:
+		logger.SetLevel(logrus.DebugLevel)
+	case LogLevelWarn:
+		logger.SetLevel(logrus.WarnLevel)
+	case LogLevelError:
+		logger.SetLevel(logrus.ErrorLevel)
+	default:
+		return fmt.Errorf("unsupported log level '%s', must be one of: debug, info, warn, error", level)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFileName is the project configuration file discovered in the scan path
+const ProjectConfigFileName = "bedrock-forge.yaml"
+
+var (
+	accountIDPattern     = regexp.MustCompile(`^\d{12}$`)
+	providerNamePattern  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	roleArnPattern       = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/.+$`)
+	moduleAddressPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*)+$`)
+)
+
+// ProjectConfig holds project-wide generator and validation settings
+type ProjectConfig struct {
+	ProjectName     string                    `yaml:"projectName,omitempty"`
+	Environment     string                    `yaml:"environment,omitempty"`
+	ModuleRegistry  string                    `yaml:"moduleRegistry,omitempty"`
+	ModuleVersion   string                    `yaml:"moduleVersion,omitempty"`
+	LambdaKmsKeyArn string                    `yaml:"lambdaKmsKeyArn,omitempty"` // Default key for Lambda environment variables
+	GenerationMode  string                    `yaml:"generationMode,omitempty"`  // module or native
+	GlobalTags      map[string]string         `yaml:"globalTags,omitempty"`      // Added to the provider default_tags
+	OutputLayout    string                    `yaml:"outputLayout,omitempty"`    // single, per-kind or per-resource
+	OutputSyntax    string                    `yaml:"outputSyntax,omitempty"`    // hcl or json
+	Account         string                    `yaml:"account,omitempty"`         // Deployment account ID, ARNs in other accounts are warned about
+	Region          string                    `yaml:"region,omitempty"`          // Deployment region, ARNs in other regions are warned about
+	Providers       map[string]ProviderConfig `yaml:"providers,omitempty"`       // Named AWS providers selected with metadata.provider
+	Backend         *BackendConfig            `yaml:"backend,omitempty"`         // Terraform state backend, local state when unset
+	S3Bucket        string                    `yaml:"s3Bucket,omitempty"`        // Bucket packaged artifacts are uploaded to, required with --upload
+	S3Prefix        string                    `yaml:"s3Prefix,omitempty"`        // Key prefix of uploaded artifacts
+	Validation      ProjectValidationConfig   `yaml:"validation,omitempty"`
+
+	// GuardrailImportAddress is the guardrail resource inside the guardrail module that metadata.import
+	// adopts existing guardrails into, default: aws_bedrock_guardrail.this
+	GuardrailImportAddress string `yaml:"guardrailImportAddress,omitempty"`
+}
+
+// ProviderConfig is a named AWS provider, typically assuming a role in another account
+type ProviderConfig struct {
+	Region     string            `yaml:"region,omitempty"` // Defaults to the region of the default provider
+	AssumeRole *AssumeRoleConfig `yaml:"assumeRole,omitempty"`
+}
+
+// AssumeRoleConfig is the role a named provider assumes
+type AssumeRoleConfig struct {
+	RoleArn     string `yaml:"roleArn"`
+	SessionName string `yaml:"sessionName,omitempty"`
+	ExternalID  string `yaml:"externalId,omitempty"`
+}
+
+// Account returns the account the provider deploys to, or "" when it doesn't assume a role
+func (p ProviderConfig) Account() string {
+	if p.AssumeRole == nil || !roleArnPattern.MatchString(p.AssumeRole.RoleArn) {
+		return ""
+	}
+	// arn:partition:iam::account:role/name
+	return strings.Split(p.AssumeRole.RoleArn, ":")[4]
+}
+
+// ProjectValidationConfig selects the validation rules used by the validate command
+type ProjectValidationConfig struct {
+	Profile    string `yaml:"profile,omitempty"`    // default or enterprise
+	ConfigPath string `yaml:"configPath,omitempty"` // Relative to the project config file
+}
+
+// LoadProjectConfig loads bedrock-forge.yaml from dir. It returns an empty config and
+// an empty path if the file does not exist.
+func LoadProjectConfig(dir string) (*ProjectConfig, string, error) {
+	path := filepath.Join(dir, ProjectConfigFileName)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ProjectConfig{}, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read project config %s: %w", path, err)
+	}
+
+	var projectConfig ProjectConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// Reject unknown keys so typos don't silently fall back to defaults
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&projectConfig); err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+
+	if err := projectConfig.Validate(); err != nil {
+		return nil, "", fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+
+	// Resolve the validation config relative to the project config
+	if projectConfig.Validation.ConfigPath != "" && !filepath.IsAbs(projectConfig.Validation.ConfigPath) {
+		projectConfig.Validation.ConfigPath = filepath.Join(dir, projectConfig.Validation.ConfigPath)
+	}
+
+	return &projectConfig, path, nil
+}
+
+// Validate checks the project config for unsupported values
+func (c *ProjectConfig) Validate() error {
+	switch c.GenerationMode {
+	case "", "module", "native":
+	default:
+		return fmt.Errorf("unsupported generation mode '%s', must be one of: module, native", c.GenerationMode)
+	}
+
+	switch c.OutputLayout {
+	case "", "single", "per-kind", "per-resource":
+	default:
+		return fmt.Errorf("unsupported output layout '%s', must be one of: single, per-kind, per-resource", c.OutputLayout)
+	}
+
+	switch c.OutputSyntax {
+	case "", "hcl", "json":
+	default:
+		return fmt.Errorf("unsupported output syntax '%s', must be one of: hcl, json", c.OutputSyntax)
+	}
+
+	if c.Account != "" && !accountIDPattern.MatchString(c.Account) {
+		return fmt.Errorf("invalid account '%s', must be a 12-digit AWS account ID", c.Account)
+	}
+
+	if c.GuardrailImportAddress != "" && !moduleAddressPattern.MatchString(c.GuardrailImportAddress) {
+		return fmt.Errorf("invalid guardrailImportAddress '%s', must be a resource address such as aws_bedrock_guardrail.this", c.GuardrailImportAddress)
+	}
+
+	names := make([]string, 0, len(c.Providers))
+	for name := range c.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := c.Providers[name].validate(name); err != nil {
+			return err
+		}
+	}
+
+	if c.Backend != nil {
+		if err := c.Backend.Validate(); err != nil {
+			return fmt.Errorf("invalid backend: %w", err)
+		}
+	}
+
+	switch c.Validation.Profile {
+	case "", "default", "enterprise":
+		return nil
+	default:
+		return fmt.Errorf("unsupported validation profile '%s', must be one of: default, enterprise", c.Validation.Profile)
+	}
+}
+
+func (p ProviderConfig) validate(name string) error {
+	if !providerNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid provider name '%s', must start with a letter and contain only letters, digits, underscores and hyphens", name)
+	}
+	if p.AssumeRole != nil && !roleArnPattern.MatchString(p.AssumeRole.RoleArn) {
+		return fmt.Errorf("provider '%s' assumeRole.roleArn '%s' must be an IAM role ARN", name, p.AssumeRole.RoleArn)
+	}
+	return nil
+}
+
+// Override replaces values with the non-empty values of overrides, e.g. from CLI flags
+func (c *ProjectConfig) Override(overrides ProjectConfig) {
+	if overrides.ProjectName != "" {
+		c.ProjectName = overrides.ProjectName
+	}
+	if overrides.Environment != "" {
+		c.Environment = overrides.Environment
+	}
+	if overrides.ModuleRegistry != "" {
+		c.ModuleRegistry = overrides.ModuleRegistry
+	}
+	if overrides.ModuleVersion != "" {
+		c.ModuleVersion = overrides.ModuleVersion
+	}
+	if overrides.LambdaKmsKeyArn != "" {
+		c.LambdaKmsKeyArn = overrides.LambdaKmsKeyArn
+	}
+	if overrides.GenerationMode != "" {
+		c.GenerationMode = overrides.GenerationMode
+	}
+	if overrides.Account != "" {
+		c.Account = overrides.Account
+	}
+	if overrides.Region != "" {
+		c.Region = overrides.Region
+	}
+	if overrides.OutputLayout != "" {
+		c.OutputLayout = overrides.OutputLayout
+	}
+	if overrides.OutputSyntax != "" {
+		c.OutputSyntax = overrides.OutputSyntax
+	}
+	for key, value := range overrides.GlobalTags {
+		if c.GlobalTags == nil {
+			c.GlobalTags = make(map[string]string)
+		}
+		c.GlobalTags[key] = value
+	}
+	if overrides.Backend != nil {
+		c.Backend = overrides.Backend
+	}
+	if overrides.S3Bucket != "" {
+		c.S3Bucket = overrides.S3Bucket
+	}
+	if overrides.S3Prefix != "" {
+		c.S3Prefix = overrides.S3Prefix
+	}
+	if overrides.GuardrailImportAddress != "" {
+		c.GuardrailImportAddress = overrides.GuardrailImportAddress
+	}
+	for name, provider := range overrides.Providers {
+		if c.Providers == nil {
+			c.Providers = make(map[string]ProviderConfig)
+		}
+		c.Providers[name] = provider
+	}
+	if overrides.Validation.Profile != "" {
+		c.Validation.Profile = overrides.Validation.Profile
+	}
+	if overrides.Validation.ConfigPath != "" {
+		c.Validation.ConfigPath = overrides.Validation.ConfigPath
+	}
+}
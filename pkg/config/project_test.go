@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectConfigBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *BackendConfig
+		err     string
+	}{
+		{
+			name: "s3",
+			content: `backend:
+  type: s3
+  bucket: tf-state
+  key: bedrock/terraform.tfstate
+  region: us-east-1
+  dynamodbTable: tf-locks
+`,
+			want: &BackendConfig{Type: "s3", Bucket: "tf-state", Key: "bedrock/terraform.tfstate", Region: "us-east-1", DynamoDBTable: "tf-locks"},
+		},
+		{
+			name: "remote",
+			content: `backend:
+  type: remote
+  organization: company
+  workspaceName: bedrock-prod
+`,
+			want: &BackendConfig{Type: "remote", Organization: "company", WorkspaceName: "bedrock-prod"},
+		},
+		{
+			name:    "no backend",
+			content: "projectName: customer-support\n",
+		},
+		{
+			name:    "missing required attribute",
+			content: "backend:\n  type: s3\n  bucket: tf-state\n  region: us-east-1\n",
+			err:     "invalid backend: s3 backend requires 'key'",
+		},
+		{
+			name:    "unsupported type",
+			content: "backend:\n  type: gcs\n",
+			err:     "unsupported backend type 'gcs'",
+		},
+		{
+			name:    "misspelled attribute",
+			content: "backend:\n  type: s3\n  bucket: tf-state\n  key: state\n  region: us-east-1\n  dynamoTable: tf-locks\n",
+			err:     "field dynamoTable not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ProjectConfigFileName), []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			projectConfig, _, err := LoadProjectConfig(dir)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("LoadProjectConfig() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProjectConfig: %v", err)
+			}
+
+			switch {
+			case test.want == nil && projectConfig.Backend != nil:
+				t.Errorf("unexpected backend %+v", projectConfig.Backend)
+			case test.want != nil && (projectConfig.Backend == nil || *projectConfig.Backend != *test.want):
+				t.Errorf("backend = %+v, want %+v", projectConfig.Backend, test.want)
+			}
+		})
+	}
+}
+
+func TestOverrideBackend(t *testing.T) {
+	projectConfig := ProjectConfig{Backend: &BackendConfig{Type: "local"}}
+
+	projectConfig.Override(ProjectConfig{ProjectName: "customer-support"})
+	if projectConfig.Backend == nil || projectConfig.Backend.Type != "local" {
+		t.Errorf("override without a backend replaced it: %+v", projectConfig.Backend)
+	}
+
+	projectConfig.Override(ProjectConfig{Backend: &BackendConfig{Type: "remote", Organization: "company", WorkspacePrefix: "bedrock-"}})
+	if projectConfig.Backend.Type != "remote" {
+		t.Errorf("backend = %+v, want the override", projectConfig.Backend)
+	}
+}